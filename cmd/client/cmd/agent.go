@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/agent"
+	"github.com/Mldlr/storety/internal/client/config"
+	"github.com/Mldlr/storety/internal/client/pkg/helpers"
+	"github.com/Mldlr/storety/internal/client/pkg/output"
+	"github.com/samber/do"
+	cobra "github.com/spf13/cobra"
+	"log"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// agentStartTimeout is the time a background agent is given to listen on its socket.
+const agentStartTimeout = 5 * time.Second
+
+// agentClientCommand creates a cobra command for managing the agent.
+func agentClientCommand(i *do.Injector) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "agent",
+		Short: "Agent operations",
+		Long:  "Running the agent holding the unlocked key for one-shot commands",
+		Run:   func(cmd *cobra.Command, args []string) {},
+	}
+	return cmd
+}
+
+// startAgentCmd creates a cobra command for starting the agent.
+func startAgentCmd(i *do.Injector) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "start",
+		Short: "Start the agent",
+		Long: "Starts the agent in the background and returns once it listens on its socket.\n" +
+			"With --foreground the agent runs in this process until interrupted. The agent starts locked.",
+		Args: cobra.ExactArgs(0),
+		RunE: runStartAgent(i),
+	}
+	cmd.Flags().Bool("foreground", false, "run the agent in the foreground until interrupted")
+	return cmd
+}
+
+// agentStatusCmd creates a cobra command for showing the agent status.
+func agentStatusCmd(i *do.Injector) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show agent status",
+		Long:  "",
+		Args:  cobra.ExactArgs(0),
+		RunE:  runAgentStatus(i),
+	}
+	return cmd
+}
+
+// unlockCmd creates a cobra command for unlocking the agent.
+func unlockCmd(i *do.Injector) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unlock [name]",
+		Short: "Unlock the agent",
+		Long: "Logs the agent into the account, keeping the key in the agent until it is locked or idle.\n" +
+			"The password is asked without echo unless passed with a password flag.",
+		Args: cobra.ExactArgs(1),
+		RunE: runUnlock(i),
+	}
+	addPasswordFlags(cmd)
+	return cmd
+}
+
+// lockCmd creates a cobra command for locking the agent.
+func lockCmd(i *do.Injector) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lock",
+		Short: "Lock the agent",
+		Long:  "Wipes the key held by the agent.",
+		Args:  cobra.ExactArgs(0),
+		RunE:  runLock(i),
+	}
+	return cmd
+}
+
+// runStartAgent is a wrapper starting the agent in the background,
+// or serving it until SIGINT or SIGTERM with --foreground.
+func runStartAgent(i *do.Injector) RunEFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		cfg := do.MustInvoke[*config.Config](i)
+		if foreground, _ := cmd.Flags().GetBool("foreground"); !foreground {
+			pid, err := startAgentProcess(cfg.AgentSocket)
+			if err != nil {
+				return helpers.LogError(err)
+			}
+			return printResult(cmd, &output.Message{Message: fmt.Sprintf("Agent started on %s with pid %d", cfg.AgentSocket, pid)})
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		log.Printf("Agent listening on %s\n", cfg.AgentSocket)
+		err := agent.NewServer(i).Serve(ctx)
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		log.Println("Agent stopped")
+		return nil
+	}
+}
+
+// startAgentProcess starts this client as a background agent detached from the terminal
+// and waits until it answers on the socket, returning its process ID.
+// The output of the agent is appended to agent.log next to the socket.
+func startAgentProcess(socket string) (int, error) {
+	if client, err := agent.Dial(socket); err == nil {
+		_, err = client.Status()
+		client.Close()
+		if err == nil {
+			return 0, fmt.Errorf("an agent is already running on %s", socket)
+		}
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return 0, err
+	}
+	dir := filepath.Dir(socket)
+	if err = os.MkdirAll(dir, 0700); err != nil {
+		return 0, fmt.Errorf("failed to create socket directory: %v", err)
+	}
+	logFile := filepath.Join(dir, "agent.log")
+	logOut, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return 0, err
+	}
+	defer logOut.Close()
+	// The arguments of this process are kept, so the agent reads the same config.
+	args := append(append([]string{}, os.Args[1:]...), "--foreground")
+	child := exec.Command(executable, args...)
+	child.Stdout = logOut
+	child.Stderr = logOut
+	detach(child)
+	if err = child.Start(); err != nil {
+		return 0, err
+	}
+	exited := make(chan error, 1)
+	go func() {
+		exited <- child.Wait()
+	}()
+	deadline := time.After(agentStartTimeout)
+	for {
+		select {
+		case err = <-exited:
+			return 0, fmt.Errorf("agent exited on start: %v, see %s", err, logFile)
+		case <-deadline:
+			_ = child.Process.Kill()
+			return 0, fmt.Errorf("agent did not listen on %s within %v, see %s", socket, agentStartTimeout, logFile)
+		case <-time.After(50 * time.Millisecond):
+		}
+		client, err := agent.Dial(socket)
+		if err != nil {
+			continue
+		}
+		_, err = client.Status()
+		client.Close()
+		if err == nil {
+			return child.Process.Pid, nil
+		}
+	}
+}
+
+// runAgentStatus is a wrapper printing whether the agent is running and unlocked.
+func runAgentStatus(i *do.Injector) RunEFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		client, err := dialAgent(i)
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		defer client.Close()
+		status, err := client.Status()
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		return printResult(cmd, status)
+	}
+}
+
+// runUnlock is a wrapper unlocking the agent.
+func runUnlock(i *do.Injector) RunEFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		password, err := readPassword(cmd, "Password", false)
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		client, err := dialAgent(i)
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		defer client.Close()
+		err = client.Unlock(args[0], password)
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		return printResult(cmd, &output.Message{Message: "Agent unlocked"})
+	}
+}
+
+// runLock is a wrapper locking the agent.
+func runLock(i *do.Injector) RunEFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		client, err := dialAgent(i)
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		defer client.Close()
+		err = client.Lock()
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		return printResult(cmd, &output.Message{Message: "Agent locked"})
+	}
+}
+
+// dialAgent connects to the agent on the configured socket.
+func dialAgent(i *do.Injector) (*agent.Client, error) {
+	cfg := do.MustInvoke[*config.Config](i)
+	return agent.Dial(cfg.AgentSocket)
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/pkg/audit"
+	"github.com/Mldlr/storety/internal/client/pkg/helpers"
+	"github.com/Mldlr/storety/internal/client/service/vault"
+	"github.com/Mldlr/storety/internal/constants"
+	"github.com/samber/do"
+	cobra "github.com/spf13/cobra"
+	"time"
+)
+
+// auditDataCmd creates a cobra command for checking the vault for weak, reused and old passwords and expiring cards.
+func auditDataCmd(i *do.Injector) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Audit vault health",
+		Long: "Decrypts the Cred and Card items locally and reports weak passwords, passwords shared by several items,\n" +
+			"passwords not changed for --max-age-days and cards that expired or expire within --expiry-warning-days.\n" +
+			"--breach-db checks the passwords against a local copy of the breached password SHA-1 range files, offline.\n" +
+			"Use --output json for scripts and --fail to exit with code 6 when issues are found.",
+		Args: cobra.ExactArgs(0),
+		RunE: runAuditData(i),
+	}
+	cmd.Flags().Int("min-score", audit.DefaultMinScore, "lowest password strength score from 0 to 4 not reported as weak")
+	cmd.Flags().Int("max-age-days", int(audit.DefaultMaxAge.Hours()/24), "days after which a password is reported as old, 0 disables the check")
+	cmd.Flags().Int("expiry-warning-days", int(audit.DefaultExpiryWarning.Hours()/24), "days before their expiry cards are reported")
+	cmd.Flags().String("breach-db", "", "directory of breached password SHA-1 range files named by their 5 character hash prefix")
+	cmd.Flags().Bool("fail", false, "exit with code 6 when issues are found")
+	return cmd
+}
+
+// runAuditData is a wrapper auditing all items of the vault.
+func runAuditData(i *do.Injector) RunEFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		vaultService := do.MustInvoke[vault.Service](i)
+		opts := audit.DefaultOptions()
+		opts.MinScore, _ = cmd.Flags().GetInt("min-score")
+		maxAge, _ := cmd.Flags().GetInt("max-age-days")
+		opts.MaxAge = time.Duration(maxAge) * 24 * time.Hour
+		warning, _ := cmd.Flags().GetInt("expiry-warning-days")
+		opts.ExpiryWarning = time.Duration(warning) * 24 * time.Hour
+		if dir, _ := cmd.Flags().GetString("breach-db"); dir != "" {
+			db, err := audit.OpenBreachDB(dir)
+			if err != nil {
+				return helpers.LogError(err)
+			}
+			opts.BreachDB = db
+		}
+		data, err := vaultService.ExportData()
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		report, err := audit.Run(data, opts)
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		if err = printResult(cmd, report); err != nil {
+			return err
+		}
+		if fail, _ := cmd.Flags().GetBool("fail"); fail && report.Findings() > 0 {
+			// The report is the output already, so only the exit code is left to set.
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%w: %d issues", constants.ErrAuditFindings, report.Findings())
+		}
+		return nil
+	}
+}
@@ -0,0 +1,351 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/config"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/Mldlr/storety/internal/client/pkg/backup"
+	"github.com/Mldlr/storety/internal/client/pkg/exporter"
+	"github.com/Mldlr/storety/internal/client/pkg/helpers"
+	"github.com/Mldlr/storety/internal/client/pkg/output"
+	"github.com/Mldlr/storety/internal/client/pkg/utils"
+	"github.com/Mldlr/storety/internal/client/service/crypto"
+	"github.com/Mldlr/storety/internal/client/service/user"
+	"github.com/Mldlr/storety/internal/client/service/vault"
+	"github.com/samber/do"
+	cobra "github.com/spf13/cobra"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// attachmentsSuffix is appended to the name of a plaintext export to name its directory of file contents.
+const attachmentsSuffix = ".attachments"
+
+// Restore modes of restore-backup.
+const (
+	restoreMerge   = "merge"
+	restoreReplace = "replace"
+)
+
+// exportDataCmd creates a cobra command for writing an encrypted backup archive or a plaintext export of the vault.
+func exportDataCmd(i *do.Injector) *cobra.Command {
+	formats := make([]string, len(exporter.Formats))
+	for n, f := range exporter.Formats {
+		formats[n] = string(f)
+	}
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export vault to a backup archive",
+		Long: "Writes every item of the vault to a self-contained backup archive encrypted with a backup passphrase,\n" +
+			"independent of the account password. Restore it with restore-backup into this or any other account.\n" +
+			"--plaintext writes the items unencrypted in the --format for other password managers instead,\n" +
+			"after asking for the account password again; it requires --confirm-plaintext.\n" +
+			"File contents are written to the <out>" + attachmentsSuffix + " directory and referred to by their path.",
+		Args: cobra.ExactArgs(0),
+		RunE: runExportData(i),
+	}
+	cmd.Flags().String("out", "", "file to write the export to, usually with the "+backup.Extension+" extension for backups")
+	cmd.Flags().Bool("plaintext", false, "write the items unencrypted instead of a backup archive")
+	cmd.Flags().String("format", "", "format of the plaintext export: "+strings.Join(formats, ", "))
+	cmd.Flags().Bool("confirm-plaintext", false, "confirm writing every secret of the vault unencrypted")
+	cmd.MarkFlagRequired("out")
+	addPasswordFlags(cmd)
+	return cmd
+}
+
+// restoreBackupCmd creates a cobra command for restoring a backup archive into the vault.
+func restoreBackupCmd(i *do.Injector) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore-backup [file]",
+		Short: "Restore vault from a backup archive",
+		Long: "Restores the items of a backup archive into the vault of the logged in account.\n" +
+			"--mode merge keeps existing items and skips archived items with the same name,\n" +
+			"--mode replace deletes every existing item first and asks for confirmation unless --yes is given.\n" +
+			"--account-export restores the export written by user delete-account instead of a backup archive,\n" +
+			"asking for the password of the deleted account to decrypt it.",
+		Args: cobra.ExactArgs(1),
+		RunE: runRestoreBackup(i),
+	}
+	cmd.Flags().String("mode", restoreMerge, "merge or replace")
+	cmd.Flags().Bool("yes", false, "replace without asking for confirmation")
+	cmd.Flags().Bool("account-export", false, "the file is an export written before deleting an account")
+	addPasswordFlags(cmd)
+	return cmd
+}
+
+// runExportData is a wrapper writing the backup archive of the vault.
+func runExportData(i *do.Injector) RunEFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		vaultService := do.MustInvoke[vault.Service](i)
+		cfg := do.MustInvoke[*config.Config](i)
+		out, _ := cmd.Flags().GetString("out")
+		if plaintext, _ := cmd.Flags().GetBool("plaintext"); plaintext {
+			return exportPlaintext(i, cmd, out)
+		}
+		if format, _ := cmd.Flags().GetString("format"); format != "" {
+			return helpers.LogError(errors.New("--format is only used with --plaintext, backups have a single format"))
+		}
+		data, err := vaultService.ExportData()
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		passphrase, err := readPassword(cmd, "Backup passphrase", true)
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		if err = writeBackup(out, backup.New(cfg.Username, data), passphrase); err != nil {
+			return helpers.LogError(err)
+		}
+		return printResult(cmd, &output.Message{Message: fmt.Sprintf("Exported %d items to %s", len(data), out)})
+	}
+}
+
+// exportPlaintext writes the items of the vault unencrypted in the format selected by the flags,
+// after checking the confirmation flag and the account password.
+func exportPlaintext(i *do.Injector, cmd *cobra.Command, out string) error {
+	vaultService := do.MustInvoke[vault.Service](i)
+	userService := do.MustInvoke[user.Service](i)
+	format, _ := cmd.Flags().GetString("format")
+	confirmed, _ := cmd.Flags().GetBool("confirm-plaintext")
+	if !confirmed {
+		return helpers.LogError(errors.New("a plaintext export holds every secret of the vault unencrypted, pass --confirm-plaintext to write it"))
+	}
+	if format == "" {
+		return helpers.LogError(errors.New("--format is required with --plaintext"))
+	}
+	username, err := currentUsername(i)
+	if err != nil {
+		return helpers.LogError(err)
+	}
+	password, err := readPassword(cmd, "Account password", false)
+	if err != nil {
+		return helpers.LogError(err)
+	}
+	if err = userService.VerifyPassword(username, password); err != nil {
+		return helpers.LogError(err)
+	}
+	data, err := vaultService.ExportData()
+	if err != nil {
+		return helpers.LogError(err)
+	}
+	file, err := os.OpenFile(out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return helpers.LogError(err)
+	}
+	dir := out + attachmentsSuffix
+	attachments, err := exporter.Write(exporter.Format(format), file, data, filepath.Base(dir))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = writeAttachments(dir, attachments)
+	}
+	if err != nil {
+		os.Remove(out)
+		return helpers.LogError(err)
+	}
+	message := fmt.Sprintf("Exported %d items unencrypted to %s", len(data), out)
+	if len(attachments) > 0 {
+		message += fmt.Sprintf(" and %d files to %s", len(attachments), dir)
+	}
+	log.Println("Delete the export once it is imported, it is not encrypted")
+	return printResult(cmd, &output.Message{Message: message})
+}
+
+// writeAttachments writes the attachments of a plaintext export to a new directory readable by the user only.
+func writeAttachments(dir string, attachments []exporter.Attachment) error {
+	if len(attachments) == 0 {
+		return nil
+	}
+	if err := os.Mkdir(dir, 0700); err != nil {
+		return err
+	}
+	for _, a := range attachments {
+		if err := os.WriteFile(filepath.Join(dir, a.Path), a.Data, 0600); err != nil {
+			os.RemoveAll(dir)
+			return err
+		}
+	}
+	return nil
+}
+
+// currentUsername returns the name of the user logged in by this process or unlocked in the agent.
+func currentUsername(i *do.Injector) (string, error) {
+	cfg := do.MustInvoke[*config.Config](i)
+	if cfg.Username != "" {
+		return cfg.Username, nil
+	}
+	client, err := dialAgent(i)
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+	status, err := client.Status()
+	if err != nil {
+		return "", err
+	}
+	return status.Username, nil
+}
+
+// writeBackup writes the archive to a new file readable by the user only, removing it if writing fails.
+func writeBackup(filename string, archive *backup.Archive, passphrase string) error {
+	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	err = backup.Write(file, archive, []byte(passphrase))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(filename)
+	}
+	return err
+}
+
+// runRestoreBackup is a wrapper restoring a backup archive into the vault.
+func runRestoreBackup(i *do.Injector) RunEFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		vaultService := do.MustInvoke[vault.Service](i)
+		mode, _ := cmd.Flags().GetString("mode")
+		yes, _ := cmd.Flags().GetBool("yes")
+		if mode != restoreMerge && mode != restoreReplace {
+			return helpers.LogError(fmt.Errorf("unknown restore mode %q, use merge or replace", mode))
+		}
+		restored, err := readRestored(cmd, args[0])
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		existing, err := vaultService.ExportData()
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		if mode == restoreReplace && len(existing) > 0 && !yes {
+			ok, err := confirm(cmd, fmt.Sprintf("Delete all %d items of the vault and restore %d items from the backup?", len(existing), len(restored)))
+			if err != nil {
+				return helpers.LogError(err)
+			}
+			if !ok {
+				return helpers.LogError(errors.New("restore cancelled"))
+			}
+		}
+		report, data := planRestore(restored, existing, mode)
+		if mode == restoreReplace {
+			err = replaceVault(vaultService, existing, data)
+		} else if len(data) > 0 {
+			err = vaultService.CreateBatchData(data)
+		}
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		return printResult(cmd, report)
+	}
+}
+
+// readRestored reads the decrypted items of the backup archive or, with --account-export, of the account export in the file.
+func readRestored(cmd *cobra.Command, filename string) ([]models.Data, error) {
+	if accountExport, _ := cmd.Flags().GetBool("account-export"); accountExport {
+		export, err := utils.ReadVaultExport(filename)
+		if err != nil {
+			return nil, err
+		}
+		password, err := readPassword(cmd, "Password of "+export.Username, false)
+		if err != nil {
+			return nil, err
+		}
+		return decryptVaultExport(export, password)
+	}
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	passphrase, err := readPassword(cmd, "Backup passphrase", false)
+	if err != nil {
+		return nil, err
+	}
+	archive, err := backup.Read(file, []byte(passphrase))
+	if err != nil {
+		return nil, err
+	}
+	return archive.Data(), nil
+}
+
+// decryptVaultExport decrypts the items of the account export with the key derived from the password and the exported salt.
+func decryptVaultExport(export *models.VaultExport, password string) ([]models.Data, error) {
+	salt, err := base64.StdEncoding.DecodeString(export.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid salt in export: %w", err)
+	}
+	c := crypto.NewCryptoWithKey(crypto.DeriveKey(password, salt))
+	data := make([]models.Data, len(export.Items))
+	for i, it := range export.Items {
+		content, err := c.DecryptWithAES256(it.Content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt %s, check the password: %w", it.Name, err)
+		}
+		data[i] = models.Data{ID: it.ID, Name: it.Name, Type: it.Type, Content: content, UpdatedAt: it.UpdatedAt}
+	}
+	return data, nil
+}
+
+// planRestore selects the restored items to store and reports the outcome of every item.
+func planRestore(restored, existing []models.Data, mode string) (*output.ImportReport, []models.Data) {
+	taken := map[string]bool{}
+	for _, d := range existing {
+		taken[d.Name] = true
+	}
+	report := &output.ImportReport{Items: make([]output.ImportedItem, 0, len(restored))}
+	var data []models.Data
+	for _, d := range restored {
+		it := output.ImportedItem{Name: d.Name, Type: d.Type, Status: "restored"}
+		switch {
+		case mode == restoreMerge && taken[d.Name]:
+			it.Status = "skipped"
+			report.Skipped++
+		default:
+			if taken[d.Name] {
+				it.Status = "replaced"
+			}
+			data = append(data, d)
+			report.Imported++
+		}
+		report.Items = append(report.Items, it)
+	}
+	return report, data
+}
+
+// replaceVault deletes the existing items and stores the restored ones.
+// If storing fails, the deleted items are stored again.
+func replaceVault(vaultService vault.Service, existing, data []models.Data) error {
+	var deleted []models.Data
+	for _, d := range existing {
+		if err := vaultService.DeleteData(d.Name); err != nil {
+			restoreItems(vaultService, deleted)
+			return err
+		}
+		deleted = append(deleted, d)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	err := vaultService.CreateBatchData(data)
+	if err != nil {
+		restoreItems(vaultService, deleted)
+	}
+	return err
+}
+
+// restoreItems stores the deleted items again after a failed replace.
+func restoreItems(vaultService vault.Service, deleted []models.Data) {
+	if len(deleted) == 0 {
+		return
+	}
+	if err := vaultService.CreateBatchData(deleted); err != nil {
+		log.Printf("Failed to restore %d deleted items: %v\n", len(deleted), err)
+	}
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/config"
+	"github.com/Mldlr/storety/internal/client/itemtype"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/Mldlr/storety/internal/client/pkg/clipboard"
+	"github.com/Mldlr/storety/internal/client/pkg/output"
+	"github.com/Mldlr/storety/internal/constants"
+	"github.com/samber/do"
+	cobra "github.com/spf13/cobra"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ClearClipboardCmdName is the name of the hidden command clearing the clipboard in the background.
+// It needs neither the server nor the vault, so main runs it with ExecuteClearClipboard before setting them up.
+const ClearClipboardCmdName = "clear-clipboard"
+
+// clearClipboardCmd creates the hidden cobra command started by data get --copy to clear the clipboard later.
+// It reads the hex SHA-256 hash of the copied value from stdin, so the value itself never reaches the process.
+func clearClipboardCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:    ClearClipboardCmdName,
+		Short:  "Clear the clipboard if it still holds the copied value",
+		Hidden: true,
+		Args:   cobra.ExactArgs(0),
+		RunE:   runClearClipboard,
+	}
+	cmd.Flags().String("backend", clipboard.Auto, "clipboard backend")
+	cmd.Flags().Duration("after", 0, "time to wait before clearing")
+	return cmd
+}
+
+// runClearClipboard waits and clears the clipboard, surviving the terminal of the copying command being closed.
+func runClearClipboard(cmd *cobra.Command, args []string) error {
+	signal.Ignore(syscall.SIGHUP)
+	name, _ := cmd.Flags().GetString("backend")
+	after, _ := cmd.Flags().GetDuration("after")
+	line, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil {
+		return err
+	}
+	hash, err := hex.DecodeString(strings.TrimSpace(line))
+	if err != nil {
+		return err
+	}
+	// The process has no output of its own, so OSC 52 sequences go to the controlling terminal.
+	terminal := os.Stderr
+	if tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0); err == nil {
+		defer tty.Close()
+		terminal = tty
+	}
+	b, err := clipboard.New(name, terminal)
+	if err != nil {
+		return err
+	}
+	_, err = clipboard.ClearAfter(context.Background(), b, hash, after)
+	return err
+}
+
+// copyField copies the field of the item to the clipboard and starts a process clearing it after the timeout.
+// Without a field name the first secret field of the item type is copied, like the password of credentials.
+func copyField(cmd *cobra.Command, i *do.Injector, item *models.Item, field string, timeout time.Duration) error {
+	if field == "" {
+		field = defaultCopyField(item)
+	}
+	value, ok := item.Field(field)
+	if !ok {
+		return fmt.Errorf("%w: %s has no field %q", constants.ErrFieldNotFound, item.Name, field)
+	}
+	cfg := do.MustInvoke[*config.Config](i)
+	b, err := clipboard.New(cfg.ClipboardBackend, cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+	if err = b.Copy(value); err != nil {
+		return fmt.Errorf("failed to copy to the clipboard: %w", err)
+	}
+	message := fmt.Sprintf("Copied %s of %s to the clipboard", field, item.Name)
+	if timeout > 0 {
+		if err = scheduleClear(b, value, timeout); err != nil {
+			return fmt.Errorf("copied %s of %s, but failed to schedule clearing the clipboard: %w", field, item.Name, err)
+		}
+		message += fmt.Sprintf(", clearing it in %s", timeout)
+	}
+	return printResult(cmd, &output.Message{Message: message})
+}
+
+// defaultCopyField returns the first secret field of the item type, or the first field of items without one.
+func defaultCopyField(item *models.Item) string {
+	if t, ok := itemtype.Lookup(item.Type); ok {
+		for _, f := range t.Fields {
+			if f.Secret {
+				return f.Name
+			}
+		}
+	}
+	if len(item.Fields) > 0 {
+		return item.Fields[0].Name
+	}
+	return ""
+}
+
+// scheduleClear starts this client in the background to clear the clipboard after the timeout,
+// as the copying command exits right away. The hash of the value is written to a pipe before the start,
+// so the process gets it even when this one exits first. The process is not waited for.
+func scheduleClear(b clipboard.Backend, value string, timeout time.Duration) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	_, err = fmt.Fprintln(w, hex.EncodeToString(clipboard.Hash(value)))
+	w.Close()
+	if err != nil {
+		return err
+	}
+	child := exec.Command(executable, ClearClipboardCmdName, "--backend", b.Name(), "--after", timeout.String())
+	child.Stdin = r
+	if err = child.Start(); err != nil {
+		return err
+	}
+	return child.Process.Release()
+}
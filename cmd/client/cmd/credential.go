@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/pkg/credhelper"
+	"github.com/Mldlr/storety/internal/client/pkg/helpers"
+	"github.com/Mldlr/storety/internal/client/service/vault"
+	"github.com/Mldlr/storety/internal/constants"
+	"github.com/samber/do"
+	cobra "github.com/spf13/cobra"
+)
+
+// gitCredentialCmd creates a cobra command implementing the git credential helper protocol.
+func gitCredentialCmd(i *do.Injector) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "git-credential [get|store|erase]",
+		Short: "Git credential helper",
+		Long: "Serves git credential requests from Cred items, enable it with\n" +
+			"  git config --global credential.helper '!client git-credential'\n" +
+			"Credentials are looked up by the url field of Cred items and by names like git:https://user@host.\n" +
+			"Stored credentials are saved as Cred items and synced like any other item.",
+		Args: cobra.ExactArgs(1),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if !do.MustInvoke[vault.Service](i).Unlocked() {
+				return helpers.LogError(constants.ErrNotLoggedIn)
+			}
+			return nil
+		},
+		RunE: runGitCredential(i),
+	}
+	return cmd
+}
+
+// runGitCredential is a wrapper serving a single git credential request from stdin.
+func runGitCredential(i *do.Injector) RunEFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		vaultService := do.MustInvoke[vault.Service](i)
+		err := credhelper.Git(vaultService, args[0], cmd.InOrStdin(), cmd.OutOrStdout())
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		return nil
+	}
+}
+
+// dockerCredentialCmd creates a cobra command implementing the docker credential helper protocol.
+// The client runs it when invoked as docker-credential-storety.
+func dockerCredentialCmd(i *do.Injector) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "docker-credential [get|store|erase|list]",
+		Short: "Docker credential helper",
+		Long: "Serves docker credential requests from Cred items tagged with the registry server url.\n" +
+			"Link the client as docker-credential-storety on the PATH and set \"credsStore\": \"storety\" in ~/.docker/config.json.",
+		Args: cobra.ExactArgs(1),
+		// Docker reads errors from stdout, so they are written there by the command.
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE:          runDockerCredential(i),
+	}
+	return cmd
+}
+
+// runDockerCredential is a wrapper serving a single docker credential request from stdin.
+func runDockerCredential(i *do.Injector) RunEFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		vaultService := do.MustInvoke[vault.Service](i)
+		err := constants.ErrNotLoggedIn
+		if vaultService.Unlocked() {
+			err = credhelper.Docker(vaultService, args[0], cmd.InOrStdin(), cmd.OutOrStdout())
+		}
+		if err != nil {
+			fmt.Fprintln(cmd.OutOrStdout(), err)
+			return err
+		}
+		return nil
+	}
+}
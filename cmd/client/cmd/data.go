@@ -1,30 +1,28 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"github.com/Mldlr/storety/internal/client/config"
-	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/Mldlr/storety/internal/client/itemtype"
 	"github.com/Mldlr/storety/internal/client/pkg/helpers"
-	"github.com/Mldlr/storety/internal/client/service/crypto"
-	"github.com/Mldlr/storety/internal/client/service/data"
+	"github.com/Mldlr/storety/internal/client/pkg/otp"
+	"github.com/Mldlr/storety/internal/client/pkg/output"
+	"github.com/Mldlr/storety/internal/client/service/vault"
+	"github.com/Mldlr/storety/internal/constants"
 	"github.com/samber/do"
 	cobra "github.com/spf13/cobra"
-	"io"
-	"log"
-	"os"
+	"time"
 )
 
 // dataClientCommand creates a cobra command for interacting with data service.
 func dataClientCommand(i *do.Injector) *cobra.Command {
-	cfg := do.MustInvoke[*config.Config](i)
 	cmd := &cobra.Command{
 		Use:   "data",
 		Short: "Data service operations",
 		Long:  "Creating and deleting data",
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-			if cfg.EncryptionKey == nil {
-				return helpers.LogError(fmt.Errorf("NOT LOGGED IN"))
+			if !do.MustInvoke[vault.Service](i).Unlocked() {
+				return helpers.LogError(constants.ErrNotLoggedIn)
 			}
 			return nil
 		},
@@ -34,50 +32,14 @@ func dataClientCommand(i *do.Injector) *cobra.Command {
 	return cmd
 }
 
-// createCredentials creates a cobra command for creating a new credentials pair.
-func createCredentials(i *do.Injector) *cobra.Command {
+// getOTP creates a cobra command for printing the current code of an OTP.
+func getOTP(i *do.Injector) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "create_cred [data_name] [login] [password] [meta]",
-		Short: "Store new credentials pair",
-		Long:  "",
-		Args:  cobra.ExactArgs(4),
-		RunE:  runCreateCredentials(i),
-	}
-	return cmd
-}
-
-// createCard creates a cobra command for creating a new card.
-func createCard(i *do.Injector) *cobra.Command {
-	cmd := &cobra.Command{
-		Use:   "create_card [data_name] [number] [expires] [name] [surname] [cvv] [meta]",
-		Short: "Store new card",
-		Long:  "",
-		Args:  cobra.ExactArgs(7),
-		RunE:  runCreateCard(i),
-	}
-	return cmd
-}
-
-// createText creates a cobra command for creating a new text data item.
-func createText(i *do.Injector) *cobra.Command {
-	cmd := &cobra.Command{
-		Use:   "create_text [data_name] [text] [meta]",
-		Short: "Store new text",
-		Long:  "",
-		Args:  cobra.ExactArgs(3),
-		RunE:  runCreateText(i),
-	}
-	return cmd
-}
-
-// createBinary creates a cobra command for creating a new binary data item.
-func createBinary(i *do.Injector) *cobra.Command {
-	cmd := &cobra.Command{
-		Use:   "create_binary [data_name] [filename] [meta]",
-		Short: "Store new binary",
-		Long:  "",
-		Args:  cobra.ExactArgs(3),
-		RunE:  runCreateBinary(i),
+		Use:   "otp [data_name]",
+		Short: "Print current OTP code",
+		Long:  "Prints the current code of an OTP item, or of the OTP linked to a Cred item, with the seconds it stays valid.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runGetOTP(i),
 	}
 	return cmd
 }
@@ -97,12 +59,19 @@ func listData(i *do.Injector) *cobra.Command {
 // getData creates a cobra command for getting a data item.
 func getData(i *do.Injector) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "get [data_name]",
+		Use:   "get [data_name] [field]",
 		Short: "Get data item",
-		Long:  "",
-		Args:  cobra.ExactArgs(1),
-		RunE:  runGetData(i),
-	}
+		Long: "Prints the fields of an item. Card numbers and CVVs are masked unless --reveal is given, --field prints the raw value.\n" +
+			"--copy puts a field on the clipboard instead of printing it, the first secret field like the password unless a field is named,\n" +
+			"and clears the clipboard after --clear-after if it still holds the copied value.",
+		Args: cobra.RangeArgs(1, 2),
+		RunE: runGetData(i),
+	}
+	cmd.Flags().String("field", "", "print only the raw value of the field, for example password")
+	cmd.Flags().Bool("reveal", false, "show masked fields like card numbers in full")
+	cmd.Flags().Bool("copy", false, "copy the field to the clipboard instead of printing it")
+	cmd.Flags().Duration("clear-after", 0, "time after which the copied value is cleared, clipboard_timeout of the config by default, 0s keeps it")
+	cmd.MarkFlagsMutuallyExclusive("copy", "field")
 	return cmd
 }
 
@@ -130,211 +99,98 @@ func syncData(i *do.Injector) *cobra.Command {
 	return cmd
 }
 
-// runCreateCredentials is a wrapper creating a new Credentials data item.
-func runCreateCredentials(i *do.Injector) RunEFunc {
-	return func(cmd *cobra.Command, args []string) error {
-		dataService := do.MustInvoke[data.Service](i)
-		cryptoService := do.MustInvoke[crypto.Crypto](i)
-		dataName := args[0]
-		cred := &models.Credentials{
-			Login:    args[1],
-			Password: args[2],
-			Meta:     args[3],
-		}
-		encodedCred, err := json.Marshal(cred)
-		if err != nil {
-			return helpers.LogError(err)
-		}
-		encryptCred, err := cryptoService.EncryptWithAES256(encodedCred)
-		err = dataService.CreateData(dataName, "Cred", encryptCred)
-		if err != nil {
-			return helpers.LogError(err)
-		}
-		log.Println("Successfully created new credentials pair")
-		return nil
-	}
-}
-
-// runCreateCard is a wrapper creating a new Card data item.
-func runCreateCard(i *do.Injector) RunEFunc {
+// runGetOTP is a wrapper printing the current code of an OTP.
+func runGetOTP(i *do.Injector) RunEFunc {
 	return func(cmd *cobra.Command, args []string) error {
-		dataService := do.MustInvoke[data.Service](i)
-		cryptoService := do.MustInvoke[crypto.Crypto](i)
-		dataName := args[0]
-		cred := &models.Card{
-			Number:  args[1],
-			Expires: args[2],
-			CVV:     args[3],
-			Name:    args[4],
-			Surname: args[5],
-			Meta:    args[6],
-		}
-		encodedCred, err := json.Marshal(cred)
+		vaultService := do.MustInvoke[vault.Service](i)
+		o, err := otp.Find(vaultService, args[0])
 		if err != nil {
 			return helpers.LogError(err)
 		}
-		encryptCred, err := cryptoService.EncryptWithAES256(encodedCred)
-		err = dataService.CreateData(dataName, "Card", encryptCred)
+		code, remaining, err := otp.Code(o, time.Now())
 		if err != nil {
 			return helpers.LogError(err)
 		}
-		log.Println("Successfully created new card")
-		return nil
-	}
-}
-
-// runCreateText is a wrapper creating a new text data item.
-func runCreateText(i *do.Injector) RunEFunc {
-	return func(cmd *cobra.Command, args []string) error {
-		dataService := do.MustInvoke[data.Service](i)
-		cryptoService := do.MustInvoke[crypto.Crypto](i)
-		dataName := args[0]
-		cred := &models.Text{
-			Text: args[1],
-			Meta: args[2],
-		}
-		encodedCred, err := json.Marshal(cred)
-		if err != nil {
-			return helpers.LogError(err)
-		}
-		encryptCred, err := cryptoService.EncryptWithAES256(encodedCred)
-		err = dataService.CreateData(dataName, "Text", encryptCred)
-		if err != nil {
-			return helpers.LogError(err)
-		}
-		log.Println("Successfully created new text")
-		return nil
-	}
-}
-
-// runCreateBinary is a wrapper creating a new binary data item.
-func runCreateBinary(i *do.Injector) RunEFunc {
-	return func(cmd *cobra.Command, args []string) error {
-		dataService := do.MustInvoke[data.Service](i)
-		cryptoService := do.MustInvoke[crypto.Crypto](i)
-		dataName := args[0]
-		file, err := os.Open(args[1])
-		if err != nil {
-			return helpers.LogError(err)
-		}
-		defer file.Close()
-
-		blob, err := io.ReadAll(file)
-		if err != nil {
-			return helpers.LogError(err)
-		}
-		cred := &models.Binary{
-			Blob: blob,
-			Meta: args[2],
-		}
-		encodedCred, err := json.Marshal(cred)
-		if err != nil {
-			return helpers.LogError(err)
-		}
-		encryptCred, err := cryptoService.EncryptWithAES256(encodedCred)
-		err = dataService.CreateData(dataName, "Binary", encryptCred)
-		if err != nil {
-			return helpers.LogError(err)
-		}
-		log.Println("Successfully created new binary blob")
-		return nil
+		return printResult(cmd, &output.OTPCode{Code: code, Remaining: int(remaining.Round(time.Second) / time.Second)})
 	}
 }
 
 // runListData is a wrapper for getting data info from the server
 func runListData(i *do.Injector) RunEFunc {
 	return func(cmd *cobra.Command, args []string) error {
-		dataService := do.MustInvoke[data.Service](i)
-		data, err := dataService.ListData()
+		vaultService := do.MustInvoke[vault.Service](i)
+		data, err := vaultService.ListData()
 		if err != nil {
 			return helpers.LogError(err)
 		}
-		for i, v := range data {
-			log.Printf("%d. %s - %s\n", i+1, v.Name, v.Type)
+		list := output.ItemList(data)
+		if list == nil {
+			list = output.ItemList{}
 		}
-		return nil
+		return printResult(cmd, list)
 	}
 }
 
 // runGetData is a wrapper for getting data from the server and formatting it.
+// With the field flag only the raw value of the field is printed, otherwise masked fields are masked unless revealed.
+// With the copy flag the field is copied to the clipboard instead.
 func runGetData(i *do.Injector) RunEFunc {
 	return func(cmd *cobra.Command, args []string) error {
-		dataService := do.MustInvoke[data.Service](i)
-		cryptoService := do.MustInvoke[crypto.Crypto](i)
-		content, typ, err := dataService.GetData(args[0])
-		if err != nil {
-			return helpers.LogError(err)
-		}
-		decryptCred, err := cryptoService.DecryptWithAES256(content)
+		vaultService := do.MustInvoke[vault.Service](i)
+		item, err := vaultService.GetItem(args[0])
 		if err != nil {
 			return helpers.LogError(err)
 		}
-		switch typ {
-		case "Cred":
-			cred := &models.Credentials{}
-			err = json.Unmarshal(decryptCred, cred)
-			if err != nil {
-				return helpers.LogError(err)
+		if copyFlag, _ := cmd.Flags().GetBool("copy"); copyFlag {
+			field := ""
+			if len(args) > 1 {
+				field = args[1]
 			}
-			log.Printf("Login: %s\n", cred.Login)
-			log.Printf("Password: %s\n", cred.Password)
-			log.Printf("Meta: %s\n", cred.Meta)
-		case "Card":
-			card := &models.Card{}
-			err = json.Unmarshal(decryptCred, card)
-			if err != nil {
-				return helpers.LogError(err)
+			timeout := do.MustInvoke[*config.Config](i).ClipboardTimeout
+			if cmd.Flags().Changed("clear-after") {
+				timeout, _ = cmd.Flags().GetDuration("clear-after")
 			}
-			log.Printf("Number: %s\n", card.Number)
-			log.Printf("Expires: %s\n", card.Expires)
-			log.Printf("CVV: %s\n", card.CVV)
-			log.Printf("Name: %s\n", card.Name)
-			log.Printf("Surname: %s\n", card.Surname)
-			log.Printf("Meta: %s\n", card.Meta)
-		case "Text":
-			text := &models.Text{}
-			err = json.Unmarshal(decryptCred, text)
-			if err != nil {
-				return helpers.LogError(err)
-			}
-			log.Printf("Text: %s\n", text.Text)
-			log.Printf("Meta: %s\n", text.Meta)
-		case "Binary":
-			binary := &models.Binary{}
-			err = json.Unmarshal(decryptCred, binary)
-			if err != nil {
-				return helpers.LogError(err)
+			return helpers.LogError(copyField(cmd, i, item, field, timeout))
+		}
+		if len(args) > 1 {
+			return helpers.LogError(fmt.Errorf("the field argument is only accepted with --copy, use --field %s to print it", args[1]))
+		}
+		field, _ := cmd.Flags().GetString("field")
+		if field != "" {
+			value, ok := item.Field(field)
+			if !ok {
+				return helpers.LogError(fmt.Errorf("%w: %s has no field %q", constants.ErrFieldNotFound, item.Name, field))
 			}
-			log.Printf("Blob: %s\n", binary.Blob)
-			log.Printf("Meta: %s\n", binary.Meta)
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), value)
+			return err
+		}
+		if reveal, _ := cmd.Flags().GetBool("reveal"); !reveal {
+			itemtype.Mask(item)
 		}
-		return nil
+		return printResult(cmd, (*output.Item)(item))
 	}
 }
 
 // runDeleteData is a wrapper for deleting data.
 func runDeleteData(i *do.Injector) RunEFunc {
 	return func(cmd *cobra.Command, args []string) error {
-		dataService := do.MustInvoke[data.Service](i)
-		err := dataService.DeleteData(args[0])
+		vaultService := do.MustInvoke[vault.Service](i)
+		err := vaultService.DeleteData(args[0])
 		if err != nil {
 			return helpers.LogError(err)
 		}
-		log.Println("Successfully deleted data")
-		return nil
+		return printResult(cmd, &output.Message{Message: "Successfully deleted data"})
 	}
 }
 
 // runSync is a wrapper for syncing data.
 func runSync(i *do.Injector) RunEFunc {
 	return func(cmd *cobra.Command, args []string) error {
-		dataService := do.MustInvoke[data.Service](i)
-		err := dataService.SyncData()
+		vaultService := do.MustInvoke[vault.Service](i)
+		err := vaultService.SyncData()
 		if err != nil {
-			log.Println("Error syncing data: ", err)
 			return helpers.LogError(err)
 		}
-		log.Println("Successfully synced data")
-		return nil
+		return printResult(cmd, &output.SyncStatus{Synced: true, SyncedAt: time.Now().UTC()})
 	}
 }
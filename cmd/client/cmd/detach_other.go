@@ -0,0 +1,8 @@
+//go:build !unix
+
+package cmd
+
+import "os/exec"
+
+// detach is a no-op on systems without sessions.
+func detach(c *exec.Cmd) {}
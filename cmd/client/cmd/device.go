@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/config"
+	"github.com/Mldlr/storety/internal/client/pkg/helpers"
+	"github.com/Mldlr/storety/internal/client/pkg/output"
+	"github.com/Mldlr/storety/internal/client/service/device"
+	"github.com/Mldlr/storety/internal/constants"
+	"github.com/samber/do"
+	cobra "github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"log"
+)
+
+// deviceClientCommand creates a cobra command for interacting with the device service.
+// All device commands require a logged in user and a server connection.
+func deviceClientCommand(i *do.Injector) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "device",
+		Short: "Device service operations",
+		Long:  "Enrollment and revocation of device client certificates",
+		Run:   func(cmd *cobra.Command, args []string) {},
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			cfg := do.MustInvoke[*config.Config](i)
+			if cfg.EncryptionKey == nil {
+				return helpers.LogError(constants.ErrNotLoggedIn)
+			}
+			conn := do.MustInvoke[*grpc.ClientConn](i)
+			if conn == nil {
+				return helpers.LogError(constants.ErrNoConnection)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+// enrollDeviceCmd creates a cobra command for enrolling this device.
+func enrollDeviceCmd(i *do.Injector) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "enroll [device_name]",
+		Short: "Enroll this device",
+		Long:  "Generates a device key and requests a client certificate for it from the server CA.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runEnrollDevice(i),
+	}
+	return cmd
+}
+
+// listDevicesCmd creates a cobra command for listing the enrolled devices.
+func listDevicesCmd(i *do.Injector) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List enrolled devices",
+		Long:  "",
+		Args:  cobra.ExactArgs(0),
+		RunE:  runListDevices(i),
+	}
+	return cmd
+}
+
+// revokeDeviceCmd creates a cobra command for revoking a device.
+func revokeDeviceCmd(i *do.Injector) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "revoke [device_id]",
+		Short: "Revoke device certificate",
+		Long:  "",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runRevokeDevice(i),
+	}
+	return cmd
+}
+
+// runEnrollDevice returns a RunEFunc that serves as a CLI wrapper for client.Enroll.
+func runEnrollDevice(i *do.Injector) RunEFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		deviceService := do.MustInvoke[device.Service](i)
+		cfg := do.MustInvoke[*config.Config](i)
+		id, err := deviceService.Enroll(args[0])
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		log.Println("Restart the client to connect with the device certificate")
+		return printResult(cmd, &output.Message{
+			Message: fmt.Sprintf("Successfully enrolled device %s, certificate written to %s", id, cfg.DeviceCertFile),
+		})
+	}
+}
+
+// runListDevices returns a RunEFunc that serves as a CLI wrapper for client.ListDevices.
+func runListDevices(i *do.Injector) RunEFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		deviceService := do.MustInvoke[device.Service](i)
+		devices, err := deviceService.ListDevices()
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		return printResult(cmd, output.DeviceList(devices))
+	}
+}
+
+// runRevokeDevice returns a RunEFunc that serves as a CLI wrapper for client.RevokeDevice.
+func runRevokeDevice(i *do.Injector) RunEFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		deviceService := do.MustInvoke[device.Service](i)
+		err := deviceService.RevokeDevice(args[0])
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		return printResult(cmd, &output.Message{Message: "Successfully revoked device"})
+	}
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/itemtype"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/Mldlr/storety/internal/client/pkg/helpers"
+	"github.com/Mldlr/storety/internal/client/pkg/output"
+	"github.com/Mldlr/storety/internal/client/service/vault"
+	"github.com/Mldlr/storety/internal/constants"
+	"github.com/samber/do"
+	cobra "github.com/spf13/cobra"
+	"strconv"
+	"strings"
+)
+
+// fieldClientCommand creates a cobra command for managing the custom fields of items.
+func fieldClientCommand(i *do.Injector) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "field",
+		Short: "Custom field operations",
+		Long: "Adding, changing, removing and ordering custom fields of an item.\n" +
+			"Field kinds are " + strings.Join(itemtype.FieldKinds, ", ") + "; values of hidden fields are asked without echo.",
+		Run: func(cmd *cobra.Command, args []string) {},
+	}
+	return cmd
+}
+
+// addFieldCmd creates a cobra command for adding a custom field.
+func addFieldCmd(i *do.Injector) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add [data_name] [field_name] [value]",
+		Short: "Add custom field",
+		Long:  "Appends a custom field to the item. The value of hidden fields is asked without echo instead of passed as an argument.",
+		Args:  cobra.RangeArgs(2, 3),
+		RunE:  runAddField(i),
+	}
+	cmd.Flags().String("kind", itemtype.FieldText, "kind of the field: "+strings.Join(itemtype.FieldKinds, ", "))
+	return cmd
+}
+
+// setFieldCmd creates a cobra command for changing the value of a custom field.
+func setFieldCmd(i *do.Injector) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set [data_name] [field_name] [value]",
+		Short: "Change custom field",
+		Long:  "Changes the value of a custom field. The value of hidden fields is asked without echo instead of passed as an argument.",
+		Args:  cobra.RangeArgs(2, 3),
+		RunE:  runSetField(i),
+	}
+	return cmd
+}
+
+// removeFieldCmd creates a cobra command for removing a custom field.
+func removeFieldCmd(i *do.Injector) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove [data_name] [field_name]",
+		Short: "Remove custom field",
+		Long:  "",
+		Args:  cobra.ExactArgs(2),
+		RunE:  runRemoveField(i),
+	}
+	return cmd
+}
+
+// moveFieldCmd creates a cobra command for moving a custom field to another position.
+func moveFieldCmd(i *do.Injector) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "move [data_name] [field_name] [position]",
+		Short: "Move custom field",
+		Long:  "Moves a custom field to the position, counted from 1 among the custom fields.",
+		Args:  cobra.ExactArgs(3),
+		RunE:  runMoveField(i),
+	}
+	return cmd
+}
+
+// runAddField is a wrapper appending a custom field to an item.
+func runAddField(i *do.Injector) RunEFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		kind, _ := cmd.Flags().GetString("kind")
+		value, err := fieldValue(cmd, args, kind)
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		err = updateCustomFields(i, args[0], func(fields []models.CustomField) ([]models.CustomField, error) {
+			return append(fields, models.CustomField{Name: args[1], Value: value, Kind: kind}), nil
+		})
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		return printResult(cmd, &output.Message{Message: "Successfully added field " + args[1]})
+	}
+}
+
+// runSetField is a wrapper changing the value of a custom field of an item.
+func runSetField(i *do.Injector) RunEFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		err := updateCustomFields(i, args[0], func(fields []models.CustomField) ([]models.CustomField, error) {
+			n, err := findCustomField(fields, args[0], args[1])
+			if err != nil {
+				return nil, err
+			}
+			fields[n].Value, err = fieldValue(cmd, args, fields[n].Kind)
+			return fields, err
+		})
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		return printResult(cmd, &output.Message{Message: "Successfully changed field " + args[1]})
+	}
+}
+
+// runRemoveField is a wrapper removing a custom field of an item.
+func runRemoveField(i *do.Injector) RunEFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		err := updateCustomFields(i, args[0], func(fields []models.CustomField) ([]models.CustomField, error) {
+			n, err := findCustomField(fields, args[0], args[1])
+			if err != nil {
+				return nil, err
+			}
+			return append(fields[:n], fields[n+1:]...), nil
+		})
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		return printResult(cmd, &output.Message{Message: "Successfully removed field " + args[1]})
+	}
+}
+
+// runMoveField is a wrapper moving a custom field of an item to another position.
+func runMoveField(i *do.Injector) RunEFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		err := updateCustomFields(i, args[0], func(fields []models.CustomField) ([]models.CustomField, error) {
+			n, err := findCustomField(fields, args[0], args[1])
+			if err != nil {
+				return nil, err
+			}
+			position, err := strconv.Atoi(args[2])
+			if err != nil || position < 1 || position > len(fields) {
+				return nil, fmt.Errorf("position must be between 1 and %d", len(fields))
+			}
+			field := fields[n]
+			fields = append(fields[:n], fields[n+1:]...)
+			fields = append(fields[:position-1], append([]models.CustomField{field}, fields[position-1:]...)...)
+			return fields, nil
+		})
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		return printResult(cmd, &output.Message{Message: "Successfully moved field " + args[1]})
+	}
+}
+
+// fieldValue returns the value argument of a field command, or asks for the value of hidden fields.
+func fieldValue(cmd *cobra.Command, args []string, kind string) (string, error) {
+	if kind == itemtype.FieldHidden {
+		if len(args) > 2 {
+			return "", fmt.Errorf("values of hidden fields are asked, not passed as an argument")
+		}
+		return askNewSecret(cmd, args[1])
+	}
+	if len(args) < 3 {
+		return "", fmt.Errorf("value of %s is missing", args[1])
+	}
+	return args[2], nil
+}
+
+// findCustomField returns the index of the custom field with the name, ignoring case.
+func findCustomField(fields []models.CustomField, item, name string) (int, error) {
+	for n, f := range fields {
+		if strings.EqualFold(f.Name, name) {
+			return n, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %s has no custom field %q", constants.ErrFieldNotFound, item, name)
+}
+
+// updateCustomFields replaces the custom fields of the item with those returned by the update function.
+func updateCustomFields(i *do.Injector, name string, update func(fields []models.CustomField) ([]models.CustomField, error)) error {
+	vaultService := do.MustInvoke[vault.Service](i)
+	content, typ, err := vaultService.GetData(name)
+	if err != nil {
+		return err
+	}
+	t, ok := itemtype.Lookup(typ)
+	if !ok {
+		return fmt.Errorf("items of type %s cannot be edited by this client", typ)
+	}
+	fields, err := itemtype.CustomFields(content)
+	if err != nil {
+		return err
+	}
+	fields, err = update(fields)
+	if err != nil {
+		return err
+	}
+	updated, err := t.SetCustomFields(content, fields)
+	if err != nil {
+		return err
+	}
+	return vaultService.UpdateData(name, updated)
+}
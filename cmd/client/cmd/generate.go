@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"errors"
+	"github.com/Mldlr/storety/internal/client/config"
+	"github.com/Mldlr/storety/internal/client/pkg/helpers"
+	"github.com/Mldlr/storety/internal/client/pkg/output"
+	"github.com/Mldlr/storety/internal/client/pkg/passgen"
+	"github.com/Mldlr/storety/internal/client/service/vault"
+	"github.com/Mldlr/storety/internal/constants"
+	"github.com/samber/do"
+	cobra "github.com/spf13/cobra"
+	"strings"
+)
+
+// generateCmd creates a cobra command for generating a password.
+func generateCmd(i *do.Injector) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate password",
+		Long: "Generates a password from the character classes, or a passphrase of words with --passphrase or --words.\n" +
+			"--item starts from the policy stored in a Cred item, so the rules of an earlier generated password are reused,\n" +
+			"or from the policy set with policy set for a prefix of its name.",
+		Args: cobra.ExactArgs(0),
+		RunE: runGenerate(i),
+	}
+	cmd.Flags().String("item", "", "item whose stored or name prefix password policy is used")
+	addPolicyFlags(cmd)
+	return cmd
+}
+
+// addPolicyFlags registers the flags of the password policy.
+func addPolicyFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("length", passgen.DefaultPolicy.Length, "number of characters of the password")
+	cmd.Flags().StringSlice("classes", passgen.DefaultPolicy.Classes, "character classes: "+strings.Join(passgen.Classes, ", "))
+	cmd.Flags().Bool("exclude-ambiguous", false, "leave out characters that are easily confused, like 0 and O")
+	cmd.Flags().Bool("passphrase", false, "generate a passphrase of words instead of characters")
+	cmd.Flags().Int("words", passgen.DefaultWords, "number of words of the passphrase, implies --passphrase")
+	cmd.Flags().String("separator", passgen.DefaultSeparator, "separator of the passphrase words")
+}
+
+// policyFromFlags returns the base policy with the policy flags given on the command line applied.
+// Character flags switch a passphrase policy to characters and passphrase flags the other way round.
+func policyFromFlags(cmd *cobra.Command, base passgen.Policy) passgen.Policy {
+	p := base
+	flags := cmd.Flags()
+	if flags.Changed("length") || flags.Changed("classes") || flags.Changed("exclude-ambiguous") {
+		p.Words = 0
+		p.Separator = ""
+		if p.Length == 0 {
+			p.Length = passgen.DefaultPolicy.Length
+		}
+		if len(p.Classes) == 0 {
+			p.Classes = passgen.DefaultPolicy.Classes
+		}
+	}
+	if flags.Changed("length") {
+		p.Length, _ = flags.GetInt("length")
+	}
+	if flags.Changed("classes") {
+		p.Classes, _ = flags.GetStringSlice("classes")
+	}
+	if flags.Changed("exclude-ambiguous") {
+		p.ExcludeAmbiguous, _ = flags.GetBool("exclude-ambiguous")
+	}
+	if passphrase, _ := flags.GetBool("passphrase"); passphrase || flags.Changed("words") || flags.Changed("separator") {
+		p = passgen.Policy{Words: base.Words, Separator: base.Separator}
+		if p.Words == 0 || flags.Changed("words") {
+			p.Words, _ = flags.GetInt("words")
+		}
+		if p.Separator == "" || flags.Changed("separator") {
+			p.Separator, _ = flags.GetString("separator")
+		}
+	}
+	return p
+}
+
+// resolvePolicy returns the policy for a password of the item with the name and content, nil for a new item:
+// the policy stored in the item, the policy of its name prefix or the default policy.
+func resolvePolicy(i *do.Injector, name string, content []byte) (passgen.Policy, error) {
+	policies, err := passgen.ReadPrefixPolicies(do.MustInvoke[*config.Config](i).PoliciesFile)
+	if err != nil {
+		return passgen.Policy{}, err
+	}
+	return policies.Resolve(name, content)
+}
+
+// runGenerate is a wrapper generating a password.
+func runGenerate(i *do.Injector) RunEFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		base := passgen.DefaultPolicy
+		if name, _ := cmd.Flags().GetString("item"); name != "" {
+			content, _, err := do.MustInvoke[vault.Service](i).GetData(name)
+			if err != nil && !errors.Is(err, constants.ErrGetData) {
+				return helpers.LogError(err)
+			}
+			if base, err = resolvePolicy(i, name, content); err != nil {
+				return helpers.LogError(err)
+			}
+		}
+		policy := policyFromFlags(cmd, base)
+		password, err := passgen.Generate(policy)
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		return printResult(cmd, &output.GeneratedPassword{Password: password, Entropy: int(policy.Entropy())})
+	}
+}
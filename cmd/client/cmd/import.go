@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"github.com/Mldlr/storety/internal/client/pkg/helpers"
+	"github.com/Mldlr/storety/internal/client/pkg/importer"
+	"github.com/Mldlr/storety/internal/client/pkg/output"
+	"github.com/Mldlr/storety/internal/client/service/vault"
+	"github.com/samber/do"
+	cobra "github.com/spf13/cobra"
+	"os"
+	"strings"
+)
+
+// importDataCmd creates a cobra command for importing the export of another password manager.
+func importDataCmd(i *do.Injector) *cobra.Command {
+	formats := make([]string, len(importer.Formats))
+	for n, f := range importer.Formats {
+		formats[n] = string(f)
+	}
+	cmd := &cobra.Command{
+		Use:   "import [file]",
+		Short: "Import data from another password manager",
+		Long: "Imports an unencrypted export of another password manager as Cred, Card, Text and Binary items.\n" +
+			"Fields without a counterpart become custom fields. Entries named like existing items are skipped,\n" +
+			"renamed or fail the import depending on --on-duplicate. Items are stored in a single batch,\n" +
+			"so either all of them are imported or none. Use --dry-run to preview the import.",
+		Args: cobra.ExactArgs(1),
+		RunE: runImportData(i),
+	}
+	cmd.Flags().String("format", "", "format of the export: "+strings.Join(formats, ", "))
+	cmd.Flags().String("on-duplicate", string(importer.Skip), "what to do with entries named like existing items: skip, rename or fail")
+	cmd.Flags().Bool("dry-run", false, "print what would be imported without storing anything")
+	cmd.MarkFlagRequired("format")
+	return cmd
+}
+
+// runImportData is a wrapper importing the entries of an export file.
+func runImportData(i *do.Injector) RunEFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		vaultService := do.MustInvoke[vault.Service](i)
+		format, _ := cmd.Flags().GetString("format")
+		policy, _ := cmd.Flags().GetString("on-duplicate")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		file, err := os.Open(args[0])
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		defer file.Close()
+		entries, err := importer.Parse(importer.Format(format), file)
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		existing, err := vaultService.ListData()
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		results, err := importer.Plan(entries, existing, importer.DuplicatePolicy(policy))
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		report := &output.ImportReport{DryRun: dryRun, Items: make([]output.ImportedItem, 0, len(results))}
+		for _, r := range results {
+			it := output.ImportedItem{Name: r.Name, Type: r.Entry.Type, Status: r.Status}
+			if r.Err != nil {
+				it.Error = r.Err.Error()
+			}
+			if r.Imported() {
+				report.Imported++
+			} else {
+				report.Skipped++
+			}
+			report.Items = append(report.Items, it)
+		}
+		if !dryRun && report.Imported > 0 {
+			if err = vaultService.CreateBatchData(importer.Data(results)); err != nil {
+				return helpers.LogError(err)
+			}
+		}
+		return printResult(cmd, report)
+	}
+}
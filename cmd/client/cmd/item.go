@@ -0,0 +1,302 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/itemtype"
+	"github.com/Mldlr/storety/internal/client/pkg/helpers"
+	"github.com/Mldlr/storety/internal/client/pkg/output"
+	"github.com/Mldlr/storety/internal/client/pkg/passgen"
+	"github.com/Mldlr/storety/internal/client/service/vault"
+	"github.com/Mldlr/storety/internal/constants"
+	"github.com/samber/do"
+	cobra "github.com/spf13/cobra"
+	"os"
+	"strings"
+)
+
+// createItemCmd creates a cobra command for creating an item of the registered type.
+// Arg and File fields become positional arguments after the item name and Flag fields become flags.
+func createItemCmd(i *do.Injector, t *itemtype.Type) *cobra.Command {
+	use := []string{"create_" + t.Command, "[data_name]"}
+	for _, f := range t.Fields {
+		switch f.Input {
+		case itemtype.Arg:
+			use = append(use, "["+f.Name+"]")
+		case itemtype.File:
+			use = append(use, "["+f.Name+"_file]")
+		}
+	}
+	cmd := &cobra.Command{
+		Use:   strings.Join(use, " "),
+		Short: t.Short,
+		Long:  t.Long,
+		Args:  cobra.ExactArgs(len(use) - 1),
+		RunE:  runCreateItem(i, t),
+	}
+	flags := append([]itemtype.Field{}, t.Flags...)
+	for _, f := range t.Fields {
+		if f.Input == itemtype.Flag {
+			flags = append(flags, f)
+		}
+	}
+	for _, f := range flags {
+		if f.Kind == itemtype.Bool {
+			cmd.Flags().Bool(f.Name, false, f.Usage)
+		} else {
+			cmd.Flags().String(f.Name, "", f.Usage)
+		}
+	}
+	if len(promptFields(t)) == 1 {
+		addPasswordFlags(cmd)
+	}
+	if f := generatedField(t); f != nil {
+		cmd.Flags().Bool("generate", false, "generate the "+strings.ToLower(fieldLabel(f))+" instead of asking for it")
+		addPolicyFlags(cmd)
+		if cmd.Flags().Lookup("password-stdin") != nil {
+			cmd.MarkFlagsMutuallyExclusive("generate", "password-stdin", "password-file")
+		}
+	}
+	return cmd
+}
+
+// editDataCmd creates a cobra command for changing the fields of an item.
+func editDataCmd(i *do.Injector) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "edit [data_name]",
+		Short: "Edit data item",
+		Long: "Changes fields of an item. --set sets a field to a value, --ask asks for the value of a secret field without echo.\n" +
+			"--generate creates a new password with the policy stored in the item or set for a prefix of its name,\n" +
+			"changed by the policy flags.\n" +
+			"Generated fields like keys cannot be changed, create a new item instead.",
+		Args: cobra.ExactArgs(1),
+		RunE: runEditData(i),
+	}
+	cmd.Flags().StringArray("set", nil, "field=value to set, may be repeated")
+	cmd.Flags().StringArray("ask", nil, "secret field to ask for, may be repeated")
+	cmd.Flags().String("generate", "", "field to generate a new password for, like password")
+	addPolicyFlags(cmd)
+	return cmd
+}
+
+// promptFields returns the fields of the type asked for without echo.
+func promptFields(t *itemtype.Type) []itemtype.Field {
+	var fields []itemtype.Field
+	for _, f := range t.Fields {
+		if f.Input == itemtype.Prompt {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// generatedField returns the field of the type the password generator can create, if any.
+func generatedField(t *itemtype.Type) *itemtype.Field {
+	for i := range t.Fields {
+		if t.Fields[i].Generate {
+			return &t.Fields[i]
+		}
+	}
+	return nil
+}
+
+// fieldLabel returns the prompt of the field.
+func fieldLabel(f *itemtype.Field) string {
+	if f.Label != "" {
+		return f.Label
+	}
+	return f.Name
+}
+
+// commandPrompter implements itemtype.Prompter for a command.
+type commandPrompter struct {
+	cmd *cobra.Command
+}
+
+// Secret implements the itemtype.Prompter Secret method.
+func (p commandPrompter) Secret(prompt string) (string, error) {
+	return askSecret(p.cmd, prompt)
+}
+
+// Flag implements the itemtype.Prompter Flag method.
+func (p commandPrompter) Flag(name string) string {
+	f := p.cmd.Flags().Lookup(name)
+	if f == nil {
+		return ""
+	}
+	return f.Value.String()
+}
+
+// runCreateItem is a wrapper creating an item of the type from the arguments, flags and prompts.
+// A type with a single prompted field reads it like a password, accepting the password flags.
+func runCreateItem(i *do.Injector, t *itemtype.Type) RunEFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		vaultService := do.MustInvoke[vault.Service](i)
+		dataName := args[0]
+		prompter := commandPrompter{cmd: cmd}
+		scripted := len(promptFields(t)) == 1
+		generate, _ := cmd.Flags().GetBool("generate")
+		policy := passgen.DefaultPolicy
+		if generate {
+			base, err := resolvePolicy(i, dataName, nil)
+			if err != nil {
+				return helpers.LogError(err)
+			}
+			policy = policyFromFlags(cmd, base)
+		}
+		values := itemtype.Values{}
+		next := 1
+		for _, f := range t.Fields {
+			var err error
+			switch f.Input {
+			case itemtype.Arg:
+				values[f.Name] = args[next]
+				next++
+			case itemtype.File:
+				var content []byte
+				content, err = os.ReadFile(args[next])
+				values[f.Name] = base64.StdEncoding.EncodeToString(content)
+				next++
+			case itemtype.Flag:
+				values[f.Name] = prompter.Flag(f.Name)
+			case itemtype.Prompt:
+				if generate && f.Generate {
+					values[f.Name], err = passgen.Generate(policy)
+				} else if scripted {
+					values[f.Name], err = readPassword(cmd, fieldLabel(&f), true)
+				} else {
+					values[f.Name], err = askNewSecret(cmd, fieldLabel(&f))
+				}
+			}
+			if err != nil {
+				return helpers.LogError(err)
+			}
+		}
+		if t.Prepare != nil {
+			if err := t.Prepare(values, prompter); err != nil {
+				return helpers.LogError(err)
+			}
+		}
+		if err := vault.CheckLinks(vaultService, t, values); err != nil {
+			return helpers.LogError(err)
+		}
+		content, err := t.Encode(values)
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		message := t.CreatedMessage(values)
+		if generate {
+			if content, err = passgen.SetItemPolicy(content, policy); err != nil {
+				return helpers.LogError(err)
+			}
+			message += fmt.Sprintf(" with a generated %s, show it with data get %s --field %s",
+				generatedField(t).Name, dataName, generatedField(t).Name)
+		}
+		err = vaultService.CreateData(dataName, t.Name, content)
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		return printResult(cmd, &output.Message{Message: message})
+	}
+}
+
+// runEditData is a wrapper changing the fields of an item.
+func runEditData(i *do.Injector) RunEFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		vaultService := do.MustInvoke[vault.Service](i)
+		dataName := args[0]
+		content, typ, err := vaultService.GetData(dataName)
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		t, ok := itemtype.Lookup(typ)
+		if !ok {
+			return helpers.LogError(fmt.Errorf("items of type %s cannot be edited by this client", typ))
+		}
+		changes, policy, err := readChanges(i, cmd, t, dataName, content)
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		if err = vault.CheckLinks(vaultService, t, changes); err != nil {
+			return helpers.LogError(err)
+		}
+		updated, err := t.Update(content, changes)
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		if policy != nil {
+			if updated, err = passgen.SetItemPolicy(updated, *policy); err != nil {
+				return helpers.LogError(err)
+			}
+		}
+		if err = vaultService.UpdateData(dataName, updated); err != nil {
+			return helpers.LogError(err)
+		}
+		return printResult(cmd, &output.Message{Message: "Successfully updated " + t.Noun})
+	}
+}
+
+// readChanges reads the field values given with the set, ask and generate flags of the edit command.
+// Secret fields are only accepted from prompts or the generator, keeping them out of the shell history.
+// A generated value is returned with the policy it was generated with, to be stored in the item.
+func readChanges(i *do.Injector, cmd *cobra.Command, t *itemtype.Type, item string, content []byte) (itemtype.Values, *passgen.Policy, error) {
+	changes := itemtype.Values{}
+	editable := func(name string) (*itemtype.Field, error) {
+		f, ok := t.Field(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s has no field %q", constants.ErrFieldNotFound, t.Name, name)
+		}
+		if f.Input == itemtype.Generated {
+			return nil, fmt.Errorf("field %s is generated and cannot be edited", f.Name)
+		}
+		return f, nil
+	}
+	sets, _ := cmd.Flags().GetStringArray("set")
+	for _, set := range sets {
+		name, value, ok := strings.Cut(set, "=")
+		if !ok {
+			return nil, nil, fmt.Errorf("--set expects field=value, got %q", set)
+		}
+		f, err := editable(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if f.Secret {
+			return nil, nil, fmt.Errorf("field %s is secret, use --ask %s", f.Name, f.Name)
+		}
+		if f.Kind == itemtype.Bytes {
+			return nil, nil, fmt.Errorf("field %s holds file content, create a new item instead", f.Name)
+		}
+		changes[f.Name] = value
+	}
+	asks, _ := cmd.Flags().GetStringArray("ask")
+	for _, name := range asks {
+		f, err := editable(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if changes[f.Name], err = askNewSecret(cmd, fieldLabel(f)); err != nil {
+			return nil, nil, err
+		}
+	}
+	var policy *passgen.Policy
+	if name, _ := cmd.Flags().GetString("generate"); name != "" {
+		f, ok := t.Field(name)
+		if !ok || !f.Generate {
+			return nil, nil, fmt.Errorf("field %s of %s cannot be generated", name, t.Name)
+		}
+		base, err := resolvePolicy(i, item, content)
+		if err != nil {
+			return nil, nil, err
+		}
+		p := policyFromFlags(cmd, base)
+		if changes[f.Name], err = passgen.Generate(p); err != nil {
+			return nil, nil, err
+		}
+		policy = &p
+	}
+	if len(changes) == 0 {
+		return nil, nil, fmt.Errorf("nothing to change, use --set, --ask or --generate")
+	}
+	return changes, policy, nil
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"github.com/Mldlr/storety/internal/client/pkg/output"
+	"github.com/spf13/cobra"
+	"io"
+	"log"
+	"os"
+)
+
+// outputFlag is the value of the global output flag.
+var outputFlag string
+
+// addOutputFlag registers the global output flag on the root command.
+// Structured formats silence the log and the errors printed by cobra,
+// so stdout carries only the result and stderr only the error document.
+func addOutputFlag(root *cobra.Command) {
+	root.PersistentFlags().StringVar(&outputFlag, "output", string(output.Text), "output format: text, json, yaml or table")
+	cobra.OnInitialize(func() {
+		format, err := output.ParseFormat(outputFlag)
+		structured := err == nil && format != output.Text
+		root.SilenceErrors = structured
+		root.SilenceUsage = structured
+		if structured {
+			log.SetOutput(io.Discard)
+			return
+		}
+		log.SetOutput(os.Stderr)
+	})
+}
+
+// printResult writes the result of the command in the selected output format.
+func printResult(cmd *cobra.Command, v output.Value) error {
+	format, err := output.ParseFormat(outputFlag)
+	if err != nil {
+		return err
+	}
+	return output.Print(cmd.OutOrStdout(), format, v)
+}
+
+// printError writes the error of a failed command to stderr in the selected output format.
+// Text output relies on the error already logged by the command and printed by cobra.
+func printError(err error) {
+	format, parseErr := output.ParseFormat(outputFlag)
+	if parseErr != nil {
+		format = output.Text
+	}
+	if format == output.Text {
+		return
+	}
+	_ = output.Print(os.Stderr, format, output.NewError(err))
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/config"
+	"github.com/Mldlr/storety/internal/client/pkg/helpers"
+	"github.com/Mldlr/storety/internal/client/pkg/output"
+	"github.com/Mldlr/storety/internal/client/pkg/passgen"
+	"github.com/samber/do"
+	cobra "github.com/spf13/cobra"
+)
+
+// policyClientCommand creates a cobra command for managing the password policies of item name prefixes.
+func policyClientCommand(i *do.Injector) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "policy",
+		Short: "Password policies of item name prefixes",
+		Long: "Password policies kept for a prefix of item names, like work/, apply to the items starting with it\n" +
+			"that have no policy of their own. The longest matching prefix wins.",
+		Run: func(cmd *cobra.Command, args []string) {},
+	}
+	return cmd
+}
+
+// setPolicyCmd creates a cobra command for setting the password policy of a prefix.
+func setPolicyCmd(i *do.Injector) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set [prefix]",
+		Short: "Set the password policy of a prefix",
+		Long:  "Sets the policy given by the policy flags, starting from the default policy, for the items named with the prefix.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runSetPolicy(i),
+	}
+	addPolicyFlags(cmd)
+	return cmd
+}
+
+// listPoliciesCmd creates a cobra command for listing the password policies of the prefixes.
+func listPoliciesCmd(i *do.Injector) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the password policies of the prefixes",
+		Long:  "",
+		Args:  cobra.ExactArgs(0),
+		RunE:  runListPolicies(i),
+	}
+	return cmd
+}
+
+// deletePolicyCmd creates a cobra command for deleting the password policy of a prefix.
+func deletePolicyCmd(i *do.Injector) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete [prefix]",
+		Short: "Delete the password policy of a prefix",
+		Long:  "",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runDeletePolicy(i),
+	}
+	return cmd
+}
+
+// runSetPolicy is a wrapper storing the policy of the flags for the prefix.
+func runSetPolicy(i *do.Injector) RunEFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		filename := do.MustInvoke[*config.Config](i).PoliciesFile
+		policies, err := passgen.ReadPrefixPolicies(filename)
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		policy := policyFromFlags(cmd, passgen.DefaultPolicy)
+		if err = policy.Validate(); err != nil {
+			return helpers.LogError(err)
+		}
+		policies[args[0]] = policy
+		if err = policies.Write(filename); err != nil {
+			return helpers.LogError(err)
+		}
+		return printResult(cmd, &output.Message{Message: fmt.Sprintf("Items named %s* are generated with %s", args[0], policy)})
+	}
+}
+
+// runListPolicies is a wrapper printing the policies of the prefixes.
+func runListPolicies(i *do.Injector) RunEFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		policies, err := passgen.ReadPrefixPolicies(do.MustInvoke[*config.Config](i).PoliciesFile)
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		return printResult(cmd, policies)
+	}
+}
+
+// runDeletePolicy is a wrapper deleting the policy of the prefix.
+func runDeletePolicy(i *do.Injector) RunEFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		filename := do.MustInvoke[*config.Config](i).PoliciesFile
+		policies, err := passgen.ReadPrefixPolicies(filename)
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		if _, ok := policies[args[0]]; !ok {
+			return helpers.LogError(fmt.Errorf("no policy is set for prefix %s", args[0]))
+		}
+		delete(policies, args[0])
+		if err = policies.Write(filename); err != nil {
+			return helpers.LogError(err)
+		}
+		return printResult(cmd, &output.Message{Message: "Successfully deleted the policy of " + args[0]})
+	}
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"io"
+	"os"
+	"strings"
+)
+
+// ask prints the question and reads a single line answer from the command input.
+// An empty answer is replaced with the default value.
+func ask(cmd *cobra.Command, question, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s: ", question)
+	}
+	answer, err := readLine(cmd.InOrStdin())
+	if err != nil {
+		return "", err
+	}
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return def, nil
+	}
+	return answer, nil
+}
+
+// confirm asks a yes/no question and reports whether it was answered with yes.
+func confirm(cmd *cobra.Command, question string) (bool, error) {
+	answer, err := ask(cmd, question+" [y/N]", "")
+	if err != nil {
+		return false, err
+	}
+	answer = strings.ToLower(answer)
+	return answer == "y" || answer == "yes", nil
+}
+
+// errSecretMismatch is returned when the confirmation of a new secret differs from the secret.
+var errSecretMismatch = errors.New("entries do not match")
+
+// askSecret prints the prompt and reads a secret without echoing it when the command input is a terminal.
+// Prompts go to the error output, keeping them out of redirected command output.
+func askSecret(cmd *cobra.Command, prompt string) (string, error) {
+	fmt.Fprintf(cmd.ErrOrStderr(), "%s: ", prompt)
+	in := cmd.InOrStdin()
+	if f, ok := in.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		secret, err := term.ReadPassword(int(f.Fd()))
+		fmt.Fprintln(cmd.ErrOrStderr())
+		if err != nil {
+			return "", err
+		}
+		return string(secret), nil
+	}
+	return readLine(in)
+}
+
+// askNewSecret reads a secret twice without echo and fails if the entries differ or are empty.
+func askNewSecret(cmd *cobra.Command, prompt string) (string, error) {
+	secret, err := askSecret(cmd, prompt)
+	if err != nil {
+		return "", err
+	}
+	if secret == "" {
+		return "", fmt.Errorf("%s must not be empty", strings.ToLower(prompt))
+	}
+	repeated, err := askSecret(cmd, "Repeat "+strings.ToLower(prompt))
+	if err != nil {
+		return "", err
+	}
+	if secret != repeated {
+		return "", errSecretMismatch
+	}
+	return secret, nil
+}
+
+// readLine reads a single line without buffering past its end, so following reads from the same input still work.
+func readLine(r io.Reader) (string, error) {
+	var line []byte
+	b := make([]byte, 1)
+	for {
+		n, err := r.Read(b)
+		if n > 0 {
+			if b[0] == '\n' {
+				break
+			}
+			line = append(line, b[0])
+		}
+		if err == io.EOF && len(line) > 0 {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return strings.TrimSuffix(string(line), "\r"), nil
+}
+
+// addPasswordFlags registers the flags for passing the password of a command without a prompt.
+func addPasswordFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("password-stdin", false, "read the password from standard input")
+	cmd.Flags().String("password-file", "", "read the password from the file")
+	cmd.MarkFlagsMutuallyExclusive("password-stdin", "password-file")
+}
+
+// readPassword returns the password passed with the password flags or asks for it without echo.
+// A new password is asked twice. Passwords from stdin or a file are read up to the first line break,
+// leaving the rest of stdin to the answers of following questions.
+func readPassword(cmd *cobra.Command, prompt string, isNew bool) (string, error) {
+	fromStdin, _ := cmd.Flags().GetBool("password-stdin")
+	file, _ := cmd.Flags().GetString("password-file")
+	var password string
+	switch {
+	case fromStdin:
+		line, err := readLine(cmd.InOrStdin())
+		if err != nil {
+			return "", fmt.Errorf("failed to read password from stdin: %v", err)
+		}
+		password = line
+	case file != "":
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("failed to read password file: %v", err)
+		}
+		password = firstLine(content)
+	case isNew:
+		return askNewSecret(cmd, prompt)
+	default:
+		return askSecret(cmd, prompt)
+	}
+	if password == "" {
+		return "", fmt.Errorf("%s must not be empty", strings.ToLower(prompt))
+	}
+	return password, nil
+}
+
+// firstLine returns the content up to the first line break.
+func firstLine(content []byte) string {
+	line, _, _ := strings.Cut(string(content), "\n")
+	return strings.TrimSuffix(line, "\r")
+}
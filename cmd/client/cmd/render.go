@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/pkg/helpers"
+	"github.com/Mldlr/storety/internal/client/pkg/output"
+	"github.com/Mldlr/storety/internal/client/pkg/render"
+	"github.com/Mldlr/storety/internal/client/pkg/utils"
+	"github.com/Mldlr/storety/internal/client/service/vault"
+	"github.com/Mldlr/storety/internal/constants"
+	"github.com/samber/do"
+	cobra "github.com/spf13/cobra"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// renderCmd creates a cobra command for rendering a template with vault references.
+func renderCmd(i *do.Injector) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "render -i [template] [-o output]",
+		Short: "Render a config file template with vault references",
+		Long: "Renders a Go text/template referencing stored items, for example\n" +
+			"  password: {{ secret \"prod-db\" \"password\" }}\n" +
+			"  number: {{ card \"corp\" \"number\" }}\n" +
+			"The functions secret, cred and card take an item and a field, text takes a text item.\n" +
+			"The output file is written readable by the owner only.",
+		Args: cobra.ExactArgs(0),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if !do.MustInvoke[vault.Service](i).Unlocked() {
+				return helpers.LogError(constants.ErrNotLoggedIn)
+			}
+			return nil
+		},
+		RunE: runRender(i),
+	}
+	cmd.Flags().StringP("input", "i", "", "template file")
+	cmd.Flags().StringP("out", "o", "", "output file, stdout if empty")
+	cmd.Flags().Bool("check", false, "only verify that every reference resolves")
+	_ = cmd.MarkFlagRequired("input")
+	return cmd
+}
+
+// runRender is a wrapper rendering the template to the output file or stdout.
+func runRender(i *do.Injector) RunEFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		vaultService := do.MustInvoke[vault.Service](i)
+		input, _ := cmd.Flags().GetString("input")
+		out, _ := cmd.Flags().GetString("out")
+		check, _ := cmd.Flags().GetBool("check")
+		text, err := os.ReadFile(input)
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		renderer := render.New(vaultService.GetItem)
+		if check {
+			unresolved, err := renderer.Check(filepath.Base(input), string(text))
+			if err != nil {
+				return helpers.LogError(err)
+			}
+			if len(unresolved) > 0 {
+				return helpers.LogError(fmt.Errorf("%w: unresolved references:\n  %s",
+					constants.ErrFieldNotFound, strings.Join(unresolved, "\n  ")))
+			}
+			return printResult(cmd, &output.Message{Message: "All references resolve"})
+		}
+		buf := &bytes.Buffer{}
+		err = renderer.Render(filepath.Base(input), string(text), buf)
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		if out == "" {
+			_, err = cmd.OutOrStdout().Write(buf.Bytes())
+			return err
+		}
+		err = utils.WriteSecretFile(out, buf.Bytes())
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		return printResult(cmd, &output.Message{Message: fmt.Sprintf("Rendered %s to %s", input, out)})
+	}
+}
@@ -2,9 +2,12 @@
 package cmd
 
 import (
+	"github.com/Mldlr/storety/internal/client/itemtype"
+	"github.com/Mldlr/storety/internal/client/pkg/output"
 	shell "github.com/brianstrauch/cobra-shell"
 	"github.com/samber/do"
 	"github.com/spf13/cobra"
+	"os"
 )
 
 // RunEFunc is a function type that can be used as a cobra command's RunE function.
@@ -14,21 +17,75 @@ type RunEFunc func(cmd *cobra.Command, args []string) error
 var rootCmd = &cobra.Command{}
 
 // Execute initializes and runs the root command along with its subcommands.
+// A failed command exits the process with the exit code of its error.
 func Execute(i *do.Injector) {
+	addOutputFlag(rootCmd)
 	userCmd := userClientCommand(i)
 	userCmd.AddCommand(logInCmd(i))
 	userCmd.AddCommand(createUserCmd(i))
+	userCmd.AddCommand(deleteAccountCmd(i))
 	rootCmd.AddCommand(userCmd)
 	dataCmd := dataClientCommand(i)
-	dataCmd.AddCommand(createCredentials(i))
-	dataCmd.AddCommand(createCard(i))
-	dataCmd.AddCommand(createText(i))
-	dataCmd.AddCommand(createBinary(i))
+	for _, t := range itemtype.All() {
+		dataCmd.AddCommand(createItemCmd(i, t))
+	}
+	dataCmd.AddCommand(editDataCmd(i))
+	dataCmd.AddCommand(importDataCmd(i))
+	dataCmd.AddCommand(exportDataCmd(i))
+	dataCmd.AddCommand(restoreBackupCmd(i))
+	dataCmd.AddCommand(auditDataCmd(i))
+	fieldCmd := fieldClientCommand(i)
+	fieldCmd.AddCommand(addFieldCmd(i))
+	fieldCmd.AddCommand(setFieldCmd(i))
+	fieldCmd.AddCommand(removeFieldCmd(i))
+	fieldCmd.AddCommand(moveFieldCmd(i))
+	dataCmd.AddCommand(fieldCmd)
+	dataCmd.AddCommand(getOTP(i))
 	dataCmd.AddCommand(listData(i))
 	dataCmd.AddCommand(getData(i))
 	dataCmd.AddCommand(deleteData(i))
 	dataCmd.AddCommand(syncData(i))
 	rootCmd.AddCommand(dataCmd)
+	deviceCmd := deviceClientCommand(i)
+	deviceCmd.AddCommand(enrollDeviceCmd(i))
+	deviceCmd.AddCommand(listDevicesCmd(i))
+	deviceCmd.AddCommand(revokeDeviceCmd(i))
+	rootCmd.AddCommand(deviceCmd)
+	agentCmd := agentClientCommand(i)
+	agentCmd.AddCommand(startAgentCmd(i))
+	agentCmd.AddCommand(agentStatusCmd(i))
+	rootCmd.AddCommand(agentCmd)
+	rootCmd.AddCommand(unlockCmd(i))
+	rootCmd.AddCommand(lockCmd(i))
+	rootCmd.AddCommand(runCmd(i))
+	rootCmd.AddCommand(renderCmd(i))
+	rootCmd.AddCommand(generateCmd(i))
+	policyCmd := policyClientCommand(i)
+	policyCmd.AddCommand(setPolicyCmd(i))
+	policyCmd.AddCommand(listPoliciesCmd(i))
+	policyCmd.AddCommand(deletePolicyCmd(i))
+	rootCmd.AddCommand(policyCmd)
+	rootCmd.AddCommand(gitCredentialCmd(i))
+	rootCmd.AddCommand(dockerCredentialCmd(i))
+	rootCmd.AddCommand(sshAgentCmd(i))
+	rootCmd.AddCommand(tuiCmd(i))
+	rootCmd.AddCommand(clearClipboardCmd())
 	rootCmd.AddCommand(shell.New(rootCmd, nil))
-	_ = rootCmd.Execute()
+	run()
+}
+
+// ExecuteClearClipboard runs the hidden clear-clipboard command alone, without the services of the other commands.
+func ExecuteClearClipboard() {
+	addOutputFlag(rootCmd)
+	rootCmd.AddCommand(clearClipboardCmd())
+	run()
+}
+
+// run executes the root command, exiting the process with the exit code of its error.
+func run() {
+	err := rootCmd.Execute()
+	if err != nil {
+		printError(err)
+		os.Exit(output.ExitCode(err))
+	}
 }
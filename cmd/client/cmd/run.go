@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/pkg/helpers"
+	"github.com/Mldlr/storety/internal/client/pkg/secretenv"
+	"github.com/Mldlr/storety/internal/client/service/vault"
+	"github.com/Mldlr/storety/internal/constants"
+	"github.com/samber/do"
+	cobra "github.com/spf13/cobra"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+)
+
+// runCmd creates a cobra command for running a program with secrets in its environment.
+func runCmd(i *do.Injector) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run [flags] -- [program] [args...]",
+		Short: "Run a program with secrets as environment variables",
+		Long: "Runs the program with environment variables set to fields of stored items, for example\n" +
+			"  run --env DB_PASS=prod-db.password --env API_KEY=stripe.text -- ./app\n" +
+			"Secrets echoed by the program to stdout or stderr are masked.",
+		Args: cobra.MinimumNArgs(1),
+		// Failures are logged by the command and the exit code of the program is passed on as is.
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if !do.MustInvoke[vault.Service](i).Unlocked() {
+				return helpers.LogError(constants.ErrNotLoggedIn)
+			}
+			return nil
+		},
+		RunE: runRun(i),
+	}
+	cmd.Flags().SetInterspersed(false)
+	cmd.Flags().StringArray("env", nil, "set the variable to a field of an item, as VAR=item.field")
+	cmd.Flags().String("env-file", "", "read VAR=item.field mappings from the file, one per line")
+	return cmd
+}
+
+// runRun is a wrapper resolving the mappings and running the program with them.
+// The program's exit code becomes the exit code of the command.
+func runRun(i *do.Injector) RunEFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		vaultService := do.MustInvoke[vault.Service](i)
+		mappings, err := readMappings(cmd)
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		env, secrets, err := secretenv.Resolve(mappings, vaultService.GetItem)
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		stdout := secretenv.NewMasker(cmd.OutOrStdout(), secrets)
+		stderr := secretenv.NewMasker(cmd.ErrOrStderr(), secrets)
+		child := exec.Command(args[0], args[1:]...)
+		child.Env = append(os.Environ(), env...)
+		child.Stdin = cmd.InOrStdin()
+		child.Stdout = stdout
+		child.Stderr = stderr
+		if err = child.Start(); err != nil {
+			return helpers.LogError(fmt.Errorf("failed to start %s: %v", args[0], err))
+		}
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+		go func() {
+			for sig := range signals {
+				_ = child.Process.Signal(sig)
+			}
+		}()
+		err = child.Wait()
+		signal.Stop(signals)
+		close(signals)
+		_ = stdout.Flush()
+		_ = stderr.Flush()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return err
+		}
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		return nil
+	}
+}
+
+// readMappings collects the mappings of the env file followed by those of the env flags,
+// so variables passed on the command line override the file.
+func readMappings(cmd *cobra.Command) ([]secretenv.Mapping, error) {
+	var mappings []secretenv.Mapping
+	envFile, _ := cmd.Flags().GetString("env-file")
+	if envFile != "" {
+		f, err := os.Open(envFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open env file: %v", err)
+		}
+		defer f.Close()
+		mappings, err = secretenv.ParseFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse env file: %w", err)
+		}
+	}
+	envFlags, _ := cmd.Flags().GetStringArray("env")
+	for _, e := range envFlags {
+		m, err := secretenv.ParseMapping(e)
+		if err != nil {
+			return nil, err
+		}
+		mappings = append(mappings, m)
+	}
+	if len(mappings) == 0 {
+		return nil, errors.New("no variables to set, use --env or --env-file")
+	}
+	return mappings, nil
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/agent"
+	"github.com/Mldlr/storety/internal/client/config"
+	"github.com/Mldlr/storety/internal/client/pkg/helpers"
+	"github.com/Mldlr/storety/internal/client/pkg/sshagent"
+	"github.com/Mldlr/storety/internal/client/service/vault"
+	"github.com/samber/do"
+	cobra "github.com/spf13/cobra"
+	"log"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+)
+
+// sshAgentCmd creates a cobra command running an ssh agent serving the SSH keys of the vault.
+func sshAgentCmd(i *do.Injector) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ssh-agent",
+		Short: "Run an ssh agent",
+		Long: "Runs an ssh agent in the foreground serving the SSH keys stored in the vault on ssh_agent_socket.\n" +
+			"Keys are read from the vault on every request, so the agent must be unlocked or this process logged in.\n" +
+			"Keys created with --confirm are used only after confirming with the askpass program or on the terminal.",
+		Args: cobra.ExactArgs(0),
+		RunE: runSSHAgent(i),
+	}
+	cmd.Flags().String("askpass", os.Getenv("SSH_ASKPASS"), "program asked to confirm the use of keys, exit status 0 allows the use")
+	return cmd
+}
+
+// runSSHAgent is a wrapper serving the ssh agent until SIGINT or SIGTERM.
+func runSSHAgent(i *do.Injector) RunEFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		cfg := do.MustInvoke[*config.Config](i)
+		vaultService := do.MustInvoke[vault.Service](i)
+		socket, err := filepath.Abs(cfg.SSHAgentSocket)
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		listener, err := agent.Listen(socket)
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		defer os.Remove(socket)
+		if !vaultService.Unlocked() {
+			log.Println("Vault is locked, keys are served once the agent is unlocked")
+		}
+		askpass, _ := cmd.Flags().GetString("askpass")
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		fmt.Fprintf(cmd.OutOrStdout(), "SSH_AUTH_SOCK=%s; export SSH_AUTH_SOCK;\n", socket)
+		err = sshagent.New(vaultService, confirmKey(cmd, askpass)).Serve(ctx, listener)
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		log.Println("SSH agent stopped")
+		return nil
+	}
+}
+
+// confirmKey returns a sshagent.ConfirmFunc running the askpass program in the confirm mode of ssh-askpass,
+// or asking on the terminal of the agent when no program is set.
+func confirmKey(cmd *cobra.Command, askpass string) sshagent.ConfirmFunc {
+	return func(name, comment string) bool {
+		question := fmt.Sprintf("Allow use of ssh key %s (%s)?", name, comment)
+		if askpass != "" {
+			c := exec.Command(askpass, question)
+			c.Env = append(os.Environ(), "SSH_ASKPASS_PROMPT=confirm")
+			return c.Run() == nil
+		}
+		ok, err := confirm(cmd, question)
+		return err == nil && ok
+	}
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"github.com/Mldlr/storety/internal/client/config"
+	"github.com/Mldlr/storety/internal/client/pkg/clipboard"
+	"github.com/Mldlr/storety/internal/client/pkg/helpers"
+	"github.com/Mldlr/storety/internal/client/pkg/passgen"
+	"github.com/Mldlr/storety/internal/client/pkg/tui"
+	"github.com/Mldlr/storety/internal/client/service/vault"
+	"github.com/Mldlr/storety/internal/constants"
+	"github.com/gdamore/tcell/v2"
+	"github.com/samber/do"
+	cobra "github.com/spf13/cobra"
+)
+
+// tuiCmd creates a cobra command for browsing and editing the vault in a full-screen terminal UI.
+func tuiCmd(i *do.Injector) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tui",
+		Short: "Browse and edit the vault in a full-screen terminal UI",
+		Long: "Opens a full-screen terminal UI listing the items with a search filter and the fields of the selected item,\n" +
+			"with secret fields hidden until revealed. Items are created, edited, deleted and synced from the UI,\n" +
+			"and fields are copied to the clipboard with c or 1-9, cleared after clipboard_timeout of the config.\n" +
+			"The vault is synced with the server on start unless --offline is given.",
+		Args: cobra.ExactArgs(0),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if !do.MustInvoke[vault.Service](i).Unlocked() {
+				return helpers.LogError(constants.ErrNotLoggedIn)
+			}
+			return nil
+		},
+		RunE: runTUI(i),
+	}
+	cmd.Flags().Bool("offline", false, "do not sync with the server on start")
+	return cmd
+}
+
+// runTUI is a wrapper running the terminal UI until the user quits.
+func runTUI(i *do.Injector) RunEFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		cfg := do.MustInvoke[*config.Config](i)
+		b, err := clipboard.New(cfg.ClipboardBackend, cmd.OutOrStdout())
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		policies, err := passgen.ReadPrefixPolicies(cfg.PoliciesFile)
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		offline, _ := cmd.Flags().GetBool("offline")
+		app := tui.New(do.MustInvoke[vault.Service](i), tui.Options{
+			Clipboard:     b,
+			ClearAfter:    cfg.ClipboardTimeout,
+			ScheduleClear: scheduleClear,
+			SyncOnStart:   !offline,
+			Policies:      policies,
+		})
+		screen, err := tcell.NewScreen()
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		if err = screen.Init(); err != nil {
+			return helpers.LogError(err)
+		}
+		// The screen is restored before an error is logged, and on panics.
+		err = func() error {
+			defer screen.Fini()
+			return app.Run(screen)
+		}()
+		return helpers.LogError(err)
+	}
+}
@@ -1,16 +1,22 @@
 package cmd
 
 import (
+	"encoding/base64"
 	"fmt"
 	"github.com/Mldlr/storety/internal/client/config"
+	"github.com/Mldlr/storety/internal/client/models"
 	"github.com/Mldlr/storety/internal/client/pkg/helpers"
+	"github.com/Mldlr/storety/internal/client/pkg/output"
+	"github.com/Mldlr/storety/internal/client/pkg/utils"
 	"github.com/Mldlr/storety/internal/client/service/data"
 	"github.com/Mldlr/storety/internal/client/service/user"
 	"github.com/Mldlr/storety/internal/client/storage/sqlite"
+	"github.com/Mldlr/storety/internal/constants"
 	"github.com/samber/do"
 	cobra "github.com/spf13/cobra"
 	"google.golang.org/grpc"
 	"log"
+	"time"
 )
 
 // userClientCommand creates a cobra command for interacting with the user service.
@@ -27,31 +33,60 @@ func userClientCommand(i *do.Injector) *cobra.Command {
 // createUserCmd creates a cobra command for creating a new user.
 func createUserCmd(i *do.Injector) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "create [name] [password]",
+		Use:   "create [name]",
 		Short: "Create new account",
-		Long:  "",
-		Args:  cobra.ExactArgs(2),
+		Long:  "Creates a new account. The password is asked twice without echo unless passed with a password flag.",
+		Args:  cobra.ExactArgs(1),
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 			conn := do.MustInvoke[*grpc.ClientConn](i)
 			if conn == nil {
-				return helpers.LogError(fmt.Errorf("NO SERVER CONNECTION"))
+				return helpers.LogError(constants.ErrNoConnection)
 			}
 			return nil
 		},
 		RunE: runCreateUserCmd(i),
 	}
+	addPasswordFlags(cmd)
 	return cmd
 }
 
 // logInCmd creates a cobra command for logging in a user.
 func logInCmd(i *do.Injector) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "login [name] [password]",
+		Use:   "login [name]",
 		Short: "Log into account",
-		Long:  "",
-		Args:  cobra.ExactArgs(2),
+		Long:  "Logs into the account. The password is asked without echo unless passed with a password flag.",
+		Args:  cobra.ExactArgs(1),
 		RunE:  runLogInCmd(i),
 	}
+	addPasswordFlags(cmd)
+	return cmd
+}
+
+// deleteAccountCmd creates a cobra command for deleting the account of the logged in user.
+func deleteAccountCmd(i *do.Injector) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete-account",
+		Short: "Delete account with all stored data",
+		Long: "Deletes the account with all data on the server, the local database and the locally stored authorization data.\n" +
+			"An encrypted export of the vault is offered before deletion.",
+		Args: cobra.ExactArgs(0),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			cfg := do.MustInvoke[*config.Config](i)
+			if cfg.EncryptionKey == nil {
+				return helpers.LogError(constants.ErrNotLoggedIn)
+			}
+			conn := do.MustInvoke[*grpc.ClientConn](i)
+			if conn == nil {
+				return helpers.LogError(constants.ErrNoConnection)
+			}
+			return nil
+		},
+		RunE: runDeleteAccountCmd(i),
+	}
+	cmd.Flags().String("export", "", "write an encrypted export of the vault to the file before deletion")
+	cmd.Flags().Bool("skip-export", false, "delete the account without offering an export")
+	addPasswordFlags(cmd)
 	return cmd
 }
 
@@ -61,19 +96,22 @@ func runCreateUserCmd(i *do.Injector) RunEFunc {
 		cfg := do.MustInvoke[*config.Config](i)
 		userService := do.MustInvoke[user.Service](i)
 		dataService := do.MustInvoke[data.Service](i)
-		username, password := args[0], args[1]
-		err := userService.CreateUser(username, password)
+		username := args[0]
+		password, err := readPassword(cmd, "Password", true)
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		err = userService.CreateUser(username, password)
 		if err != nil {
 			return helpers.LogError(err)
 		}
-		log.Println("Successfully created new user")
 		db, err := sqlite.NewDB(cfg.DBFilePrefix, username)
 		if err != nil {
 			return helpers.LogError(fmt.Errorf("failed to create new local database, u can continue using remote: %v", err))
 		}
 		dataService.SetStorage(db)
 		log.Println("Successfully initiated new local database")
-		return nil
+		return printResult(cmd, &output.Message{Message: "Successfully created new user"})
 	}
 }
 
@@ -83,17 +121,95 @@ func runLogInCmd(i *do.Injector) RunEFunc {
 		userService := do.MustInvoke[user.Service](i)
 		dataService := do.MustInvoke[data.Service](i)
 		cfg := do.MustInvoke[*config.Config](i)
-		username, password := args[0], args[1]
-		err := userService.LogInUser(username, password)
+		username := args[0]
+		password, err := readPassword(cmd, "Password", false)
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		err = userService.LogInUser(username, password)
 		if err != nil {
 			return helpers.LogError(err)
 		}
-		log.Println("Successfully logged in")
 		db, err := sqlite.NewDB(cfg.DBFilePrefix, username)
 		if err != nil {
 			return helpers.LogError(fmt.Errorf("failed to locate or create local database: %v", err))
 		}
 		dataService.SetStorage(db)
-		return nil
+		return printResult(cmd, &output.Message{Message: "Successfully logged in"})
+	}
+}
+
+// runDeleteAccountCmd returns a RunEFunc that serves as a CLI wrapper for client.DeleteAccount.
+// It exports the vault if requested, deletes the account and wipes the local database and authorization data.
+func runDeleteAccountCmd(i *do.Injector) RunEFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		cfg := do.MustInvoke[*config.Config](i)
+		userService := do.MustInvoke[user.Service](i)
+		dataService := do.MustInvoke[data.Service](i)
+		username := cfg.Username
+		password, err := readPassword(cmd, "Password", false)
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		exportFile, _ := cmd.Flags().GetString("export")
+		skipExport, _ := cmd.Flags().GetBool("skip-export")
+		if exportFile == "" && !skipExport {
+			doExport, err := confirm(cmd, "Export the encrypted vault before deleting the account?")
+			if err != nil {
+				return helpers.LogError(err)
+			}
+			if doExport {
+				exportFile, err = ask(cmd, "Export file", username+"-vault.json")
+				if err != nil {
+					return helpers.LogError(err)
+				}
+			}
+		}
+		if exportFile != "" {
+			err := exportVault(cfg, dataService, exportFile)
+			if err != nil {
+				return helpers.LogError(fmt.Errorf("failed to export vault, account was not deleted: %v", err))
+			}
+			log.Printf("Vault exported to %s, restore it with data restore-backup --account-export and your current password\n", exportFile)
+		}
+		err = userService.DeleteAccount(password)
+		if err != nil {
+			return helpers.LogError(err)
+		}
+		dataService.SetStorage(nil)
+		err = sqlite.RemoveDB(cfg.DBFilePrefix, username)
+		if err != nil {
+			return helpers.LogError(fmt.Errorf("failed to remove local database: %v", err))
+		}
+		log.Println("Successfully wiped local data")
+		return printResult(cmd, &output.Message{Message: "Successfully deleted account"})
+	}
+}
+
+// exportVault writes all local data items of the logged in user, still encrypted, together with the key salt to the file.
+func exportVault(cfg *config.Config, dataService data.Service, filename string) error {
+	_, salt, _, _, err := utils.GetAuthData(cfg.SaltsFile, cfg.Username)
+	if err != nil {
+		return err
+	}
+	items, err := dataService.ExportData()
+	if err != nil {
+		return err
+	}
+	export := &models.VaultExport{
+		Username:   cfg.Username,
+		Salt:       base64.StdEncoding.EncodeToString(salt),
+		ExportedAt: time.Now().UTC(),
+		Items:      make([]models.ExportItem, len(items)),
+	}
+	for i, d := range items {
+		export.Items[i] = models.ExportItem{
+			ID:        d.ID,
+			Name:      d.Name,
+			Type:      d.Type,
+			Content:   d.Content,
+			UpdatedAt: d.UpdatedAt,
+		}
 	}
+	return utils.SaveVaultExport(filename, export)
 }
@@ -1,20 +1,27 @@
 package main
 
 import (
-	"crypto/tls"
+	"bufio"
 	"fmt"
 	"github.com/Mldlr/storety/cmd/client/cmd"
 	"github.com/Mldlr/storety/internal/client/config"
 	interceptors "github.com/Mldlr/storety/internal/client/interceptor"
+	pkgTls "github.com/Mldlr/storety/internal/client/pkg/tls"
 	"github.com/Mldlr/storety/internal/client/service/crypto"
 	"github.com/Mldlr/storety/internal/client/service/data"
+	"github.com/Mldlr/storety/internal/client/service/device"
 	"github.com/Mldlr/storety/internal/client/service/user"
+	"github.com/Mldlr/storety/internal/client/service/vault"
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	"github.com/samber/do"
+	"golang.org/x/term"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/keepalive"
 	"log"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -39,9 +46,22 @@ func main() {
 	if len(buildCommit) == 0 {
 		buildCommit = NA
 	}
-	fmt.Printf("Build version: %s\n", buildVersion)
-	fmt.Printf("Build date: %s\n", buildDate)
-	fmt.Printf("Build commit: %s\n", buildCommit)
+	// Build info goes to stderr, keeping stdout for command results.
+	fmt.Fprintf(os.Stderr, "Build version: %s\n", buildVersion)
+	fmt.Fprintf(os.Stderr, "Build date: %s\n", buildDate)
+	fmt.Fprintf(os.Stderr, "Build commit: %s\n", buildCommit)
+
+	// Invoked as a docker credential helper the operation is the only argument.
+	if filepath.Base(os.Args[0]) == "docker-credential-storety" {
+		os.Args = append([]string{os.Args[0], "docker-credential"}, os.Args[1:]...)
+	}
+
+	// The process clearing the clipboard after data get --copy waits for the whole timeout,
+	// so it skips connecting to the server and opening the vault.
+	if len(os.Args) > 1 && os.Args[1] == cmd.ClearClipboardCmdName {
+		cmd.ExecuteClearClipboard()
+		return
+	}
 
 	injector := do.New()
 	cfg := config.NewConfig()
@@ -51,14 +71,14 @@ func main() {
 			return cfg, nil
 		},
 	)
-	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if cfg.CAFile == "" && cfg.ServerFingerprint == "" {
+		trustOnFirstUse(cfg)
+	}
+	tlsCfg, err := pkgTls.NewConfig(cfg)
 	if err != nil {
-		log.Fatal("Failed to load certificate:", err)
+		log.Println("Server can not be verified, running in local:", err)
+		tlsCfg = nil
 	}
-	cred := credentials.NewTLS(&tls.Config{
-		Certificates:       []tls.Certificate{cert},
-		InsecureSkipVerify: true,
-	})
 	keepaliveParams := keepalive.ClientParameters{
 		Time:                10 * time.Second,
 		Timeout:             2 * time.Second,
@@ -67,17 +87,19 @@ func main() {
 	var conn *grpc.ClientConn
 	authInterceptor := interceptors.NewAuthClientInterceptor(cfg)
 	retryInterceptor := interceptors.NewRetryClientInterceptor(cfg, 10, 5*time.Second, conn)
-	opts := []grpc.DialOption{
-		grpc.WithTransportCredentials(cred),
-		grpc.WithKeepaliveParams(keepaliveParams),
-		grpc.WithUnaryInterceptor(grpc_middleware.ChainUnaryClient(
-			authInterceptor.UnaryInterceptor,
-			retryInterceptor.UnaryInterceptor,
-		)),
-	}
-	conn, err = grpc.Dial(cfg.ServiceAddress, opts...)
-	if err != nil {
-		log.Println("Failed to connect to server, running in local:", err)
+	if tlsCfg != nil {
+		opts := []grpc.DialOption{
+			grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg)),
+			grpc.WithKeepaliveParams(keepaliveParams),
+			grpc.WithUnaryInterceptor(grpc_middleware.ChainUnaryClient(
+				authInterceptor.UnaryInterceptor,
+				retryInterceptor.UnaryInterceptor,
+			)),
+		}
+		conn, err = grpc.Dial(cfg.ServiceAddress, opts...)
+		if err != nil {
+			log.Println("Failed to connect to server, running in local:", err)
+		}
 	}
 	do.Provide(
 		injector,
@@ -89,6 +111,7 @@ func main() {
 	cryptoSvc := crypto.NewCrypto(injector)
 	userService := user.NewServiceImpl(injector)
 	dataService := data.NewServiceImpl(injector)
+	deviceService := device.NewServiceImpl(injector)
 	dataService.StartSyncData()
 	do.Provide(
 		injector,
@@ -108,5 +131,47 @@ func main() {
 			return dataService, nil
 		},
 	)
+	do.Provide(
+		injector,
+		func(i *do.Injector) (device.Service, error) {
+			return deviceService, nil
+		},
+	)
+	do.Provide(
+		injector,
+		func(i *do.Injector) (vault.Service, error) {
+			return vault.NewServiceImpl(i), nil
+		},
+	)
 	cmd.Execute(injector)
 }
+
+// trustOnFirstUse fetches the key fingerprint of the server, asks the user to trust it
+// and pins it in the config when accepted.
+// The question goes to stderr, keeping stdout for command results and credential helper protocols,
+// and is only asked on a terminal, as credential helpers and scripts cannot answer it.
+func trustOnFirstUse(cfg *config.Config) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		log.Println("Server is not trusted on first use without a terminal, set ca_file or server_fingerprint in the config")
+		return
+	}
+	fingerprint, err := pkgTls.FetchFingerprint(cfg.ServiceAddress, 5*time.Second)
+	if err != nil {
+		log.Println("Failed to reach server for first use trust:", err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "The server at %s presented the key fingerprint\n  %s\n", cfg.ServiceAddress, fingerprint)
+	fmt.Fprint(os.Stderr, "Verify it with the server administrator. Trust this server? [y/N]: ")
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if answer != "y" && answer != "yes" {
+		log.Println("Server was not trusted")
+		return
+	}
+	err = cfg.UpdateServerFingerprint(fingerprint)
+	if err != nil {
+		log.Println("Failed to save server fingerprint:", err)
+		return
+	}
+	log.Println("Server fingerprint pinned in config")
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/Mldlr/storety/internal/server/config"
+	pkgTls "github.com/Mldlr/storety/internal/server/pkg/tls"
+	"time"
+)
+
+// certUsage is printed when the cert subcommand is called without a known action.
+const certUsage = "usage: storety-server cert generate [flags]"
+
+// runCert runs the cert subcommand. The only action is generate, which writes a new server certificate and key,
+// signed by the CA or self-signed. Defaults are taken from the server's environment configuration.
+func runCert(args []string) error {
+	if len(args) == 0 || args[0] != "generate" {
+		return errors.New(certUsage)
+	}
+	cfg := config.NewEnvConfig()
+	fs := flag.NewFlagSet("cert generate", flag.ContinueOnError)
+	hosts := fs.String("hosts", cfg.CertHosts, "comma separated DNS names and IP addresses the certificate is valid for")
+	keyType := fs.String("key-type", cfg.CertKeyType, "key type: ecdsa, ed25519 or rsa")
+	rsaBits := fs.Int("rsa-bits", 2048, "size of rsa keys")
+	days := fs.Int("days", cfg.CertValidityDays, "validity in days")
+	certFile := fs.String("cert", cfg.CertFile, "output certificate file path")
+	keyFile := fs.String("key", cfg.KeyFile, "output key file path")
+	selfSigned := fs.Bool("self-signed", false, "self-sign the certificate instead of signing it with the CA")
+	fs.StringVar(&cfg.CAFile, "ca", cfg.CAFile, "CA cert file path, created if missing")
+	fs.StringVar(&cfg.CAKeyFile, "ca-key", cfg.CAKeyFile, "CA key file path, created if missing")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *days <= 0 {
+		return fmt.Errorf("validity must be positive, got %d days", *days)
+	}
+	opts := pkgTls.CertOptions{
+		Hosts:    pkgTls.ParseHosts(*hosts),
+		KeyType:  *keyType,
+		RSABits:  *rsaBits,
+		Validity: time.Duration(*days) * 24 * time.Hour,
+		CertFile: *certFile,
+		KeyFile:  *keyFile,
+	}
+	var ca *pkgTls.CA
+	if !*selfSigned {
+		var err error
+		ca, err = pkgTls.LoadOrCreateCA(cfg)
+		if err != nil {
+			return err
+		}
+	}
+	if err := pkgTls.GenerateCert(opts, ca); err != nil {
+		return err
+	}
+	fmt.Printf("Certificate written to %s, key written to %s\n", opts.CertFile, opts.KeyFile)
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/Mldlr/storety/internal/server/di"
 	"github.com/Mldlr/storety/internal/server/grpcServer"
 	"go.uber.org/zap"
+	"os"
 )
 
 // Build info
@@ -19,6 +20,13 @@ var (
 const NA string = "N/A"
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "cert" {
+		if err := runCert(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
 	if len(buildVersion) == 0 {
 		buildVersion = NA
 	}
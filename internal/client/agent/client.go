@@ -0,0 +1,172 @@
+// Package agent provides the local agent holding the unlocked key of the user
+// and the client used by one-shot commands to reach it over a Unix socket.
+package agent
+
+import (
+	"context"
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/Mldlr/storety/internal/constants"
+	pb "github.com/Mldlr/storety/internal/proto"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Status is the state of the agent.
+type Status struct {
+	Unlocked bool      `json:"unlocked" yaml:"unlocked"`
+	Username string    `json:"username" yaml:"username"`
+	LocksAt  time.Time `json:"locks_at" yaml:"locks_at"`
+}
+
+// Text returns the status as printed by the text output.
+func (s *Status) Text() string {
+	if !s.Unlocked {
+		return "Agent is locked"
+	}
+	return fmt.Sprintf("Agent is unlocked for %s until %s", s.Username, s.LocksAt.Format("15:04:05"))
+}
+
+// Table returns the header and row of the status as printed by the table output.
+func (s *Status) Table() ([]string, [][]string) {
+	locksAt := ""
+	if !s.LocksAt.IsZero() {
+		locksAt = s.LocksAt.Format(time.RFC3339)
+	}
+	return []string{"UNLOCKED", "USERNAME", "LOCKS AT"}, [][]string{{fmt.Sprint(s.Unlocked), s.Username, locksAt}}
+}
+
+// Client is a client of the agent.
+type Client struct {
+	ctx          context.Context
+	conn         *grpc.ClientConn
+	remoteClient pb.AgentClient
+}
+
+// Dial connects to the agent listening on the socket.
+func Dial(socket string) (*Client, error) {
+	if _, err := os.Stat(socket); err != nil {
+		return nil, constants.ErrAgentNotRunning
+	}
+	// The target takes an absolute path, a relative one would be read as the authority of the URL.
+	socket, err := filepath.Abs(socket)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := grpc.Dial("unix://"+socket, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to agent: %v", err)
+	}
+	return &Client{
+		ctx:          context.Background(),
+		conn:         conn,
+		remoteClient: pb.NewAgentClient(conn),
+	}, nil
+}
+
+// Close closes the connection to the agent.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Unlock makes a request to the Unlock RPC to log the user in on the agent.
+func (c *Client) Unlock(username, password string) error {
+	_, err := c.remoteClient.Unlock(c.ctx, &pb.UnlockRequest{Login: username, Password: password})
+	return err
+}
+
+// Lock makes a request to the Lock RPC to wipe the key held by the agent.
+func (c *Client) Lock() error {
+	_, err := c.remoteClient.Lock(c.ctx, &pb.LockRequest{})
+	return err
+}
+
+// Status makes a request to the Status RPC and returns the state of the agent.
+func (c *Client) Status() (*Status, error) {
+	result, err := c.remoteClient.Status(c.ctx, &pb.AgentStatusRequest{})
+	if err != nil {
+		return nil, err
+	}
+	status := &Status{Unlocked: result.Unlocked, Username: result.Login}
+	if result.LocksAt != nil {
+		status.LocksAt = result.LocksAt.AsTime()
+	}
+	return status, nil
+}
+
+// CreateData makes a request to the CreateData RPC to encrypt and store a data entry.
+func (c *Client) CreateData(name, typ string, content []byte) error {
+	_, err := c.remoteClient.CreateData(c.ctx, &pb.AgentCreateDataRequest{Name: name, Type: typ, Content: content})
+	return err
+}
+
+// CreateBatchData makes a request to the CreateBatchData RPC to encrypt and store several data entries.
+func (c *Client) CreateBatchData(data []models.Data) error {
+	request := &pb.AgentCreateBatchDataRequest{Data: make([]*pb.AgentCreateDataRequest, len(data))}
+	for i, d := range data {
+		request.Data[i] = &pb.AgentCreateDataRequest{Name: d.Name, Type: d.Type, Content: d.Content}
+	}
+	_, err := c.remoteClient.CreateBatchData(c.ctx, request)
+	return err
+}
+
+// GetData makes a request to the GetData RPC and returns the decrypted content and the type of a data entry.
+func (c *Client) GetData(name string) ([]byte, string, error) {
+	result, err := c.remoteClient.GetData(c.ctx, &pb.AgentGetDataRequest{Name: name})
+	if err != nil {
+		return nil, "", err
+	}
+	return result.Content, result.Type, nil
+}
+
+// ListData makes a request to the ListData RPC and returns the names and types of the stored data entries.
+func (c *Client) ListData() ([]models.DataInfo, error) {
+	result, err := c.remoteClient.ListData(c.ctx, &pb.AgentListDataRequest{})
+	if err != nil {
+		return nil, err
+	}
+	list := make([]models.DataInfo, len(result.Data))
+	for i, d := range result.Data {
+		list[i] = models.DataInfo{Name: d.Name, Type: d.Type}
+	}
+	return list, nil
+}
+
+// ExportData makes a request to the ExportData RPC and returns all data entries with decrypted contents.
+func (c *Client) ExportData() ([]models.Data, error) {
+	result, err := c.remoteClient.ExportData(c.ctx, &pb.AgentExportDataRequest{})
+	if err != nil {
+		return nil, err
+	}
+	data := make([]models.Data, len(result.Data))
+	for i, d := range result.Data {
+		id, err := uuid.Parse(d.Id)
+		if err != nil {
+			return nil, err
+		}
+		data[i] = models.Data{ID: id, Name: d.Name, Type: d.Type, Content: d.Content, UpdatedAt: d.UpdatedAt.AsTime()}
+	}
+	return data, nil
+}
+
+// UpdateData makes a request to the UpdateData RPC to replace the content of a data entry.
+func (c *Client) UpdateData(name string, content []byte) error {
+	_, err := c.remoteClient.UpdateData(c.ctx, &pb.AgentUpdateDataRequest{Name: name, Content: content})
+	return err
+}
+
+// DeleteData makes a request to the DeleteData RPC to delete a data entry.
+func (c *Client) DeleteData(name string) error {
+	_, err := c.remoteClient.DeleteData(c.ctx, &pb.AgentDeleteDataRequest{Name: name})
+	return err
+}
+
+// SyncData makes a request to the SyncData RPC to sync the agent's data with the server.
+func (c *Client) SyncData() error {
+	_, err := c.remoteClient.SyncData(c.ctx, &pb.AgentSyncDataRequest{})
+	return err
+}
@@ -0,0 +1,328 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/config"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/Mldlr/storety/internal/client/service/crypto"
+	"github.com/Mldlr/storety/internal/client/service/data"
+	"github.com/Mldlr/storety/internal/client/service/user"
+	"github.com/Mldlr/storety/internal/client/storage"
+	"github.com/Mldlr/storety/internal/client/storage/sqlite"
+	"github.com/Mldlr/storety/internal/constants"
+	pb "github.com/Mldlr/storety/internal/proto"
+	"github.com/samber/do"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Server is the agent holding the unlocked key of the user and serving data requests of one-shot commands.
+// It locks itself after being idle for the configured timeout and wipes the key on every lock.
+type Server struct {
+	pb.UnimplementedAgentServer
+	cfg         *config.Config
+	userService user.Service
+	dataService data.Service
+	crypto      crypto.Crypto
+	openStorage func(username string) (storage.Storage, error)
+	idleTimeout time.Duration
+	now         func() time.Time
+
+	// mu serializes requests and the idle lock.
+	mu        sync.Mutex
+	idleTimer *time.Timer
+	locksAt   time.Time
+}
+
+// NewServer creates a new agent Server using the user and data services of the client.
+func NewServer(i *do.Injector) *Server {
+	cfg := do.MustInvoke[*config.Config](i)
+	return &Server{
+		cfg:         cfg,
+		userService: do.MustInvoke[user.Service](i),
+		dataService: do.MustInvoke[data.Service](i),
+		crypto:      do.MustInvoke[crypto.Crypto](i),
+		openStorage: func(username string) (storage.Storage, error) {
+			return sqlite.NewDB(cfg.DBFilePrefix, username)
+		},
+		idleTimeout: cfg.AgentIdleTimeout,
+		now:         time.Now,
+	}
+}
+
+// Serve listens on the configured Unix socket and serves requests until the context is done.
+// On return the agent is locked and the socket removed.
+func (s *Server) Serve(ctx context.Context) error {
+	listener, err := Listen(s.cfg.AgentSocket)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(s.cfg.AgentSocket)
+	srv := grpc.NewServer(grpc.UnaryInterceptor(s.UnaryInterceptor))
+	pb.RegisterAgentServer(srv, s)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Serve(listener)
+	}()
+	select {
+	case <-ctx.Done():
+		srv.GracefulStop()
+	case err = <-errCh:
+	}
+	s.mu.Lock()
+	s.lock()
+	s.mu.Unlock()
+	return err
+}
+
+// Listen creates the Unix socket inside a directory accessible only by the user and restricts the socket itself.
+// A socket left behind by a crashed agent is replaced, a socket of a running agent is an error.
+// It is shared by the agent and the ssh agent.
+func Listen(socket string) (net.Listener, error) {
+	dir := filepath.Dir(socket)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create socket directory: %v", err)
+	}
+	if err := os.Chmod(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to restrict socket directory: %v", err)
+	}
+	if _, err := os.Stat(socket); err == nil {
+		if conn, err := net.DialTimeout("unix", socket, time.Second); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("an agent is already running on %s", socket)
+		}
+		if err = os.Remove(socket); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket: %v", err)
+		}
+	}
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on socket: %v", err)
+	}
+	if err = os.Chmod(socket, 0600); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to restrict socket: %v", err)
+	}
+	return listener, nil
+}
+
+// UnaryInterceptor implements the UnaryInterceptor method of the grpc.UnaryServerInterceptor interface.
+// It serializes requests, rejects data requests while locked and postpones the idle lock on every data request.
+func (s *Server) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch info.FullMethod {
+	case "/proto.Agent/Unlock", "/proto.Agent/Lock", "/proto.Agent/Status":
+		return handler(ctx, req)
+	}
+	if s.cfg.EncryptionKey == nil {
+		return nil, status.Error(codes.FailedPrecondition, constants.ErrAgentLocked.Error())
+	}
+	s.touch()
+	return handler(ctx, req)
+}
+
+// touch postpones the idle lock by the idle timeout. It must be called with mu held.
+func (s *Server) touch() {
+	if s.idleTimeout <= 0 {
+		return
+	}
+	s.locksAt = s.now().Add(s.idleTimeout)
+	if s.idleTimer == nil {
+		s.idleTimer = time.AfterFunc(s.idleTimeout, s.idleLock)
+		return
+	}
+	s.idleTimer.Reset(s.idleTimeout)
+}
+
+// idleLock locks the agent once the idle timeout passed without requests.
+func (s *Server) idleLock() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cfg.EncryptionKey == nil || s.now().Before(s.locksAt) {
+		return
+	}
+	s.lock()
+	log.Println("Agent locked after being idle")
+}
+
+// lock closes the local storage and wipes the key and tokens of the user. It must be called with mu held.
+func (s *Server) lock() {
+	if s.idleTimer != nil {
+		s.idleTimer.Stop()
+		s.idleTimer = nil
+	}
+	s.locksAt = time.Time{}
+	if s.cfg.EncryptionKey == nil {
+		return
+	}
+	s.dataService.SetStorage(nil)
+	s.cfg.Logout()
+}
+
+// Unlock logs the user in and opens the user's local storage.
+func (s *Server) Unlock(ctx context.Context, request *pb.UnlockRequest) (*pb.UnlockResponse, error) {
+	s.lock()
+	err := s.userService.LogInUser(request.Login, request.Password)
+	if err != nil {
+		s.cfg.Logout()
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	db, err := s.openStorage(request.Login)
+	if err != nil {
+		s.cfg.Logout()
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to open local database: %v", err))
+	}
+	s.dataService.SetStorage(db)
+	s.touch()
+	log.Printf("Agent unlocked for %s\n", request.Login)
+	return &pb.UnlockResponse{}, nil
+}
+
+// Lock locks the agent.
+func (s *Server) Lock(ctx context.Context, request *pb.LockRequest) (*pb.LockResponse, error) {
+	s.lock()
+	log.Println("Agent locked")
+	return &pb.LockResponse{}, nil
+}
+
+// Status returns the state of the agent.
+func (s *Server) Status(ctx context.Context, request *pb.AgentStatusRequest) (*pb.AgentStatusResponse, error) {
+	response := &pb.AgentStatusResponse{Unlocked: s.cfg.EncryptionKey != nil, Login: s.cfg.Username}
+	if !s.locksAt.IsZero() {
+		response.LocksAt = timestamppb.New(s.locksAt)
+	}
+	return response, nil
+}
+
+// CreateData encrypts and stores a new data entry.
+func (s *Server) CreateData(ctx context.Context, request *pb.AgentCreateDataRequest) (*pb.AgentCreateDataResponse, error) {
+	content, err := s.crypto.EncryptWithAES256(request.Content)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	err = s.dataService.CreateData(request.Name, request.Type, content)
+	if err != nil {
+		return nil, dataError(err)
+	}
+	return &pb.AgentCreateDataResponse{}, nil
+}
+
+// CreateBatchData encrypts and stores several new data entries at once.
+func (s *Server) CreateBatchData(ctx context.Context, request *pb.AgentCreateBatchDataRequest) (*pb.AgentCreateBatchDataResponse, error) {
+	batch := make([]models.Data, len(request.Data))
+	for i, d := range request.Data {
+		content, err := s.crypto.EncryptWithAES256(d.Content)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		batch[i] = models.Data{Name: d.Name, Type: d.Type, Content: content}
+	}
+	err := s.dataService.CreateBatchData(batch)
+	if err != nil {
+		return nil, dataError(err)
+	}
+	return &pb.AgentCreateBatchDataResponse{}, nil
+}
+
+// GetData returns the decrypted content and the type of a data entry.
+func (s *Server) GetData(ctx context.Context, request *pb.AgentGetDataRequest) (*pb.AgentGetDataResponse, error) {
+	content, typ, err := s.dataService.GetData(request.Name)
+	if err != nil {
+		return nil, dataError(err)
+	}
+	content, err = s.crypto.DecryptWithAES256(content)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.AgentGetDataResponse{Content: content, Type: typ}, nil
+}
+
+// ListData returns the names and types of the stored data entries.
+func (s *Server) ListData(ctx context.Context, request *pb.AgentListDataRequest) (*pb.AgentListDataResponse, error) {
+	list, err := s.dataService.ListData()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	response := &pb.AgentListDataResponse{Data: make([]*pb.DataInfo, len(list))}
+	for i, d := range list {
+		response.Data[i] = &pb.DataInfo{Name: d.Name, Type: d.Type}
+	}
+	return response, nil
+}
+
+// ExportData returns all stored data entries with decrypted contents.
+func (s *Server) ExportData(ctx context.Context, request *pb.AgentExportDataRequest) (*pb.AgentExportDataResponse, error) {
+	data, err := s.dataService.ExportData()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	response := &pb.AgentExportDataResponse{Data: make([]*pb.DataItem, len(data))}
+	for i, d := range data {
+		content, err := s.crypto.DecryptWithAES256(d.Content)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		response.Data[i] = &pb.DataItem{
+			Id:        d.ID.String(),
+			Name:      d.Name,
+			Type:      d.Type,
+			Content:   content,
+			UpdatedAt: timestamppb.New(d.UpdatedAt),
+		}
+	}
+	return response, nil
+}
+
+// UpdateData encrypts and stores the new content of a data entry.
+func (s *Server) UpdateData(ctx context.Context, request *pb.AgentUpdateDataRequest) (*pb.AgentUpdateDataResponse, error) {
+	content, err := s.crypto.EncryptWithAES256(request.Content)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	err = s.dataService.UpdateData(request.Name, content)
+	if err != nil {
+		return nil, dataError(err)
+	}
+	return &pb.AgentUpdateDataResponse{}, nil
+}
+
+// DeleteData deletes a data entry.
+func (s *Server) DeleteData(ctx context.Context, request *pb.AgentDeleteDataRequest) (*pb.AgentDeleteDataResponse, error) {
+	err := s.dataService.DeleteData(request.Name)
+	if err != nil {
+		return nil, dataError(err)
+	}
+	return &pb.AgentDeleteDataResponse{}, nil
+}
+
+// SyncData syncs the local data with the server.
+func (s *Server) SyncData(ctx context.Context, request *pb.AgentSyncDataRequest) (*pb.AgentSyncDataResponse, error) {
+	err := s.dataService.SyncData()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.AgentSyncDataResponse{}, nil
+}
+
+// dataError converts an error of the data service to a status error,
+// keeping missing and duplicate entries distinguishable for the client.
+func dataError(err error) error {
+	switch {
+	case errors.Is(err, constants.ErrDataExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, constants.ErrGetData), errors.Is(err, constants.ErrDeleteData):
+		return status.Error(codes.NotFound, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
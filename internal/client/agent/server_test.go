@@ -0,0 +1,244 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"github.com/Mldlr/storety/internal/client/config"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/Mldlr/storety/internal/client/service/crypto"
+	"github.com/Mldlr/storety/internal/client/storage"
+	"github.com/Mldlr/storety/internal/constants"
+	"github.com/samber/do"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeUserService logs in the user "username" with the password "password".
+type fakeUserService struct {
+	cfg *config.Config
+}
+
+func (f *fakeUserService) CreateUser(username, password string) error     { return nil }
+func (f *fakeUserService) RefreshToken() error                            { return nil }
+func (f *fakeUserService) DeleteAccount(password string) error            { return nil }
+func (f *fakeUserService) VerifyPassword(username, password string) error { return nil }
+func (f *fakeUserService) LogInUser(username, password string) error {
+	if username != "username" || password != "password" {
+		return errors.New("invalid credentials")
+	}
+	f.cfg.Username = username
+	f.cfg.EncryptionKey = make([]byte, 32)
+	for i := range f.cfg.EncryptionKey {
+		f.cfg.EncryptionKey[i] = byte(i + 1)
+	}
+	return nil
+}
+
+// fakeDataService keeps the stored entries in memory.
+type fakeDataService struct {
+	entries map[string]models.Data
+	opened  bool
+}
+
+func (f *fakeDataService) CreateData(n, t string, content []byte) error {
+	f.entries[n] = models.Data{Name: n, Type: t, Content: content}
+	return nil
+}
+func (f *fakeDataService) CreateBatchData(data []models.Data) error {
+	for _, d := range data {
+		if _, ok := f.entries[d.Name]; ok {
+			return constants.ErrDataExists
+		}
+	}
+	for _, d := range data {
+		f.entries[d.Name] = d
+	}
+	return nil
+}
+func (f *fakeDataService) ListData() ([]models.DataInfo, error) {
+	list := make([]models.DataInfo, 0, len(f.entries))
+	for _, d := range f.entries {
+		list = append(list, models.DataInfo{Name: d.Name, Type: d.Type})
+	}
+	return list, nil
+}
+func (f *fakeDataService) GetData(n string) ([]byte, string, error) {
+	d, ok := f.entries[n]
+	if !ok {
+		return nil, "", constants.ErrGetData
+	}
+	return d.Content, d.Type, nil
+}
+func (f *fakeDataService) ExportData() ([]models.Data, error) {
+	data := make([]models.Data, 0, len(f.entries))
+	for _, d := range f.entries {
+		data = append(data, d)
+	}
+	return data, nil
+}
+func (f *fakeDataService) UpdateData(n string, content []byte) error {
+	d, ok := f.entries[n]
+	if !ok {
+		return errors.Join(constants.ErrUpdateData, constants.ErrGetData)
+	}
+	d.Content = content
+	f.entries[n] = d
+	return nil
+}
+func (f *fakeDataService) DeleteData(n string) error {
+	delete(f.entries, n)
+	return nil
+}
+func (f *fakeDataService) SyncData() error              { return nil }
+func (f *fakeDataService) SetStorage(s storage.Storage) { f.opened = s != nil }
+
+// startAgent serves an agent on a temporary socket and returns it with a connected client.
+func startAgent(t *testing.T, idleTimeout time.Duration) (*Server, *fakeDataService, *Client) {
+	// Unix socket paths are limited in length, so the socket is not placed in t.TempDir.
+	dir, err := os.MkdirTemp("", "agent")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	cfg := &config.Config{AgentSocket: filepath.Join(dir, "run", "agent.sock")}
+	i := do.New()
+	do.ProvideValue(i, cfg)
+	dataService := &fakeDataService{entries: map[string]models.Data{}}
+	server := &Server{
+		cfg:         cfg,
+		userService: &fakeUserService{cfg: cfg},
+		dataService: dataService,
+		crypto:      *crypto.NewCrypto(i),
+		openStorage: func(username string) (storage.Storage, error) {
+			return &sqliteStub{}, nil
+		},
+		idleTimeout: idleTimeout,
+		now:         time.Now,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Serve(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(cfg.AgentSocket)
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+	client, err := Dial(cfg.AgentSocket)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return server, dataService, client
+}
+
+// sqliteStub stands in for the local database opened on unlock.
+type sqliteStub struct {
+	storage.Storage
+}
+
+func TestServer_Permissions(t *testing.T) {
+	server, _, _ := startAgent(t, 0)
+	info, err := os.Stat(server.cfg.AgentSocket)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	info, err = os.Stat(filepath.Dir(server.cfg.AgentSocket))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+
+	_, err = Listen(server.cfg.AgentSocket)
+	assert.Error(t, err, "second agent must not take over the socket")
+}
+
+func TestServer_LockedRejectsData(t *testing.T) {
+	_, _, client := startAgent(t, 0)
+	_, _, err := client.GetData("name")
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	err = client.Unlock("username", "wrong")
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	st, err := client.Status()
+	require.NoError(t, err)
+	assert.False(t, st.Unlocked)
+}
+
+func TestServer_UnlockAndLock(t *testing.T) {
+	server, dataService, client := startAgent(t, time.Hour)
+	require.NoError(t, client.Unlock("username", "password"))
+	assert.True(t, dataService.opened)
+
+	st, err := client.Status()
+	require.NoError(t, err)
+	assert.True(t, st.Unlocked)
+	assert.Equal(t, "username", st.Username)
+	assert.False(t, st.LocksAt.IsZero())
+
+	require.NoError(t, client.CreateData("name", "Text", []byte("secret")))
+	assert.NotEqual(t, []byte("secret"), dataService.entries["name"].Content, "content must be stored encrypted")
+	content, typ, err := client.GetData("name")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret"), content)
+	assert.Equal(t, "Text", typ)
+	list, err := client.ListData()
+	require.NoError(t, err)
+	assert.Equal(t, []models.DataInfo{{Name: "name", Type: "Text"}}, list)
+	require.NoError(t, client.CreateBatchData([]models.Data{
+		{Name: "first", Type: "Text", Content: []byte("one")},
+		{Name: "second", Type: "Text", Content: []byte("two")},
+	}))
+	assert.NotEqual(t, []byte("two"), dataService.entries["second"].Content, "content must be stored encrypted")
+	content, _, err = client.GetData("second")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("two"), content)
+	exported, err := client.ExportData()
+	require.NoError(t, err)
+	contents := map[string]string{}
+	for _, d := range exported {
+		contents[d.Name] = string(d.Content)
+	}
+	assert.Equal(t, map[string]string{"name": "secret", "first": "one", "second": "two"}, contents)
+	require.NoError(t, client.UpdateData("name", []byte("changed")))
+	assert.NotEqual(t, []byte("changed"), dataService.entries["name"].Content, "content must be stored encrypted")
+	content, typ, err = client.GetData("name")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("changed"), content)
+	assert.Equal(t, "Text", typ)
+	err = client.UpdateData("missing", []byte("content"))
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	err = client.CreateBatchData([]models.Data{{Name: "third", Type: "Text"}, {Name: "first", Type: "Text"}})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	_, _, err = client.GetData("third")
+	assert.Equal(t, codes.NotFound, status.Code(err), "no entry of a conflicting batch must be stored")
+	for _, name := range []string{"first", "second"} {
+		require.NoError(t, client.DeleteData(name))
+	}
+	require.NoError(t, client.DeleteData("name"))
+	assert.Empty(t, dataService.entries)
+	_, _, err = client.GetData("name")
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	key := server.cfg.EncryptionKey
+	require.NoError(t, client.Lock())
+	assert.Nil(t, server.cfg.EncryptionKey)
+	assert.Equal(t, make([]byte, 32), key, "key must be wiped on lock")
+	assert.False(t, dataService.opened)
+	_, err = client.ListData()
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestServer_IdleLock(t *testing.T) {
+	server, _, client := startAgent(t, 50*time.Millisecond)
+	require.NoError(t, client.Unlock("username", "password"))
+	key := server.cfg.EncryptionKey
+	assert.Eventually(t, func() bool {
+		st, err := client.Status()
+		return err == nil && !st.Unlocked
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, make([]byte, 32), key, "key must be wiped on idle lock")
+}
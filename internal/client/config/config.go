@@ -4,18 +4,29 @@ package config
 import (
 	"github.com/spf13/viper"
 	"log"
+	"time"
 )
 
 // Config is the configuration for the Storety client.
 type Config struct {
-	ServiceAddress  string `mapstructure:"service_address"`
-	JWTAuthToken    string
-	JWTRefreshToken string
-	CertFile        string `mapstructure:"cert_file"`
-	KeyFile         string `mapstructure:"key_file"`
-	SaltsFile       string `mapstructure:"salts_file"`
-	DBFilePrefix    string `mapstructure:"db_path"`
-	EncryptionKey   []byte
+	ServiceAddress    string `mapstructure:"service_address"`
+	Username          string
+	JWTAuthToken      string
+	JWTRefreshToken   string
+	CAFile            string        `mapstructure:"ca_file"`
+	ServerFingerprint string        `mapstructure:"server_fingerprint"`
+	ServerName        string        `mapstructure:"server_name"`
+	DeviceCertFile    string        `mapstructure:"device_cert_file"`
+	DeviceKeyFile     string        `mapstructure:"device_key_file"`
+	SaltsFile         string        `mapstructure:"salts_file"`
+	PoliciesFile      string        `mapstructure:"policies_file"`
+	DBFilePrefix      string        `mapstructure:"db_path"`
+	AgentSocket       string        `mapstructure:"agent_socket"`
+	AgentIdleTimeout  time.Duration `mapstructure:"agent_idle_timeout"`
+	SSHAgentSocket    string        `mapstructure:"ssh_agent_socket"`
+	ClipboardBackend  string        `mapstructure:"clipboard_backend"`
+	ClipboardTimeout  time.Duration `mapstructure:"clipboard_timeout"`
+	EncryptionKey     []byte
 }
 
 // NewConfig creates a new Config instance and returns a pointer to it.
@@ -25,10 +36,19 @@ func NewConfig() *Config {
 	viper.SetDefault("service_address", ":8081")
 	viper.SetDefault("jwt_auth_token", nil)
 	viper.SetDefault("jwt_refresh_token", nil)
-	viper.SetDefault("cert_file", "cert.pem")
-	viper.SetDefault("key_file", "key.pem")
+	viper.SetDefault("ca_file", "")
+	viper.SetDefault("server_fingerprint", "")
+	viper.SetDefault("server_name", "")
+	viper.SetDefault("device_cert_file", "device.pem")
+	viper.SetDefault("device_key_file", "device_key.pem")
 	viper.SetDefault("salts_file", "salts.json")
+	viper.SetDefault("policies_file", "policies.json")
 	viper.SetDefault("db_path", "")
+	viper.SetDefault("agent_socket", ".storety-agent/agent.sock")
+	viper.SetDefault("agent_idle_timeout", "15m")
+	viper.SetDefault("ssh_agent_socket", ".storety-agent/ssh-agent.sock")
+	viper.SetDefault("clipboard_backend", "auto")
+	viper.SetDefault("clipboard_timeout", "45s")
 	c := &Config{}
 	viper.ReadInConfig()
 	if err := viper.Unmarshal(c); err != nil {
@@ -45,6 +65,30 @@ func (c *Config) UpdateTokens(auth, refresh string) {
 	c.JWTRefreshToken = refresh
 }
 
+// UpdateUsername updates the name of the logged in user in the config.
+func (c *Config) UpdateUsername(username string) {
+	c.Username = username
+}
+
+// Logout clears the user, tokens and encryption key from the config.
+// The key is overwritten with zeros before it is dropped.
+func (c *Config) Logout() {
+	c.Username = ""
+	c.JWTAuthToken = ""
+	c.JWTRefreshToken = ""
+	for i := range c.EncryptionKey {
+		c.EncryptionKey[i] = 0
+	}
+	c.EncryptionKey = nil
+}
+
+// UpdateServerFingerprint updates the pinned server key fingerprint and persists it to the config file.
+func (c *Config) UpdateServerFingerprint(fingerprint string) error {
+	c.ServerFingerprint = fingerprint
+	viper.Set("server_fingerprint", fingerprint)
+	return viper.WriteConfig()
+}
+
 // UpdateKey updates the encryption key in the config.
 // It takes a password string as a parameter and updates the configuration accordingly.
 func (c *Config) UpdateKey(key []byte) {
@@ -4,6 +4,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"os"
 	"testing"
+	"time"
 )
 
 func TestNewConfig(t *testing.T) {
@@ -11,11 +12,17 @@ func TestNewConfig(t *testing.T) {
 	assert.NotNil(t, cfg)
 	defer os.Remove("cfg.yaml")
 	expectedCfg := &Config{
-		ServiceAddress: ":8081",
-		CertFile:       "cert.pem",
-		KeyFile:        "key.pem",
-		SaltsFile:      "salts.json",
-		DBFilePrefix:   "",
+		ServiceAddress:   ":8081",
+		DeviceCertFile:   "device.pem",
+		DeviceKeyFile:    "device_key.pem",
+		SaltsFile:        "salts.json",
+		PoliciesFile:     "policies.json",
+		DBFilePrefix:     "",
+		AgentSocket:      ".storety-agent/agent.sock",
+		AgentIdleTimeout: 15 * time.Minute,
+		SSHAgentSocket:   ".storety-agent/ssh-agent.sock",
+		ClipboardBackend: "auto",
+		ClipboardTimeout: 45 * time.Second,
 	}
 	assert.Equal(t, expectedCfg, cfg)
 }
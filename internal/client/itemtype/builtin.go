@@ -0,0 +1,232 @@
+package itemtype
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/Mldlr/storety/internal/client/pkg/card"
+	"github.com/Mldlr/storety/internal/client/pkg/otp"
+	"github.com/Mldlr/storety/internal/client/pkg/sshagent"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Names of the built-in types.
+const (
+	Cred   = "Cred"
+	Card   = "Card"
+	Text   = "Text"
+	Binary = "Binary"
+	SSHKey = sshagent.ItemType
+	OTP    = otp.ItemType
+)
+
+// meta is the free-form description every built-in type carries as its last field.
+var meta = Field{Name: "meta"}
+
+func init() {
+	Register(&Type{
+		Name:    Cred,
+		Command: "cred",
+		Noun:    "credentials pair",
+		Short:   "Store new credentials pair",
+		Long:    "Stores a new credentials pair. The password is asked twice without echo unless passed with a password flag.",
+		Fields: []Field{
+			{Name: "login"},
+			{Name: "password", Label: "Password", Input: Prompt, Secret: true, Generate: true, Track: true},
+			{Name: "url", Usage: "url of the service, used by the git and docker credential helpers", Input: Flag, OmitEmpty: true, Validate: validateURL},
+			{Name: "otp", Usage: "name of the OTP item holding the second factor of the credentials", Input: Flag, OmitEmpty: true, Link: OTP},
+			meta,
+		},
+	})
+	Register(&Type{
+		Name:    Card,
+		Command: "card",
+		Noun:    "card",
+		Short:   "Store new card",
+		Long: "Stores a new card. The number and CVV are asked twice without echo.\n" +
+			"The number must pass the Luhn check and have a length of its brand, the expiry date is MM/YY\n" +
+			"and the CVV has the length of the brand, 4 digits for American Express and 3 otherwise.",
+		Fields: []Field{
+			{Name: "number", Label: "Card number", Input: Prompt, Secret: true, Validate: card.ValidateNumber, Normalize: card.Normalize, Mask: card.Mask},
+			{Name: "expires", Validate: validateExpiry, Normalize: normalizeExpiry},
+			{Name: "cvv", Label: "CVV", Input: Prompt, Secret: true, Validate: validateCVV, Mask: maskCVV},
+			{Name: "name"},
+			{Name: "surname"},
+			meta,
+		},
+		Check:       checkCard,
+		CheckFields: []string{"number", "cvv"},
+		Created:     cardCreated,
+	})
+	Register(&Type{
+		Name:    Text,
+		Command: "text",
+		Noun:    "text",
+		Short:   "Store new text",
+		Fields:  []Field{{Name: "text"}, meta},
+	})
+	Register(&Type{
+		Name:    Binary,
+		Command: "binary",
+		Noun:    "binary blob",
+		Short:   "Store new binary",
+		Fields:  []Field{{Name: "blob", Kind: Bytes, Input: File}, meta},
+	})
+	Register(&Type{
+		Name:    SSHKey,
+		Command: "ssh",
+		Noun:    "ssh key",
+		Short:   "Store new SSH key",
+		Long: "Generates a new Ed25519 key, or imports an existing private key with --import, and prints its public key.\n" +
+			"The key is served by the ssh-agent command.",
+		Fields: []Field{
+			{Name: "public_key", Input: Generated},
+			{Name: "private_key", Input: Generated, Secret: true},
+			{Name: "comment"},
+			{Name: "confirm", Usage: "ask for confirmation every time the ssh agent uses the key", Kind: Bool, Input: Flag},
+			meta,
+		},
+		Flags:   []Field{{Name: "import", Usage: "file with the private key to import instead of generating one"}},
+		Prepare: prepareSSHKey,
+		Created: func(values Values) string { return values["public_key"] },
+	})
+	Register(&Type{
+		Name:    OTP,
+		Command: "otp",
+		Noun:    "otp",
+		Short:   "Store new OTP secret",
+		Long: "Stores a time-based one-time password secret imported from an otpauth://totp URI,\n" +
+			"as shown by the QR code of the service. The URI is asked without echo.",
+		Fields: []Field{
+			{Name: "issuer", Input: Generated},
+			{Name: "account", Input: Generated},
+			{Name: "secret", Input: Generated, Secret: true},
+			{Name: "algorithm", Input: Generated},
+			{Name: "digits", Kind: Int, Input: Generated},
+			{Name: "period", Kind: Int, Input: Generated},
+			meta,
+		},
+		Prepare: prepareOTP,
+	})
+}
+
+// validateURL checks that the value is an absolute URL.
+func validateURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil {
+		return err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return errors.New("must be an absolute url like https://example.com")
+	}
+	return nil
+}
+
+// validateExpiry checks that the value is a card expiry date.
+func validateExpiry(value string) error {
+	_, err := card.ParseExpiry(value)
+	return err
+}
+
+// normalizeExpiry stores card expiry dates as MM/YY, leaving values that are no expiry date to the validation.
+func normalizeExpiry(value string) string {
+	e, err := card.ParseExpiry(value)
+	if err != nil {
+		return value
+	}
+	return e.String()
+}
+
+// validateCVV checks the length of the CVV on its own, checkCard checks it against the brand of the number.
+func validateCVV(value string) error {
+	return card.ValidateCVV(value, "")
+}
+
+// maskCVV hides the CVV, including its length.
+func maskCVV(string) string {
+	return "***"
+}
+
+// checkCard checks that the CVV has the length of the brand of the card number.
+func checkCard(values Values) error {
+	if values["cvv"] == "" || values["number"] == "" {
+		return nil
+	}
+	if err := card.ValidateCVV(values["cvv"], values["number"]); err != nil {
+		return fmt.Errorf("invalid cvv: %w", err)
+	}
+	return nil
+}
+
+// cardCreated names the brand and last digits of the new card and warns when it expired or expires soon.
+func cardCreated(values Values) string {
+	message := "Successfully created new card"
+	if values["number"] != "" {
+		if b, ok := card.Detect(values["number"]); ok {
+			message = "Successfully created new " + b.Name + " card"
+		}
+		message += " ending in " + card.Last4(values["number"])
+	}
+	if e, err := card.ParseExpiry(values["expires"]); err == nil {
+		if warning := e.Warning(time.Now()); warning != "" {
+			message += ", note that " + warning
+		}
+	}
+	return message
+}
+
+// prepareSSHKey generates a key or imports the key file, asking for the passphrase of encrypted keys.
+func prepareSSHKey(values Values, p Prompter) error {
+	var key *models.SSHKey
+	var err error
+	if file := p.Flag("import"); file != "" {
+		key, err = importSSHKey(file, values["comment"], p)
+	} else {
+		key, err = sshagent.GenerateKey(values["comment"])
+	}
+	if err != nil {
+		return err
+	}
+	values["public_key"] = key.PublicKey
+	values["private_key"] = key.PrivateKey
+	return nil
+}
+
+// importSSHKey reads the private key from the file, asking for the passphrase if it is encrypted.
+func importSSHKey(file, comment string, p Prompter) (*models.SSHKey, error) {
+	pemBytes, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	key, err := sshagent.ImportKey(pemBytes, nil, comment)
+	if !errors.Is(err, sshagent.ErrPassphraseRequired) {
+		return key, err
+	}
+	passphrase, err := p.Secret("Passphrase")
+	if err != nil {
+		return nil, err
+	}
+	return sshagent.ImportKey(pemBytes, []byte(passphrase), comment)
+}
+
+// prepareOTP asks for the otpauth URI and sets the values parsed from it.
+func prepareOTP(values Values, p Prompter) error {
+	uri, err := p.Secret("otpauth URI")
+	if err != nil {
+		return err
+	}
+	o, err := otp.ParseURI(uri)
+	if err != nil {
+		return fmt.Errorf("failed to import otp: %w", err)
+	}
+	values["issuer"] = o.Issuer
+	values["account"] = o.Account
+	values["secret"] = o.Secret
+	values["algorithm"] = o.Algorithm
+	values["digits"] = strconv.Itoa(o.Digits)
+	values["period"] = strconv.Itoa(o.Period)
+	return nil
+}
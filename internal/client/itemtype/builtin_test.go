@@ -0,0 +1,99 @@
+package itemtype
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakePrompter answers prompts and flags from maps.
+type fakePrompter struct {
+	secrets map[string]string
+	flags   map[string]string
+}
+
+func (p fakePrompter) Secret(prompt string) (string, error) {
+	return p.secrets[prompt], nil
+}
+
+func (p fakePrompter) Flag(name string) string {
+	return p.flags[name]
+}
+
+func TestPrepareOTP(t *testing.T) {
+	otpType, _ := Lookup(OTP)
+	values := Values{"meta": "shared"}
+	p := fakePrompter{secrets: map[string]string{"otpauth URI": "otpauth://totp/GitHub:octocat?secret=JBSWY3DPEHPK3PXP"}}
+	require.NoError(t, otpType.Prepare(values, p))
+	assert.Equal(t, Values{
+		"issuer":    "GitHub",
+		"account":   "octocat",
+		"secret":    "JBSWY3DPEHPK3PXP",
+		"algorithm": "SHA1",
+		"digits":    "6",
+		"period":    "30",
+		"meta":      "shared",
+	}, values)
+
+	p.secrets["otpauth URI"] = "https://example.com"
+	assert.Error(t, otpType.Prepare(Values{}, p))
+}
+
+func TestPrepareSSHKey(t *testing.T) {
+	sshType, _ := Lookup(SSHKey)
+	values := Values{"comment": "laptop"}
+	require.NoError(t, sshType.Prepare(values, fakePrompter{}))
+	assert.True(t, strings.HasPrefix(values["public_key"], "ssh-ed25519 "))
+	assert.Contains(t, values["private_key"], "PRIVATE KEY")
+	assert.Equal(t, values["public_key"], sshType.CreatedMessage(values))
+
+	file := filepath.Join(t.TempDir(), "id")
+	require.NoError(t, os.WriteFile(file, []byte(values["private_key"]), 0600))
+	imported := Values{"comment": "laptop"}
+	require.NoError(t, sshType.Prepare(imported, fakePrompter{flags: map[string]string{"import": file}}))
+	assert.Equal(t, values["public_key"], imported["public_key"])
+}
+
+func TestCard(t *testing.T) {
+	cardType, _ := Lookup(Card)
+	content, err := cardType.Encode(Values{"number": "3782 8224 6310 005", "expires": "7/2030", "cvv": "1234", "name": "John"})
+	require.NoError(t, err)
+	values, err := cardType.Decode(content)
+	require.NoError(t, err)
+	assert.Equal(t, "378282246310005", values["number"], "number is stored without spaces")
+	assert.Equal(t, "07/30", values["expires"], "expiry is stored as MM/YY")
+	assert.Equal(t, "Successfully created new American Express card ending in 0005", cardType.CreatedMessage(values))
+
+	_, err = cardType.Encode(Values{"number": "4111111111111112"})
+	assert.ErrorContains(t, err, "Luhn")
+	_, err = cardType.Encode(Values{"expires": "2030-07-01"})
+	assert.ErrorContains(t, err, "MM/YY")
+	_, err = cardType.Encode(Values{"number": "4111111111111111", "cvv": "1234"})
+	assert.ErrorContains(t, err, "Visa cards have a 3 digit CVV")
+	_, err = cardType.Update(content, Values{"number": "4111111111111111"})
+	assert.ErrorContains(t, err, "Visa cards have a 3 digit CVV", "the stored CVV is checked against the new number")
+	broken := []byte(`{"number":"4111111111111111","cvv":"1234","meta":""}`)
+	_, err = cardType.Update(broken, Values{"meta": "old card"})
+	assert.NoError(t, err, "items breaking the check can be edited without touching number or cvv")
+	_, err = cardType.Update(broken, Values{"cvv": "4321"})
+	assert.ErrorContains(t, err, "Visa cards have a 3 digit CVV")
+
+	expired, err := cardType.Encode(Values{"number": "4111111111111111", "expires": "01/20"})
+	require.NoError(t, err, "expired cards can be stored")
+	values, err = cardType.Decode(expired)
+	require.NoError(t, err)
+	assert.Equal(t, "Successfully created new Visa card ending in 1111, note that the card expired in 01/20", cardType.CreatedMessage(values))
+
+	item, err := cardType.Item("amex", content)
+	require.NoError(t, err)
+	Mask(item)
+	number, _ := item.Field("number")
+	assert.Equal(t, "**** ****** *0005 (American Express)", number)
+	cvv, _ := item.Field("cvv")
+	assert.Equal(t, "***", cvv)
+	name, _ := item.Field("name")
+	assert.Equal(t, "John", name)
+}
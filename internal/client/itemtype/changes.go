@@ -0,0 +1,67 @@
+package itemtype
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// changedAtKey is the content key of the times the tracked fields last changed.
+const changedAtKey = "changed_at"
+
+// now returns the time recorded for changed fields, replaced in tests.
+var now = time.Now
+
+// ChangedAt returns when the tracked field of the content last changed.
+// It reports false for fields that are not tracked or were written by a client not tracking changes.
+func ChangedAt(content []byte, field string) (time.Time, bool) {
+	changes, err := changedAt(content)
+	if err != nil {
+		return time.Time{}, false
+	}
+	at, ok := changes[field]
+	return at, ok
+}
+
+// changedAt returns the times the tracked fields of the content last changed.
+func changedAt(content []byte) (map[string]time.Time, error) {
+	raw := map[string]json.RawMessage{}
+	if err := json.Unmarshal(content, &raw); err != nil {
+		return nil, err
+	}
+	changes := map[string]time.Time{}
+	if encoded, ok := raw[changedAtKey]; ok {
+		if err := json.Unmarshal(encoded, &changes); err != nil {
+			return nil, fmt.Errorf("change times: %w", err)
+		}
+	}
+	return changes, nil
+}
+
+// trackChanges records the current time for the tracked fields whose value differs between old and raw.
+func (t *Type) trackChanges(old, raw map[string]json.RawMessage) error {
+	changes := map[string]time.Time{}
+	if encoded, ok := raw[changedAtKey]; ok {
+		if err := json.Unmarshal(encoded, &changes); err != nil {
+			return fmt.Errorf("change times: %w", err)
+		}
+	}
+	changed := false
+	for _, f := range t.Fields {
+		if !f.Track || bytes.Equal(old[f.Name], raw[f.Name]) {
+			continue
+		}
+		changes[f.Name] = now().UTC().Truncate(time.Second)
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	encoded, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+	raw[changedAtKey] = encoded
+	return nil
+}
@@ -0,0 +1,42 @@
+package itemtype
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestType_TrackChanges(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	now = func() time.Time { return created }
+	t.Cleanup(func() { now = time.Now })
+	cred, _ := Lookup(Cred)
+
+	content, err := cred.Encode(Values{"login": "octocat", "password": "secret"})
+	require.NoError(t, err)
+	at, ok := ChangedAt(content, "password")
+	require.True(t, ok)
+	assert.Equal(t, created, at)
+	_, ok = ChangedAt(content, "login")
+	assert.False(t, ok, "login is not tracked")
+
+	now = func() time.Time { return created.Add(time.Hour) }
+	updated, err := cred.Update(content, Values{"login": "hubot"})
+	require.NoError(t, err)
+	at, _ = ChangedAt(updated, "password")
+	assert.Equal(t, created, at, "unchanged password keeps its time")
+
+	updated, err = cred.Update(updated, Values{"password": "secret"})
+	require.NoError(t, err)
+	at, _ = ChangedAt(updated, "password")
+	assert.Equal(t, created, at, "setting the same password is no change")
+
+	updated, err = cred.Update(updated, Values{"password": "new secret"})
+	require.NoError(t, err)
+	at, _ = ChangedAt(updated, "password")
+	assert.Equal(t, created.Add(time.Hour), at)
+
+	_, ok = ChangedAt([]byte(`{"password":"old"}`), "password")
+	assert.False(t, ok)
+}
@@ -0,0 +1,128 @@
+package itemtype
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/models"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// SchemaVersion is the version of the content layout written by this client.
+// Version 1 contents hold the fields of their type only, version 2 adds the custom fields.
+// Clients ignore keys they do not know and keep them on edits, so older clients keep working with newer contents.
+const SchemaVersion = 2
+
+// Content keys of the schema version and the custom fields.
+const (
+	schemaKey       = "schema"
+	customFieldsKey = "custom_fields"
+)
+
+// Kinds of custom fields.
+const (
+	FieldText   = "text"
+	FieldHidden = "hidden"
+	FieldURL    = "url"
+	FieldEmail  = "email"
+	FieldDate   = "date"
+)
+
+// DateLayout is the layout of date custom fields.
+const DateLayout = "2006-01-02"
+
+// ErrFieldExists is returned when adding a custom field with the name of an existing field.
+var ErrFieldExists = errors.New("field already exists")
+
+// FieldKinds are the kinds of custom fields.
+var FieldKinds = []string{FieldText, FieldHidden, FieldURL, FieldEmail, FieldDate}
+
+// ValidateCustomField checks the name, kind and value of a custom field.
+func ValidateCustomField(f models.CustomField) error {
+	if strings.TrimSpace(f.Name) == "" {
+		return errors.New("field name must not be empty")
+	}
+	if f.Value == "" {
+		return nil
+	}
+	var err error
+	switch f.Kind {
+	case FieldText, FieldHidden:
+	case FieldURL:
+		err = validateURL(f.Value)
+	case FieldEmail:
+		_, err = mail.ParseAddress(f.Value)
+	case FieldDate:
+		_, err = time.Parse(DateLayout, f.Value)
+		if err != nil {
+			err = errors.New("must be a date like 2024-12-31")
+		}
+	default:
+		return fmt.Errorf("unknown field kind %q, must be one of %s", f.Kind, strings.Join(FieldKinds, ", "))
+	}
+	if err != nil {
+		return fmt.Errorf("invalid %s %s: %w", f.Kind, f.Name, err)
+	}
+	return nil
+}
+
+// CustomFields returns the custom fields stored in the content in their order.
+func CustomFields(content []byte) ([]models.CustomField, error) {
+	raw := map[string]json.RawMessage{}
+	if err := json.Unmarshal(content, &raw); err != nil {
+		return nil, err
+	}
+	var fields []models.CustomField
+	if encoded, ok := raw[customFieldsKey]; ok {
+		if err := json.Unmarshal(encoded, &fields); err != nil {
+			return nil, fmt.Errorf("custom fields: %w", err)
+		}
+	}
+	return fields, nil
+}
+
+// SetCustomFields validates the custom fields and replaces those stored in the content of an item of the type.
+// Custom fields must not share a name with each other or with a field of the type, ignoring case.
+func (t *Type) SetCustomFields(content []byte, fields []models.CustomField) ([]byte, error) {
+	seen := map[string]bool{}
+	for _, f := range t.Fields {
+		seen[strings.ToLower(f.Name)] = true
+	}
+	for _, f := range fields {
+		if err := ValidateCustomField(f); err != nil {
+			return nil, err
+		}
+		if seen[strings.ToLower(f.Name)] {
+			return nil, fmt.Errorf("%w: %s", ErrFieldExists, f.Name)
+		}
+		seen[strings.ToLower(f.Name)] = true
+	}
+	raw := map[string]json.RawMessage{}
+	if err := json.Unmarshal(content, &raw); err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		delete(raw, customFieldsKey)
+	} else {
+		encoded, err := json.Marshal(fields)
+		if err != nil {
+			return nil, err
+		}
+		raw[customFieldsKey] = encoded
+	}
+	setSchema(raw)
+	return json.Marshal(raw)
+}
+
+// setSchema marks the content with the schema version of this client, unless a newer client wrote it.
+func setSchema(raw map[string]json.RawMessage) {
+	var version int
+	if encoded, ok := raw[schemaKey]; ok {
+		json.Unmarshal(encoded, &version)
+	}
+	if version < SchemaVersion {
+		raw[schemaKey] = json.RawMessage(fmt.Sprint(SchemaVersion))
+	}
+}
@@ -0,0 +1,87 @@
+package itemtype
+
+import (
+	"encoding/json"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestValidateCustomField(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   models.CustomField
+		wantErr bool
+	}{
+		{name: "Text", field: models.CustomField{Name: "Mother's maiden name", Value: "Smith", Kind: FieldText}},
+		{name: "Hidden", field: models.CustomField{Name: "PIN", Value: "1234", Kind: FieldHidden}},
+		{name: "URL", field: models.CustomField{Name: "Portal", Value: "https://bank.example.com", Kind: FieldURL}},
+		{name: "Email", field: models.CustomField{Name: "Recovery", Value: "alice@example.com", Kind: FieldEmail}},
+		{name: "Date", field: models.CustomField{Name: "Opened", Value: "2023-04-01", Kind: FieldDate}},
+		{name: "Empty value of any kind", field: models.CustomField{Name: "Opened", Kind: FieldDate}},
+		{name: "Empty name", field: models.CustomField{Name: " ", Value: "x", Kind: FieldText}, wantErr: true},
+		{name: "Unknown kind", field: models.CustomField{Name: "x", Value: "x", Kind: "phone"}, wantErr: true},
+		{name: "Relative URL", field: models.CustomField{Name: "Portal", Value: "bank.example.com", Kind: FieldURL}, wantErr: true},
+		{name: "Invalid email", field: models.CustomField{Name: "Recovery", Value: "alice", Kind: FieldEmail}, wantErr: true},
+		{name: "Invalid date", field: models.CustomField{Name: "Opened", Value: "01/04/2023", Kind: FieldDate}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCustomField(tt.field)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestType_SetCustomFields(t *testing.T) {
+	cred, _ := Lookup(Cred)
+	content := []byte(`{"login":"alice","password":"secret","meta":""}`)
+	fields := []models.CustomField{
+		{Name: "PIN", Value: "1234", Kind: FieldHidden},
+		{Name: "Recovery", Value: "alice@example.com", Kind: FieldEmail},
+	}
+	updated, err := cred.SetCustomFields(content, fields)
+	require.NoError(t, err)
+
+	got, err := CustomFields(updated)
+	require.NoError(t, err)
+	assert.Equal(t, fields, got)
+
+	// Clients before custom fields decode the content into the model of the type.
+	old := &models.Credentials{}
+	require.NoError(t, json.Unmarshal(updated, old))
+	assert.Equal(t, &models.Credentials{Login: "alice", Password: "secret"}, old)
+
+	item, err := cred.Item("bank", updated)
+	require.NoError(t, err)
+	assert.Equal(t, []models.Field{
+		{Name: "login", Value: "alice"},
+		{Name: "password", Value: "secret"},
+		{Name: "meta", Value: ""},
+		{Name: "PIN", Value: "1234", Kind: FieldHidden},
+		{Name: "Recovery", Value: "alice@example.com", Kind: FieldEmail},
+	}, item.Fields)
+
+	// Editing the fields of the type keeps the custom fields.
+	edited, err := cred.Update(updated, Values{"login": "bob"})
+	require.NoError(t, err)
+	got, err = CustomFields(edited)
+	require.NoError(t, err)
+	assert.Equal(t, fields, got)
+
+	removed, err := cred.SetCustomFields(updated, nil)
+	require.NoError(t, err)
+	got, err = CustomFields(removed)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	_, err = cred.SetCustomFields(content, []models.CustomField{{Name: "Password", Value: "x", Kind: FieldText}})
+	assert.ErrorIs(t, err, ErrFieldExists)
+	_, err = cred.SetCustomFields(content, []models.CustomField{{Name: "a", Kind: FieldText}, {Name: "A", Kind: FieldText}})
+	assert.ErrorIs(t, err, ErrFieldExists)
+}
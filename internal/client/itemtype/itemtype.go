@@ -0,0 +1,382 @@
+// Package itemtype is the registry of the item types of the client.
+// Every type declares the fields of its decrypted content together with how they are entered,
+// validated and shown, so commands and decoding work for every registered type without type specific code.
+// The server stores the type as an opaque string, so registering a type needs no server change.
+package itemtype
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/models"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Kind is the JSON representation of a field value in the item content.
+type Kind int
+
+const (
+	// String values are stored as JSON strings.
+	String Kind = iota
+	// Bool values are stored as JSON booleans.
+	Bool
+	// Int values are stored as JSON numbers.
+	Int
+	// Bytes values are stored as base64 JSON strings and shown base64 encoded.
+	Bytes
+)
+
+// Input is how a create command obtains the value of a field.
+type Input int
+
+const (
+	// Arg fields are positional arguments in the order of declaration.
+	Arg Input = iota
+	// Prompt fields are asked twice without echo.
+	Prompt
+	// File fields are positional arguments naming the file holding the value.
+	File
+	// Flag fields are optional flags named after the field.
+	Flag
+	// Generated fields are set by the Prepare hook of the type.
+	Generated
+)
+
+// Field declares a field of an item type.
+type Field struct {
+	// Name is the key of the field in the content and its name in the output.
+	Name string
+	// Label is the prompt of Prompt fields, the name is used when empty.
+	Label string
+	// Usage describes flags.
+	Usage string
+	Kind  Kind
+	Input Input
+	// Secret fields hold values that must not be shown or entered in clear text.
+	Secret bool
+	// OmitEmpty fields are left out of the output when empty.
+	OmitEmpty bool
+	// Link names the type of the item a non-empty value must name, for example the OTP of credentials.
+	Link string
+	// Generate marks Prompt fields whose value the password generator can create instead of asking for it.
+	Generate bool
+	// Track fields record when their value last changed, see ChangedAt.
+	Track bool
+	// Validate checks non-empty values entered by the user.
+	Validate func(value string) error
+	// Normalize brings non-empty values into their stored form before they are validated, like removing spaces.
+	Normalize func(value string) string
+	// Mask returns the form of a non-empty value shown unless the user asks to reveal it.
+	Mask func(value string) string
+}
+
+// Prompter gives the Prepare hook of a type access to the user input.
+type Prompter interface {
+	// Secret asks for a value without echo.
+	Secret(prompt string) (string, error)
+	// Flag returns the value of one of the flags of the type.
+	Flag(name string) string
+}
+
+// Values are the field values of an item in their text form, Bytes values base64 encoded.
+type Values map[string]string
+
+// Type declares an item type.
+type Type struct {
+	// Name is the type stored with the items.
+	Name string
+	// Command is the suffix of the create command, like cred for create_cred.
+	Command string
+	// Noun describes an item in messages, like credentials pair.
+	Noun  string
+	Short string
+	Long  string
+	// Fields are the fields of the content in display order.
+	Fields []Field
+	// Flags are additional flags read by the Prepare hook only.
+	Flags []Field
+	// Prepare completes the values entered for a new item, for example by generating a key.
+	Prepare func(values Values, p Prompter) error
+	// Created returns the message printed after creating an item, a generic message is printed when nil.
+	Created func(values Values) string
+	// Check validates the values of an item together, for rules spanning several fields.
+	// It runs on new items and on updates changing one of the CheckFields, so older items breaking a rule
+	// can still be edited otherwise.
+	Check       func(values Values) error
+	CheckFields []string
+}
+
+var (
+	mu       sync.RWMutex
+	registry = map[string]*Type{}
+)
+
+// Register adds the type to the registry. It panics if a type with the name is registered already,
+// as that is a programming error.
+func Register(t *Type) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := registry[t.Name]; ok {
+		panic(fmt.Sprintf("itemtype: type %s registered twice", t.Name))
+	}
+	registry[t.Name] = t
+}
+
+// Lookup returns the registered type with the name.
+func Lookup(name string) (*Type, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	t, ok := registry[name]
+	return t, ok
+}
+
+// All returns the registered types sorted by their command.
+func All() []*Type {
+	mu.RLock()
+	defer mu.RUnlock()
+	types := make([]*Type, 0, len(registry))
+	for _, t := range registry {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Command < types[j].Command })
+	return types
+}
+
+// Field returns the field with the name, ignoring case.
+func (t *Type) Field(name string) (*Field, bool) {
+	for i := range t.Fields {
+		if strings.EqualFold(t.Fields[i].Name, name) {
+			return &t.Fields[i], true
+		}
+	}
+	return nil, false
+}
+
+// CreatedMessage returns the message printed after creating an item with the values.
+func (t *Type) CreatedMessage(values Values) string {
+	if t.Created != nil {
+		return t.Created(values)
+	}
+	return "Successfully created new " + t.Noun
+}
+
+// Validate checks that the values fit the kinds of their fields and pass the field validation.
+func (t *Type) Validate(values Values) error {
+	for name, value := range values {
+		f, ok := t.Field(name)
+		if !ok {
+			return fmt.Errorf("%s has no field %s", t.Name, name)
+		}
+		if value == "" {
+			continue
+		}
+		if _, err := f.encode(value); err != nil {
+			return err
+		}
+		if f.Validate != nil {
+			if err := f.Validate(value); err != nil {
+				return fmt.Errorf("invalid %s: %w", f.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Encode validates the values and encodes them as the content of a new item.
+func (t *Type) Encode(values Values) ([]byte, error) {
+	return t.Update(nil, values)
+}
+
+// Update validates the values and sets them in the existing content.
+// Keys of the content unknown to the type are kept, so items written by newer clients survive edits.
+// Tracked fields whose value changes get the current time recorded.
+func (t *Type) Update(content []byte, values Values) ([]byte, error) {
+	values = t.normalize(values)
+	if err := t.Validate(values); err != nil {
+		return nil, err
+	}
+	raw := map[string]json.RawMessage{}
+	if len(content) > 0 {
+		if err := json.Unmarshal(content, &raw); err != nil {
+			return nil, err
+		}
+	}
+	old := make(map[string]json.RawMessage, len(raw))
+	for k, v := range raw {
+		old[k] = v
+	}
+	for _, f := range t.Fields {
+		value, ok := values[f.Name]
+		if _, exists := raw[f.Name]; !ok && exists {
+			continue
+		}
+		encoded, err := f.encode(value)
+		if err != nil {
+			return nil, err
+		}
+		raw[f.Name] = encoded
+	}
+	if err := t.trackChanges(old, raw); err != nil {
+		return nil, err
+	}
+	setSchema(raw)
+	updated, err := json.Marshal(raw)
+	if err != nil || !t.checks(content, values) {
+		return updated, err
+	}
+	merged, err := t.Decode(updated)
+	if err != nil {
+		return nil, err
+	}
+	if err = t.Check(merged); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// checks reports whether the Check of the type runs on a new item or on an update of the content setting the values.
+func (t *Type) checks(content []byte, values Values) bool {
+	if t.Check == nil {
+		return false
+	}
+	if len(content) == 0 {
+		return true
+	}
+	for _, name := range t.CheckFields {
+		if _, ok := values[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize returns a copy of the values with the non-empty values of fields with a Normalize function normalized.
+func (t *Type) normalize(values Values) Values {
+	normalized := make(Values, len(values))
+	for name, value := range values {
+		if f, ok := t.Field(name); ok && f.Normalize != nil && value != "" {
+			value = f.Normalize(value)
+		}
+		normalized[name] = value
+	}
+	return normalized
+}
+
+// Mask replaces the values of the fields of the item with their masked form, for fields of its type with a Mask function.
+// Items of unknown types are left unchanged.
+func Mask(item *models.Item) {
+	t, ok := Lookup(item.Type)
+	if !ok {
+		return
+	}
+	for i, field := range item.Fields {
+		if f, ok := t.Field(field.Name); ok && f.Mask != nil && field.Value != "" {
+			item.Fields[i].Value = f.Mask(field.Value)
+		}
+	}
+}
+
+// Decode decodes the content of an item into its values.
+// Fields missing in the content have the zero value of their kind.
+func (t *Type) Decode(content []byte) (Values, error) {
+	raw := map[string]json.RawMessage{}
+	if err := json.Unmarshal(content, &raw); err != nil {
+		return nil, err
+	}
+	values := Values{}
+	for _, f := range t.Fields {
+		value, err := f.decode(raw[f.Name])
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", f.Name, err)
+		}
+		values[f.Name] = value
+	}
+	return values, nil
+}
+
+// Item decodes the content of an item into its fields in display order, followed by its custom fields.
+func (t *Type) Item(name string, content []byte) (*models.Item, error) {
+	values, err := t.Decode(content)
+	if err != nil {
+		return nil, err
+	}
+	custom, err := CustomFields(content)
+	if err != nil {
+		return nil, err
+	}
+	item := &models.Item{Name: name, Type: t.Name, Fields: []models.Field{}}
+	for _, f := range t.Fields {
+		if f.OmitEmpty && values[f.Name] == "" {
+			continue
+		}
+		item.Fields = append(item.Fields, models.Field{Name: f.Name, Value: values[f.Name]})
+	}
+	for _, f := range custom {
+		item.Fields = append(item.Fields, models.Field{Name: f.Name, Value: f.Value, Kind: f.Kind})
+	}
+	return item, nil
+}
+
+// encode converts the text form of a value into its JSON representation.
+func (f *Field) encode(value string) (json.RawMessage, error) {
+	switch f.Kind {
+	case Bool:
+		if value == "" {
+			return json.RawMessage("false"), nil
+		}
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false", f.Name)
+		}
+		return json.Marshal(b)
+	case Int:
+		if value == "" {
+			return json.RawMessage("0"), nil
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a number", f.Name)
+		}
+		return json.Marshal(n)
+	case Bytes:
+		if _, err := base64.StdEncoding.DecodeString(value); err != nil {
+			return nil, fmt.Errorf("%s must be base64 encoded", f.Name)
+		}
+	}
+	return json.Marshal(value)
+}
+
+// decode converts the JSON representation of a value into its text form.
+func (f *Field) decode(raw json.RawMessage) (string, error) {
+	switch f.Kind {
+	case Bool:
+		var b bool
+		if len(raw) > 0 {
+			if err := json.Unmarshal(raw, &b); err != nil {
+				return "", err
+			}
+		}
+		return strconv.FormatBool(b), nil
+	case Int:
+		var n int
+		if len(raw) > 0 {
+			if err := json.Unmarshal(raw, &n); err != nil {
+				return "", err
+			}
+		}
+		return strconv.Itoa(n), nil
+	}
+	var s *string
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return "", err
+		}
+	}
+	if s == nil {
+		return "", nil
+	}
+	return *s, nil
+}
@@ -0,0 +1,122 @@
+package itemtype
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// testType is a type with a field of every kind.
+var testType = &Type{
+	Name: "Test",
+	Fields: []Field{
+		{Name: "text"},
+		{Name: "flag", Kind: Bool},
+		{Name: "count", Kind: Int},
+		{Name: "blob", Kind: Bytes},
+		{Name: "note", OmitEmpty: true, Validate: func(value string) error {
+			if value == "bad" {
+				return errors.New("bad note")
+			}
+			return nil
+		}},
+	},
+}
+
+func TestType_EncodeDecode(t *testing.T) {
+	content, err := testType.Encode(Values{"text": "hello", "flag": "true", "count": "3", "blob": "AQI="})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"text":"hello","flag":true,"count":3,"blob":"AQI=","note":"","schema":2}`, string(content))
+
+	values, err := testType.Decode(content)
+	require.NoError(t, err)
+	assert.Equal(t, Values{"text": "hello", "flag": "true", "count": "3", "blob": "AQI=", "note": ""}, values)
+
+	values, err = testType.Decode([]byte(`{"text":null}`))
+	require.NoError(t, err)
+	assert.Equal(t, Values{"text": "", "flag": "false", "count": "0", "blob": "", "note": ""}, values)
+}
+
+func TestType_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  Values
+		wantErr bool
+	}{
+		{name: "Valid", values: Values{"flag": "false", "count": "-1", "note": "fine"}},
+		{name: "Empty values are not validated", values: Values{"flag": "", "count": ""}},
+		{name: "Unknown field", values: Values{"other": "x"}, wantErr: true},
+		{name: "Not a bool", values: Values{"flag": "maybe"}, wantErr: true},
+		{name: "Not a number", values: Values{"count": "three"}, wantErr: true},
+		{name: "Not base64", values: Values{"blob": "!!"}, wantErr: true},
+		{name: "Field validation", values: Values{"note": "bad"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := testType.Validate(tt.values)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestType_Update(t *testing.T) {
+	content := []byte(`{"text":"hello","flag":true,"count":3,"blob":"","note":""}`)
+	updated, err := testType.Update(content, Values{"text": "bye", "note": "changed"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"text":"bye","flag":true,"count":3,"blob":"","note":"changed","schema":2}`, string(updated))
+
+	content = []byte(`{"text":"hello","flag":true,"count":3,"blob":"","note":"","schema":3,"added_by_newer_client":[1,2]}`)
+	updated, err = testType.Update(content, Values{"text": "bye"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"text":"bye","flag":true,"count":3,"blob":"","note":"","schema":3,"added_by_newer_client":[1,2]}`, string(updated))
+
+	_, err = testType.Update([]byte("{"), Values{"text": "bye"})
+	assert.Error(t, err)
+}
+
+func TestType_Item(t *testing.T) {
+	item, err := testType.Item("name", []byte(`{"text":"hello","flag":false,"count":1,"blob":"","note":""}`))
+	require.NoError(t, err)
+	assert.Equal(t, &models.Item{Name: "name", Type: "Test", Fields: []models.Field{
+		{Name: "text", Value: "hello"},
+		{Name: "flag", Value: "false"},
+		{Name: "count", Value: "1"},
+		{Name: "blob", Value: ""},
+	}}, item)
+}
+
+func TestRegister(t *testing.T) {
+	_, ok := Lookup("Test")
+	assert.False(t, ok)
+	for _, name := range []string{Cred, Card, Text, Binary, SSHKey, OTP} {
+		_, ok = Lookup(name)
+		assert.True(t, ok, name)
+	}
+	assert.Panics(t, func() { Register(&Type{Name: Cred}) })
+}
+
+func TestBuiltin_CompatibleWithModels(t *testing.T) {
+	cred, _ := Lookup(Cred)
+	content, err := cred.Encode(Values{"login": "octocat", "password": "secret", "url": "https://github.com", "meta": "work"})
+	require.NoError(t, err)
+	decoded := &models.Credentials{}
+	require.NoError(t, json.Unmarshal(content, decoded))
+	assert.Equal(t, &models.Credentials{Login: "octocat", Password: "secret", URL: "https://github.com", Meta: "work"}, decoded)
+
+	_, err = cred.Encode(Values{"url": "github.com"})
+	assert.Error(t, err, "url must be absolute")
+
+	sshKey, _ := Lookup(SSHKey)
+	content, err = sshKey.Encode(Values{"public_key": "ssh-ed25519 AAAA", "confirm": "true"})
+	require.NoError(t, err)
+	key := &models.SSHKey{}
+	require.NoError(t, json.Unmarshal(content, key))
+	assert.True(t, key.Confirm)
+}
@@ -0,0 +1,251 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	grpc "google.golang.org/grpc"
+
+	mock "github.com/stretchr/testify/mock"
+
+	proto "github.com/Mldlr/storety/internal/proto"
+)
+
+// DeviceClient is an autogenerated mock type for the DeviceClient type
+type DeviceClient struct {
+	mock.Mock
+}
+
+type DeviceClient_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *DeviceClient) EXPECT() *DeviceClient_Expecter {
+	return &DeviceClient_Expecter{mock: &_m.Mock}
+}
+
+// Enroll provides a mock function with given fields: ctx, in, opts
+func (_m *DeviceClient) Enroll(ctx context.Context, in *proto.EnrollDeviceRequest, opts ...grpc.CallOption) (*proto.EnrollDeviceResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *proto.EnrollDeviceResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *proto.EnrollDeviceRequest, ...grpc.CallOption) (*proto.EnrollDeviceResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *proto.EnrollDeviceRequest, ...grpc.CallOption) *proto.EnrollDeviceResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*proto.EnrollDeviceResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *proto.EnrollDeviceRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeviceClient_Enroll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Enroll'
+type DeviceClient_Enroll_Call struct {
+	*mock.Call
+}
+
+// Enroll is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *proto.EnrollDeviceRequest
+//   - opts ...grpc.CallOption
+func (_e *DeviceClient_Expecter) Enroll(ctx interface{}, in interface{}, opts ...interface{}) *DeviceClient_Enroll_Call {
+	return &DeviceClient_Enroll_Call{Call: _e.mock.On("Enroll",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *DeviceClient_Enroll_Call) Run(run func(ctx context.Context, in *proto.EnrollDeviceRequest, opts ...grpc.CallOption)) *DeviceClient_Enroll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*proto.EnrollDeviceRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *DeviceClient_Enroll_Call) Return(_a0 *proto.EnrollDeviceResponse, _a1 error) *DeviceClient_Enroll_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DeviceClient_Enroll_Call) RunAndReturn(run func(context.Context, *proto.EnrollDeviceRequest, ...grpc.CallOption) (*proto.EnrollDeviceResponse, error)) *DeviceClient_Enroll_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListDevices provides a mock function with given fields: ctx, in, opts
+func (_m *DeviceClient) ListDevices(ctx context.Context, in *proto.ListDevicesRequest, opts ...grpc.CallOption) (*proto.ListDevicesResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *proto.ListDevicesResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *proto.ListDevicesRequest, ...grpc.CallOption) (*proto.ListDevicesResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *proto.ListDevicesRequest, ...grpc.CallOption) *proto.ListDevicesResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*proto.ListDevicesResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *proto.ListDevicesRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeviceClient_ListDevices_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListDevices'
+type DeviceClient_ListDevices_Call struct {
+	*mock.Call
+}
+
+// ListDevices is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *proto.ListDevicesRequest
+//   - opts ...grpc.CallOption
+func (_e *DeviceClient_Expecter) ListDevices(ctx interface{}, in interface{}, opts ...interface{}) *DeviceClient_ListDevices_Call {
+	return &DeviceClient_ListDevices_Call{Call: _e.mock.On("ListDevices",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *DeviceClient_ListDevices_Call) Run(run func(ctx context.Context, in *proto.ListDevicesRequest, opts ...grpc.CallOption)) *DeviceClient_ListDevices_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*proto.ListDevicesRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *DeviceClient_ListDevices_Call) Return(_a0 *proto.ListDevicesResponse, _a1 error) *DeviceClient_ListDevices_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DeviceClient_ListDevices_Call) RunAndReturn(run func(context.Context, *proto.ListDevicesRequest, ...grpc.CallOption) (*proto.ListDevicesResponse, error)) *DeviceClient_ListDevices_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeDevice provides a mock function with given fields: ctx, in, opts
+func (_m *DeviceClient) RevokeDevice(ctx context.Context, in *proto.RevokeDeviceRequest, opts ...grpc.CallOption) (*proto.RevokeDeviceResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *proto.RevokeDeviceResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *proto.RevokeDeviceRequest, ...grpc.CallOption) (*proto.RevokeDeviceResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *proto.RevokeDeviceRequest, ...grpc.CallOption) *proto.RevokeDeviceResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*proto.RevokeDeviceResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *proto.RevokeDeviceRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeviceClient_RevokeDevice_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeDevice'
+type DeviceClient_RevokeDevice_Call struct {
+	*mock.Call
+}
+
+// RevokeDevice is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *proto.RevokeDeviceRequest
+//   - opts ...grpc.CallOption
+func (_e *DeviceClient_Expecter) RevokeDevice(ctx interface{}, in interface{}, opts ...interface{}) *DeviceClient_RevokeDevice_Call {
+	return &DeviceClient_RevokeDevice_Call{Call: _e.mock.On("RevokeDevice",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *DeviceClient_RevokeDevice_Call) Run(run func(ctx context.Context, in *proto.RevokeDeviceRequest, opts ...grpc.CallOption)) *DeviceClient_RevokeDevice_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*proto.RevokeDeviceRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *DeviceClient_RevokeDevice_Call) Return(_a0 *proto.RevokeDeviceResponse, _a1 error) *DeviceClient_RevokeDevice_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DeviceClient_RevokeDevice_Call) RunAndReturn(run func(context.Context, *proto.RevokeDeviceRequest, ...grpc.CallOption) (*proto.RevokeDeviceResponse, error)) *DeviceClient_RevokeDevice_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+type mockConstructorTestingTNewDeviceClient interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewDeviceClient creates a new instance of DeviceClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewDeviceClient(t mockConstructorTestingTNewDeviceClient) *DeviceClient {
+	mock := &DeviceClient{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -65,6 +65,49 @@ func (_c *Storage_Close_Call) RunAndReturn(run func() error) *Storage_Close_Call
 	return _c
 }
 
+// CreateBatchData provides a mock function with given fields: ctx, data
+func (_m *Storage) CreateBatchData(ctx context.Context, data []models.Data) error {
+	ret := _m.Called(ctx, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []models.Data) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Storage_CreateBatchData_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateBatchData'
+type Storage_CreateBatchData_Call struct {
+	*mock.Call
+}
+
+// CreateBatchData is a helper method to define mock.On call
+//   - ctx context.Context
+//   - data []models.Data
+func (_e *Storage_Expecter) CreateBatchData(ctx interface{}, data interface{}) *Storage_CreateBatchData_Call {
+	return &Storage_CreateBatchData_Call{Call: _e.mock.On("CreateBatchData", ctx, data)}
+}
+
+func (_c *Storage_CreateBatchData_Call) Run(run func(ctx context.Context, data []models.Data)) *Storage_CreateBatchData_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]models.Data))
+	})
+	return _c
+}
+
+func (_c *Storage_CreateBatchData_Call) Return(_a0 error) *Storage_CreateBatchData_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Storage_CreateBatchData_Call) RunAndReturn(run func(context.Context, []models.Data) error) *Storage_CreateBatchData_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateData provides a mock function with given fields: ctx, data
 func (_m *Storage) CreateData(ctx context.Context, data *models.Data) error {
 	ret := _m.Called(ctx, data)
@@ -151,6 +194,60 @@ func (_c *Storage_DeleteDataByName_Call) RunAndReturn(run func(context.Context,
 	return _c
 }
 
+// GetAllData provides a mock function with given fields: ctx
+func (_m *Storage) GetAllData(ctx context.Context) ([]models.Data, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []models.Data
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Data, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.Data); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Data)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Storage_GetAllData_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllData'
+type Storage_GetAllData_Call struct {
+	*mock.Call
+}
+
+// GetAllData is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *Storage_Expecter) GetAllData(ctx interface{}) *Storage_GetAllData_Call {
+	return &Storage_GetAllData_Call{Call: _e.mock.On("GetAllData", ctx)}
+}
+
+func (_c *Storage_GetAllData_Call) Run(run func(ctx context.Context)) *Storage_GetAllData_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Storage_GetAllData_Call) Return(_a0 []models.Data, _a1 error) *Storage_GetAllData_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Storage_GetAllData_Call) RunAndReturn(run func(context.Context) ([]models.Data, error)) *Storage_GetAllData_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetAllDataInfo provides a mock function with given fields: ctx
 func (_m *Storage) GetAllDataInfo(ctx context.Context) ([]models.DataInfo, error) {
 	ret := _m.Called(ctx)
@@ -516,6 +613,50 @@ func (_c *Storage_SyncBatch_Call) RunAndReturn(run func(context.Context, []model
 	return _c
 }
 
+// UpdateDataByName provides a mock function with given fields: ctx, name, content
+func (_m *Storage) UpdateDataByName(ctx context.Context, name string, content []byte) error {
+	ret := _m.Called(ctx, name, content)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []byte) error); ok {
+		r0 = rf(ctx, name, content)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Storage_UpdateDataByName_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateDataByName'
+type Storage_UpdateDataByName_Call struct {
+	*mock.Call
+}
+
+// UpdateDataByName is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - content []byte
+func (_e *Storage_Expecter) UpdateDataByName(ctx interface{}, name interface{}, content interface{}) *Storage_UpdateDataByName_Call {
+	return &Storage_UpdateDataByName_Call{Call: _e.mock.On("UpdateDataByName", ctx, name, content)}
+}
+
+func (_c *Storage_UpdateDataByName_Call) Run(run func(ctx context.Context, name string, content []byte)) *Storage_UpdateDataByName_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].([]byte))
+	})
+	return _c
+}
+
+func (_c *Storage_UpdateDataByName_Call) Return(_a0 error) *Storage_UpdateDataByName_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Storage_UpdateDataByName_Call) RunAndReturn(run func(context.Context, string, []byte) error) *Storage_UpdateDataByName_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 type mockConstructorTestingTNewStorage interface {
 	mock.TestingT
 	Cleanup(func())
@@ -95,6 +95,76 @@ func (_c *UserClient_CreateUser_Call) RunAndReturn(run func(context.Context, *pr
 	return _c
 }
 
+// DeleteAccount provides a mock function with given fields: ctx, in, opts
+func (_m *UserClient) DeleteAccount(ctx context.Context, in *proto.DeleteAccountRequest, opts ...grpc.CallOption) (*proto.DeleteAccountResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *proto.DeleteAccountResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *proto.DeleteAccountRequest, ...grpc.CallOption) (*proto.DeleteAccountResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *proto.DeleteAccountRequest, ...grpc.CallOption) *proto.DeleteAccountResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*proto.DeleteAccountResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *proto.DeleteAccountRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserClient_DeleteAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteAccount'
+type UserClient_DeleteAccount_Call struct {
+	*mock.Call
+}
+
+// DeleteAccount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *proto.DeleteAccountRequest
+//   - opts ...grpc.CallOption
+func (_e *UserClient_Expecter) DeleteAccount(ctx interface{}, in interface{}, opts ...interface{}) *UserClient_DeleteAccount_Call {
+	return &UserClient_DeleteAccount_Call{Call: _e.mock.On("DeleteAccount",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *UserClient_DeleteAccount_Call) Run(run func(ctx context.Context, in *proto.DeleteAccountRequest, opts ...grpc.CallOption)) *UserClient_DeleteAccount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*proto.DeleteAccountRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *UserClient_DeleteAccount_Call) Return(_a0 *proto.DeleteAccountResponse, _a1 error) *UserClient_DeleteAccount_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserClient_DeleteAccount_Call) RunAndReturn(run func(context.Context, *proto.DeleteAccountRequest, ...grpc.CallOption) (*proto.DeleteAccountResponse, error)) *UserClient_DeleteAccount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // LogInUser provides a mock function with given fields: ctx, in, opts
 func (_m *UserClient) LogInUser(ctx context.Context, in *proto.LoginUserRequest, opts ...grpc.CallOption) (*proto.LoginUserResponse, error) {
 	_va := make([]interface{}, len(opts))
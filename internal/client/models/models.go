@@ -3,6 +3,7 @@ package models
 
 import (
 	"github.com/google/uuid"
+	"strings"
 	"time"
 )
 
@@ -10,7 +11,10 @@ import (
 type Credentials struct {
 	Login    string `json:"login"`
 	Password string `json:"password"`
-	Meta     string `json:"meta"`
+	URL      string `json:"url,omitempty"`
+	// OTP is the name of the OTP item holding the second factor of the credentials.
+	OTP  string `json:"otp,omitempty"`
+	Meta string `json:"meta"`
 }
 
 // Card is a struct that represents a card.
@@ -35,6 +39,27 @@ type Binary struct {
 	Meta string `json:"meta"`
 }
 
+// SSHKey is a struct that represents an SSH key pair served by the ssh agent.
+// The private key is PEM encoded, the public key is in the authorized_keys format.
+type SSHKey struct {
+	PrivateKey string `json:"private_key"`
+	PublicKey  string `json:"public_key"`
+	Comment    string `json:"comment"`
+	Confirm    bool   `json:"confirm"`
+	Meta       string `json:"meta"`
+}
+
+// OTP is a struct that represents the secret of a time-based one-time password.
+type OTP struct {
+	Secret    string `json:"secret"`
+	Issuer    string `json:"issuer"`
+	Account   string `json:"account"`
+	Algorithm string `json:"algorithm"`
+	Digits    int    `json:"digits"`
+	Period    int    `json:"period"`
+	Meta      string `json:"meta"`
+}
+
 // AuthData is a struct that represents a hashed key, salt and tokens locally stored for user.
 type AuthData struct {
 	HashedKey    string `json:"hashed_key"`
@@ -61,8 +86,68 @@ type SyncData struct {
 	Hash      string
 }
 
+// VaultExport is an export of the whole vault.
+// Item contents stay encrypted with the key derived from the account password and the stored salt.
+type VaultExport struct {
+	Username   string       `json:"username"`
+	Salt       string       `json:"salt"`
+	ExportedAt time.Time    `json:"exported_at"`
+	Items      []ExportItem `json:"items"`
+}
+
+// ExportItem is a single encrypted data item of a vault export.
+type ExportItem struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Content   []byte    `json:"content"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
 // DataInfo is the data info model.
 type DataInfo struct {
-	Name string
-	Type string
+	Name string `json:"name" yaml:"name"`
+	Type string `json:"type" yaml:"type"`
+}
+
+// Field is a named value of a decrypted data item.
+// Kind is set for custom fields only.
+type Field struct {
+	Name  string `json:"name" yaml:"name"`
+	Value string `json:"value" yaml:"value"`
+	Kind  string `json:"kind,omitempty" yaml:"kind,omitempty"`
+}
+
+// CustomField is a user-defined field stored with an item.
+type CustomField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Kind  string `json:"kind"`
+}
+
+// Item is a decrypted data item with its fields in display order.
+type Item struct {
+	Name   string  `json:"name" yaml:"name"`
+	Type   string  `json:"type" yaml:"type"`
+	Fields []Field `json:"fields" yaml:"fields"`
+}
+
+// Field returns the value of the field with the name, ignoring case.
+func (it *Item) Field(name string) (string, bool) {
+	for _, f := range it.Fields {
+		if strings.EqualFold(f.Name, name) {
+			return f.Value, true
+		}
+	}
+	return "", false
+}
+
+// Device is an enrolled device of the user.
+type Device struct {
+	ID        string    `json:"id" yaml:"id"`
+	Name      string    `json:"name" yaml:"name"`
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+	ExpiresAt time.Time `json:"expires_at" yaml:"expires_at"`
+	Revoked   bool      `json:"revoked" yaml:"revoked"`
+	Current   bool      `json:"current" yaml:"current"`
 }
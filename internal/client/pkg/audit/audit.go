@@ -0,0 +1,258 @@
+// Package audit checks the decrypted items of a vault for weak, reused and old passwords and for expiring cards.
+// It works on the exported items only, so no secret leaves the client.
+package audit
+
+import (
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/itemtype"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/Mldlr/storety/internal/client/pkg/card"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Defaults of the audit options.
+const (
+	DefaultMinScore      = 3
+	DefaultMaxAge        = 365 * 24 * time.Hour
+	DefaultExpiryWarning = card.ExpiryWarning
+)
+
+// Statuses of checked cards.
+const (
+	CardExpired  = "expired"
+	CardExpiring = "expiring"
+	CardInvalid  = "invalid"
+)
+
+// day is the unit of the ages and remaining times in the report.
+const day = 24 * time.Hour
+
+// Options are the thresholds of the audit.
+type Options struct {
+	// Now is the time ages and expiry dates are compared with.
+	Now time.Time
+	// MinScore is the lowest password strength score from 0 to 4 not reported as weak.
+	MinScore int
+	// MaxAge is the age from which passwords are reported as old, zero disables the check.
+	MaxAge time.Duration
+	// ExpiryWarning is how long before their expiry cards are reported.
+	ExpiryWarning time.Duration
+	// BreachDB is the breached password database passwords are checked against, nil skips the check.
+	BreachDB *BreachDB
+}
+
+// DefaultOptions returns the default options at the current time.
+func DefaultOptions() Options {
+	return Options{Now: time.Now(), MinScore: DefaultMinScore, MaxAge: DefaultMaxAge, ExpiryWarning: DefaultExpiryWarning}
+}
+
+// Weak is a credentials item with a password weaker than the minimum score.
+type Weak struct {
+	Item string `json:"item" yaml:"item"`
+	Strength
+}
+
+// Reuse is a password shared by several credentials items.
+type Reuse struct {
+	Items []string `json:"items" yaml:"items"`
+}
+
+// Breach is a credentials item whose password was seen Count times in breaches.
+type Breach struct {
+	Item  string `json:"item" yaml:"item"`
+	Count int    `json:"count" yaml:"count"`
+}
+
+// Old is a credentials item whose password was not changed for longer than the maximum age.
+// Estimated is set when the item did not record the password change and its last update is used instead.
+type Old struct {
+	Item      string    `json:"item" yaml:"item"`
+	ChangedAt time.Time `json:"changed_at" yaml:"changed_at"`
+	Days      int       `json:"days" yaml:"days"`
+	Estimated bool      `json:"estimated" yaml:"estimated"`
+}
+
+// Card is a card that expired, expires soon or has an expiry date that cannot be read.
+// Days is the number of days until the card expires, negative for expired cards.
+type Card struct {
+	Item    string `json:"item" yaml:"item"`
+	Expires string `json:"expires" yaml:"expires"`
+	Status  string `json:"status" yaml:"status"`
+	Days    int    `json:"days" yaml:"days"`
+}
+
+// Report is the result of an audit.
+type Report struct {
+	Credentials int      `json:"credentials" yaml:"credentials"`
+	Cards       int      `json:"cards" yaml:"cards"`
+	Weak        []Weak   `json:"weak" yaml:"weak"`
+	Reused      []Reuse  `json:"reused" yaml:"reused"`
+	Breached    []Breach `json:"breached" yaml:"breached"`
+	Old         []Old    `json:"old" yaml:"old"`
+	Expiring    []Card   `json:"expiring_cards" yaml:"expiring_cards"`
+}
+
+// Findings returns the number of issues in the report.
+func (r *Report) Findings() int {
+	return len(r.Weak) + len(r.Reused) + len(r.Breached) + len(r.Old) + len(r.Expiring)
+}
+
+// Text returns the report as printed by the text output.
+func (r *Report) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Checked %d credentials and %d cards\n", r.Credentials, r.Cards)
+	for _, row := range r.rows() {
+		fmt.Fprintf(&b, "%s: %s - %s\n", row[1], row[0], row[2])
+	}
+	if findings := r.Findings(); findings == 0 {
+		b.WriteString("No issues found")
+	} else {
+		fmt.Fprintf(&b, "Found %d issues", findings)
+	}
+	return b.String()
+}
+
+// Table returns the header and rows of the report as printed by the table output.
+func (r *Report) Table() ([]string, [][]string) {
+	return []string{"ITEM", "ISSUE", "DETAILS"}, r.rows()
+}
+
+// rows returns an item, issue and details row for every finding of the report.
+func (r *Report) rows() [][]string {
+	var rows [][]string
+	for _, w := range r.Weak {
+		rows = append(rows, []string{w.Item, "weak", fmt.Sprintf("score %d, %.0f bits (%s)", w.Score, w.Bits, strings.Join(w.Patterns, ", "))})
+	}
+	for _, reuse := range r.Reused {
+		rows = append(rows, []string{strings.Join(reuse.Items, ", "), "reused", fmt.Sprintf("same password in %d items", len(reuse.Items))})
+	}
+	for _, breach := range r.Breached {
+		rows = append(rows, []string{breach.Item, "breached", fmt.Sprintf("seen %d times in breaches", breach.Count)})
+	}
+	for _, o := range r.Old {
+		details := fmt.Sprintf("changed %s, %d days ago", o.ChangedAt.Format("2006-01-02"), o.Days)
+		if o.Estimated {
+			details = fmt.Sprintf("updated %s, %d days ago", o.ChangedAt.Format("2006-01-02"), o.Days)
+		}
+		rows = append(rows, []string{o.Item, "old", details})
+	}
+	for _, c := range r.Expiring {
+		details := "expires " + c.Expires
+		switch c.Status {
+		case CardExpired:
+			details = fmt.Sprintf("expired %s, %d days ago", c.Expires, -c.Days)
+		case CardExpiring:
+			details = fmt.Sprintf("expires %s, in %d days", c.Expires, c.Days)
+		case CardInvalid:
+			details = fmt.Sprintf("expiry date %q is not MM/YY", c.Expires)
+		}
+		rows = append(rows, []string{c.Item, c.Status, details})
+	}
+	return rows
+}
+
+// Run audits the decrypted items. Items of other types than credentials and cards are ignored.
+func Run(data []models.Data, opts Options) (*Report, error) {
+	cred, _ := itemtype.Lookup(itemtype.Cred)
+	cardType, _ := itemtype.Lookup(itemtype.Card)
+	report := &Report{Weak: []Weak{}, Reused: []Reuse{}, Breached: []Breach{}, Old: []Old{}, Expiring: []Card{}}
+	data = append([]models.Data(nil), data...)
+	sort.Slice(data, func(i, j int) bool { return data[i].Name < data[j].Name })
+	byPassword := map[string][]string{}
+	for _, d := range data {
+		switch d.Type {
+		case itemtype.Cred:
+			values, err := cred.Decode(d.Content)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode %s: %w", d.Name, err)
+			}
+			report.Credentials++
+			password := values["password"]
+			if s := Estimate(password); s.Score < opts.MinScore {
+				report.Weak = append(report.Weak, Weak{Item: d.Name, Strength: s})
+			}
+			if password != "" {
+				byPassword[password] = append(byPassword[password], d.Name)
+			}
+			if old, ok := checkAge(d, opts); ok {
+				report.Old = append(report.Old, old)
+			}
+		case itemtype.Card:
+			values, err := cardType.Decode(d.Content)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode %s: %w", d.Name, err)
+			}
+			report.Cards++
+			if c, ok := checkExpiry(d.Name, values["expires"], opts); ok {
+				report.Expiring = append(report.Expiring, c)
+			}
+		}
+	}
+	for password, items := range byPassword {
+		if len(items) > 1 {
+			report.Reused = append(report.Reused, Reuse{Items: items})
+		}
+		if opts.BreachDB == nil {
+			continue
+		}
+		count, err := opts.BreachDB.Count(password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check %s against the breach database: %w", items[0], err)
+		}
+		if count > 0 {
+			for _, item := range items {
+				report.Breached = append(report.Breached, Breach{Item: item, Count: count})
+			}
+		}
+	}
+	sort.Slice(report.Reused, func(i, j int) bool { return report.Reused[i].Items[0] < report.Reused[j].Items[0] })
+	sort.Slice(report.Breached, func(i, j int) bool { return report.Breached[i].Item < report.Breached[j].Item })
+	return report, nil
+}
+
+// checkAge reports the credentials item if its password is older than the maximum age.
+func checkAge(d models.Data, opts Options) (Old, bool) {
+	if opts.MaxAge <= 0 {
+		return Old{}, false
+	}
+	changedAt, ok := itemtype.ChangedAt(d.Content, "password")
+	if !ok {
+		changedAt = d.UpdatedAt
+	}
+	if changedAt.IsZero() {
+		return Old{}, false
+	}
+	age := opts.Now.Sub(changedAt)
+	if age <= opts.MaxAge {
+		return Old{}, false
+	}
+	return Old{Item: d.Name, ChangedAt: changedAt, Days: int(age / day), Estimated: !ok}, true
+}
+
+// checkExpiry reports the card if it expired, expires within the warning period or its expiry date cannot be read.
+// Cards without an expiry date are not reported.
+func checkExpiry(name, expires string, opts Options) (Card, bool) {
+	if strings.TrimSpace(expires) == "" {
+		return Card{}, false
+	}
+	expiry, err := card.ParseExpiry(expires)
+	if err != nil {
+		return Card{Item: name, Expires: expires, Status: CardInvalid}, true
+	}
+	left := expiry.End().Sub(opts.Now)
+	c := Card{Item: name, Expires: expires, Days: int(left / day)}
+	switch {
+	case left <= 0:
+		c.Status = CardExpired
+		if left%day != 0 {
+			c.Days--
+		}
+	case left <= opts.ExpiryWarning:
+		c.Status = CardExpiring
+	default:
+		return Card{}, false
+	}
+	return c, true
+}
@@ -0,0 +1,111 @@
+package audit
+
+import (
+	"encoding/json"
+	"github.com/Mldlr/storety/internal/client/itemtype"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// encode encodes the values as the content of an item of the type.
+func encode(t *testing.T, typ string, values itemtype.Values) []byte {
+	it, _ := itemtype.Lookup(typ)
+	content, err := it.Encode(values)
+	require.NoError(t, err)
+	return content
+}
+
+func TestRun(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	strong := "x7#Qm9$vL2!pR4&w"
+	data := []models.Data{
+		{Name: "gitlab", Type: itemtype.Cred, Content: encode(t, itemtype.Cred, itemtype.Values{"password": strong}), UpdatedAt: now},
+		{Name: "github", Type: itemtype.Cred, Content: encode(t, itemtype.Cred, itemtype.Values{"password": strong}), UpdatedAt: now},
+		{Name: "mail", Type: itemtype.Cred, Content: encode(t, itemtype.Cred, itemtype.Values{"password": "password"}), UpdatedAt: now},
+		{Name: "legacy", Type: itemtype.Cred, Content: []byte(`{"password":"k2#Vq8!mZ4$tL9@x"}`), UpdatedAt: now.AddDate(-2, 0, 0)},
+		{Name: "visa", Type: itemtype.Card, Content: encode(t, itemtype.Card, itemtype.Values{"expires": "09/26"})},
+		{Name: "amex", Type: itemtype.Card, Content: encode(t, itemtype.Card, itemtype.Values{"expires": "11/2026"})},
+		{Name: "mastercard", Type: itemtype.Card, Content: encode(t, itemtype.Card, itemtype.Values{"expires": "12/30"})},
+		{Name: "broken", Type: itemtype.Card, Content: []byte(`{"expires":"soon"}`)},
+		{Name: "note", Type: itemtype.Text, Content: encode(t, itemtype.Text, itemtype.Values{"text": "password"})},
+	}
+	opts := Options{Now: now, MinScore: DefaultMinScore, MaxAge: DefaultMaxAge, ExpiryWarning: DefaultExpiryWarning}
+	report, err := Run(data, opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, report.Credentials)
+	assert.Equal(t, 4, report.Cards)
+	require.Len(t, report.Weak, 1)
+	assert.Equal(t, "mail", report.Weak[0].Item)
+	assert.Equal(t, []Reuse{{Items: []string{"github", "gitlab"}}}, report.Reused)
+	assert.Equal(t, []Old{{Item: "legacy", ChangedAt: now.AddDate(-2, 0, 0), Days: 730, Estimated: true}}, report.Old)
+	assert.Equal(t, []Card{
+		{Item: "amex", Expires: "11/26", Status: CardExpiring, Days: 42},
+		{Item: "broken", Expires: "soon", Status: CardInvalid},
+		{Item: "visa", Expires: "09/26", Status: CardExpired, Days: -19},
+	}, report.Expiring)
+	assert.Equal(t, 6, report.Findings())
+
+	opts.MaxAge = 0
+	report, err = Run(data, opts)
+	require.NoError(t, err)
+	assert.Empty(t, report.Old, "zero max age disables the check")
+
+	_, err = Run([]models.Data{{Name: "bad", Type: itemtype.Cred, Content: []byte("{")}}, opts)
+	assert.Error(t, err)
+}
+
+func TestRun_TrackedPasswordChange(t *testing.T) {
+	now := time.Now().UTC()
+	content := encode(t, itemtype.Cred, itemtype.Values{"password": "x7#Qm9$vL2!pR4&w"})
+	report, err := Run([]models.Data{{Name: "github", Type: itemtype.Cred, Content: content, UpdatedAt: now.AddDate(-3, 0, 0)}},
+		Options{Now: now, MaxAge: DefaultMaxAge})
+	require.NoError(t, err)
+	assert.Empty(t, report.Old, "the recorded change time wins over the update time")
+
+	report, err = Run([]models.Data{{Name: "github", Type: itemtype.Cred, Content: content}},
+		Options{Now: now.AddDate(2, 0, 0), MaxAge: DefaultMaxAge})
+	require.NoError(t, err)
+	require.Len(t, report.Old, 1)
+	assert.False(t, report.Old[0].Estimated)
+}
+
+func TestReport_Text(t *testing.T) {
+	report := &Report{Credentials: 3, Cards: 1,
+		Weak:     []Weak{{Item: "mail", Strength: Strength{Bits: 1, Patterns: []string{"dictionary"}}}},
+		Reused:   []Reuse{{Items: []string{"github", "gitlab"}}},
+		Breached: []Breach{{Item: "mail", Count: 42}},
+		Old:      []Old{{Item: "legacy", ChangedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Days: 400, Estimated: true}},
+		Expiring: []Card{{Item: "visa", Expires: "11/26", Status: CardExpiring, Days: 42}},
+	}
+	assert.Equal(t, "Checked 3 credentials and 1 cards\n"+
+		"weak: mail - score 0, 1 bits (dictionary)\n"+
+		"reused: github, gitlab - same password in 2 items\n"+
+		"breached: mail - seen 42 times in breaches\n"+
+		"old: legacy - updated 2024-01-02, 400 days ago\n"+
+		"expiring: visa - expires 11/26, in 42 days\n"+
+		"Found 5 issues", report.Text())
+
+	header, rows := report.Table()
+	assert.Equal(t, []string{"ITEM", "ISSUE", "DETAILS"}, header)
+	assert.Len(t, rows, 5)
+}
+
+func TestReport_JSON(t *testing.T) {
+	report := &Report{Credentials: 1, Weak: []Weak{}, Reused: []Reuse{}, Breached: []Breach{}, Old: []Old{}, Expiring: []Card{}}
+	content, err := json.MarshalIndent(report, "", "  ")
+	require.NoError(t, err)
+	assert.Equal(t, `{
+  "credentials": 1,
+  "cards": 0,
+  "weak": [],
+  "reused": [],
+  "breached": [],
+  "old": [],
+  "expiring_cards": []
+}`, string(content))
+	assert.Equal(t, "Checked 1 credentials and 0 cards\nNo issues found", report.Text())
+}
@@ -0,0 +1,79 @@
+package audit
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// prefixLength is the number of hex characters of the SHA-1 hash naming a range file.
+const prefixLength = 5
+
+// ErrRangeMissing is returned when the breach database has no range file for the prefix of a hash.
+// A complete copy has a file for every prefix, so a missing one means the copy is incomplete.
+var ErrRangeMissing = errors.New("range file missing from breach database")
+
+// BreachDB is a local copy of the breached password range files in the k-anonymity layout:
+// a file per first five hex characters of the SHA-1 hash, optionally with a .txt extension,
+// holding a SUFFIX:COUNT line for every breached hash with that prefix.
+// Only the files are read, so no password or hash leaves the machine.
+type BreachDB struct {
+	dir string
+}
+
+// OpenBreachDB opens the breach database in the directory.
+func OpenBreachDB(dir string) (*BreachDB, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("breach database %s is not a directory", dir)
+	}
+	return &BreachDB{dir: dir}, nil
+}
+
+// Count returns how often the password was seen in breaches, zero if it was not.
+func (db *BreachDB) Count(password string) (int, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:prefixLength], hash[prefixLength:]
+	f, err := db.openRange(prefix)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		hashSuffix, count, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if !ok || !strings.EqualFold(hashSuffix, suffix) {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(count))
+		if err != nil {
+			return 0, fmt.Errorf("range file %s: invalid count %q", prefix, count)
+		}
+		return n, nil
+	}
+	return 0, scanner.Err()
+}
+
+// openRange opens the range file of the prefix, accepting upper and lower case names with or without .txt.
+func (db *BreachDB) openRange(prefix string) (*os.File, error) {
+	for _, name := range []string{prefix, prefix + ".txt", strings.ToLower(prefix), strings.ToLower(prefix) + ".txt"} {
+		f, err := os.Open(filepath.Join(db.dir, name))
+		if err == nil {
+			return f, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrRangeMissing, prefix)
+}
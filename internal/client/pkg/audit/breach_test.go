@@ -0,0 +1,68 @@
+package audit
+
+import (
+	"errors"
+	"github.com/Mldlr/storety/internal/client/itemtype"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// breachDB writes range files to a temporary directory and opens it.
+// The hash of password is listed in 5BAA6, the hash of x7#Qm9$vL2!pR4&w has the prefix 844E7.
+func breachDB(t *testing.T, files map[string]string) *BreachDB {
+	dir := t.TempDir()
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+	db, err := OpenBreachDB(dir)
+	require.NoError(t, err)
+	return db
+}
+
+func TestBreachDB_Count(t *testing.T) {
+	db := breachDB(t, map[string]string{
+		"5BAA6":     "003D68EB55068C33ACE09247EE4C639306B:3\r\n1E4C9B93F3F0682250B6CF8331B7EE68FD8:9545824\r\n",
+		"844e7.txt": "2B0C3861F2A72F8692F1A305C014AE26EF8:2\n2B0C3861F2A72F8692F1A305C014AE26EF7:0\n",
+	})
+	count, err := db.Count("password")
+	require.NoError(t, err)
+	assert.Equal(t, 9545824, count)
+
+	count, err = db.Count("x7#Qm9$vL2!pR4&w")
+	require.NoError(t, err)
+	assert.Zero(t, count, "padding lines with a zero count are no breach")
+
+	_, err = db.Count("hunter2")
+	assert.True(t, errors.Is(err, ErrRangeMissing))
+
+	_, err = OpenBreachDB(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
+
+func TestRun_Breached(t *testing.T) {
+	db := breachDB(t, map[string]string{
+		"5BAA6": "1E4C9B93F3F0682250B6CF8331B7EE68FD8:42\n",
+		"844E7": "",
+	})
+	data := []models.Data{
+		{Name: "mail", Type: itemtype.Cred, Content: encode(t, itemtype.Cred, itemtype.Values{"password": "password"})},
+		{Name: "forum", Type: itemtype.Cred, Content: encode(t, itemtype.Cred, itemtype.Values{"password": "password"})},
+		{Name: "github", Type: itemtype.Cred, Content: encode(t, itemtype.Cred, itemtype.Values{"password": "x7#Qm9$vL2!pR4&w"})},
+	}
+	report, err := Run(data, Options{Now: time.Now(), BreachDB: db})
+	require.NoError(t, err)
+	assert.Equal(t, []Breach{{Item: "forum", Count: 42}, {Item: "mail", Count: 42}}, report.Breached)
+
+	report, err = Run(data, Options{Now: time.Now()})
+	require.NoError(t, err)
+	assert.Empty(t, report.Breached, "no breach database skips the check")
+
+	data = append(data, models.Data{Name: "shop", Type: itemtype.Cred, Content: encode(t, itemtype.Cred, itemtype.Values{"password": "hunter2"})})
+	_, err = Run(data, Options{Now: time.Now(), BreachDB: db})
+	assert.True(t, errors.Is(err, ErrRangeMissing))
+}
@@ -0,0 +1,421 @@
+package audit
+
+import (
+	_ "embed"
+	"github.com/Mldlr/storety/internal/client/pkg/passgen"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+//go:embed common.txt
+var commonFile string
+
+// dictionary maps lowercase words to their rank, common passwords first and then the passphrase words.
+var dictionary = buildDictionary()
+
+// longestWord is the length of the longest dictionary word, longer parts of a password are not looked up.
+var longestWord = func() int {
+	n := 0
+	for w := range dictionary {
+		if l := len([]rune(w)); l > n {
+			n = l
+		}
+	}
+	return n
+}()
+
+// buildDictionary ranks the common passwords by their position in the list.
+// The passphrase words are not ordered by frequency, so they all share the rank of the size of their list.
+func buildDictionary() map[string]int {
+	d := map[string]int{}
+	for i, w := range strings.Fields(commonFile) {
+		if _, ok := d[w]; !ok {
+			d[w] = i + 1
+		}
+	}
+	words := passgen.Wordlist()
+	for _, w := range words {
+		if _, ok := d[w]; !ok {
+			d[w] = len(words)
+		}
+	}
+	return d
+}
+
+// leet maps common character substitutions back to the letters they replace.
+var leet = map[rune]rune{'@': 'a', '4': 'a', '3': 'e', '1': 'i', '!': 'i', '0': 'o', '$': 's', '5': 's', '7': 't', '+': 't'}
+
+// keyboardRows are the rows of a US QWERTY keyboard, unshifted and shifted.
+var keyboardRows = []string{
+	"`1234567890-=", "qwertyuiop[]\\", "asdfghjkl;'", "zxcvbnm,./",
+	"~!@#$%^&*()_+", "{}|", ":\"", "<>?",
+}
+
+// keyboardKeys is the number of keys patterns on the keyboard can start from.
+const keyboardKeys = 47
+
+// Pattern names of the strength estimate.
+const (
+	PatternDictionary = "dictionary"
+	PatternSequence   = "sequence"
+	PatternRepeat     = "repeat"
+	PatternKeyboard   = "keyboard"
+	PatternYear       = "year"
+	PatternBruteForce = "bruteforce"
+)
+
+// Strength is the estimated resistance of a password to a guessing attack.
+// Bits is the base 2 logarithm of the estimated number of guesses, Score rates it from 0 to 4 like zxcvbn.
+type Strength struct {
+	Bits     float64  `json:"entropy_bits" yaml:"entropy_bits"`
+	Score    int      `json:"score" yaml:"score"`
+	Patterns []string `json:"patterns,omitempty" yaml:"patterns,omitempty"`
+}
+
+// scoreBits are the guess counts of 10^3, 10^6, 10^8 and 10^10 in bits, the lower bounds of the scores 1 to 4.
+var scoreBits = []float64{3 * math.Log2(10), 6 * math.Log2(10), 8 * math.Log2(10), 10 * math.Log2(10)}
+
+// match is a part of the password from i to j, excluding j, that an attacker guesses in 2^bits attempts.
+type match struct {
+	i, j    int
+	bits    float64
+	pattern string
+}
+
+// maxLength caps the characters estimated. Longer passwords are estimated by their first maxLength characters,
+// which already rate far beyond the best score.
+const maxLength = 256
+
+// Estimate estimates the strength of the password the way zxcvbn does: the password is split into the parts
+// an attacker guesses most cheaply, like dictionary words, sequences, repeats and keyboard walks,
+// and the guesses for the parts are multiplied. Characters outside any pattern are guessed by brute force.
+func Estimate(password string) Strength {
+	runes := []rune(password)
+	if len(runes) == 0 {
+		return Strength{}
+	}
+	if len(runes) > maxLength {
+		runes = runes[:maxLength]
+	}
+	matches := append(findMatches(runes), repeatMatches(runes)...)
+	bits, found := cheapest(runes, matches)
+	s := Strength{Bits: math.Round(bits*10) / 10}
+	for _, b := range scoreBits {
+		if bits >= b {
+			s.Score++
+		}
+	}
+	for p := range found {
+		s.Patterns = append(s.Patterns, p)
+	}
+	sort.Strings(s.Patterns)
+	return s
+}
+
+// cheapest returns the bits of the cheapest split of the password into the matches and brute forced characters,
+// and the patterns of the split.
+func cheapest(runes []rune, matches []match) (float64, map[string]bool) {
+	charBits := math.Log2(float64(pool(runes)))
+
+	// best[j] is the cheapest estimate for the first j characters, reached through the match ending at j.
+	best := make([]float64, len(runes)+1)
+	via := make([]*match, len(runes)+1)
+	for j := 1; j <= len(runes); j++ {
+		best[j] = best[j-1] + charBits
+		for k := range matches {
+			m := &matches[k]
+			if m.j == j && best[m.i]+m.bits < best[j] {
+				best[j] = best[m.i] + m.bits
+				via[j] = m
+			}
+		}
+	}
+
+	// The attacker also has to guess how the parts are combined, which zxcvbn counts as the factorial of the parts.
+	parts := 0
+	found := map[string]bool{}
+	for j, bruteForce := len(runes), false; j > 0; {
+		if m := via[j]; m != nil {
+			parts++
+			found[m.pattern] = true
+			j, bruteForce = m.i, false
+			continue
+		}
+		if !bruteForce {
+			parts++
+		}
+		found[PatternBruteForce] = true
+		j, bruteForce = j-1, true
+	}
+	bits := best[len(runes)]
+	for n := 2; n <= parts; n++ {
+		bits += math.Log2(float64(n))
+	}
+	return bits, found
+}
+
+// pool returns the size of the character classes used by the password.
+func pool(runes []rune) int {
+	var lower, upper, digit, symbol, other bool
+	for _, r := range runes {
+		switch {
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= '0' && r <= '9':
+			digit = true
+		case r < unicode.MaxASCII:
+			symbol = true
+		default:
+			other = true
+		}
+	}
+	n := 0
+	for _, c := range []struct {
+		used bool
+		size int
+	}{{lower, 26}, {upper, 26}, {digit, 10}, {symbol, 33}, {other, 100}} {
+		if c.used {
+			n += c.size
+		}
+	}
+	return n
+}
+
+// findMatches returns the patterns found in the password other than repeats.
+func findMatches(runes []rune) []match {
+	var matches []match
+	matches = append(matches, dictionaryMatches(runes)...)
+	matches = append(matches, sequenceMatches(runes)...)
+	matches = append(matches, keyboardMatches(runes)...)
+	matches = append(matches, yearMatches(runes)...)
+	return matches
+}
+
+// dictionaryMatches finds dictionary words of at least three characters, also reversed or with leet substitutions.
+func dictionaryMatches(runes []rune) []match {
+	var matches []match
+	for i := range runes {
+		for j := i + 3; j <= len(runes) && j-i <= longestWord; j++ {
+			part := runes[i:j]
+			word := strings.ToLower(string(part))
+			extra := caseBits(part)
+			if rank, ok := dictionary[word]; ok {
+				matches = append(matches, match{i, j, math.Log2(float64(rank)) + extra, PatternDictionary})
+			}
+			if rank, ok := dictionary[reverse(word)]; ok {
+				matches = append(matches, match{i, j, math.Log2(float64(rank)) + extra + 1, PatternDictionary})
+			}
+			if plain, subs := unleet(word); subs > 0 {
+				if rank, ok := dictionary[plain]; ok {
+					matches = append(matches, match{i, j, math.Log2(float64(rank)) + extra + float64(subs), PatternDictionary})
+				}
+			}
+		}
+	}
+	return matches
+}
+
+// caseBits returns the guesses needed for the capitalization of a word, in bits.
+// Lowercase words need none, capitalized or uppercase words one, other mixes count the possible positions.
+func caseBits(part []rune) float64 {
+	var upper, lower int
+	for _, r := range part {
+		if unicode.IsUpper(r) {
+			upper++
+		} else if unicode.IsLower(r) {
+			lower++
+		}
+	}
+	switch {
+	case upper == 0:
+		return 0
+	case lower == 0, upper == 1 && unicode.IsUpper(part[0]):
+		return 1
+	}
+	n, k := upper+lower, upper
+	if lower < k {
+		k = lower
+	}
+	variations := 0.0
+	for i := 1; i <= k; i++ {
+		variations += binomial(n, i)
+	}
+	return math.Log2(variations)
+}
+
+// binomial returns n choose k.
+func binomial(n, k int) float64 {
+	r := 1.0
+	for i := 1; i <= k; i++ {
+		r = r * float64(n-k+i) / float64(i)
+	}
+	return r
+}
+
+// unleet replaces leet substitutions in the word with letters, returning the number of replaced characters.
+func unleet(word string) (string, int) {
+	subs := 0
+	plain := []rune(word)
+	for i, r := range plain {
+		if l, ok := leet[r]; ok {
+			plain[i] = l
+			subs++
+		}
+	}
+	return string(plain), subs
+}
+
+// reverse returns the string backwards.
+func reverse(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+// sequenceMatches finds runs of at least three characters of one class counting up or down, like abc or 9876.
+func sequenceMatches(runes []rune) []match {
+	var matches []match
+	for i := 0; i < len(runes)-2; {
+		delta := runes[i+1] - runes[i]
+		j := i + 1
+		if (delta == 1 || delta == -1) && sameClass(runes[i], runes[j]) {
+			for j+1 < len(runes) && runes[j+1]-runes[j] == delta && sameClass(runes[j], runes[j+1]) {
+				j++
+			}
+		}
+		if j-i+1 >= 3 {
+			bits := math.Log2(float64(j-i+1)) + sequenceStartBits(runes[i])
+			if delta < 0 {
+				bits++
+			}
+			matches = append(matches, match{i, j + 1, bits, PatternSequence})
+			i = j
+			continue
+		}
+		i++
+	}
+	return matches
+}
+
+// sequenceStartBits returns the guesses for the first character of a sequence, in bits.
+// Sequences starting at the ends of the alphabet or the digits are tried first.
+func sequenceStartBits(r rune) float64 {
+	switch {
+	case strings.ContainsRune("aAzZ019", r):
+		return 2
+	case unicode.IsDigit(r):
+		return math.Log2(10)
+	}
+	return math.Log2(26)
+}
+
+// sameClass reports whether both characters are lowercase letters, uppercase letters or digits.
+func sameClass(a, b rune) bool {
+	class := func(r rune) int {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return 1
+		case r >= 'A' && r <= 'Z':
+			return 2
+		case r >= '0' && r <= '9':
+			return 3
+		}
+		return 0
+	}
+	return class(a) != 0 && class(a) == class(b)
+}
+
+// repeatMatches finds characters or blocks of characters repeated at least twice, like aaa or abcabc.
+// A block is guessed by the patterns other than repeats found in it, estimated once for every distinct block.
+func repeatMatches(runes []rune) []match {
+	var matches []match
+	blocks := map[string]float64{}
+	for i := range runes {
+		for size := 1; i+2*size <= len(runes); size++ {
+			block := runes[i : i+size]
+			count := 1
+			for j := i + size; j+size <= len(runes) && equal(runes[j:j+size], block); j += size {
+				count++
+			}
+			if count < 2 || size == 1 && count < 3 {
+				continue
+			}
+			blockBits, ok := blocks[string(block)]
+			if !ok {
+				if size == 1 {
+					blockBits = math.Log2(float64(pool(block)))
+				} else {
+					blockBits, _ = cheapest(block, findMatches(block))
+				}
+				blocks[string(block)] = blockBits
+			}
+			matches = append(matches, match{i, i + size*count, blockBits + math.Log2(float64(count)), PatternRepeat})
+		}
+	}
+	return matches
+}
+
+// equal reports whether both parts hold the same characters.
+func equal(a, b []rune) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// keyboardMatches finds walks of at least four neighbouring keys along a keyboard row, like qwer or lkjh.
+func keyboardMatches(runes []rune) []match {
+	var matches []match
+	lower := []rune(strings.ToLower(string(runes)))
+	for i := 0; i < len(lower)-3; i++ {
+		for _, row := range keyboardRows {
+			for _, dir := range []int{1, -1} {
+				j := i + 1
+				for j < len(lower) && adjacent(row, lower[j-1], lower[j], dir) {
+					j++
+				}
+				if j-i >= 4 {
+					bits := math.Log2(float64(keyboardKeys*2*(j-i))) + caseBits(runes[i:j])
+					matches = append(matches, match{i, j, bits, PatternKeyboard})
+				}
+			}
+		}
+	}
+	return matches
+}
+
+// adjacent reports whether b follows a on the keyboard row in the direction.
+func adjacent(row string, a, b rune, dir int) bool {
+	keys := []rune(row)
+	for k, r := range keys {
+		if r == a {
+			n := k + dir
+			return n >= 0 && n < len(keys) && keys[n] == b
+		}
+	}
+	return false
+}
+
+// yearMatches finds years from 1900 to 2099, often appended to passwords.
+func yearMatches(runes []rune) []match {
+	var matches []match
+	for i := 0; i+4 <= len(runes); i++ {
+		year, err := strconv.Atoi(string(runes[i : i+4]))
+		if err == nil && year >= 1900 && year <= 2099 && unicode.IsDigit(runes[i]) {
+			matches = append(matches, match{i, i + 4, math.Log2(200), PatternYear})
+		}
+	}
+	return matches
+}
@@ -0,0 +1,64 @@
+package audit
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEstimate(t *testing.T) {
+	tests := []struct {
+		password string
+		maxScore int
+		minScore int
+		pattern  string
+	}{
+		{password: "", maxScore: 0},
+		{password: "password", maxScore: 0, pattern: PatternDictionary},
+		{password: "P@ssw0rd", maxScore: 1, pattern: PatternDictionary},
+		{password: "drowssap", maxScore: 1, pattern: PatternDictionary},
+		{password: "qwertyuiop", maxScore: 0, pattern: PatternDictionary},
+		{password: "poiuytrewq", maxScore: 1, pattern: PatternDictionary},
+		{password: "lkjhgfds", maxScore: 1, pattern: PatternKeyboard},
+		{password: "abcdefghij", maxScore: 0, pattern: PatternSequence},
+		{password: "98765432", maxScore: 0, pattern: PatternSequence},
+		{password: "aaaaaaaaaaaa", maxScore: 0, pattern: PatternRepeat},
+		{password: "catcatcatcat", maxScore: 1, pattern: PatternRepeat},
+		{password: "Winter1987", maxScore: 2, pattern: PatternYear},
+		{password: "correct-horse-battery-staple-orbit-lemon", minScore: 4, pattern: PatternDictionary},
+		{password: "x7#Qm9$vL2!pR4&w", minScore: 4, pattern: PatternBruteForce},
+	}
+	for _, tt := range tests {
+		t.Run(tt.password, func(t *testing.T) {
+			s := Estimate(tt.password)
+			if tt.maxScore > 0 || tt.minScore == 0 {
+				assert.LessOrEqual(t, s.Score, tt.maxScore, "%+v", s)
+			}
+			assert.GreaterOrEqual(t, s.Score, tt.minScore, "%+v", s)
+			if tt.pattern != "" {
+				assert.Contains(t, s.Patterns, tt.pattern)
+			}
+		})
+	}
+}
+
+func TestEstimate_LongerIsStronger(t *testing.T) {
+	assert.Less(t, Estimate("k9#vQ2").Bits, Estimate("k9#vQ2mT").Bits)
+	assert.Less(t, Estimate("password").Bits, Estimate("x8Tq2mVz").Bits)
+}
+
+func TestEstimate_LongRepeats(t *testing.T) {
+	for _, password := range []string{
+		strings.Repeat("a", 256),
+		strings.Repeat("abcd", 64),
+		strings.Repeat("Winter1987", 30),
+	} {
+		start := time.Now()
+		s := Estimate(password)
+		assert.Less(t, time.Since(start), time.Second, "%d characters", len(password))
+		assert.Contains(t, s.Patterns, PatternRepeat)
+	}
+	assert.Equal(t, Estimate(strings.Repeat("x7#Qm9$v", 32)), Estimate(strings.Repeat("x7#Qm9$v", 40)),
+		"characters past the maximum length are not estimated")
+}
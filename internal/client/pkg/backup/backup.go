@@ -0,0 +1,257 @@
+// Package backup reads and writes encrypted backup archives of a vault.
+//
+// An archive is self-contained: items are stored decrypted inside a payload encrypted with AES-256-GCM
+// under a key derived from a backup passphrase with Argon2id, so it can be restored into any account.
+// The header records the key derivation parameters and the payload length, and a SHA-256 checksum
+// of the whole archive follows the payload, so truncated or damaged files are detected before decryption.
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/argon2"
+	"io"
+	"time"
+)
+
+// Extension is the file extension of backup archives.
+const Extension = ".stbk"
+
+// Version is the archive format written by this client.
+const Version = 1
+
+// magic starts every archive.
+var magic = [4]byte{'S', 'T', 'B', 'K'}
+
+// Default key derivation parameters, following the second recommended option of RFC 9106.
+const (
+	defaultTime    = 3
+	defaultMemory  = 64 * 1024
+	defaultThreads = 4
+)
+
+// Sizes of the archive parts.
+const (
+	saltSize     = 16
+	keySize      = 32
+	checksumSize = sha256.Size
+	// maxPayload rejects headers stating a payload larger than 4 GiB as damaged.
+	maxPayload = 1 << 32
+	// maxTime and maxMemory bound the key derivation cost accepted from an archive.
+	maxTime   = 64
+	maxMemory = 1024 * 1024
+)
+
+var (
+	// ErrNotBackup is returned for files that are not backup archives.
+	ErrNotBackup = errors.New("file is not a storety backup")
+
+	// ErrUnsupportedVersion is returned for archives written by a newer client.
+	ErrUnsupportedVersion = errors.New("backup was written by a newer client")
+
+	// ErrTruncated is returned for archives shorter than their header states.
+	ErrTruncated = errors.New("backup is truncated")
+
+	// ErrChecksum is returned for archives whose content does not match their checksums.
+	ErrChecksum = errors.New("backup checksum mismatch, the file is damaged")
+
+	// ErrWrongPassphrase is returned when the archive cannot be decrypted with the passphrase.
+	ErrWrongPassphrase = errors.New("wrong backup passphrase")
+)
+
+// Archive is the content of a backup.
+type Archive struct {
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+	Items     []Item    `json:"items"`
+}
+
+// Item is a vault item in a backup, with the checksum of its content.
+type Item struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Content   []byte    `json:"content"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Checksum  string    `json:"sha256"`
+}
+
+// header is the fixed size start of an archive, authenticated together with the payload.
+type header struct {
+	Magic   [4]byte
+	Version uint8
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+	Salt    [saltSize]byte
+	Nonce   [12]byte
+	Length  uint64
+}
+
+// New creates an archive of the decrypted items of a vault.
+func New(username string, data []models.Data) *Archive {
+	a := &Archive{Username: username, CreatedAt: time.Now().UTC(), Items: make([]Item, len(data))}
+	for i, d := range data {
+		a.Items[i] = Item{
+			ID:        d.ID,
+			Name:      d.Name,
+			Type:      d.Type,
+			Content:   d.Content,
+			UpdatedAt: d.UpdatedAt,
+			Checksum:  checksum(d.Content),
+		}
+	}
+	return a
+}
+
+// Data returns the items of the archive.
+func (a *Archive) Data() []models.Data {
+	data := make([]models.Data, len(a.Items))
+	for i, it := range a.Items {
+		data[i] = models.Data{ID: it.ID, Name: it.Name, Type: it.Type, Content: it.Content, UpdatedAt: it.UpdatedAt}
+	}
+	return data
+}
+
+// Write encrypts the archive with a key derived from the passphrase and writes it to w.
+func Write(w io.Writer, a *Archive, passphrase []byte) error {
+	var payload bytes.Buffer
+	gz := gzip.NewWriter(&payload)
+	if err := json.NewEncoder(gz).Encode(a); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	h := header{Magic: magic, Version: Version, Time: defaultTime, Memory: defaultMemory, Threads: defaultThreads}
+	if _, err := io.ReadFull(rand.Reader, h.Salt[:]); err != nil {
+		return err
+	}
+	if _, err := io.ReadFull(rand.Reader, h.Nonce[:]); err != nil {
+		return err
+	}
+	aead, err := newAEAD(&h, passphrase)
+	if err != nil {
+		return err
+	}
+	h.Length = uint64(payload.Len() + aead.Overhead())
+	var headerBytes bytes.Buffer
+	if err = binary.Write(&headerBytes, binary.BigEndian, &h); err != nil {
+		return err
+	}
+	sealed := aead.Seal(nil, h.Nonce[:], payload.Bytes(), headerBytes.Bytes())
+	sum := sha256.New()
+	out := io.MultiWriter(w, sum)
+	if _, err = out.Write(headerBytes.Bytes()); err != nil {
+		return err
+	}
+	if _, err = out.Write(sealed); err != nil {
+		return err
+	}
+	_, err = w.Write(sum.Sum(nil))
+	return err
+}
+
+// Read reads an archive from r, verifies its checksums and decrypts it with the passphrase.
+func Read(r io.Reader, passphrase []byte) (*Archive, error) {
+	sum := sha256.New()
+	in := io.TeeReader(r, sum)
+	var h header
+	if err := binary.Read(in, binary.BigEndian, &h); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, ErrNotBackup
+		}
+		return nil, err
+	}
+	if h.Magic != magic {
+		return nil, ErrNotBackup
+	}
+	if h.Version > Version {
+		return nil, fmt.Errorf("%w: version %d", ErrUnsupportedVersion, h.Version)
+	}
+	if h.Length > maxPayload || h.Time == 0 || h.Time > maxTime || h.Memory > maxMemory || h.Threads == 0 {
+		return nil, ErrChecksum
+	}
+	// The payload is read as it arrives instead of allocating the stated length up front,
+	// so a damaged length cannot allocate more memory than the file holds.
+	sealed, err := io.ReadAll(io.LimitReader(in, int64(h.Length)))
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(sealed)) < h.Length {
+		return nil, ErrTruncated
+	}
+	expected := sum.Sum(nil)
+	stored := make([]byte, checksumSize)
+	if _, err := io.ReadFull(r, stored); err != nil {
+		return nil, truncated(err)
+	}
+	if !bytes.Equal(stored, expected) {
+		return nil, ErrChecksum
+	}
+	aead, err := newAEAD(&h, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	var headerBytes bytes.Buffer
+	if err = binary.Write(&headerBytes, binary.BigEndian, &h); err != nil {
+		return nil, err
+	}
+	payload, err := aead.Open(nil, h.Nonce[:], sealed, headerBytes.Bytes())
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	a := &Archive{}
+	if err = json.NewDecoder(gz).Decode(a); err != nil {
+		return nil, err
+	}
+	for _, it := range a.Items {
+		if checksum(it.Content) != it.Checksum {
+			return nil, fmt.Errorf("%w: item %s", ErrChecksum, it.Name)
+		}
+	}
+	return a, nil
+}
+
+// newAEAD derives the key of the archive from the passphrase and creates its cipher.
+func newAEAD(h *header, passphrase []byte) (cipher.AEAD, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("backup passphrase must not be empty")
+	}
+	key := argon2.IDKey(passphrase, h.Salt[:], h.Time, h.Memory, h.Threads, keySize)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// truncated maps errors of reading past the end of the archive to ErrTruncated.
+func truncated(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrTruncated
+	}
+	return err
+}
+
+// checksum returns the hex encoded SHA-256 checksum of the content.
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
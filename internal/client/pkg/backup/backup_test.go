@@ -0,0 +1,74 @@
+package backup
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// lengthOffset is the offset of the payload length in the header.
+const lengthOffset = 4 + 1 + 4 + 4 + 1 + saltSize + 12
+
+func TestWriteRead(t *testing.T) {
+	updated := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	data := []models.Data{
+		{ID: uuid.New(), Name: "github", Type: "Cred", Content: []byte(`{"login":"bob","password":"secret"}`), UpdatedAt: updated},
+		{ID: uuid.New(), Name: "key.bin", Type: "Binary", Content: []byte{0, 1, 2, 255}, UpdatedAt: updated},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, New("bob", data), []byte("correct horse")))
+	assert.NotContains(t, buf.String(), "secret", "items must be encrypted")
+	archive := buf.Bytes()
+
+	a, err := Read(bytes.NewReader(archive), []byte("correct horse"))
+	require.NoError(t, err)
+	assert.Equal(t, "bob", a.Username)
+	assert.Equal(t, data, a.Data())
+
+	tests := []struct {
+		name       string
+		archive    []byte
+		passphrase string
+		wantErr    error
+	}{
+		{name: "wrong passphrase", archive: archive, passphrase: "wrong", wantErr: ErrWrongPassphrase},
+		{name: "truncated payload", archive: archive[:len(archive)/2], passphrase: "correct horse", wantErr: ErrTruncated},
+		{name: "truncated checksum", archive: archive[:len(archive)-1], passphrase: "correct horse", wantErr: ErrTruncated},
+		{name: "damaged", archive: flip(archive, len(archive)-checksumSize-1), passphrase: "correct horse", wantErr: ErrChecksum},
+		{name: "damaged header", archive: flip(archive, 8), passphrase: "correct horse", wantErr: ErrChecksum},
+		{name: "length past the end", archive: withLength(archive, maxPayload), passphrase: "correct horse", wantErr: ErrTruncated},
+		{name: "length too large", archive: withLength(archive, maxPayload+1), passphrase: "correct horse", wantErr: ErrChecksum},
+		{name: "not a backup", archive: []byte(`{"items": []}`), passphrase: "correct horse", wantErr: ErrNotBackup},
+		{name: "empty", archive: nil, passphrase: "correct horse", wantErr: ErrNotBackup},
+		{name: "newer version", archive: flip(archive, 4), passphrase: "correct horse", wantErr: ErrUnsupportedVersion},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read(bytes.NewReader(tt.archive), []byte(tt.passphrase))
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestWrite_EmptyPassphrase(t *testing.T) {
+	assert.Error(t, Write(&bytes.Buffer{}, New("bob", nil), nil))
+}
+
+// withLength returns a copy of the archive with the payload length in the header set to n.
+func withLength(archive []byte, n uint64) []byte {
+	damaged := append([]byte{}, archive...)
+	binary.BigEndian.PutUint64(damaged[lengthOffset:], n)
+	return damaged
+}
+
+// flip returns a copy of the archive with the byte at i changed.
+func flip(archive []byte, i int) []byte {
+	damaged := append([]byte{}, archive...)
+	damaged[i] ^= 0xff
+	return damaged
+}
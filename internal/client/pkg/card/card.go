@@ -0,0 +1,284 @@
+// Package card validates, formats and masks payment card numbers, expiry dates and security codes.
+// Brands are detected from the issuer identification number, the leading digits of the card number.
+package card
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExpiryWarning is how long before its expiry a card is reported as expiring soon.
+const ExpiryWarning = 60 * 24 * time.Hour
+
+// Lengths of numbers of unknown brands.
+const (
+	MinLength = 12
+	MaxLength = 19
+)
+
+// Brand is a card network with the number lengths and security code length of its cards.
+type Brand struct {
+	Name    string
+	Lengths []int
+	CVV     int
+	// groups are the lengths of the digit groups of a formatted number, groups of four when empty.
+	groups []int
+}
+
+// iin is a range of issuer identification numbers of the same number of digits.
+type iin struct {
+	low, high string
+}
+
+// brands lists the brands with their IIN ranges, more specific ranges first.
+var brands = []struct {
+	brand  Brand
+	ranges []iin
+}{
+	{Brand{Name: "American Express", Lengths: []int{15}, CVV: 4, groups: []int{4, 6, 5}}, []iin{{"34", "34"}, {"37", "37"}}},
+	{Brand{Name: "Diners Club", Lengths: lengths(14, 19), CVV: 3, groups: []int{4, 6, 4}}, []iin{{"300", "305"}, {"3095", "3095"}, {"36", "36"}, {"38", "39"}}},
+	{Brand{Name: "JCB", Lengths: lengths(16, 19), CVV: 3}, []iin{{"3528", "3589"}}},
+	{Brand{Name: "Mir", Lengths: lengths(16, 19), CVV: 3}, []iin{{"2200", "2204"}}},
+	{Brand{Name: "Mastercard", Lengths: []int{16}, CVV: 3}, []iin{{"51", "55"}, {"2221", "2720"}}},
+	{Brand{Name: "Visa", Lengths: []int{13, 16, 19}, CVV: 3}, []iin{{"4", "4"}}},
+	{Brand{Name: "Discover", Lengths: lengths(16, 19), CVV: 3}, []iin{{"6011", "6011"}, {"622126", "622925"}, {"644", "649"}, {"65", "65"}}},
+	{Brand{Name: "UnionPay", Lengths: lengths(16, 19), CVV: 3}, []iin{{"62", "62"}}},
+	{Brand{Name: "Maestro", Lengths: lengths(12, 19), CVV: 3}, []iin{{"5018", "5018"}, {"5020", "5020"}, {"5038", "5038"}, {"5893", "5893"}, {"6304", "6304"}, {"6759", "6759"}, {"6761", "6763"}}},
+}
+
+// lengths returns the lengths from min to max.
+func lengths(min, max int) []int {
+	var l []int
+	for n := min; n <= max; n++ {
+		l = append(l, n)
+	}
+	return l
+}
+
+// Normalize removes the spaces and dashes grouping the digits of a card number.
+func Normalize(number string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(number))
+}
+
+// Detect returns the brand of the card number, false when its IIN belongs to no known brand.
+func Detect(number string) (Brand, bool) {
+	number = Normalize(number)
+	for _, b := range brands {
+		for _, r := range b.ranges {
+			if len(number) < len(r.low) {
+				continue
+			}
+			prefix := number[:len(r.low)]
+			if prefix >= r.low && prefix <= r.high {
+				return b.brand, true
+			}
+		}
+	}
+	return Brand{}, false
+}
+
+// Luhn reports whether the digits pass the Luhn checksum every card number carries in its last digit.
+func Luhn(digits string) bool {
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if d < 0 || d > 9 {
+			return false
+		}
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return len(digits) > 0 && sum%10 == 0
+}
+
+// ValidateNumber checks that the card number has only digits, a length of its brand and a valid Luhn checksum.
+func ValidateNumber(number string) error {
+	number = Normalize(number)
+	if !digitsOnly(number) {
+		return errors.New("card number must have digits only")
+	}
+	if b, ok := Detect(number); ok {
+		if !contains(b.Lengths, len(number)) {
+			return fmt.Errorf("%s numbers have %s digits, not %d", b.Name, describeLengths(b.Lengths), len(number))
+		}
+	} else if len(number) < MinLength || len(number) > MaxLength {
+		return fmt.Errorf("card number must have %d to %d digits", MinLength, MaxLength)
+	}
+	if !Luhn(number) {
+		return errors.New("card number fails the Luhn check, check it for typos")
+	}
+	return nil
+}
+
+// ValidateCVV checks that the security code has three or four digits,
+// or exactly the length of the brand of the card number when it is known.
+func ValidateCVV(cvv, number string) error {
+	if !digitsOnly(cvv) {
+		return errors.New("CVV must have digits only")
+	}
+	if b, ok := Detect(number); ok {
+		if len(cvv) != b.CVV {
+			return fmt.Errorf("%s cards have a %d digit CVV", b.Name, b.CVV)
+		}
+		return nil
+	}
+	if len(cvv) != 3 && len(cvv) != 4 {
+		return errors.New("CVV must have 3 or 4 digits")
+	}
+	return nil
+}
+
+// Format groups the digits of the card number the way they are printed on cards of its brand.
+func Format(number string) string {
+	number = Normalize(number)
+	b, _ := Detect(number)
+	groups := b.groups
+	if len(groups) == 0 || sum(groups) != len(number) {
+		groups = nil
+		for n := len(number); n > 0; n -= 4 {
+			groups = append(groups, 4)
+		}
+	}
+	var parts []string
+	for _, g := range groups {
+		if g > len(number) {
+			g = len(number)
+		}
+		parts = append(parts, number[:g])
+		number = number[g:]
+	}
+	return strings.Join(parts, " ")
+}
+
+// Mask formats the card number with all but its last four digits replaced by asterisks, followed by its brand.
+func Mask(number string) string {
+	formatted := []rune(Format(number))
+	visible := 4
+	for i := len(formatted) - 1; i >= 0; i-- {
+		if formatted[i] == ' ' {
+			continue
+		}
+		if visible > 0 {
+			visible--
+			continue
+		}
+		formatted[i] = '*'
+	}
+	if b, ok := Detect(number); ok {
+		return fmt.Sprintf("%s (%s)", string(formatted), b.Name)
+	}
+	return string(formatted)
+}
+
+// Last4 returns the last four digits of the card number.
+func Last4(number string) string {
+	number = Normalize(number)
+	if len(number) < 4 {
+		return number
+	}
+	return number[len(number)-4:]
+}
+
+// Expiry is the month a card expires in.
+type Expiry struct {
+	Month int
+	Year  int
+}
+
+// ParseExpiry parses an expiry date like 07/27, 7/2027 or 07-27.
+func ParseExpiry(s string) (Expiry, error) {
+	invalid := fmt.Errorf("expiry date %q must be MM/YY", s)
+	month, year, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok {
+		month, year, ok = strings.Cut(strings.TrimSpace(s), "-")
+	}
+	month, year = strings.TrimSpace(month), strings.TrimSpace(year)
+	m, err := strconv.Atoi(month)
+	if !ok || err != nil || len(month) > 2 || m < 1 || m > 12 {
+		return Expiry{}, invalid
+	}
+	y, err := strconv.Atoi(year)
+	if err != nil || len(year) != 2 && len(year) != 4 {
+		return Expiry{}, invalid
+	}
+	if len(year) == 2 {
+		y += 2000
+	}
+	return Expiry{Month: m, Year: y}, nil
+}
+
+// String returns the expiry date as MM/YY.
+func (e Expiry) String() string {
+	return fmt.Sprintf("%02d/%02d", e.Month, e.Year%100)
+}
+
+// End returns the end of the expiry month in UTC, the moment the card stops being valid.
+func (e Expiry) End() time.Time {
+	return time.Date(e.Year, time.Month(e.Month)+1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// Warning describes the expiry of a card that expired or expires within ExpiryWarning of now, empty otherwise.
+func (e Expiry) Warning(now time.Time) string {
+	left := e.End().Sub(now)
+	switch {
+	case left <= 0:
+		return "the card expired in " + e.String()
+	case left <= ExpiryWarning:
+		return fmt.Sprintf("the card expires in %d days", int(left.Hours()/24))
+	}
+	return ""
+}
+
+// digitsOnly reports whether s is a non-empty string of ASCII digits.
+func digitsOnly(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// contains reports whether the lengths contain n.
+func contains(lengths []int, n int) bool {
+	for _, l := range lengths {
+		if l == n {
+			return true
+		}
+	}
+	return false
+}
+
+// sum returns the sum of the numbers.
+func sum(numbers []int) int {
+	s := 0
+	for _, n := range numbers {
+		s += n
+	}
+	return s
+}
+
+// describeLengths describes the lengths as a range or a list, like 16 to 19 or 13, 16 or 19.
+func describeLengths(l []int) string {
+	if len(l) == 1 {
+		return strconv.Itoa(l[0])
+	}
+	if l[len(l)-1]-l[0] == len(l)-1 {
+		return fmt.Sprintf("%d to %d", l[0], l[len(l)-1])
+	}
+	parts := make([]string, len(l)-1)
+	for i, n := range l[:len(l)-1] {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, ", ") + " or " + strconv.Itoa(l[len(l)-1])
+}
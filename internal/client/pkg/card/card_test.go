@@ -0,0 +1,107 @@
+package card
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		number string
+		want   string
+	}{
+		{number: "4111111111111111", want: "Visa"},
+		{number: "5555 5555 5555 4444", want: "Mastercard"},
+		{number: "2223003122003222", want: "Mastercard"},
+		{number: "378282246310005", want: "American Express"},
+		{number: "6011111111111117", want: "Discover"},
+		{number: "6221260000000000", want: "Discover"},
+		{number: "6200000000000005", want: "UnionPay"},
+		{number: "3530111333300000", want: "JCB"},
+		{number: "36227206271667", want: "Diners Club"},
+		{number: "2200000000000004", want: "Mir"},
+		{number: "6759649826438453", want: "Maestro"},
+		{number: "9999999999999995", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.number, func(t *testing.T) {
+			b, ok := Detect(tt.number)
+			assert.Equal(t, tt.want != "", ok)
+			assert.Equal(t, tt.want, b.Name)
+		})
+	}
+}
+
+func TestValidateNumber(t *testing.T) {
+	tests := []struct {
+		name    string
+		number  string
+		wantErr string
+	}{
+		{name: "Valid", number: "4111-1111-1111-1111"},
+		{name: "Valid Amex", number: "3782 822463 10005"},
+		{name: "Valid unknown brand", number: "9999999999999995"},
+		{name: "Luhn", number: "4111111111111112", wantErr: "Luhn"},
+		{name: "Letters", number: "4111abcd11111111", wantErr: "digits only"},
+		{name: "Brand length", number: "41111111111111111", wantErr: "Visa numbers have 13, 16 or 19 digits, not 17"},
+		{name: "Amex length", number: "3782822463100051", wantErr: "American Express numbers have 15 digits"},
+		{name: "Unknown brand length", number: "99999999995", wantErr: "12 to 19 digits"},
+		{name: "Empty", number: "", wantErr: "digits only"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateNumber(tt.number)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestValidateCVV(t *testing.T) {
+	assert.NoError(t, ValidateCVV("123", "4111111111111111"))
+	assert.ErrorContains(t, ValidateCVV("1234", "4111111111111111"), "Visa cards have a 3 digit CVV")
+	assert.NoError(t, ValidateCVV("1234", "378282246310005"))
+	assert.ErrorContains(t, ValidateCVV("123", "378282246310005"), "4 digit")
+	assert.NoError(t, ValidateCVV("1234", ""))
+	assert.Error(t, ValidateCVV("12", ""))
+	assert.Error(t, ValidateCVV("12a", ""))
+}
+
+func TestFormatAndMask(t *testing.T) {
+	assert.Equal(t, "4111 1111 1111 1111", Format("4111111111111111"))
+	assert.Equal(t, "3782 822463 10005", Format("378282246310005"))
+	assert.Equal(t, "4222 2222 2222 2", Format("4222222222222"))
+	assert.Equal(t, "**** **** **** 1111 (Visa)", Mask("4111 1111 1111 1111"))
+	assert.Equal(t, "**** ****** *0005 (American Express)", Mask("378282246310005"))
+	assert.Equal(t, "**** **** **** 9995", Mask("9999999999999995"))
+	assert.Equal(t, "1111", Last4("4111-1111-1111-1111"))
+}
+
+func TestParseExpiry(t *testing.T) {
+	for _, s := range []string{"07/27", "7/27", "07/2027", " 07 / 27 ", "07-27"} {
+		e, err := ParseExpiry(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, Expiry{Month: 7, Year: 2027}, e)
+		assert.Equal(t, "07/27", e.String())
+		assert.Equal(t, time.Date(2027, 8, 1, 0, 0, 0, 0, time.UTC), e.End())
+	}
+	e, err := ParseExpiry("12/2030")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC), e.End())
+	for _, s := range []string{"13/27", "00/27", "7", "07/7", "ab/cd", "007/27", ""} {
+		_, err = ParseExpiry(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestExpiry_Warning(t *testing.T) {
+	now := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, "the card expired in 09/26", Expiry{Month: 9, Year: 2026}.Warning(now))
+	assert.Equal(t, "the card expires in 43 days", Expiry{Month: 11, Year: 2026}.Warning(now))
+	assert.Empty(t, Expiry{Month: 12, Year: 2030}.Warning(now))
+}
@@ -0,0 +1,243 @@
+// Package clipboard copies secrets to the system clipboard and clears them again after a timeout.
+// Backends wrap the clipboard tools of X11, Wayland and macOS or write OSC 52 terminal escape sequences,
+// which works over SSH as the terminal emulator sets its own clipboard.
+package clipboard
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Names of the backends.
+const (
+	Auto    = "auto"
+	Wayland = "wayland"
+	XClip   = "xclip"
+	XSel    = "xsel"
+	PBCopy  = "pbcopy"
+	OSC52   = "osc52"
+)
+
+// Backends are the names accepted by New.
+var Backends = []string{Auto, Wayland, XClip, XSel, PBCopy, OSC52}
+
+// ErrUnreadable is returned by backends that can write the clipboard but not read it back.
+var ErrUnreadable = errors.New("clipboard cannot be read")
+
+// Backend is a system clipboard.
+type Backend interface {
+	// Name returns the name of the backend.
+	Name() string
+	// Copy puts the value on the clipboard.
+	Copy(value string) error
+	// Paste returns the value on the clipboard, ErrUnreadable if the backend cannot read it.
+	Paste() (string, error)
+	// Clear empties the clipboard.
+	Clear() error
+}
+
+// New returns the backend with the name. Auto picks the first of Wayland, xclip, xsel and pbcopy
+// whose display is set and whose tools are installed, falling back to OSC 52 written to the terminal out.
+func New(name string, terminal io.Writer) (Backend, error) {
+	return newBackend(name, terminal, os.Getenv, exec.LookPath)
+}
+
+// newBackend returns the backend with the name, looking up the environment and the tools with the functions.
+func newBackend(name string, terminal io.Writer, getenv func(string) string, lookPath func(string) (string, error)) (Backend, error) {
+	installed := func(tools ...string) bool {
+		for _, tool := range tools {
+			if _, err := lookPath(tool); err != nil {
+				return false
+			}
+		}
+		return true
+	}
+	switch name {
+	case Auto, "":
+		switch {
+		case getenv("WAYLAND_DISPLAY") != "" && installed("wl-copy", "wl-paste"):
+			return tools[Wayland], nil
+		case getenv("DISPLAY") != "" && installed("xclip"):
+			return tools[XClip], nil
+		case getenv("DISPLAY") != "" && installed("xsel"):
+			return tools[XSel], nil
+		case installed("pbcopy", "pbpaste"):
+			return tools[PBCopy], nil
+		}
+		return &Terminal{Out: terminal}, nil
+	case OSC52:
+		return &Terminal{Out: terminal}, nil
+	}
+	t, ok := tools[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown clipboard backend %q, use one of %s", name, strings.Join(Backends, ", "))
+	}
+	return t, nil
+}
+
+// tools are the backends running clipboard tools.
+var tools = map[string]*Command{
+	Wayland: {name: Wayland, copy: []string{"wl-copy"}, paste: []string{"wl-paste", "--no-newline"}, clear: []string{"wl-copy", "--clear"}},
+	XClip:   {name: XClip, copy: []string{"xclip", "-selection", "clipboard"}, paste: []string{"xclip", "-selection", "clipboard", "-o"}},
+	XSel:    {name: XSel, copy: []string{"xsel", "--clipboard", "--input"}, paste: []string{"xsel", "--clipboard", "--output"}, clear: []string{"xsel", "--clipboard", "--delete"}},
+	PBCopy:  {name: PBCopy, copy: []string{"pbcopy"}, paste: []string{"pbpaste"}},
+}
+
+// Command is a backend running clipboard tools, the value is passed on stdin and never as an argument.
+type Command struct {
+	name               string
+	copy, paste, clear []string
+}
+
+// Name implements the Backend interface Name method.
+func (c *Command) Name() string {
+	return c.name
+}
+
+// Copy implements the Backend interface Copy method.
+func (c *Command) Copy(value string) error {
+	return run(c.copy, value, nil)
+}
+
+// Paste implements the Backend interface Paste method.
+func (c *Command) Paste() (string, error) {
+	out := &bytes.Buffer{}
+	if err := run(c.paste, "", out); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// Clear implements the Backend interface Clear method, copying an empty value for tools without a clear option.
+func (c *Command) Clear() error {
+	if c.clear == nil {
+		return c.Copy("")
+	}
+	return run(c.clear, "", nil)
+}
+
+// run runs the tool with the input on stdin, writing its output to out.
+// Tools like xclip keep serving the selection in a background process inheriting their output,
+// so the output of copying tools is not captured, as waiting for it would block until the selection changes.
+func run(args []string, input string, out io.Writer) error {
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(input)
+	stderr := &bytes.Buffer{}
+	if out != nil {
+		cmd.Stdout = out
+		cmd.Stderr = stderr
+	}
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %w %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// Terminal is a backend writing OSC 52 escape sequences, which ask the terminal emulator to set its clipboard.
+// Terminals do not let programs read the clipboard back, so Paste returns ErrUnreadable.
+type Terminal struct {
+	Out io.Writer
+}
+
+// Name implements the Backend interface Name method.
+func (t *Terminal) Name() string {
+	return OSC52
+}
+
+// Copy implements the Backend interface Copy method.
+func (t *Terminal) Copy(value string) error {
+	_, err := fmt.Fprintf(t.Out, "\x1b]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(value)))
+	return err
+}
+
+// Paste implements the Backend interface Paste method.
+func (t *Terminal) Paste() (string, error) {
+	return "", ErrUnreadable
+}
+
+// Clear implements the Backend interface Clear method.
+func (t *Terminal) Clear() error {
+	return t.Copy("")
+}
+
+// Fake is an in-memory clipboard for tests.
+type Fake struct {
+	mu      sync.Mutex
+	value   string
+	cleared int
+}
+
+// Name implements the Backend interface Name method.
+func (f *Fake) Name() string {
+	return "fake"
+}
+
+// Copy implements the Backend interface Copy method.
+func (f *Fake) Copy(value string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.value = value
+	return nil
+}
+
+// Paste implements the Backend interface Paste method.
+func (f *Fake) Paste() (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.value, nil
+}
+
+// Clear implements the Backend interface Clear method.
+func (f *Fake) Clear() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.value = ""
+	f.cleared++
+	return nil
+}
+
+// Cleared returns how often the clipboard was cleared.
+func (f *Fake) Cleared() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.cleared
+}
+
+// Hash returns the SHA-256 hash of the value, which is all the clearing process needs to know of it.
+func Hash(value string) []byte {
+	sum := sha256.Sum256([]byte(value))
+	return sum[:]
+}
+
+// ClearAfter waits for the timeout and then clears the clipboard if it still holds the value with the hash,
+// so a value copied by the user in the meantime is kept. Backends that cannot read the clipboard are cleared anyway.
+// It reports whether the clipboard was cleared.
+func ClearAfter(ctx context.Context, b Backend, hash []byte, timeout time.Duration) (bool, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false, ctx.Err()
+	case <-timer.C:
+	}
+	current, err := b.Paste()
+	switch {
+	case errors.Is(err, ErrUnreadable):
+	case err != nil:
+		return false, err
+	case subtle.ConstantTimeCompare(Hash(current), hash) != 1:
+		return false, nil
+	}
+	return true, b.Clear()
+}
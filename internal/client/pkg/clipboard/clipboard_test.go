@@ -0,0 +1,115 @@
+package clipboard
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		backend string
+		env     map[string]string
+		tools   []string
+		want    string
+		wantErr bool
+	}{
+		{name: "Wayland", backend: Auto, env: map[string]string{"WAYLAND_DISPLAY": "wayland-0", "DISPLAY": ":0"}, tools: []string{"wl-copy", "wl-paste", "xclip"}, want: Wayland},
+		{name: "Wayland without tools", backend: Auto, env: map[string]string{"WAYLAND_DISPLAY": "wayland-0", "DISPLAY": ":0"}, tools: []string{"xclip"}, want: XClip},
+		{name: "X11 xsel", backend: Auto, env: map[string]string{"DISPLAY": ":0"}, tools: []string{"xsel"}, want: XSel},
+		{name: "macOS", backend: "", tools: []string{"pbcopy", "pbpaste"}, want: PBCopy},
+		{name: "SSH session", backend: Auto, tools: []string{"xclip"}, want: OSC52},
+		{name: "Explicit", backend: XClip, want: XClip},
+		{name: "Explicit OSC 52", backend: OSC52, env: map[string]string{"DISPLAY": ":0"}, tools: []string{"xclip"}, want: OSC52},
+		{name: "Unknown", backend: "clip.exe", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			lookPath := func(tool string) (string, error) {
+				for _, installed := range tt.tools {
+					if installed == tool {
+						return "/usr/bin/" + tool, nil
+					}
+				}
+				return "", errors.New("not found")
+			}
+			b, err := newBackend(tt.backend, &bytes.Buffer{}, getenv, lookPath)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, b.Name())
+		})
+	}
+}
+
+func TestTerminal(t *testing.T) {
+	out := &bytes.Buffer{}
+	b := &Terminal{Out: out}
+	require.NoError(t, b.Copy("secret"))
+	assert.Equal(t, "\x1b]52;c;c2VjcmV0\a", out.String())
+	_, err := b.Paste()
+	assert.True(t, errors.Is(err, ErrUnreadable))
+	out.Reset()
+	require.NoError(t, b.Clear())
+	assert.Equal(t, "\x1b]52;c;\a", out.String())
+}
+
+func TestCommand(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "clipboard")
+	b := &Command{
+		name:  "file",
+		copy:  []string{"sh", "-c", `cat > "$0"`, file},
+		paste: []string{"cat", file},
+	}
+	require.NoError(t, b.Copy("secret"))
+	value, err := b.Paste()
+	require.NoError(t, err)
+	assert.Equal(t, "secret", value)
+	require.NoError(t, b.Clear())
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Empty(t, content)
+
+	b.paste = []string{"sh", "-c", "echo no display >&2; exit 1"}
+	_, err = b.Paste()
+	assert.ErrorContains(t, err, "no display")
+}
+
+func TestClearAfter(t *testing.T) {
+	b := &Fake{}
+	require.NoError(t, b.Copy("secret"))
+	cleared, err := ClearAfter(context.Background(), b, Hash("secret"), time.Millisecond)
+	require.NoError(t, err)
+	assert.True(t, cleared)
+	value, _ := b.Paste()
+	assert.Empty(t, value)
+
+	require.NoError(t, b.Copy("copied by the user"))
+	cleared, err = ClearAfter(context.Background(), b, Hash("secret"), time.Millisecond)
+	require.NoError(t, err)
+	assert.False(t, cleared, "a value copied in the meantime is kept")
+	value, _ = b.Paste()
+	assert.Equal(t, "copied by the user", value)
+	assert.Equal(t, 1, b.Cleared())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = ClearAfter(ctx, b, Hash("copied by the user"), time.Hour)
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	out := &bytes.Buffer{}
+	cleared, err = ClearAfter(context.Background(), &Terminal{Out: out}, Hash("secret"), time.Millisecond)
+	require.NoError(t, err)
+	assert.True(t, cleared, "unreadable clipboards are cleared anyway")
+	assert.Equal(t, "\x1b]52;c;\a", out.String())
+}
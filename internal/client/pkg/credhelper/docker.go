@@ -0,0 +1,89 @@
+package credhelper
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Mldlr/storety/internal/constants"
+	"io"
+	"strings"
+)
+
+// DockerPrefix is the name prefix of items stored by the docker credential helper.
+const DockerPrefix = "docker:"
+
+// ErrCredentialsNotFound is the error docker expects from helpers for unknown servers.
+var ErrCredentialsNotFound = errors.New("credentials not found in native keychain")
+
+// DockerCredential is the credential message of the docker credential helper protocol.
+type DockerCredential struct {
+	ServerURL string
+	Username  string
+	Secret    string
+}
+
+// Docker serves the docker credential helper operations get, store, erase and list.
+// Requests are read from in and responses written to out as described by docker-credential-helpers.
+func Docker(vault Vault, operation string, in io.Reader, out io.Writer) error {
+	store := NewStore(vault, DockerPrefix, "stored by docker credential helper")
+	switch operation {
+	case "get":
+		serverURL, err := readServerURL(in)
+		if err != nil {
+			return err
+		}
+		c, err := store.Find(serverURL, "")
+		if errors.Is(err, constants.ErrGetData) {
+			return ErrCredentialsNotFound
+		}
+		if err != nil {
+			return err
+		}
+		return json.NewEncoder(out).Encode(&DockerCredential{ServerURL: serverURL, Username: c.Username, Secret: c.Secret})
+	case "store":
+		c := &DockerCredential{}
+		if err := json.NewDecoder(in).Decode(c); err != nil {
+			return fmt.Errorf("invalid credential: %v", err)
+		}
+		if c.ServerURL == "" {
+			return errors.New("credential without server url")
+		}
+		// Docker keeps a single credential per server.
+		return store.Put(Credential{URL: c.ServerURL, Username: c.Username, Secret: c.Secret})
+	case "erase":
+		serverURL, err := readServerURL(in)
+		if err != nil {
+			return err
+		}
+		err = store.Erase(serverURL, "")
+		if errors.Is(err, constants.ErrGetData) {
+			return ErrCredentialsNotFound
+		}
+		return err
+	case "list":
+		creds, err := store.List()
+		if err != nil {
+			return err
+		}
+		list := make(map[string]string, len(creds))
+		for _, c := range creds {
+			list[c.URL] = c.Username
+		}
+		return json.NewEncoder(out).Encode(list)
+	}
+	return fmt.Errorf("unknown docker credential operation %q", operation)
+}
+
+// readServerURL reads the server URL sent as plain text.
+func readServerURL(in io.Reader) (string, error) {
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	serverURL := strings.TrimSpace(line)
+	if serverURL == "" {
+		return "", errors.New("missing server url")
+	}
+	return serverURL, nil
+}
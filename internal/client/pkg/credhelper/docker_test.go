@@ -0,0 +1,60 @@
+package credhelper
+
+import (
+	"bytes"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestDocker(t *testing.T) {
+	v := newFakeVault()
+	run := func(operation, input string) (string, error) {
+		out := &bytes.Buffer{}
+		err := Docker(v, operation, strings.NewReader(input), out)
+		return out.String(), err
+	}
+
+	_, err := run("get", "https://index.docker.io/v1/\n")
+	assert.ErrorIs(t, err, ErrCredentialsNotFound)
+
+	_, err = run("store", `{"ServerURL":"https://index.docker.io/v1/","Username":"whale","Secret":"dckr_pat_1"}`)
+	require.NoError(t, err)
+	id := v.ID("docker:https://index.docker.io/v1")
+	assert.NotEqual(t, uuid.Nil, id, "items are named by the server url only")
+	_, err = run("store", `{"ServerURL":"https://index.docker.io/v1/","Username":"orca","Secret":"dckr_pat_2"}`)
+	require.NoError(t, err)
+	assert.Equal(t, 1, v.Len(), "a server keeps a single credential")
+	assert.Equal(t, id, v.ID("docker:https://index.docker.io/v1"), "the stored item is updated in place")
+	item, err := v.GetItem("docker:https://index.docker.io/v1")
+	require.NoError(t, err)
+	meta, _ := item.Field("meta")
+	assert.Equal(t, "stored by docker credential helper", meta)
+
+	out, err := run("get", "https://index.docker.io/v1/")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"ServerURL":"https://index.docker.io/v1/","Username":"orca","Secret":"dckr_pat_2"}`, out)
+
+	addCred(t, v, "registry", models.Credentials{Login: "ci", Password: "token", URL: "registry.example.com"})
+	addCred(t, v, "unrelated", models.Credentials{Login: "me", Password: "pw"})
+	out, err = run("get", "registry.example.com")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"ServerURL":"registry.example.com","Username":"ci","Secret":"token"}`, out)
+
+	out, err = run("list", "")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"https://index.docker.io/v1/":"orca","registry.example.com":"ci"}`, out, "every credential get finds is listed")
+
+	_, err = run("erase", "https://index.docker.io/v1/\n")
+	require.NoError(t, err)
+	_, err = run("erase", "https://index.docker.io/v1/\n")
+	assert.ErrorIs(t, err, ErrCredentialsNotFound)
+
+	_, err = run("store", `{"Username":"whale"}`)
+	assert.Error(t, err)
+	_, err = run("version", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,113 @@
+package credhelper
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/Mldlr/storety/internal/constants"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// GitPrefix is the name prefix of items stored by the git credential helper.
+const GitPrefix = "git:"
+
+// GitRequest holds the attributes of a git credential helper request.
+type GitRequest struct {
+	Protocol string
+	Host     string
+	Path     string
+	Username string
+	Password string
+}
+
+// URL returns the URL described by the request.
+func (r *GitRequest) URL() string {
+	u := url.URL{Scheme: r.Protocol, Host: r.Host}
+	if r.Path != "" {
+		u.Path = "/" + strings.TrimPrefix(r.Path, "/")
+	}
+	return u.String()
+}
+
+// ReadGitRequest reads the key=value lines of a request up to an empty line or the end of input.
+// A url attribute is expanded into the protocol, host, path and username attributes. Unknown attributes are ignored.
+func ReadGitRequest(r io.Reader) (*GitRequest, error) {
+	req := &GitRequest{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if line == "" {
+			break
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid credential attribute %q", line)
+		}
+		switch key {
+		case "protocol":
+			req.Protocol = value
+		case "host":
+			req.Host = value
+		case "path":
+			req.Path = value
+		case "username":
+			req.Username = value
+		case "password":
+			req.Password = value
+		case "url":
+			u, err := url.Parse(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid credential url: %v", err)
+			}
+			req.Protocol, req.Host, req.Path = u.Scheme, u.Host, strings.TrimPrefix(u.Path, "/")
+			if u.User != nil {
+				req.Username = u.User.Username()
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if req.Protocol == "" || req.Host == "" {
+		return nil, errors.New("credential request without protocol or host")
+	}
+	return req, nil
+}
+
+// Git serves the git credential helper operations get, store and erase.
+// See gitcredentials(7) for the protocol.
+func Git(vault Vault, operation string, in io.Reader, out io.Writer) error {
+	req, err := ReadGitRequest(in)
+	if err != nil {
+		return err
+	}
+	store := NewStore(vault, GitPrefix, "stored by git credential helper")
+	switch operation {
+	case "get":
+		c, err := store.Find(req.URL(), req.Username)
+		if errors.Is(err, constants.ErrGetData) {
+			// Git asks the next helper or the user when nothing is returned.
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, "protocol=%s\nhost=%s\nusername=%s\npassword=%s\n", req.Protocol, req.Host, c.Username, c.Secret)
+		return err
+	case "store":
+		if req.Username == "" || req.Password == "" {
+			return errors.New("credential to store without username or password")
+		}
+		return store.Save(Credential{URL: req.URL(), Username: req.Username, Secret: req.Password})
+	case "erase":
+		err = store.Erase(req.URL(), req.Username)
+		if errors.Is(err, constants.ErrGetData) {
+			return nil
+		}
+		return err
+	}
+	// Helpers silently ignore operations they do not know.
+	return nil
+}
@@ -0,0 +1,54 @@
+package credhelper
+
+import (
+	"bytes"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestReadGitRequest(t *testing.T) {
+	req, err := ReadGitRequest(strings.NewReader("protocol=https\nhost=github.com\npath=org/repo.git\nwwwauth[]=Basic\n\nignored=1\n"))
+	require.NoError(t, err)
+	assert.Equal(t, &GitRequest{Protocol: "https", Host: "github.com", Path: "org/repo.git"}, req)
+	assert.Equal(t, "https://github.com/org/repo.git", req.URL())
+
+	req, err = ReadGitRequest(strings.NewReader("url=https://alice@gitlab.com/group/project\n"))
+	require.NoError(t, err)
+	assert.Equal(t, &GitRequest{Protocol: "https", Host: "gitlab.com", Path: "group/project", Username: "alice"}, req)
+
+	_, err = ReadGitRequest(strings.NewReader("host=github.com\n"))
+	assert.Error(t, err)
+}
+
+func TestGit(t *testing.T) {
+	v := newFakeVault()
+	run := func(operation, input string) string {
+		out := &bytes.Buffer{}
+		require.NoError(t, Git(v, operation, strings.NewReader(input), out))
+		return out.String()
+	}
+
+	assert.Empty(t, run("get", "protocol=https\nhost=github.com\n\n"), "unknown credential yields no answer")
+
+	run("store", "protocol=https\nhost=github.com\nusername=octocat\npassword=ghp_token\n\n")
+	_, typ, err := v.GetData("git:https://octocat@github.com")
+	require.NoError(t, err)
+	assert.Equal(t, "Cred", typ)
+
+	assert.Equal(t, "protocol=https\nhost=github.com\nusername=octocat\npassword=ghp_token\n",
+		run("get", "protocol=https\nhost=github.com\n\n"))
+
+	run("erase", "protocol=https\nhost=github.com\nusername=octocat\npassword=ghp_token\n\n")
+	assert.Zero(t, v.Len())
+	assert.Empty(t, run("erase", "protocol=https\nhost=github.com\n\n"), "erasing a missing credential succeeds")
+	assert.Empty(t, run("capabilities", "protocol=https\nhost=github.com\n\n"), "unknown operations are ignored")
+
+	addCred(t, v, "work", models.Credentials{Login: "ci", Password: "pat", URL: "https://git.example.com"})
+	assert.Contains(t, run("get", "protocol=https\nhost=git.example.com\npath=team/app.git\n"), "password=pat\n")
+
+	err = Git(v, "store", strings.NewReader("protocol=https\nhost=github.com\nusername=octocat\n"), &bytes.Buffer{})
+	assert.Error(t, err, "store needs a password")
+}
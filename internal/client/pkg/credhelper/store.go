@@ -0,0 +1,249 @@
+// Package credhelper implements credential helper protocols of external tools on top of the vault.
+// Credentials are stored as Cred items carrying the URL of the service they belong to.
+package credhelper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/itemtype"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/Mldlr/storety/internal/constants"
+	"net/url"
+	"strings"
+)
+
+// Vault is the part of the vault service used by the credential helpers.
+type Vault interface {
+	ListData() ([]models.DataInfo, error)
+	GetItem(name string) (*models.Item, error)
+	GetData(name string) ([]byte, string, error)
+	CreateData(name, typ string, content []byte) error
+	UpdateData(name string, content []byte) error
+	DeleteData(name string) error
+}
+
+// Credential is a username and secret stored for a URL.
+type Credential struct {
+	// Name is the name of the item holding the credential.
+	Name     string
+	URL      string
+	Username string
+	Secret   string
+}
+
+// Store finds and saves credentials in Cred items.
+// Items are looked up by their url field first and by the naming convention <prefix><url> second,
+// where the url may carry the username, like git:https://alice@github.com.
+type Store struct {
+	vault  Vault
+	prefix string
+	meta   string
+}
+
+// NewStore creates a Store naming new items with the prefix and describing them with the meta text.
+func NewStore(vault Vault, prefix, meta string) *Store {
+	return &Store{vault: vault, prefix: prefix, meta: meta}
+}
+
+// Find returns the credential for the URL, restricted to the username if one is given.
+// It returns constants.ErrGetData if there is none.
+func (s *Store) Find(rawURL, username string) (*Credential, error) {
+	target, err := parseURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	creds, err := s.credentials()
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range creds {
+		if c.URL == "" {
+			continue
+		}
+		u, err := parseURL(c.URL)
+		if err == nil && matches(u, target) && (username == "" || c.Username == username) {
+			return c, nil
+		}
+	}
+	names := []string{s.itemName(target, "")}
+	if username != "" {
+		names = append([]string{s.itemName(target, username)}, names...)
+	}
+	for _, c := range creds {
+		for _, name := range names {
+			if c.Name == name && (username == "" || c.Username == username) {
+				return c, nil
+			}
+		}
+	}
+	return nil, constants.ErrGetData
+}
+
+// List returns the credential Find returns for every stored URL when no username is given,
+// matching Cred items by their url field first and by the name <prefix><url> second.
+// Credentials found by name get the URL of the name.
+func (s *Store) List() ([]*Credential, error) {
+	creds, err := s.credentials()
+	if err != nil {
+		return nil, err
+	}
+	var list []*Credential
+	seen := map[string]bool{}
+	add := func(u *url.URL, c *Credential) {
+		if !seen[u.String()] {
+			seen[u.String()] = true
+			list = append(list, c)
+		}
+	}
+	for _, c := range creds {
+		if c.URL == "" {
+			continue
+		}
+		if u, err := parseURL(c.URL); err == nil {
+			add(u, c)
+		}
+	}
+	for _, c := range creds {
+		if c.URL != "" || !strings.HasPrefix(c.Name, s.prefix) {
+			continue
+		}
+		u, err := parseURL(strings.TrimPrefix(c.Name, s.prefix))
+		if err != nil || c.Name != s.itemName(u, "") {
+			continue
+		}
+		c.URL = u.String()
+		add(u, c)
+	}
+	return list, nil
+}
+
+// Save stores the credential. A stored credential of the same URL and username is updated in place,
+// keeping its name, other fields and custom fields; otherwise a new item is created with the URL kept as given,
+// so tools comparing URLs literally find it again.
+// The item is written through the data service, so it is synced like any other item.
+func (s *Store) Save(c Credential) error {
+	return s.save(c, c.Username)
+}
+
+// Put stores the credential as the single credential of the URL, like Save,
+// but updates a stored credential of the URL in place whatever its username is,
+// and names a new item by the URL only, so a change of the username keeps using the same item.
+func (s *Store) Put(c Credential) error {
+	return s.save(c, "")
+}
+
+// save updates the stored credential found for the URL and username in place,
+// or creates a new item named by the URL and username.
+func (s *Store) save(c Credential, username string) error {
+	target, err := parseURL(c.URL)
+	if err != nil {
+		return err
+	}
+	stored, err := s.Find(c.URL, username)
+	if err == nil {
+		return s.update(stored.Name, c)
+	}
+	if !errors.Is(err, constants.ErrGetData) {
+		return err
+	}
+	content, err := json.Marshal(&models.Credentials{
+		Login:    c.Username,
+		Password: c.Secret,
+		URL:      c.URL,
+		Meta:     s.meta,
+	})
+	if err != nil {
+		return err
+	}
+	return s.vault.CreateData(s.itemName(target, username), itemtype.Cred, content)
+}
+
+// update sets the login and password of the credential in the stored item with the name.
+func (s *Store) update(name string, c Credential) error {
+	content, typ, err := s.vault.GetData(name)
+	if err != nil {
+		return err
+	}
+	t, ok := itemtype.Lookup(typ)
+	if !ok {
+		return fmt.Errorf("unknown item type %s", typ)
+	}
+	updated, err := t.Update(content, itemtype.Values{"login": c.Username, "password": c.Secret})
+	if err != nil {
+		return err
+	}
+	return s.vault.UpdateData(name, updated)
+}
+
+// Erase deletes the credential for the URL and username.
+// It returns constants.ErrGetData if there is none.
+func (s *Store) Erase(rawURL, username string) error {
+	c, err := s.Find(rawURL, username)
+	if err != nil {
+		return err
+	}
+	return s.vault.DeleteData(c.Name)
+}
+
+// credentials decodes the stored Cred items.
+func (s *Store) credentials() ([]*Credential, error) {
+	list, err := s.vault.ListData()
+	if err != nil {
+		return nil, err
+	}
+	var creds []*Credential
+	for _, info := range list {
+		if info.Type != itemtype.Cred {
+			continue
+		}
+		item, err := s.vault.GetItem(info.Name)
+		if err != nil {
+			return nil, err
+		}
+		c := &Credential{Name: item.Name}
+		c.Username, _ = item.Field("login")
+		c.Secret, _ = item.Field("password")
+		c.URL, _ = item.Field("url")
+		creds = append(creds, c)
+	}
+	return creds, nil
+}
+
+// itemName returns the conventional item name for the URL and username.
+func (s *Store) itemName(u *url.URL, username string) string {
+	named := *u
+	if username != "" {
+		named.User = url.User(username)
+	}
+	return s.prefix + named.String()
+}
+
+// parseURL parses and normalizes a service URL. URLs without a scheme are taken as https.
+// The scheme and host are lower cased, a trailing slash of the path is dropped and user info is removed.
+func parseURL(raw string) (*url.URL, error) {
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	if u.Host == "" {
+		return nil, errors.New("url without host")
+	}
+	return &url.URL{
+		Scheme: strings.ToLower(u.Scheme),
+		Host:   strings.ToLower(u.Host),
+		Path:   strings.TrimSuffix(u.Path, "/"),
+	}, nil
+}
+
+// matches reports whether a stored URL serves the requested one.
+// Paths are compared only if both URLs have one.
+func matches(stored, requested *url.URL) bool {
+	if stored.Scheme != requested.Scheme || stored.Host != requested.Host {
+		return false
+	}
+	return stored.Path == "" || requested.Path == "" || stored.Path == requested.Path
+}
@@ -0,0 +1,128 @@
+package credhelper
+
+import (
+	"encoding/json"
+	"github.com/Mldlr/storety/internal/client/itemtype"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/Mldlr/storety/internal/client/service/vault"
+	"github.com/Mldlr/storety/internal/client/service/vault/vaulttest"
+	"github.com/Mldlr/storety/internal/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// newFakeVault returns an empty vault decoding items like the vault service.
+func newFakeVault() *vaulttest.Fake {
+	return vaulttest.New(vault.DecodeItem)
+}
+
+// addCred stores a Cred item directly.
+func addCred(t *testing.T, v *vaulttest.Fake, name string, cred models.Credentials) {
+	content, err := json.Marshal(&cred)
+	require.NoError(t, err)
+	require.NoError(t, v.CreateData(name, "Cred", content))
+}
+
+func TestStore_Find(t *testing.T) {
+	v := newFakeVault()
+	addCred(t, v, "github token", models.Credentials{Login: "octocat", Password: "ghp_1", URL: "https://GitHub.com/"})
+	addCred(t, v, "git:https://gitlab.com", models.Credentials{Login: "alice", Password: "glpat_1"})
+	addCred(t, v, "git:https://bob@gitlab.com", models.Credentials{Login: "bob", Password: "glpat_2"})
+	addCred(t, v, "repo token", models.Credentials{Login: "ci", Password: "ghp_2", URL: "https://example.com/org/repo"})
+	store := NewStore(v, GitPrefix, "")
+	tests := []struct {
+		name     string
+		url      string
+		username string
+		want     string
+		wantErr  error
+	}{
+		{name: "By url field", url: "https://github.com", want: "ghp_1"},
+		{name: "By url field with path", url: "https://github.com/org/repo", want: "ghp_1"},
+		{name: "By url field with username", url: "https://github.com", username: "octocat", want: "ghp_1"},
+		{name: "By url field with other username", url: "https://github.com", username: "mallory", wantErr: constants.ErrGetData},
+		{name: "By name", url: "https://gitlab.com", want: "glpat_1"},
+		{name: "By name with username", url: "https://gitlab.com", username: "bob", want: "glpat_2"},
+		{name: "Stored path must match", url: "https://example.com/org/other", wantErr: constants.ErrGetData},
+		{name: "Other scheme", url: "http://github.com", wantErr: constants.ErrGetData},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := store.Find(tt.url, tt.username)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, c.Secret)
+		})
+	}
+}
+
+func TestStore_List(t *testing.T) {
+	v := newFakeVault()
+	addCred(t, v, "github token", models.Credentials{Login: "octocat", Password: "ghp_1", URL: "https://GitHub.com/"})
+	addCred(t, v, "git:https://github.com", models.Credentials{Login: "other", Password: "ghp_2"})
+	addCred(t, v, "git:https://gitlab.com", models.Credentials{Login: "alice", Password: "glpat_1"})
+	addCred(t, v, "git:https://bob@bitbucket.org", models.Credentials{Login: "bob", Password: "bb_1"})
+	addCred(t, v, "notes", models.Credentials{Login: "me", Password: "pw"})
+	store := NewStore(v, GitPrefix, "")
+
+	creds, err := store.List()
+	require.NoError(t, err)
+	listed := map[string]string{}
+	for _, c := range creds {
+		listed[c.URL] = c.Secret
+		found, err := store.Find(c.URL, "")
+		require.NoError(t, err)
+		assert.Equal(t, found.Name, c.Name, "List returns what Find returns")
+	}
+	assert.Equal(t, map[string]string{"https://GitHub.com/": "ghp_1", "https://gitlab.com": "glpat_1"}, listed)
+}
+
+func TestStore_SaveAndErase(t *testing.T) {
+	v := newFakeVault()
+	store := NewStore(v, GitPrefix, "stored by test")
+	require.NoError(t, store.Save(Credential{URL: "https://github.com", Username: "octocat", Secret: "old"}))
+	id := v.ID("git:https://octocat@github.com")
+	require.NoError(t, store.Save(Credential{URL: "https://github.com", Username: "octocat", Secret: "new"}))
+	assert.Equal(t, 1, v.Len(), "credential of the same url and user is replaced")
+	assert.Equal(t, id, v.ID("git:https://octocat@github.com"), "the item is updated, not created again")
+	item, err := v.GetItem("git:https://octocat@github.com")
+	require.NoError(t, err)
+	password, _ := item.Field("password")
+	assert.Equal(t, "new", password)
+
+	creds, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, creds, 1)
+	assert.Equal(t, "https://github.com", creds[0].URL)
+
+	require.NoError(t, store.Erase("https://github.com", "octocat"))
+	assert.Zero(t, v.Len())
+	assert.ErrorIs(t, store.Erase("https://github.com", "octocat"), constants.ErrGetData)
+}
+
+func TestStore_SaveUpdatesInPlace(t *testing.T) {
+	v := newFakeVault()
+	addCred(t, v, "github token", models.Credentials{Login: "octocat", Password: "old", URL: "https://github.com", Meta: "work account", OTP: "github-2fa"})
+	content, typ, err := v.GetData("github token")
+	require.NoError(t, err)
+	cred, _ := itemtype.Lookup(typ)
+	content, err = cred.SetCustomFields(content, []models.CustomField{{Name: "recovery", Value: "codes", Kind: itemtype.FieldText}})
+	require.NoError(t, err)
+	v.Put("github token", typ, content)
+	id := v.ID("github token")
+	store := NewStore(v, GitPrefix, "stored by test")
+
+	require.NoError(t, store.Save(Credential{URL: "https://github.com", Username: "octocat", Secret: "new"}))
+	assert.Equal(t, 1, v.Len(), "no new item is created")
+	assert.Equal(t, id, v.ID("github token"), "the item keeps its ID")
+	item, err := v.GetItem("github token")
+	require.NoError(t, err)
+	for field, want := range map[string]string{"login": "octocat", "password": "new", "url": "https://github.com", "meta": "work account", "otp": "github-2fa", "recovery": "codes"} {
+		value, _ := item.Field(field)
+		assert.Equal(t, want, value, field)
+	}
+}
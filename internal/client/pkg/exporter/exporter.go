@@ -0,0 +1,151 @@
+// Package exporter writes the items of the vault in plaintext formats read by other password managers.
+// Items are decoded into the fields of their type; file contents are not embedded but returned as
+// attachments, which the caller writes next to the export and the export refers to by their relative path.
+package exporter
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/itemtype"
+	"github.com/Mldlr/storety/internal/client/models"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Format is a plaintext export format.
+type Format string
+
+// Supported export formats.
+const (
+	CSV        Format = "csv"
+	JSON       Format = "json"
+	KeePassXML Format = "keepass-xml"
+)
+
+// Formats are the supported export formats.
+var Formats = []Format{CSV, JSON, KeePassXML}
+
+// ErrUnknownFormat is returned for formats that are not supported.
+var ErrUnknownFormat = errors.New("unknown export format")
+
+// contentField is the field holding the raw content of items of types unknown to this client.
+const contentField = "content"
+
+// Attachment is a file content of an item, written to the sidecar directory of the export.
+type Attachment struct {
+	// Path is the path of the file relative to the sidecar directory.
+	Path string
+	Data []byte
+}
+
+// field is a decoded field of an item.
+type field struct {
+	Name   string
+	Value  string
+	Secret bool
+}
+
+// record is an item decoded for export.
+type record struct {
+	Name   string
+	Type   string
+	Fields []field
+	Custom []models.CustomField
+}
+
+// value returns the value of the named field.
+func (r *record) value(name string) string {
+	for _, f := range r.Fields {
+		if f.Name == name {
+			return f.Value
+		}
+	}
+	return ""
+}
+
+// Write writes the items to w in the format and returns the attachments the export refers to.
+// The export refers to attachments by their path joined to dir, the sidecar directory relative to the export.
+func Write(format Format, w io.Writer, data []models.Data, dir string) ([]Attachment, error) {
+	records, attachments, err := decode(data, dir)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case CSV:
+		err = writeCSV(w, records)
+	case JSON:
+		err = writeJSON(w, records)
+	case KeePassXML:
+		err = writeKeePass(w, records)
+	default:
+		names := make([]string, len(Formats))
+		for i, f := range Formats {
+			names[i] = string(f)
+		}
+		return nil, fmt.Errorf("%w %q, must be one of %s", ErrUnknownFormat, format, strings.Join(names, ", "))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// unsafeChars matches the characters replaced in attachment file names.
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// decode decodes the items into records, moving file contents to attachments.
+// Items of types unknown to this client keep their raw content in the content field.
+func decode(data []models.Data, dir string) ([]record, []Attachment, error) {
+	records := make([]record, 0, len(data))
+	var attachments []Attachment
+	taken := map[string]bool{}
+	for _, d := range data {
+		r := record{Name: d.Name, Type: d.Type}
+		t, ok := itemtype.Lookup(d.Type)
+		if !ok {
+			r.Fields = []field{{Name: contentField, Value: string(d.Content), Secret: true}}
+			records = append(records, r)
+			continue
+		}
+		values, err := t.Decode(d.Content)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to decode %s: %w", d.Name, err)
+		}
+		if r.Custom, err = itemtype.CustomFields(d.Content); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode %s: %w", d.Name, err)
+		}
+		for _, f := range t.Fields {
+			value := values[f.Name]
+			if f.Kind == itemtype.Bytes && value != "" {
+				content, err := base64.StdEncoding.DecodeString(value)
+				if err != nil {
+					return nil, nil, fmt.Errorf("failed to decode %s: %w", d.Name, err)
+				}
+				p := attachmentPath(d.Name, taken)
+				attachments = append(attachments, Attachment{Path: p, Data: content})
+				value = path.Join(dir, p)
+			}
+			r.Fields = append(r.Fields, field{Name: f.Name, Value: value, Secret: f.Secret})
+		}
+		records = append(records, r)
+	}
+	return records, attachments, nil
+}
+
+// attachmentPath returns a unique file name for an attachment of the named item.
+func attachmentPath(name string, taken map[string]bool) string {
+	base := strings.Trim(unsafeChars.ReplaceAllString(name, "_"), "._")
+	if base == "" {
+		base = "attachment"
+	}
+	p := base
+	for n := 2; taken[p]; n++ {
+		ext := path.Ext(base)
+		p = fmt.Sprintf("%s-%d%s", strings.TrimSuffix(base, ext), n, ext)
+	}
+	taken[p] = true
+	return p
+}
@@ -0,0 +1,111 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/base64"
+	"github.com/Mldlr/storety/internal/client/itemtype"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/Mldlr/storety/internal/client/pkg/importer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// testData returns a Cred with a hidden custom field, a Text, two Binary items with the same file name and an item of an unknown type.
+func testData(t *testing.T) []models.Data {
+	cred, _ := itemtype.Lookup(itemtype.Cred)
+	credContent, err := cred.Encode(itemtype.Values{"login": "bob", "password": "secret", "url": "https://example.com", "meta": "work"})
+	require.NoError(t, err)
+	credContent, err = cred.SetCustomFields(credContent, []models.CustomField{{Name: "pin", Value: "1234", Kind: itemtype.FieldHidden}})
+	require.NoError(t, err)
+	text, _ := itemtype.Lookup(itemtype.Text)
+	textContent, err := text.Encode(itemtype.Values{"text": "hello", "meta": "note"})
+	require.NoError(t, err)
+	binary, _ := itemtype.Lookup(itemtype.Binary)
+	blob := base64.StdEncoding.EncodeToString([]byte{0, 1, 2})
+	binaryContent, err := binary.Encode(itemtype.Values{"blob": blob, "meta": ""})
+	require.NoError(t, err)
+	return []models.Data{
+		{Name: "github", Type: itemtype.Cred, Content: credContent},
+		{Name: "memo", Type: itemtype.Text, Content: textContent},
+		{Name: "id/key.bin", Type: itemtype.Binary, Content: binaryContent},
+		{Name: "id:key.bin", Type: itemtype.Binary, Content: binaryContent},
+		{Name: "future", Type: "Passkey", Content: []byte(`{"credential":"x"}`)},
+	}
+}
+
+func TestWrite_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	attachments, err := Write(JSON, &buf, testData(t), "vault.json.attachments")
+	require.NoError(t, err)
+	assert.Equal(t, []Attachment{{Path: "id_key.bin", Data: []byte{0, 1, 2}}, {Path: "id_key-2.bin", Data: []byte{0, 1, 2}}}, attachments)
+	assert.JSONEq(t, `{"items": [
+		{"name": "github", "type": "Cred", "fields": [
+			{"name": "login", "value": "bob"}, {"name": "password", "value": "secret"},
+			{"name": "url", "value": "https://example.com"}, {"name": "otp", "value": ""}, {"name": "meta", "value": "work"}],
+		 "custom_fields": [{"name": "pin", "value": "1234", "kind": "hidden"}]},
+		{"name": "memo", "type": "Text", "fields": [{"name": "text", "value": "hello"}, {"name": "meta", "value": "note"}]},
+		{"name": "id/key.bin", "type": "Binary", "fields": [{"name": "blob", "value": "vault.json.attachments/id_key.bin"}, {"name": "meta", "value": ""}]},
+		{"name": "id:key.bin", "type": "Binary", "fields": [{"name": "blob", "value": "vault.json.attachments/id_key-2.bin"}, {"name": "meta", "value": ""}]},
+		{"name": "future", "type": "Passkey", "fields": [{"name": "content", "value": "{\"credential\":\"x\"}"}]}
+	]}`, buf.String())
+}
+
+func TestWrite_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	_, err := Write(CSV, &buf, testData(t)[:2], "attachments")
+	require.NoError(t, err)
+	assert.Equal(t, "name,type,field:login,field:password,field:url,field:otp,field:meta,field:text,field:pin\n"+
+		"github,Cred,bob,secret,https://example.com,,work,,1234\n"+
+		"memo,Text,,,,,note,hello,\n", buf.String())
+
+	entries, err := importer.Parse(importer.GenericCSV, &buf)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, itemtype.Values{"login": "bob", "password": "secret", "url": "https://example.com", "otp": "", "meta": "work"}, entries[0].Values)
+	assert.Equal(t, []models.CustomField{{Name: "pin", Value: "1234", Kind: itemtype.FieldText}}, entries[0].Custom)
+	assert.Equal(t, itemtype.Values{"text": "hello", "meta": "note"}, entries[1].Values)
+}
+
+func TestWrite_CSVCard(t *testing.T) {
+	card, _ := itemtype.Lookup(itemtype.Card)
+	values := itemtype.Values{"number": "4111111111111111", "expires": "12/30", "cvv": "123", "name": "Alice", "surname": "Smith", "meta": "personal"}
+	content, err := card.Encode(values)
+	require.NoError(t, err)
+	content, err = card.SetCustomFields(content, []models.CustomField{{Name: "type", Value: "debit", Kind: itemtype.FieldText}})
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	_, err = Write(CSV, &buf, []models.Data{{Name: "visa", Type: itemtype.Card, Content: content}}, "attachments")
+	require.NoError(t, err)
+
+	entries, err := importer.Parse(importer.GenericCSV, &buf)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "visa", entries[0].Name, "the holder name does not overwrite the item name")
+	assert.Equal(t, itemtype.Card, entries[0].Type, "a custom type field does not overwrite the item type")
+	assert.Equal(t, values, entries[0].Values)
+	assert.Equal(t, []models.CustomField{{Name: "type", Value: "debit", Kind: itemtype.FieldText}}, entries[0].Custom)
+}
+
+func TestWrite_KeePass(t *testing.T) {
+	var buf bytes.Buffer
+	_, err := Write(KeePassXML, &buf, testData(t)[:2], "attachments")
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), `<Tags>Cred</Tags>`)
+	assert.Contains(t, buf.String(), `<Value ProtectInMemory="true">secret</Value>`)
+
+	entries, err := importer.Parse(importer.KeePassXML, &buf)
+	require.NoError(t, err)
+	assert.Equal(t, []importer.Entry{
+		{Name: "github", Type: itemtype.Cred,
+			Values: itemtype.Values{"login": "bob", "password": "secret", "url": "https://example.com", "meta": "work"},
+			Custom: []models.CustomField{{Name: "pin", Value: "1234", Kind: itemtype.FieldHidden}}},
+		{Name: "memo", Type: itemtype.Text, Values: itemtype.Values{"text": "hello", "meta": ""},
+			Custom: []models.CustomField{{Name: "meta 2", Value: "note", Kind: itemtype.FieldText}}},
+	}, entries)
+}
+
+func TestWrite_UnknownFormat(t *testing.T) {
+	_, err := Write("xlsx", &bytes.Buffer{}, nil, "")
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
@@ -0,0 +1,173 @@
+package exporter
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"github.com/Mldlr/storety/internal/client/itemtype"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/Mldlr/storety/internal/client/pkg/importer"
+	"io"
+)
+
+// writeCSV writes the records with a name and a type column followed by a column for every field and custom field,
+// in the layout read by the generic-csv import. Field columns are named with importer.FieldPrefix,
+// so fields named name or type keep their own columns. Fields missing in an item are left empty.
+func writeCSV(w io.Writer, records []record) error {
+	columns := []string{"name", "type"}
+	index := map[string]int{}
+	addColumn := func(name string) {
+		if _, ok := index[name]; !ok {
+			index[name] = len(columns)
+			columns = append(columns, importer.FieldPrefix+name)
+		}
+	}
+	for _, r := range records {
+		for _, f := range r.Fields {
+			addColumn(f.Name)
+		}
+	}
+	for _, r := range records {
+		for _, f := range r.Custom {
+			addColumn(f.Name)
+		}
+	}
+	out := csv.NewWriter(w)
+	if err := out.Write(columns); err != nil {
+		return err
+	}
+	for _, r := range records {
+		row := make([]string, len(columns))
+		row[0], row[1] = r.Name, r.Type
+		for _, f := range r.Fields {
+			row[index[f.Name]] = f.Value
+		}
+		for _, f := range r.Custom {
+			row[index[f.Name]] = f.Value
+		}
+		if err := out.Write(row); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// jsonExport is the layout of JSON exports.
+type jsonExport struct {
+	Items []jsonItem `json:"items"`
+}
+
+// jsonItem is an item of a JSON export, with its fields in display order.
+type jsonItem struct {
+	Name         string               `json:"name"`
+	Type         string               `json:"type"`
+	Fields       []models.Field       `json:"fields"`
+	CustomFields []models.CustomField `json:"custom_fields,omitempty"`
+}
+
+// writeJSON writes the records as a JSON document.
+func writeJSON(w io.Writer, records []record) error {
+	export := jsonExport{Items: make([]jsonItem, len(records))}
+	for i, r := range records {
+		item := jsonItem{Name: r.Name, Type: r.Type, Fields: make([]models.Field, len(r.Fields)), CustomFields: r.Custom}
+		for j, f := range r.Fields {
+			item.Fields[j] = models.Field{Name: f.Name, Value: f.Value}
+		}
+		export.Items[i] = item
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(export)
+}
+
+// keePassOut is the XML export of KeePass 2 written for the records.
+type keePassOut struct {
+	XMLName   xml.Name `xml:"KeePassFile"`
+	Generator string   `xml:"Meta>Generator"`
+	Group     struct {
+		UUID    string            `xml:"UUID"`
+		Name    string            `xml:"Name"`
+		Entries []keePassOutEntry `xml:"Entry"`
+	} `xml:"Root>Group"`
+}
+
+// keePassOutEntry is an entry of the KeePass export.
+type keePassOutEntry struct {
+	UUID    string             `xml:"UUID"`
+	Tags    string             `xml:"Tags"`
+	Strings []keePassOutString `xml:"String"`
+}
+
+// keePassOutString is a string field of a KeePass entry.
+type keePassOutString struct {
+	Key   string `xml:"Key"`
+	Value struct {
+		Protected bool   `xml:"ProtectInMemory,attr,omitempty"`
+		Text      string `xml:",chardata"`
+	} `xml:"Value"`
+}
+
+// keePassKeys maps fields of the items to the standard KeePass strings.
+var keePassKeys = map[string]string{
+	"login":    "UserName",
+	"password": "Password",
+	"url":      "URL",
+}
+
+// writeKeePass writes the records as a KeePass 2 XML export with one group holding every item.
+// The item type is kept in the tags of the entry. Text items keep their text in the notes,
+// other items their meta field; the remaining fields and custom fields become additional strings,
+// protected when secret or hidden.
+func writeKeePass(w io.Writer, records []record) error {
+	export := keePassOut{Generator: "storety"}
+	export.Group.UUID = keePassUUID("storety")
+	export.Group.Name = "storety"
+	for _, r := range records {
+		entry := keePassOutEntry{UUID: keePassUUID(r.Name), Tags: r.Type}
+		add := func(key, value string, protected bool) {
+			s := keePassOutString{Key: key}
+			s.Value.Text = value
+			s.Value.Protected = protected
+			entry.Strings = append(entry.Strings, s)
+		}
+		add("Title", r.Name, false)
+		notes := "meta"
+		if r.Type == itemtype.Text {
+			notes = "text"
+		}
+		add("Notes", r.value(notes), false)
+		for _, f := range r.Fields {
+			if f.Name == notes {
+				continue
+			}
+			key, ok := keePassKeys[f.Name]
+			if !ok {
+				key = f.Name
+			}
+			add(key, f.Value, f.Secret)
+		}
+		for _, f := range r.Custom {
+			add(f.Name, f.Value, f.Kind == itemtype.FieldHidden)
+		}
+		export.Group.Entries = append(export.Group.Entries, entry)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "\t")
+	if err := enc.Encode(export); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// keePassUUID derives the UUID of an entry from its name, which is unique within the vault.
+func keePassUUID(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return base64.StdEncoding.EncodeToString(sum[:16])
+}
@@ -0,0 +1,122 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/itemtype"
+	"io"
+)
+
+// Bitwarden item and custom field types.
+const (
+	bitwardenLogin    = 1
+	bitwardenNote     = 2
+	bitwardenCard     = 3
+	bitwardenIdentity = 4
+
+	bitwardenFieldText    = 0
+	bitwardenFieldHidden  = 1
+	bitwardenFieldBoolean = 2
+)
+
+// ErrEncryptedExport is returned for exports encrypted by the password manager.
+var ErrEncryptedExport = errors.New("encrypted exports are not supported, export unencrypted JSON")
+
+// bitwardenExport is the unencrypted JSON export of Bitwarden.
+type bitwardenExport struct {
+	Encrypted bool            `json:"encrypted"`
+	Items     []bitwardenItem `json:"items"`
+}
+
+// bitwardenItem is an item of a Bitwarden export.
+type bitwardenItem struct {
+	Type   int    `json:"type"`
+	Name   string `json:"name"`
+	Notes  string `json:"notes"`
+	Fields []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+		Type  int    `json:"type"`
+	} `json:"fields"`
+	Login *struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		TOTP     string `json:"totp"`
+		URIs     []struct {
+			URI string `json:"uri"`
+		} `json:"uris"`
+	} `json:"login"`
+	Card *struct {
+		CardholderName string `json:"cardholderName"`
+		Number         string `json:"number"`
+		ExpMonth       string `json:"expMonth"`
+		ExpYear        string `json:"expYear"`
+		Code           string `json:"code"`
+	} `json:"card"`
+	Identity map[string]*string `json:"identity"`
+}
+
+// identityFields are the fields of Bitwarden identities in display order.
+var identityFields = []string{
+	"title", "firstName", "middleName", "lastName", "company", "email", "phone",
+	"address1", "address2", "address3", "city", "state", "postalCode", "country",
+	"username", "ssn", "passportNumber", "licenseNumber",
+}
+
+// parseBitwarden reads a Bitwarden JSON export.
+// Logins become Cred items, cards Card items, and secure notes and identities Text items.
+func parseBitwarden(r io.Reader) ([]Entry, error) {
+	var export bitwardenExport
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return nil, fmt.Errorf("failed to parse bitwarden export: %w", err)
+	}
+	if export.Encrypted {
+		return nil, ErrEncryptedExport
+	}
+	entries := make([]Entry, 0, len(export.Items))
+	for _, item := range export.Items {
+		var e Entry
+		switch {
+		case item.Type == bitwardenLogin && item.Login != nil:
+			var url string
+			if len(item.Login.URIs) > 0 {
+				url = item.Login.URIs[0].URI
+			}
+			e = credential(item.Name, item.Login.Username, item.Login.Password, url, item.Notes)
+			for i := 1; i < len(item.Login.URIs); i++ {
+				e.addCustom("website", item.Login.URIs[i].URI, itemtype.FieldText)
+			}
+			e.addCustom("totp", item.Login.TOTP, itemtype.FieldHidden)
+		case item.Type == bitwardenCard && item.Card != nil:
+			name, surname := splitName(item.Card.CardholderName)
+			e = Entry{Name: item.Name, Type: itemtype.Card, Values: itemtype.Values{
+				"number":  item.Card.Number,
+				"expires": expires(item.Card.ExpMonth, item.Card.ExpYear),
+				"cvv":     item.Card.Code,
+				"name":    name,
+				"surname": surname,
+				"meta":    item.Notes,
+			}}
+		case item.Type == bitwardenIdentity:
+			e = note(item.Name, item.Notes)
+			for _, field := range identityFields {
+				if value := item.Identity[field]; value != nil {
+					e.addCustom(field, *value, itemtype.FieldText)
+				}
+			}
+		default:
+			e = note(item.Name, item.Notes)
+		}
+		for _, f := range item.Fields {
+			switch f.Type {
+			case bitwardenFieldText, bitwardenFieldBoolean:
+				e.addCustom(f.Name, f.Value, itemtype.FieldText)
+			case bitwardenFieldHidden:
+				e.addCustom(f.Name, f.Value, itemtype.FieldHidden)
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
@@ -0,0 +1,267 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/itemtype"
+	"io"
+	"strings"
+	"time"
+)
+
+// lastPassNoteURL is the URL LastPass exports for secure notes.
+const lastPassNoteURL = "http://sn"
+
+// FieldPrefix starts the names of the field columns of generic CSV files written by the vault export.
+const FieldPrefix = "field:"
+
+// csvTable is a CSV file with a header line.
+type csvTable struct {
+	columns []string
+	rows    []map[string]string
+}
+
+// readCSV reads a CSV file whose first line names the columns. Column names are lower cased.
+func readCSV(r io.Reader) (*csvTable, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse csv: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("csv file is empty")
+	}
+	table := &csvTable{}
+	for i, column := range records[0] {
+		if i == 0 {
+			column = strings.TrimPrefix(column, "\ufeff")
+		}
+		table.columns = append(table.columns, strings.ToLower(strings.TrimSpace(column)))
+	}
+	for _, record := range records[1:] {
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		row := map[string]string{}
+		for i, value := range record {
+			if i < len(table.columns) {
+				row[table.columns[i]] = value
+			}
+		}
+		table.rows = append(table.rows, row)
+	}
+	return table, nil
+}
+
+// require checks that the table has the columns.
+func (t *csvTable) require(columns ...string) error {
+	for _, column := range columns {
+		found := false
+		for _, c := range t.columns {
+			found = found || c == column
+		}
+		if !found {
+			return fmt.Errorf("csv has no %s column", column)
+		}
+	}
+	return nil
+}
+
+// first returns the value of the first of the columns present in the row.
+func first(row map[string]string, columns ...string) string {
+	for _, column := range columns {
+		if value, ok := row[column]; ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// addOtherColumns adds the columns of the row not in known as custom text fields.
+func (e *Entry) addOtherColumns(t *csvTable, row map[string]string, known ...string) {
+	skip := map[string]bool{}
+	for _, column := range known {
+		skip[column] = true
+	}
+	for _, column := range t.columns {
+		if !skip[column] {
+			e.addCustom(column, row[column], itemtype.FieldText)
+		}
+	}
+}
+
+// parseOnePassword reads a 1Password CSV export. Rows become Cred items.
+func parseOnePassword(r io.Reader) ([]Entry, error) {
+	table, err := readCSV(r)
+	if err != nil {
+		return nil, err
+	}
+	if err = table.require("title"); err != nil {
+		return nil, err
+	}
+	entries := make([]Entry, 0, len(table.rows))
+	for _, row := range table.rows {
+		e := credential(row["title"], row["username"], row["password"], first(row, "url", "website"), first(row, "notes", "notesplain"))
+		e.addCustom("totp", first(row, "otpauth", "one-time password"), itemtype.FieldHidden)
+		e.addOtherColumns(table, row, "title", "username", "password", "url", "website", "notes", "notesplain",
+			"otpauth", "one-time password", "favorite", "archived")
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// parseLastPass reads a LastPass CSV export.
+// Sites become Cred items, credit card notes Card items and other secure notes Text items.
+func parseLastPass(r io.Reader) ([]Entry, error) {
+	table, err := readCSV(r)
+	if err != nil {
+		return nil, err
+	}
+	if err = table.require("name", "url", "extra"); err != nil {
+		return nil, err
+	}
+	entries := make([]Entry, 0, len(table.rows))
+	for _, row := range table.rows {
+		var e Entry
+		if row["url"] == lastPassNoteURL {
+			e = lastPassNote(row["name"], row["extra"])
+		} else {
+			e = credential(row["name"], row["username"], row["password"], row["url"], row["extra"])
+			e.addCustom("totp", row["totp"], itemtype.FieldHidden)
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// lastPassNote maps a LastPass secure note to a Card item for credit cards and a Text item otherwise.
+// Card notes hold "Key:Value" lines, starting with "NoteType:Credit Card".
+func lastPassNote(name, extra string) Entry {
+	if !strings.HasPrefix(extra, "NoteType:Credit Card") {
+		return note(name, extra)
+	}
+	fields := map[string]string{}
+	var notes []string
+	inNotes := false
+	for _, line := range strings.Split(extra, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		switch {
+		case inNotes:
+			notes = append(notes, line)
+		case ok && key == "Notes":
+			inNotes = true
+			notes = append(notes, value)
+		case ok:
+			fields[key] = value
+		}
+	}
+	holder, surname := splitName(fields["Name on Card"])
+	e := Entry{Name: name, Type: itemtype.Card, Values: itemtype.Values{
+		"number":  fields["Number"],
+		"expires": lastPassExpires(fields["Expiration Date"]),
+		"cvv":     fields["Security Code"],
+		"name":    holder,
+		"surname": surname,
+		"meta":    strings.Join(notes, "\n"),
+	}}
+	e.addCustom("card type", fields["Type"], itemtype.FieldText)
+	e.addCustom("start date", fields["Start Date"], itemtype.FieldText)
+	return e
+}
+
+// lastPassExpires converts an expiration date like "June,2025" to MM/YY, keeping other values as they are.
+func lastPassExpires(value string) string {
+	month, year, ok := strings.Cut(value, ",")
+	if !ok {
+		return value
+	}
+	t, err := time.Parse("January", strings.TrimSpace(month))
+	if err != nil {
+		return value
+	}
+	return expires(fmt.Sprint(int(t.Month())), year)
+}
+
+// fields returns the table of the columns with the prefix, without the prefix.
+// It returns nil if no column has the prefix.
+func (t *csvTable) fields(prefix string) *csvTable {
+	fields := &csvTable{}
+	for _, column := range t.columns {
+		if strings.HasPrefix(column, prefix) {
+			fields.columns = append(fields.columns, strings.TrimPrefix(column, prefix))
+		}
+	}
+	if len(fields.columns) == 0 {
+		return nil
+	}
+	for _, row := range t.rows {
+		values := map[string]string{}
+		for column, value := range row {
+			if strings.HasPrefix(column, prefix) {
+				values[strings.TrimPrefix(column, prefix)] = value
+			}
+		}
+		fields.rows = append(fields.rows, values)
+	}
+	return fields
+}
+
+// parseGeneric reads a CSV file with a name column and an optional type column, Cred by default.
+// Columns named after fields of the type set them, with notes accepted for meta,
+// and the other columns become custom text fields.
+// If some columns start with FieldPrefix, like in CSV exports of the vault, only those hold fields,
+// so fields named name or type do not clash with the name and type of the item.
+func parseGeneric(r io.Reader) ([]Entry, error) {
+	table, err := readCSV(r)
+	if err != nil {
+		return nil, err
+	}
+	if err = table.require("name"); err != nil {
+		return nil, err
+	}
+	fields := table.fields(FieldPrefix)
+	var itemColumns []string
+	if fields == nil {
+		fields = table
+		itemColumns = []string{"name", "type"}
+	}
+	entries := make([]Entry, 0, len(table.rows))
+	for i, row := range table.rows {
+		typ := strings.TrimSpace(row["type"])
+		if typ == "" {
+			typ = itemtype.Cred
+		}
+		values := fields.rows[i]
+		e := Entry{Name: row["name"], Type: typ, Values: itemtype.Values{}}
+		known := append([]string{}, itemColumns...)
+		if t, ok := lookupType(typ); ok {
+			e.Type = t.Name
+			for _, f := range t.Fields {
+				column := strings.ToLower(f.Name)
+				if value, ok := values[column]; ok {
+					e.Values[f.Name] = value
+				} else if f.Name == "meta" {
+					e.Values[f.Name] = values["notes"]
+					known = append(known, "notes")
+				}
+				known = append(known, column)
+			}
+		}
+		e.addOtherColumns(fields, values, known...)
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// lookupType finds a registered type by its name or command, ignoring case.
+func lookupType(name string) (*itemtype.Type, bool) {
+	for _, t := range itemtype.All() {
+		if strings.EqualFold(t.Name, name) || strings.EqualFold(t.Command, name) {
+			return t, true
+		}
+	}
+	return nil, false
+}
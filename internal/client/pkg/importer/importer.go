@@ -0,0 +1,154 @@
+// Package importer reads the exports of other password managers into items of the vault.
+// Entries are mapped to the fields of the Cred, Card, Text and Binary types;
+// fields without a counterpart are kept as custom fields of the item.
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/itemtype"
+	"github.com/Mldlr/storety/internal/client/models"
+	"io"
+	"strings"
+)
+
+// Format is the format of an export file.
+type Format string
+
+// Supported export formats.
+const (
+	BitwardenJSON  Format = "bitwarden-json"
+	KeePassXML     Format = "keepass-xml"
+	OnePasswordCSV Format = "1password-csv"
+	LastPassCSV    Format = "lastpass-csv"
+	GenericCSV     Format = "generic-csv"
+)
+
+// Formats are the supported export formats.
+var Formats = []Format{BitwardenJSON, KeePassXML, OnePasswordCSV, LastPassCSV, GenericCSV}
+
+// ErrUnknownFormat is returned for formats that are not supported.
+var ErrUnknownFormat = errors.New("unknown import format")
+
+// Entry is an item read from an export.
+type Entry struct {
+	Name   string
+	Type   string
+	Values itemtype.Values
+	Custom []models.CustomField
+}
+
+// Content validates the entry and encodes it as the content of an item of its type.
+func (e *Entry) Content() ([]byte, error) {
+	t, ok := itemtype.Lookup(e.Type)
+	if !ok {
+		return nil, fmt.Errorf("unknown item type %s", e.Type)
+	}
+	content, err := t.Encode(e.Values)
+	if err != nil {
+		return nil, err
+	}
+	if len(e.Custom) == 0 {
+		return content, nil
+	}
+	return t.SetCustomFields(content, e.Custom)
+}
+
+// addCustom appends a custom field if the value is not empty.
+// Names taken by a field of the type or by an earlier custom field get a number appended.
+func (e *Entry) addCustom(name, value, kind string) {
+	if strings.TrimSpace(value) == "" {
+		return
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "field"
+	}
+	taken := map[string]bool{}
+	if t, ok := itemtype.Lookup(e.Type); ok {
+		for _, f := range t.Fields {
+			taken[strings.ToLower(f.Name)] = true
+		}
+	}
+	for _, f := range e.Custom {
+		taken[strings.ToLower(f.Name)] = true
+	}
+	unique := name
+	for n := 2; taken[strings.ToLower(unique)]; n++ {
+		unique = fmt.Sprintf("%s %d", name, n)
+	}
+	e.Custom = append(e.Custom, models.CustomField{Name: unique, Value: value, Kind: kind})
+}
+
+// Parse reads the entries of an export in the format.
+func Parse(format Format, r io.Reader) ([]Entry, error) {
+	switch format {
+	case BitwardenJSON:
+		return parseBitwarden(r)
+	case KeePassXML:
+		return parseKeePass(r)
+	case OnePasswordCSV:
+		return parseOnePassword(r)
+	case LastPassCSV:
+		return parseLastPass(r)
+	case GenericCSV:
+		return parseGeneric(r)
+	}
+	return nil, fmt.Errorf("%w %q, must be one of %s", ErrUnknownFormat, format, formatList())
+}
+
+// formatList returns the supported formats separated by commas.
+func formatList() string {
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return strings.Join(names, ", ")
+}
+
+// credential creates a Cred entry, moving values the url field does not accept to a custom field.
+func credential(name, login, password, url, notes string) Entry {
+	e := Entry{Name: name, Type: itemtype.Cred, Values: itemtype.Values{
+		"login":    login,
+		"password": password,
+		"meta":     notes,
+	}}
+	if url != "" {
+		t, _ := itemtype.Lookup(itemtype.Cred)
+		if t.Validate(itemtype.Values{"url": url}) == nil {
+			e.Values["url"] = url
+		} else {
+			e.addCustom("website", url, itemtype.FieldText)
+		}
+	}
+	return e
+}
+
+// note creates a Text entry.
+func note(name, text string) Entry {
+	return Entry{Name: name, Type: itemtype.Text, Values: itemtype.Values{"text": text, "meta": ""}}
+}
+
+// splitName splits a card holder name into the name and the surname at the last space.
+func splitName(holder string) (string, string) {
+	holder = strings.TrimSpace(holder)
+	if i := strings.LastIndex(holder, " "); i > 0 {
+		return holder[:i], holder[i+1:]
+	}
+	return holder, ""
+}
+
+// expires formats the expiry month and year of a card as MM/YY.
+func expires(month, year string) string {
+	month, year = strings.TrimSpace(month), strings.TrimSpace(year)
+	if month == "" && year == "" {
+		return ""
+	}
+	if len(month) == 1 {
+		month = "0" + month
+	}
+	if len(year) == 4 {
+		year = year[2:]
+	}
+	return month + "/" + year
+}
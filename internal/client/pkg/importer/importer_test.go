@@ -0,0 +1,252 @@
+package importer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/Mldlr/storety/internal/client/itemtype"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+const bitwardenExample = `{
+  "encrypted": false,
+  "items": [
+    {"type": 1, "name": "mail", "notes": "work",
+     "fields": [{"name": "pin", "value": "1234", "type": 1}, {"name": "linked", "value": null, "type": 3}],
+     "login": {"username": "bob", "password": "secret", "totp": "JBSWY3DPEHPK3PXP",
+               "uris": [{"uri": "https://mail.example.com"}, {"uri": "https://example.com"}]}},
+    {"type": 2, "name": "wifi", "notes": "guest password"},
+    {"type": 3, "name": "visa", "notes": "",
+     "card": {"cardholderName": "John Ronald Doe", "number": "4111111111111111", "expMonth": "3", "expYear": "2027", "code": "123"}},
+    {"type": 4, "name": "me", "notes": "", "identity": {"firstName": "John", "lastName": "Doe", "email": null}}
+  ]
+}`
+
+func TestParse_Bitwarden(t *testing.T) {
+	entries, err := Parse(BitwardenJSON, strings.NewReader(bitwardenExample))
+	require.NoError(t, err)
+	assert.Equal(t, []Entry{
+		{Name: "mail", Type: itemtype.Cred,
+			Values: itemtype.Values{"login": "bob", "password": "secret", "url": "https://mail.example.com", "meta": "work"},
+			Custom: []models.CustomField{
+				{Name: "website", Value: "https://example.com", Kind: itemtype.FieldText},
+				{Name: "totp", Value: "JBSWY3DPEHPK3PXP", Kind: itemtype.FieldHidden},
+				{Name: "pin", Value: "1234", Kind: itemtype.FieldHidden},
+			}},
+		{Name: "wifi", Type: itemtype.Text, Values: itemtype.Values{"text": "guest password", "meta": ""}},
+		{Name: "visa", Type: itemtype.Card, Values: itemtype.Values{
+			"number": "4111111111111111", "expires": "03/27", "cvv": "123", "name": "John Ronald", "surname": "Doe", "meta": ""}},
+		{Name: "me", Type: itemtype.Text, Values: itemtype.Values{"text": "", "meta": ""},
+			Custom: []models.CustomField{
+				{Name: "firstName", Value: "John", Kind: itemtype.FieldText},
+				{Name: "lastName", Value: "Doe", Kind: itemtype.FieldText},
+			}},
+	}, entries)
+}
+
+func TestParse_BitwardenEncrypted(t *testing.T) {
+	_, err := Parse(BitwardenJSON, strings.NewReader(`{"encrypted": true, "items": []}`))
+	assert.ErrorIs(t, err, ErrEncryptedExport)
+}
+
+func TestParse_KeePass(t *testing.T) {
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	gz.Write([]byte("attached"))
+	gz.Close()
+	export := `<?xml version="1.0" encoding="utf-8" standalone="yes"?>
+<KeePassFile>
+  <Meta>
+    <RecycleBinEnabled>True</RecycleBinEnabled>
+    <RecycleBinUUID>bin</RecycleBinUUID>
+    <Binaries><Binary ID="0" Compressed="True">` + base64.StdEncoding.EncodeToString(compressed.Bytes()) + `</Binary></Binaries>
+  </Meta>
+  <Root>
+    <Group>
+      <UUID>root</UUID><Name>Database</Name>
+      <Entry>
+        <String><Key>Title</Key><Value>bank</Value></String>
+        <String><Key>UserName</Key><Value>bob</Value></String>
+        <String><Key>Password</Key><Value ProtectInMemory="True">secret</Value></String>
+        <String><Key>URL</Key><Value>https://bank.example.com</Value></String>
+        <String><Key>Notes</Key><Value>main account</Value></String>
+        <String><Key>PIN</Key><Value ProtectInMemory="True">9876</Value></String>
+        <String><Key>login</Key><Value>alias</Value></String>
+        <Binary><Key>card.txt</Key><Value Ref="0"/></Binary>
+        <History><Entry><String><Key>Title</Key><Value>old</Value></String></Entry></History>
+      </Entry>
+      <Group>
+        <UUID>notes</UUID><Name>Notes</Name>
+        <Entry><String><Key>Title</Key><Value>recovery</Value></String><String><Key>Notes</Key><Value>codes</Value></String></Entry>
+      </Group>
+      <Group>
+        <UUID>bin</UUID><Name>Recycle Bin</Name>
+        <Entry><String><Key>Title</Key><Value>deleted</Value></String></Entry>
+      </Group>
+    </Group>
+  </Root>
+</KeePassFile>`
+	entries, err := Parse(KeePassXML, strings.NewReader(export))
+	require.NoError(t, err)
+	assert.Equal(t, []Entry{
+		{Name: "bank", Type: itemtype.Cred,
+			Values: itemtype.Values{"login": "bob", "password": "secret", "url": "https://bank.example.com", "meta": "main account"},
+			Custom: []models.CustomField{
+				{Name: "PIN", Value: "9876", Kind: itemtype.FieldHidden},
+				{Name: "login 2", Value: "alias", Kind: itemtype.FieldText},
+			}},
+		{Name: "bank/card.txt", Type: itemtype.Binary, Values: itemtype.Values{
+			"blob": base64.StdEncoding.EncodeToString([]byte("attached")), "meta": "attachment of bank"}},
+		{Name: "recovery", Type: itemtype.Text, Values: itemtype.Values{"text": "codes", "meta": ""}},
+	}, entries)
+}
+
+func TestParse_OnePassword(t *testing.T) {
+	export := "\ufeffTitle,Url,Username,Password,OTPAuth,Favorite,Archived,Tags,Notes\n" +
+		"github,https://github.com,bob,secret,otpauth://totp/GitHub:bob?secret=JBSWY3DPEHPK3PXP,true,false,dev,\n" +
+		"router,192.168.0.1,admin,admin,,false,false,,\"multi\nline\"\n"
+	entries, err := Parse(OnePasswordCSV, strings.NewReader(export))
+	require.NoError(t, err)
+	assert.Equal(t, []Entry{
+		{Name: "github", Type: itemtype.Cred,
+			Values: itemtype.Values{"login": "bob", "password": "secret", "url": "https://github.com", "meta": ""},
+			Custom: []models.CustomField{
+				{Name: "totp", Value: "otpauth://totp/GitHub:bob?secret=JBSWY3DPEHPK3PXP", Kind: itemtype.FieldHidden},
+				{Name: "tags", Value: "dev", Kind: itemtype.FieldText},
+			}},
+		{Name: "router", Type: itemtype.Cred,
+			Values: itemtype.Values{"login": "admin", "password": "admin", "meta": "multi\nline"},
+			Custom: []models.CustomField{{Name: "website", Value: "192.168.0.1", Kind: itemtype.FieldText}}},
+	}, entries)
+}
+
+func TestParse_LastPass(t *testing.T) {
+	export := "url,username,password,totp,extra,name,grouping,fav\n" +
+		"https://example.com,bob,secret,,note,example,Work,0\n" +
+		"http://sn,,,,just text,memo,,0\n" +
+		"http://sn,,,,\"NoteType:Credit Card\nLanguage:en-US\nName on Card:John Doe\nType:Visa\nNumber:4111111111111111\n" +
+		"Security Code:123\nStart Date:,\nExpiration Date:June,2027\nNotes:line one\nline two\",visa,,0\n"
+	entries, err := Parse(LastPassCSV, strings.NewReader(export))
+	require.NoError(t, err)
+	assert.Equal(t, []Entry{
+		{Name: "example", Type: itemtype.Cred,
+			Values: itemtype.Values{"login": "bob", "password": "secret", "url": "https://example.com", "meta": "note"}},
+		{Name: "memo", Type: itemtype.Text, Values: itemtype.Values{"text": "just text", "meta": ""}},
+		{Name: "visa", Type: itemtype.Card,
+			Values: itemtype.Values{"number": "4111111111111111", "expires": "06/27", "cvv": "123",
+				"name": "John", "surname": "Doe", "meta": "line one\nline two"},
+			Custom: []models.CustomField{
+				{Name: "card type", Value: "Visa", Kind: itemtype.FieldText},
+				{Name: "start date", Value: ",", Kind: itemtype.FieldText},
+			}},
+	}, entries)
+}
+
+func TestParse_Generic(t *testing.T) {
+	export := "name,type,login,password,url,notes,text,folder\n" +
+		"site,,bob,secret,https://example.com,first,,web\n" +
+		"memo,text,,,,,hello,\n" +
+		"thing,Unknown,,,,,,\n"
+	entries, err := Parse(GenericCSV, strings.NewReader(export))
+	require.NoError(t, err)
+	assert.Equal(t, []Entry{
+		{Name: "site", Type: itemtype.Cred,
+			Values: itemtype.Values{"login": "bob", "password": "secret", "url": "https://example.com", "meta": "first"},
+			Custom: []models.CustomField{{Name: "folder", Value: "web", Kind: itemtype.FieldText}}},
+		{Name: "memo", Type: itemtype.Text, Values: itemtype.Values{"text": "hello", "meta": ""}},
+		{Name: "thing", Type: "Unknown", Values: itemtype.Values{}},
+	}, entries)
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		input  string
+	}{
+		{name: "unknown format", format: "xlsx", input: ""},
+		{name: "bad json", format: BitwardenJSON, input: "{"},
+		{name: "bad xml", format: KeePassXML, input: "<KeePassFile>"},
+		{name: "empty csv", format: GenericCSV, input: ""},
+		{name: "missing column", format: GenericCSV, input: "login,password\nbob,secret\n"},
+		{name: "not lastpass", format: LastPassCSV, input: "title,username\nx,y\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.format, strings.NewReader(tt.input))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestPlan(t *testing.T) {
+	entries := []Entry{
+		note("memo", "one"),
+		note("memo", "two"),
+		note("taken", "three"),
+		note("  ", "four"),
+		{Name: "bad", Type: itemtype.Cred, Values: itemtype.Values{"url": "not a url"}},
+	}
+	existing := []models.DataInfo{{Name: "taken", Type: itemtype.Text}, {Name: "taken (2)", Type: itemtype.Text}}
+	tests := []struct {
+		policy   DuplicatePolicy
+		names    []string
+		statuses []string
+		imported int
+		wantErr  error
+	}{
+		{
+			policy:   Skip,
+			names:    []string{"memo", "memo", "taken", untitled, "bad"},
+			statuses: []string{StatusNew, StatusDuplicate, StatusDuplicate, StatusNew, StatusInvalid},
+			imported: 2,
+		},
+		{
+			policy:   Rename,
+			names:    []string{"memo", "memo (2)", "taken (3)", untitled, "bad"},
+			statuses: []string{StatusNew, StatusRenamed, StatusRenamed, StatusNew, StatusInvalid},
+			imported: 4,
+		},
+		{policy: Fail, wantErr: ErrDuplicate},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			results, err := Plan(entries, existing, tt.policy)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			var names, statuses []string
+			for _, r := range results {
+				names = append(names, r.Name)
+				statuses = append(statuses, r.Status)
+			}
+			assert.Equal(t, tt.names, names)
+			assert.Equal(t, tt.statuses, statuses)
+			assert.Len(t, Data(results), tt.imported)
+		})
+	}
+	_, err := Plan(entries, nil, "overwrite")
+	assert.Error(t, err)
+}
+
+func TestEntry_Content(t *testing.T) {
+	e := credential("mail", "bob", "secret", "https://mail.example.com", "")
+	e.addCustom("pin", "1234", itemtype.FieldHidden)
+	content, err := e.Content()
+	require.NoError(t, err)
+	var got map[string]interface{}
+	require.NoError(t, json.Unmarshal(content, &got))
+	assert.Equal(t, "bob", got["login"])
+	assert.Equal(t, float64(itemtype.SchemaVersion), got["schema"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"name": "pin", "value": "1234", "kind": "hidden"}}, got["custom_fields"])
+
+	_, err = (&Entry{Name: "x", Type: "Unknown"}).Content()
+	assert.Error(t, err)
+}
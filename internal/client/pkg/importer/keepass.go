@@ -0,0 +1,157 @@
+package importer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/itemtype"
+	"io"
+	"strings"
+)
+
+// keePassFile is the XML export of KeePass 2.
+type keePassFile struct {
+	Meta struct {
+		RecycleBinEnabled bool   `xml:"RecycleBinEnabled"`
+		RecycleBinUUID    string `xml:"RecycleBinUUID"`
+		Binaries          []struct {
+			ID         string `xml:"ID,attr"`
+			Compressed bool   `xml:"Compressed,attr"`
+			Data       string `xml:",chardata"`
+		} `xml:"Binaries>Binary"`
+	} `xml:"Meta"`
+	Groups []keePassGroup `xml:"Root>Group"`
+}
+
+// keePassGroup is a group of entries, possibly nested.
+type keePassGroup struct {
+	UUID    string         `xml:"UUID"`
+	Name    string         `xml:"Name"`
+	Entries []keePassEntry `xml:"Entry"`
+	Groups  []keePassGroup `xml:"Group"`
+}
+
+// keePassEntry is an entry of a group. The entry history is not read.
+type keePassEntry struct {
+	Strings []struct {
+		Key   string `xml:"Key"`
+		Value struct {
+			Protected bool   `xml:"ProtectInMemory,attr"`
+			Text      string `xml:",chardata"`
+		} `xml:"Value"`
+	} `xml:"String"`
+	Binaries []struct {
+		Key   string `xml:"Key"`
+		Value struct {
+			Ref string `xml:"Ref,attr"`
+		} `xml:"Value"`
+	} `xml:"Binary"`
+}
+
+// keePass standard string fields.
+const (
+	keePassTitle    = "Title"
+	keePassUserName = "UserName"
+	keePassPassword = "Password"
+	keePassURL      = "URL"
+	keePassNotes    = "Notes"
+)
+
+// parseKeePass reads a KeePass 2 XML export.
+// Entries with a user name, password or URL become Cred items and the others Text items holding the notes.
+// Other strings become custom fields, hidden when protected, and attachments become Binary items named
+// after the entry and the file. Entries of the recycle bin are skipped.
+func parseKeePass(r io.Reader) ([]Entry, error) {
+	var file keePassFile
+	if err := xml.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse keepass export: %w", err)
+	}
+	binaries := map[string][]byte{}
+	for _, b := range file.Meta.Binaries {
+		data, err := keePassBinary(b.Data, b.Compressed)
+		if err != nil {
+			return nil, fmt.Errorf("attachment %s: %w", b.ID, err)
+		}
+		binaries[b.ID] = data
+	}
+	var recycleBin string
+	if file.Meta.RecycleBinEnabled {
+		recycleBin = file.Meta.RecycleBinUUID
+	}
+	var entries []Entry
+	var walk func(groups []keePassGroup)
+	walk = func(groups []keePassGroup) {
+		for _, g := range groups {
+			if recycleBin != "" && g.UUID == recycleBin {
+				continue
+			}
+			for _, entry := range g.Entries {
+				entries = append(entries, keePassEntries(entry, binaries)...)
+			}
+			walk(g.Groups)
+		}
+	}
+	walk(file.Groups)
+	return entries, nil
+}
+
+// keePassEntries maps an entry to an item followed by items for its attachments.
+func keePassEntries(entry keePassEntry, binaries map[string][]byte) []Entry {
+	values := map[string]string{}
+	for _, s := range entry.Strings {
+		values[s.Key] = s.Value.Text
+	}
+	title := values[keePassTitle]
+	var e Entry
+	if values[keePassUserName] == "" && values[keePassPassword] == "" && values[keePassURL] == "" {
+		e = note(title, values[keePassNotes])
+	} else {
+		e = credential(title, values[keePassUserName], values[keePassPassword], values[keePassURL], values[keePassNotes])
+	}
+	for _, s := range entry.Strings {
+		switch s.Key {
+		case keePassTitle, keePassUserName, keePassPassword, keePassURL, keePassNotes:
+			continue
+		}
+		kind := itemtype.FieldText
+		if s.Value.Protected {
+			kind = itemtype.FieldHidden
+		}
+		e.addCustom(s.Key, s.Value.Text, kind)
+	}
+	entries := []Entry{e}
+	for _, b := range entry.Binaries {
+		data, ok := binaries[b.Value.Ref]
+		if !ok {
+			continue
+		}
+		entries = append(entries, Entry{
+			Name: title + "/" + b.Key,
+			Type: itemtype.Binary,
+			Values: itemtype.Values{
+				"blob": base64.StdEncoding.EncodeToString(data),
+				"meta": "attachment of " + title,
+			},
+		})
+	}
+	return entries
+}
+
+// keePassBinary decodes an attachment of the export, which is base64 encoded and optionally gzip compressed.
+func keePassBinary(data string, compressed bool) ([]byte, error) {
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(data))
+	if err != nil {
+		return nil, err
+	}
+	if !compressed {
+		return decoded, nil
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(decoded))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	return io.ReadAll(gz)
+}
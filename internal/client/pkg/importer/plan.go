@@ -0,0 +1,106 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/models"
+	"strings"
+)
+
+// DuplicatePolicy decides what happens to entries whose name is already taken.
+type DuplicatePolicy string
+
+// Duplicate policies.
+const (
+	// Skip leaves entries with taken names out of the import.
+	Skip DuplicatePolicy = "skip"
+	// Rename imports entries with taken names under the name with a number appended.
+	Rename DuplicatePolicy = "rename"
+	// Fail stops the import if any name is taken.
+	Fail DuplicatePolicy = "fail"
+)
+
+// Statuses of planned entries.
+const (
+	StatusNew       = "new"
+	StatusRenamed   = "renamed"
+	StatusDuplicate = "duplicate"
+	StatusInvalid   = "invalid"
+)
+
+// untitled is the name of entries exported without one.
+const untitled = "Untitled"
+
+// ErrDuplicate is returned by Plan with the Fail policy when a name is taken.
+var ErrDuplicate = errors.New("item already exists")
+
+// Result is the outcome planned for an entry.
+type Result struct {
+	Entry   Entry
+	Name    string
+	Status  string
+	Err     error
+	Content []byte
+}
+
+// Imported reports whether the entry is imported.
+func (r *Result) Imported() bool {
+	return r.Status == StatusNew || r.Status == StatusRenamed
+}
+
+// Plan validates the entries and resolves their names against the existing items and each other.
+// Invalid entries are never imported, duplicates are handled according to the policy.
+func Plan(entries []Entry, existing []models.DataInfo, policy DuplicatePolicy) ([]Result, error) {
+	switch policy {
+	case Skip, Rename, Fail:
+	default:
+		return nil, fmt.Errorf("unknown duplicate policy %q, must be one of %s, %s, %s", policy, Skip, Rename, Fail)
+	}
+	taken := map[string]bool{}
+	for _, info := range existing {
+		taken[info.Name] = true
+	}
+	results := make([]Result, 0, len(entries))
+	for _, e := range entries {
+		r := Result{Entry: e, Name: strings.TrimSpace(e.Name), Status: StatusNew}
+		if r.Name == "" {
+			r.Name = untitled
+		}
+		r.Content, r.Err = e.Content()
+		if r.Err != nil {
+			r.Status = StatusInvalid
+			results = append(results, r)
+			continue
+		}
+		if taken[r.Name] {
+			switch policy {
+			case Fail:
+				return nil, fmt.Errorf("%w: %s", ErrDuplicate, r.Name)
+			case Skip:
+				r.Status = StatusDuplicate
+				results = append(results, r)
+				continue
+			case Rename:
+				base := r.Name
+				for n := 2; taken[r.Name]; n++ {
+					r.Name = fmt.Sprintf("%s (%d)", base, n)
+				}
+				r.Status = StatusRenamed
+			}
+		}
+		taken[r.Name] = true
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// Data returns the items of the imported entries, ready for a batch insert.
+func Data(results []Result) []models.Data {
+	var data []models.Data
+	for _, r := range results {
+		if r.Imported() {
+			data = append(data, models.Data{Name: r.Name, Type: r.Entry.Type, Content: r.Content})
+		}
+	}
+	return data
+}
@@ -0,0 +1,207 @@
+// Package otp implements time-based one-time passwords (RFC 6238) for OTP items.
+package otp
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/Mldlr/storety/internal/constants"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ItemType is the type of the data items holding OTP secrets.
+const ItemType = "OTP"
+
+// Defaults used when an otpauth URI leaves the parameters out.
+const (
+	DefaultAlgorithm = "SHA1"
+	DefaultDigits    = 6
+	DefaultPeriod    = 30
+)
+
+var (
+	// ErrInvalidURI is returned for URIs that are not valid otpauth://totp URIs.
+	ErrInvalidURI = errors.New("invalid otpauth uri")
+
+	// ErrInvalidSecret is returned for secrets that are not base32 encoded.
+	ErrInvalidSecret = errors.New("otp secret is not base32 encoded")
+)
+
+// Vault is the part of the vault service read to find the OTP of an item.
+type Vault interface {
+	GetData(name string) ([]byte, string, error)
+}
+
+// ParseURI parses an otpauth://totp URI as exported by authenticator apps into an OTP item.
+// The issuer parameter takes precedence over the issuer prefix of the label.
+func ParseURI(uri string) (*models.OTP, error) {
+	u, err := url.Parse(strings.TrimSpace(uri))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidURI, err)
+	}
+	if u.Scheme != "otpauth" {
+		return nil, fmt.Errorf("%w: scheme must be otpauth", ErrInvalidURI)
+	}
+	if u.Host != "totp" {
+		return nil, fmt.Errorf("%w: only totp is supported", ErrInvalidURI)
+	}
+	q := u.Query()
+	o := &models.OTP{
+		Secret:    normalizeSecret(q.Get("secret")),
+		Algorithm: strings.ToUpper(q.Get("algorithm")),
+		Digits:    DefaultDigits,
+		Period:    DefaultPeriod,
+	}
+	label := strings.TrimPrefix(u.Path, "/")
+	if issuer, account, ok := strings.Cut(label, ":"); ok {
+		o.Issuer = strings.TrimSpace(issuer)
+		o.Account = strings.TrimSpace(account)
+	} else {
+		o.Account = label
+	}
+	if issuer := q.Get("issuer"); issuer != "" {
+		o.Issuer = issuer
+	}
+	if o.Algorithm == "" {
+		o.Algorithm = DefaultAlgorithm
+	}
+	if digits := q.Get("digits"); digits != "" {
+		if o.Digits, err = strconv.Atoi(digits); err != nil {
+			return nil, fmt.Errorf("%w: digits must be a number", ErrInvalidURI)
+		}
+	}
+	if period := q.Get("period"); period != "" {
+		if o.Period, err = strconv.Atoi(period); err != nil {
+			return nil, fmt.Errorf("%w: period must be a number", ErrInvalidURI)
+		}
+	}
+	if err = Validate(o); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// Validate checks that the code of the OTP can be generated.
+func Validate(o *models.OTP) error {
+	if o.Secret == "" {
+		return fmt.Errorf("%w: secret is missing", ErrInvalidURI)
+	}
+	if _, err := decodeSecret(o.Secret); err != nil {
+		return err
+	}
+	if _, err := hashFunc(o.Algorithm); err != nil {
+		return err
+	}
+	if o.Digits < 6 || o.Digits > 8 {
+		return fmt.Errorf("%w: digits must be between 6 and 8", ErrInvalidURI)
+	}
+	if o.Period <= 0 {
+		return fmt.Errorf("%w: period must be positive", ErrInvalidURI)
+	}
+	return nil
+}
+
+// Code returns the code of the OTP valid at the time and how long it stays valid.
+func Code(o *models.OTP, t time.Time) (string, time.Duration, error) {
+	key, err := decodeSecret(o.Secret)
+	if err != nil {
+		return "", 0, err
+	}
+	h, err := hashFunc(o.Algorithm)
+	if err != nil {
+		return "", 0, err
+	}
+	digits, period := o.Digits, int64(o.Period)
+	if digits == 0 {
+		digits = DefaultDigits
+	}
+	if period == 0 {
+		period = DefaultPeriod
+	}
+	counter := t.Unix() / period
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(counter))
+	mac := hmac.New(h, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	// Dynamic truncation of RFC 4226 section 5.3.
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+	code := fmt.Sprintf("%0*d", digits, value%mod)
+	expires := time.Unix((counter+1)*period, 0)
+	return code, expires.Sub(t), nil
+}
+
+// Find returns the OTP of the named item, which is either an OTP item or a Cred item linking one.
+// It returns constants.ErrFieldNotFound for items without an OTP.
+func Find(vault Vault, name string) (*models.OTP, error) {
+	content, typ, err := vault.GetData(name)
+	if err != nil {
+		return nil, err
+	}
+	if typ == "Cred" {
+		cred := &models.Credentials{}
+		if err = json.Unmarshal(content, cred); err != nil {
+			return nil, err
+		}
+		if cred.OTP == "" {
+			return nil, fmt.Errorf("%w: %s has no linked otp", constants.ErrFieldNotFound, name)
+		}
+		content, typ, err = vault.GetData(cred.OTP)
+		if err != nil {
+			return nil, fmt.Errorf("linked otp %s of %s: %w", cred.OTP, name, err)
+		}
+		name = cred.OTP
+	}
+	if typ != ItemType {
+		return nil, fmt.Errorf("%w: %s is not an otp", constants.ErrFieldNotFound, name)
+	}
+	o := &models.OTP{}
+	if err = json.Unmarshal(content, o); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// normalizeSecret removes the spacing and padding authenticator apps add to base32 secrets.
+func normalizeSecret(secret string) string {
+	secret = strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	return strings.TrimRight(secret, "=")
+}
+
+// decodeSecret decodes a base32 secret with or without padding.
+func decodeSecret(secret string) ([]byte, error) {
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(normalizeSecret(secret))
+	if err != nil {
+		return nil, ErrInvalidSecret
+	}
+	return key, nil
+}
+
+// hashFunc returns the hash of the HMAC for the algorithm name.
+func hashFunc(algorithm string) (func() hash.Hash, error) {
+	switch strings.ToUpper(algorithm) {
+	case "", "SHA1":
+		return sha1.New, nil
+	case "SHA256":
+		return sha256.New, nil
+	case "SHA512":
+		return sha512.New, nil
+	}
+	return nil, fmt.Errorf("%w: unsupported algorithm %s", ErrInvalidURI, algorithm)
+}
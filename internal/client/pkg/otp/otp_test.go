@@ -0,0 +1,145 @@
+package otp
+
+import (
+	"encoding/base32"
+	"encoding/json"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/Mldlr/storety/internal/client/service/vault/vaulttest"
+	"github.com/Mldlr/storety/internal/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestCode(t *testing.T) {
+	// Test vectors of RFC 6238 appendix B.
+	seeds := map[string]string{
+		"SHA1":   "12345678901234567890",
+		"SHA256": "12345678901234567890123456789012",
+		"SHA512": "1234567890123456789012345678901234567890123456789012345678901234",
+	}
+	tests := []struct {
+		time      int64
+		algorithm string
+		want      string
+	}{
+		{time: 59, algorithm: "SHA1", want: "94287082"},
+		{time: 59, algorithm: "SHA256", want: "46119246"},
+		{time: 59, algorithm: "SHA512", want: "90693936"},
+		{time: 1111111109, algorithm: "SHA1", want: "07081804"},
+		{time: 1111111109, algorithm: "SHA256", want: "68084774"},
+		{time: 1111111109, algorithm: "SHA512", want: "25091201"},
+		{time: 20000000000, algorithm: "SHA1", want: "65353130"},
+		{time: 20000000000, algorithm: "SHA256", want: "77737706"},
+		{time: 20000000000, algorithm: "SHA512", want: "47863826"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.algorithm+"/"+tt.want, func(t *testing.T) {
+			o := &models.OTP{
+				Secret:    base32.StdEncoding.EncodeToString([]byte(seeds[tt.algorithm])),
+				Algorithm: tt.algorithm,
+				Digits:    8,
+				Period:    30,
+			}
+			code, remaining, err := Code(o, time.Unix(tt.time, 0))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, code)
+			assert.Equal(t, time.Duration(30-tt.time%30)*time.Second, remaining)
+		})
+	}
+}
+
+func TestCode_Defaults(t *testing.T) {
+	code, remaining, err := Code(&models.OTP{Secret: "gezd gnbv gy3t qojq gezd gnbv gy3t qojq"}, time.Unix(59, 0))
+	require.NoError(t, err)
+	assert.Equal(t, "287082", code)
+	assert.Equal(t, time.Second, remaining)
+}
+
+func TestParseURI(t *testing.T) {
+	tests := []struct {
+		name    string
+		uri     string
+		want    *models.OTP
+		wantErr bool
+	}{
+		{
+			name: "Defaults",
+			uri:  "otpauth://totp/GitHub:octocat?secret=JBSWY3DPEHPK3PXP",
+			want: &models.OTP{Secret: "JBSWY3DPEHPK3PXP", Issuer: "GitHub", Account: "octocat", Algorithm: "SHA1", Digits: 6, Period: 30},
+		},
+		{
+			name: "All parameters",
+			uri:  "otpauth://totp/Example%20Co:alice@example.com?secret=jbswy3dpehpk3pxp&issuer=Example&algorithm=sha256&digits=8&period=60",
+			want: &models.OTP{Secret: "JBSWY3DPEHPK3PXP", Issuer: "Example", Account: "alice@example.com", Algorithm: "SHA256", Digits: 8, Period: 60},
+		},
+		{
+			name: "Label without issuer",
+			uri:  "otpauth://totp/ci-bot?secret=JBSWY3DPEHPK3PXP",
+			want: &models.OTP{Secret: "JBSWY3DPEHPK3PXP", Account: "ci-bot", Algorithm: "SHA1", Digits: 6, Period: 30},
+		},
+		{name: "HOTP", uri: "otpauth://hotp/x?secret=JBSWY3DPEHPK3PXP&counter=1", wantErr: true},
+		{name: "Other scheme", uri: "https://example.com", wantErr: true},
+		{name: "Missing secret", uri: "otpauth://totp/x", wantErr: true},
+		{name: "Secret not base32", uri: "otpauth://totp/x?secret=not-base32!", wantErr: true},
+		{name: "Unsupported algorithm", uri: "otpauth://totp/x?secret=JBSWY3DPEHPK3PXP&algorithm=MD5", wantErr: true},
+		{name: "Too many digits", uri: "otpauth://totp/x?secret=JBSWY3DPEHPK3PXP&digits=12", wantErr: true},
+		{name: "Zero period", uri: "otpauth://totp/x?secret=JBSWY3DPEHPK3PXP&period=0", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseURI(tt.uri)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	seed := &models.OTP{Secret: "JBSWY3DPEHPK3PXP", Issuer: "GitHub"}
+	vault := vaulttest.New(nil)
+	for name, item := range map[string]struct {
+		typ     string
+		content interface{}
+	}{
+		"github-2fa": {typ: ItemType, content: seed},
+		"github":     {typ: "Cred", content: &models.Credentials{Login: "octocat", OTP: "github-2fa"}},
+		"gitlab":     {typ: "Cred", content: &models.Credentials{Login: "octocat"}},
+		"dangling":   {typ: "Cred", content: &models.Credentials{Login: "octocat", OTP: "missing"}},
+		"wrong-link": {typ: "Cred", content: &models.Credentials{Login: "octocat", OTP: "gitlab"}},
+		"motd":       {typ: "Text", content: &models.Text{Text: "hello"}},
+	} {
+		content, err := json.Marshal(item.content)
+		require.NoError(t, err)
+		vault.Put(name, item.typ, content)
+	}
+	tests := []struct {
+		name    string
+		item    string
+		wantErr error
+	}{
+		{name: "OTP item", item: "github-2fa"},
+		{name: "Linked from credentials", item: "github"},
+		{name: "Credentials without otp", item: "gitlab", wantErr: constants.ErrFieldNotFound},
+		{name: "Link to missing item", item: "dangling", wantErr: constants.ErrGetData},
+		{name: "Link to credentials", item: "wrong-link", wantErr: constants.ErrFieldNotFound},
+		{name: "Other type", item: "motd", wantErr: constants.ErrFieldNotFound},
+		{name: "Missing item", item: "missing", wantErr: constants.ErrGetData},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Find(vault, tt.item)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, seed, got)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package output
+
+import (
+	"errors"
+	"github.com/Mldlr/storety/internal/constants"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Exit codes of the client commands.
+const (
+	ExitOK       = 0
+	ExitError    = 1
+	ExitNotFound = 2
+	ExitAuth     = 3
+	ExitOffline  = 4
+	ExitConflict = 5
+	ExitFindings = 6
+)
+
+// kinds names the exit codes in printed errors.
+var kinds = map[int]string{
+	ExitOK:       "ok",
+	ExitError:    "error",
+	ExitNotFound: "not_found",
+	ExitAuth:     "auth",
+	ExitOffline:  "offline",
+	ExitConflict: "conflict",
+	ExitFindings: "findings",
+}
+
+// ExitCode returns the exit code for the error returned by a command.
+// Errors of local services are matched by their sentinels, errors of the server and the agent by their status codes.
+// Errors carrying an exit code of their own, like those of a child process, keep it.
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, constants.ErrDataExists), errors.Is(err, constants.ErrUserExists):
+		return ExitConflict
+	case errors.Is(err, constants.ErrGetData), errors.Is(err, constants.ErrDeleteData),
+		errors.Is(err, constants.ErrNoData), errors.Is(err, constants.ErrUserNotFound),
+		errors.Is(err, constants.ErrDeviceNotFound), errors.Is(err, constants.ErrFieldNotFound):
+		return ExitNotFound
+	case errors.Is(err, constants.ErrNotLoggedIn), errors.Is(err, constants.ErrInvalidCredentials),
+		errors.Is(err, constants.ErrAgentLocked):
+		return ExitAuth
+	case errors.Is(err, constants.ErrNoConnection), errors.Is(err, constants.ErrAgentNotRunning):
+		return ExitOffline
+	case errors.Is(err, constants.ErrAuditFindings):
+		return ExitFindings
+	}
+	var exitErr interface{ ExitCode() int }
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		return exitErr.ExitCode()
+	}
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &grpcErr) {
+		return ExitError
+	}
+	switch grpcErr.GRPCStatus().Code() {
+	case codes.NotFound:
+		return ExitNotFound
+	case codes.Unauthenticated, codes.PermissionDenied, codes.FailedPrecondition:
+		return ExitAuth
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return ExitOffline
+	case codes.AlreadyExists, codes.Aborted:
+		return ExitConflict
+	}
+	return ExitError
+}
@@ -0,0 +1,46 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Mldlr/storety/internal/constants"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "Success", err: nil, want: ExitOK},
+		{name: "Unknown error", err: errors.New("boom"), want: ExitError},
+		{name: "Missing local item", err: constants.ErrGetData, want: ExitNotFound},
+		{name: "Missing field", err: fmt.Errorf("%w: password", constants.ErrFieldNotFound), want: ExitNotFound},
+		{name: "Not logged in", err: constants.ErrNotLoggedIn, want: ExitAuth},
+		{name: "Wrong local password", err: fmt.Errorf("failed local login: %w", constants.ErrInvalidCredentials), want: ExitAuth},
+		{name: "Agent not running", err: constants.ErrAgentNotRunning, want: ExitOffline},
+		{name: "No server connection", err: constants.ErrNoConnection, want: ExitOffline},
+		{name: "Duplicate local item", err: errors.Join(constants.ErrCreateData, constants.ErrDataExists), want: ExitConflict},
+		{name: "Server unavailable", err: status.Error(codes.Unavailable, "connection refused"), want: ExitOffline},
+		{name: "Wrapped server denial", err: fmt.Errorf("failed to delete account: %w", status.Error(codes.PermissionDenied, "")), want: ExitAuth},
+		{name: "Agent missing item", err: status.Error(codes.NotFound, "unable to get data"), want: ExitNotFound},
+		{name: "Audit findings", err: fmt.Errorf("%w: 2 issues", constants.ErrAuditFindings), want: ExitFindings},
+		{name: "Child process exit code", err: childExit(7), want: 7},
+		{name: "Server duplicate user", err: status.Error(codes.AlreadyExists, "username taken"), want: ExitConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ExitCode(tt.err))
+		})
+	}
+}
+
+// childExit is an error of a child process exiting with the code.
+type childExit int
+
+func (e childExit) Error() string { return fmt.Sprintf("exit status %d", int(e)) }
+func (e childExit) ExitCode() int { return int(e) }
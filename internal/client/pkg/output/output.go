@@ -0,0 +1,289 @@
+// Package output renders command results and errors of the client in text, JSON, YAML or table form
+// and maps errors to process exit codes.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/models"
+	"gopkg.in/yaml.v3"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Format is an output format of the client commands.
+type Format string
+
+// Supported output formats.
+const (
+	Text  Format = "text"
+	JSON  Format = "json"
+	YAML  Format = "yaml"
+	Table Format = "table"
+)
+
+// ParseFormat parses the value of the output flag.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case Text, JSON, YAML, Table:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown output format %q, use text, json, yaml or table", s)
+}
+
+// Value is a command result that can be printed in every format.
+// JSON and YAML output marshal the value itself, so its fields form the stable schema.
+// Results of a feature, like the audit report, implement it next to their type, keeping this package free of feature imports.
+type Value interface {
+	// Text returns the human readable form of the value.
+	Text() string
+
+	// Table returns the header and rows of the tabular form of the value.
+	Table() ([]string, [][]string)
+}
+
+// Print writes the value to w in the format.
+func Print(w io.Writer, f Format, v Value) error {
+	switch f {
+	case JSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case YAML:
+		enc := yaml.NewEncoder(w)
+		defer enc.Close()
+		return enc.Encode(v)
+	case Table:
+		header, rows := v.Table()
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(header, "\t"))
+		for _, row := range rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	default:
+		text := v.Text()
+		if text == "" {
+			return nil
+		}
+		_, err := fmt.Fprintln(w, text)
+		return err
+	}
+}
+
+// ItemList is the result of listing the stored items.
+type ItemList []models.DataInfo
+
+// Text implements the Value interface Text method.
+func (l ItemList) Text() string {
+	lines := make([]string, len(l))
+	for i, item := range l {
+		lines[i] = fmt.Sprintf("%d. %s - %s", i+1, item.Name, item.Type)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Table implements the Value interface Table method.
+func (l ItemList) Table() ([]string, [][]string) {
+	rows := make([][]string, len(l))
+	for i, item := range l {
+		rows[i] = []string{item.Name, item.Type}
+	}
+	return []string{"NAME", "TYPE"}, rows
+}
+
+// Item is the result of getting a decrypted item.
+type Item models.Item
+
+// Text implements the Value interface Text method.
+func (it *Item) Text() string {
+	lines := make([]string, len(it.Fields))
+	for i, f := range it.Fields {
+		lines[i] = fmt.Sprintf("%s: %s", capitalize(f.Name), f.Value)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// capitalize returns the string with its first letter in upper case.
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if size == 0 {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+// Table implements the Value interface Table method.
+func (it *Item) Table() ([]string, [][]string) {
+	rows := make([][]string, len(it.Fields))
+	for i, f := range it.Fields {
+		rows[i] = []string{f.Name, f.Value}
+	}
+	return []string{"FIELD", "VALUE"}, rows
+}
+
+// SyncStatus is the result of a sync with the server.
+type SyncStatus struct {
+	Synced   bool      `json:"synced" yaml:"synced"`
+	SyncedAt time.Time `json:"synced_at" yaml:"synced_at"`
+}
+
+// Text implements the Value interface Text method.
+func (s *SyncStatus) Text() string {
+	return "Successfully synced data"
+}
+
+// Table implements the Value interface Table method.
+func (s *SyncStatus) Table() ([]string, [][]string) {
+	return []string{"SYNCED", "SYNCED AT"}, [][]string{{fmt.Sprint(s.Synced), s.SyncedAt.Format(time.RFC3339)}}
+}
+
+// OTPCode is the current code of a one-time password.
+type OTPCode struct {
+	Code      string `json:"code" yaml:"code"`
+	Remaining int    `json:"remaining_seconds" yaml:"remaining_seconds"`
+}
+
+// Text implements the Value interface Text method.
+func (c *OTPCode) Text() string {
+	return fmt.Sprintf("%s (%ds remaining)", c.Code, c.Remaining)
+}
+
+// Table implements the Value interface Table method.
+func (c *OTPCode) Table() ([]string, [][]string) {
+	return []string{"CODE", "REMAINING"}, [][]string{{c.Code, fmt.Sprintf("%ds", c.Remaining)}}
+}
+
+// ImportedItem is the outcome of importing an entry of an export.
+type ImportedItem struct {
+	Name   string `json:"name" yaml:"name"`
+	Type   string `json:"type" yaml:"type"`
+	Status string `json:"status" yaml:"status"`
+	Error  string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// ImportReport is the result of importing an export, or of previewing the import with DryRun set.
+type ImportReport struct {
+	DryRun   bool           `json:"dry_run" yaml:"dry_run"`
+	Imported int            `json:"imported" yaml:"imported"`
+	Skipped  int            `json:"skipped" yaml:"skipped"`
+	Items    []ImportedItem `json:"items" yaml:"items"`
+}
+
+// Text implements the Value interface Text method.
+func (r *ImportReport) Text() string {
+	var b strings.Builder
+	for _, it := range r.Items {
+		fmt.Fprintf(&b, "%s: %s - %s", it.Status, it.Name, it.Type)
+		if it.Error != "" {
+			fmt.Fprintf(&b, " (%s)", it.Error)
+		}
+		b.WriteString("\n")
+	}
+	if r.DryRun {
+		fmt.Fprintf(&b, "Would import %d items, skipping %d", r.Imported, r.Skipped)
+	} else {
+		fmt.Fprintf(&b, "Imported %d items, skipped %d", r.Imported, r.Skipped)
+	}
+	return b.String()
+}
+
+// Table implements the Value interface Table method.
+func (r *ImportReport) Table() ([]string, [][]string) {
+	rows := make([][]string, 0, len(r.Items))
+	for _, it := range r.Items {
+		rows = append(rows, []string{it.Name, it.Type, it.Status, it.Error})
+	}
+	return []string{"NAME", "TYPE", "STATUS", "ERROR"}, rows
+}
+
+// GeneratedPassword is a password created by the password generator.
+type GeneratedPassword struct {
+	Password string `json:"password" yaml:"password"`
+	Entropy  int    `json:"entropy_bits" yaml:"entropy_bits"`
+}
+
+// Text implements the Value interface Text method.
+func (p *GeneratedPassword) Text() string {
+	return p.Password
+}
+
+// Table implements the Value interface Table method.
+func (p *GeneratedPassword) Table() ([]string, [][]string) {
+	return []string{"PASSWORD", "ENTROPY"}, [][]string{{p.Password, fmt.Sprintf("%d bits", p.Entropy)}}
+}
+
+// DeviceList is the result of listing the enrolled devices.
+type DeviceList []models.Device
+
+// Text implements the Value interface Text method.
+func (l DeviceList) Text() string {
+	lines := make([]string, len(l))
+	for i, d := range l {
+		lines[i] = fmt.Sprintf("%d. %s - %s (%s), expires %s", i+1, d.ID, d.Name, deviceState(d), d.ExpiresAt.Format("2006-01-02"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Table implements the Value interface Table method.
+func (l DeviceList) Table() ([]string, [][]string) {
+	rows := make([][]string, len(l))
+	for i, d := range l {
+		rows[i] = []string{d.ID, d.Name, deviceState(d), d.ExpiresAt.Format("2006-01-02")}
+	}
+	return []string{"ID", "NAME", "STATE", "EXPIRES"}, rows
+}
+
+// deviceState describes whether the device is revoked and whether it is the current one.
+func deviceState(d models.Device) string {
+	state := "active"
+	if d.Revoked {
+		state = "revoked"
+	}
+	if d.Current {
+		state += ", current"
+	}
+	return state
+}
+
+// Message is the result of a command without data, for example a successful creation.
+type Message struct {
+	Message string `json:"message" yaml:"message"`
+}
+
+// Text implements the Value interface Text method.
+func (m *Message) Text() string {
+	return m.Message
+}
+
+// Table implements the Value interface Table method.
+func (m *Message) Table() ([]string, [][]string) {
+	return []string{"MESSAGE"}, [][]string{{m.Message}}
+}
+
+// Error is the printed form of a failed command.
+type Error struct {
+	Error    string `json:"error" yaml:"error"`
+	Kind     string `json:"kind" yaml:"kind"`
+	ExitCode int    `json:"exit_code" yaml:"exit_code"`
+}
+
+// NewError creates the printed form of the error.
+func NewError(err error) *Error {
+	code := ExitCode(err)
+	return &Error{Error: err.Error(), Kind: kinds[code], ExitCode: code}
+}
+
+// Text implements the Value interface Text method.
+func (e *Error) Text() string {
+	return "Error: " + e.Error
+}
+
+// Table implements the Value interface Table method.
+func (e *Error) Table() ([]string, [][]string) {
+	return []string{"ERROR", "KIND", "EXIT CODE"}, [][]string{{e.Error, e.Kind, fmt.Sprint(e.ExitCode)}}
+}
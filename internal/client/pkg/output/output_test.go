@@ -0,0 +1,149 @@
+package output
+
+import (
+	"bytes"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("JSON")
+	require.NoError(t, err)
+	assert.Equal(t, JSON, format)
+	_, err = ParseFormat("xml")
+	assert.Error(t, err)
+}
+
+func TestPrint(t *testing.T) {
+	list := ItemList{{Name: "github", Type: "Cred"}, {Name: "visa", Type: "Card"}}
+	item := &Item{Name: "github", Type: "Cred", Fields: []models.Field{
+		{Name: "login", Value: "octocat"},
+		{Name: "password", Value: "secret"},
+	}}
+	tests := []struct {
+		name   string
+		format Format
+		value  Value
+		want   string
+	}{
+		{
+			name:   "List as text",
+			format: Text,
+			value:  list,
+			want:   "1. github - Cred\n2. visa - Card\n",
+		},
+		{
+			name:   "List as json",
+			format: JSON,
+			value:  list,
+			want: `[
+  {
+    "name": "github",
+    "type": "Cred"
+  },
+  {
+    "name": "visa",
+    "type": "Card"
+  }
+]
+`,
+		},
+		{
+			name:   "List as yaml",
+			format: YAML,
+			value:  list,
+			want:   "- name: github\n  type: Cred\n- name: visa\n  type: Card\n",
+		},
+		{
+			name:   "List as table",
+			format: Table,
+			value:  list,
+			want:   "NAME    TYPE\ngithub  Cred\nvisa    Card\n",
+		},
+		{
+			name:   "Item as text",
+			format: Text,
+			value:  item,
+			want:   "Login: octocat\nPassword: secret\n",
+		},
+		{
+			name:   "Item as json",
+			format: JSON,
+			value:  item,
+			want: `{
+  "name": "github",
+  "type": "Cred",
+  "fields": [
+    {
+      "name": "login",
+      "value": "octocat"
+    },
+    {
+      "name": "password",
+      "value": "secret"
+    }
+  ]
+}
+`,
+		},
+		{
+			name:   "Empty list as text",
+			format: Text,
+			value:  ItemList{},
+			want:   "",
+		},
+		{
+			name:   "Empty list as json",
+			format: JSON,
+			value:  ItemList{},
+			want:   "[]\n",
+		},
+		{
+			name:   "Item with unnamed field as text",
+			format: Text,
+			value:  &Item{Fields: []models.Field{{Name: "", Value: "value"}, {Name: "über", Value: "x"}}},
+			want:   ": value\nÜber: x\n",
+		},
+		{
+			name:   "OTP code as text",
+			format: Text,
+			value:  &OTPCode{Code: "492039", Remaining: 17},
+			want:   "492039 (17s remaining)\n",
+		},
+		{
+			name:   "Generated password as json",
+			format: JSON,
+			value:  &GeneratedPassword{Password: "correct-horse", Entropy: 22},
+			want:   "{\n  \"password\": \"correct-horse\",\n  \"entropy_bits\": 22\n}\n",
+		},
+		{
+			name:   "Import report as text",
+			format: Text,
+			value: &ImportReport{DryRun: true, Imported: 1, Skipped: 1, Items: []ImportedItem{
+				{Name: "github", Type: "Cred", Status: "new"},
+				{Name: "bad", Type: "Cred", Status: "invalid", Error: "invalid url"},
+			}},
+			want: "new: github - Cred\ninvalid: bad - Cred (invalid url)\nWould import 1 items, skipping 1\n",
+		},
+		{
+			name:   "Error as json",
+			format: JSON,
+			value:  &Error{Error: "not logged in", Kind: "auth", ExitCode: ExitAuth},
+			want: `{
+  "error": "not logged in",
+  "kind": "auth",
+  "exit_code": 3
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			require.NoError(t, Print(buf, tt.format, tt.value))
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
@@ -0,0 +1,233 @@
+// Package passgen generates passwords and passphrases following a policy, drawing on crypto/rand.
+// The policy used for a Cred item is stored in the item, so rotating its password reuses the same rules,
+// and policies kept for item name prefixes apply to the items without one.
+package passgen
+
+import (
+	"crypto/rand"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// Character classes of generated passwords.
+const (
+	Lower   = "lower"
+	Upper   = "upper"
+	Digits  = "digits"
+	Symbols = "symbols"
+)
+
+// Classes are the character classes in the order their characters are listed.
+var Classes = []string{Lower, Upper, Digits, Symbols}
+
+// classChars are the characters of each class.
+var classChars = map[string]string{
+	Lower:   "abcdefghijklmnopqrstuvwxyz",
+	Upper:   "ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+	Digits:  "0123456789",
+	Symbols: "!@#$%^&*()-_=+[]{};:,.<>?/~",
+}
+
+// ambiguous are the characters left out with ExcludeAmbiguous, as they are easily confused when read or typed.
+const ambiguous = "0Oo1lI|"
+
+// policyKey is the content key of the policy stored in an item.
+const policyKey = "password_policy"
+
+// Limits of the policy.
+const (
+	MinLength = 4
+	MaxLength = 256
+	MaxWords  = 64
+)
+
+//go:embed wordlist.txt
+var wordlistFile string
+
+// wordlist is the BIP-39 English wordlist of 2048 words, each adding 11 bits of entropy to a passphrase.
+var wordlist = strings.Fields(wordlistFile)
+
+// Wordlist returns a copy of the words passphrases are drawn from.
+func Wordlist() []string {
+	return append([]string(nil), wordlist...)
+}
+
+// Policy is the set of rules for generating a password.
+// Words greater than zero selects a passphrase of that many words joined by the separator,
+// otherwise a password of Length characters from the classes is generated, with at least one of each class.
+type Policy struct {
+	Length           int      `json:"length,omitempty"`
+	Classes          []string `json:"classes,omitempty"`
+	ExcludeAmbiguous bool     `json:"exclude_ambiguous,omitempty"`
+	Words            int      `json:"words,omitempty"`
+	Separator        string   `json:"separator,omitempty"`
+}
+
+// DefaultPolicy is used for items without a stored policy.
+var DefaultPolicy = Policy{Length: 20, Classes: []string{Lower, Upper, Digits, Symbols}}
+
+// DefaultWords is the number of words of passphrases when none is given.
+const DefaultWords = 6
+
+// DefaultSeparator joins the words of passphrases when no separator is given.
+const DefaultSeparator = "-"
+
+// Validate checks the policy.
+func (p *Policy) Validate() error {
+	if p.Words > 0 {
+		if p.Words > MaxWords {
+			return fmt.Errorf("a passphrase has at most %d words", MaxWords)
+		}
+		return nil
+	}
+	if p.Length < MinLength || p.Length > MaxLength {
+		return fmt.Errorf("length must be between %d and %d", MinLength, MaxLength)
+	}
+	if len(p.Classes) == 0 {
+		return errors.New("at least one character class is required")
+	}
+	for _, c := range p.Classes {
+		if _, ok := classChars[c]; !ok {
+			return fmt.Errorf("unknown character class %q, must be one of %s", c, strings.Join(Classes, ", "))
+		}
+	}
+	if len(p.Classes) > p.Length {
+		return fmt.Errorf("length %d is too short for %d character classes", p.Length, len(p.Classes))
+	}
+	return nil
+}
+
+// Entropy returns the strength of passwords generated with the policy in bits.
+func (p *Policy) Entropy() float64 {
+	if p.Words > 0 {
+		return float64(p.Words) * math.Log2(float64(len(wordlist)))
+	}
+	return float64(p.Length) * math.Log2(float64(len(p.alphabet())))
+}
+
+// Generate generates a password following the policy.
+func Generate(p Policy) (string, error) {
+	if err := p.Validate(); err != nil {
+		return "", err
+	}
+	if p.Words > 0 {
+		return passphrase(p)
+	}
+	sets := make([]string, len(p.Classes))
+	for i, c := range p.Classes {
+		sets[i] = p.chars(c)
+	}
+	alphabet := p.alphabet()
+	password := make([]byte, p.Length)
+	// One character of every class is placed first and the order is shuffled afterwards,
+	// so every class is present without biasing the other characters.
+	for i := range password {
+		set := alphabet
+		if i < len(sets) {
+			set = sets[i]
+		}
+		n, err := randomInt(len(set))
+		if err != nil {
+			return "", err
+		}
+		password[i] = set[n]
+	}
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := randomInt(i + 1)
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j] = password[j], password[i]
+	}
+	return string(password), nil
+}
+
+// passphrase generates a passphrase of words from the wordlist.
+func passphrase(p Policy) (string, error) {
+	separator := p.Separator
+	if separator == "" {
+		separator = DefaultSeparator
+	}
+	words := make([]string, p.Words)
+	for i := range words {
+		n, err := randomInt(len(wordlist))
+		if err != nil {
+			return "", err
+		}
+		words[i] = wordlist[n]
+	}
+	return strings.Join(words, separator), nil
+}
+
+// chars returns the characters of the class allowed by the policy.
+func (p *Policy) chars(class string) string {
+	chars := classChars[class]
+	if p.ExcludeAmbiguous {
+		chars = strings.Map(func(r rune) rune {
+			if strings.ContainsRune(ambiguous, r) {
+				return -1
+			}
+			return r
+		}, chars)
+	}
+	return chars
+}
+
+// alphabet returns the characters of all classes of the policy, without duplicates.
+func (p *Policy) alphabet() string {
+	var b strings.Builder
+	for _, c := range Classes {
+		for _, class := range p.Classes {
+			if class == c {
+				b.WriteString(p.chars(c))
+				break
+			}
+		}
+	}
+	return b.String()
+}
+
+// randomInt returns a uniform random number in [0, n).
+func randomInt(n int) (int, error) {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(v.Int64()), nil
+}
+
+// ItemPolicy returns the policy stored in the content of an item, if any.
+func ItemPolicy(content []byte) (*Policy, bool, error) {
+	raw := map[string]json.RawMessage{}
+	if err := json.Unmarshal(content, &raw); err != nil {
+		return nil, false, err
+	}
+	encoded, ok := raw[policyKey]
+	if !ok {
+		return nil, false, nil
+	}
+	p := &Policy{}
+	if err := json.Unmarshal(encoded, p); err != nil {
+		return nil, false, fmt.Errorf("password policy: %w", err)
+	}
+	return p, true, nil
+}
+
+// SetItemPolicy stores the policy in the content of an item, keeping the other keys.
+func SetItemPolicy(content []byte, p Policy) ([]byte, error) {
+	raw := map[string]json.RawMessage{}
+	if err := json.Unmarshal(content, &raw); err != nil {
+		return nil, err
+	}
+	encoded, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	raw[policyKey] = encoded
+	return json.Marshal(raw)
+}
@@ -0,0 +1,119 @@
+package passgen
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+		check  func(t *testing.T, password string)
+	}{
+		{
+			name:   "default",
+			policy: DefaultPolicy,
+			check: func(t *testing.T, password string) {
+				assert.Len(t, password, 20)
+				for _, class := range Classes {
+					assert.True(t, strings.ContainsAny(password, classChars[class]), "missing %s in %s", class, password)
+				}
+			},
+		},
+		{
+			name:   "digits only",
+			policy: Policy{Length: 8, Classes: []string{Digits}},
+			check: func(t *testing.T, password string) {
+				assert.Len(t, password, 8)
+				assert.Empty(t, strings.Trim(password, classChars[Digits]))
+			},
+		},
+		{
+			name:   "every class in a short password",
+			policy: Policy{Length: 4, Classes: []string{Symbols, Digits, Upper, Lower}},
+			check: func(t *testing.T, password string) {
+				for _, class := range Classes {
+					assert.True(t, strings.ContainsAny(password, classChars[class]), "missing %s in %s", class, password)
+				}
+			},
+		},
+		{
+			name:   "without ambiguous characters",
+			policy: Policy{Length: 256, Classes: []string{Lower, Upper, Digits, Symbols}, ExcludeAmbiguous: true},
+			check: func(t *testing.T, password string) {
+				assert.False(t, strings.ContainsAny(password, ambiguous), password)
+			},
+		},
+		{
+			name:   "passphrase",
+			policy: Policy{Words: 5, Separator: " "},
+			check: func(t *testing.T, password string) {
+				words := strings.Split(password, " ")
+				assert.Len(t, words, 5)
+				for _, w := range words {
+					assert.Contains(t, wordlist, w)
+				}
+			},
+		},
+		{
+			name:   "passphrase with default separator",
+			policy: Policy{Words: 3},
+			check: func(t *testing.T, password string) {
+				assert.Len(t, strings.Split(password, DefaultSeparator), 3)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for n := 0; n < 20; n++ {
+				password, err := Generate(tt.policy)
+				require.NoError(t, err)
+				tt.check(t, password)
+			}
+		})
+	}
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+	}{
+		{name: "too short", policy: Policy{Length: 3, Classes: []string{Lower}}},
+		{name: "too long", policy: Policy{Length: MaxLength + 1, Classes: []string{Lower}}},
+		{name: "no classes", policy: Policy{Length: 10}},
+		{name: "unknown class", policy: Policy{Length: 10, Classes: []string{"emoji"}}},
+		{name: "too many words", policy: Policy{Words: MaxWords + 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Generate(tt.policy)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestPolicy_Entropy(t *testing.T) {
+	assert.Equal(t, 66.0, (&Policy{Words: 6}).Entropy())
+	assert.InDelta(t, 4*3.32, (&Policy{Length: 4, Classes: []string{Digits}}).Entropy(), 0.01)
+	assert.Len(t, wordlist, 2048)
+}
+
+func TestItemPolicy(t *testing.T) {
+	content := []byte(`{"login":"bob","password":"secret","future":1}`)
+	_, ok, err := ItemPolicy(content)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	policy := Policy{Length: 12, Classes: []string{Lower, Digits}, ExcludeAmbiguous: true}
+	content, err = SetItemPolicy(content, policy)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"future":1`)
+	stored, ok, err := ItemPolicy(content)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, policy, *stored)
+}
@@ -0,0 +1,107 @@
+package passgen
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/pkg/utils"
+	"os"
+	"sort"
+	"strings"
+)
+
+// PrefixPolicies are the policies of the items whose names start with a prefix, like "work/" for work/mail.
+// They apply to items without a stored policy, so every item of a group is generated with the same rules.
+type PrefixPolicies map[string]Policy
+
+// ReadPrefixPolicies reads the policies from the JSON file. A missing file holds no policies.
+func ReadPrefixPolicies(filename string) (PrefixPolicies, error) {
+	content, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return PrefixPolicies{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	policies := PrefixPolicies{}
+	if err = json.Unmarshal(content, &policies); err != nil {
+		return nil, fmt.Errorf("password policies: %w", err)
+	}
+	return policies, nil
+}
+
+// Write writes the policies to the JSON file, readable by the owner only.
+func (pp PrefixPolicies) Write(filename string) error {
+	content, err := json.MarshalIndent(pp, "", "  ")
+	if err != nil {
+		return err
+	}
+	return utils.WriteSecretFile(filename, content)
+}
+
+// Lookup returns the policy of the longest prefix of the item name.
+func (pp PrefixPolicies) Lookup(name string) (Policy, bool) {
+	var longest string
+	p, found := Policy{}, false
+	for prefix, policy := range pp {
+		if strings.HasPrefix(name, prefix) && (!found || len(prefix) > len(longest)) {
+			longest, p, found = prefix, policy, true
+		}
+	}
+	return p, found
+}
+
+// Resolve returns the policy of a password of the item: the policy stored in its content,
+// the policy of its name prefix, or the default policy. The content of a new item is nil.
+func (pp PrefixPolicies) Resolve(name string, content []byte) (Policy, error) {
+	if content != nil {
+		p, ok, err := ItemPolicy(content)
+		if err != nil {
+			return Policy{}, err
+		}
+		if ok {
+			return *p, nil
+		}
+	}
+	if p, ok := pp.Lookup(name); ok {
+		return p, nil
+	}
+	return DefaultPolicy, nil
+}
+
+// Text returns the policies as printed by the text output, one prefix per line.
+func (pp PrefixPolicies) Text() string {
+	_, rows := pp.Table()
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		lines[i] = fmt.Sprintf("%s: %s", row[0], row[1])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Table returns the header and rows of the policies as printed by the table output, sorted by prefix.
+func (pp PrefixPolicies) Table() ([]string, [][]string) {
+	prefixes := make([]string, 0, len(pp))
+	for prefix := range pp {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	rows := make([][]string, len(prefixes))
+	for i, prefix := range prefixes {
+		p := pp[prefix]
+		rows[i] = []string{prefix, p.String()}
+	}
+	return []string{"PREFIX", "POLICY"}, rows
+}
+
+// String describes the rules of the policy.
+func (p Policy) String() string {
+	if p.Words > 0 {
+		return fmt.Sprintf("%d words separated by %q", p.Words, p.Separator)
+	}
+	s := fmt.Sprintf("%d characters of %s", p.Length, strings.Join(p.Classes, ", "))
+	if p.ExcludeAmbiguous {
+		s += ", no ambiguous characters"
+	}
+	return s
+}
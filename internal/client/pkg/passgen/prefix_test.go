@@ -0,0 +1,59 @@
+package passgen
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPrefixPolicies_Resolve(t *testing.T) {
+	work := Policy{Length: 32, Classes: []string{Lower, Upper, Digits}}
+	bank := Policy{Words: 8, Separator: " "}
+	stored := Policy{Length: 12, Classes: []string{Digits}}
+	policies := PrefixPolicies{"work/": work, "work/bank/": bank}
+	withPolicy, err := SetItemPolicy([]byte(`{"login":"bob"}`), stored)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		item    string
+		content []byte
+		want    Policy
+	}{
+		{name: "New item of a prefix", item: "work/mail", want: work},
+		{name: "Longest prefix wins", item: "work/bank/main", want: bank},
+		{name: "Item without a stored policy", item: "work/mail", content: []byte(`{"login":"bob"}`), want: work},
+		{name: "Stored policy wins", item: "work/bank/main", content: withPolicy, want: stored},
+		{name: "No prefix", item: "home/mail", want: DefaultPolicy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := policies.Resolve(tt.item, tt.content)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, p)
+		})
+	}
+}
+
+func TestPrefixPolicies_ReadWrite(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "policies.json")
+	policies, err := ReadPrefixPolicies(filename)
+	require.NoError(t, err, "a missing file holds no policies")
+	assert.Empty(t, policies)
+
+	policies["work.example.com/"] = Policy{Length: 24, Classes: []string{Lower, Digits}, ExcludeAmbiguous: true}
+	require.NoError(t, policies.Write(filename))
+	info, err := os.Stat(filename)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	read, err := ReadPrefixPolicies(filename)
+	require.NoError(t, err)
+	assert.Equal(t, policies, read)
+	assert.Equal(t, "work.example.com/: 24 characters of lower, digits, no ambiguous characters", read.Text())
+
+	require.NoError(t, os.WriteFile(filename, []byte("{"), 0600))
+	_, err = ReadPrefixPolicies(filename)
+	assert.Error(t, err)
+}
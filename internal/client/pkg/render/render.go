@@ -0,0 +1,96 @@
+// Package render renders text templates referencing fields of stored items.
+package render
+
+import (
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/Mldlr/storety/internal/constants"
+	"io"
+	"text/template"
+)
+
+// Renderer renders templates against the decrypted vault.
+// Templates reference fields with the functions:
+//
+//	{{ secret "item" "field" }}  a field of an item of any type
+//	{{ cred "item" "field" }}    a field of a credentials item
+//	{{ card "item" "field" }}    a field of a card item
+//	{{ text "item" }}            the text of a text item
+type Renderer struct {
+	getItem func(name string) (*models.Item, error)
+	items   map[string]*models.Item
+	// unresolved collects failed references instead of failing the rendering when checking.
+	unresolved *[]string
+}
+
+// New creates a Renderer looking up items with getItem.
+func New(getItem func(name string) (*models.Item, error)) *Renderer {
+	return &Renderer{getItem: getItem, items: make(map[string]*models.Item)}
+}
+
+// Render parses the template and writes it rendered to w.
+// It fails on the first reference that does not resolve.
+func (r *Renderer) Render(name, text string, w io.Writer) error {
+	tmpl, err := r.parse(name, text)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, nil)
+}
+
+// Check parses and evaluates the template without writing it and returns every reference that does not resolve.
+func (r *Renderer) Check(name, text string) ([]string, error) {
+	unresolved := []string{}
+	r.unresolved = &unresolved
+	defer func() { r.unresolved = nil }()
+	tmpl, err := r.parse(name, text)
+	if err != nil {
+		return nil, err
+	}
+	if err = tmpl.Execute(io.Discard, nil); err != nil {
+		return nil, err
+	}
+	return unresolved, nil
+}
+
+// parse parses the template with the vault functions.
+func (r *Renderer) parse(name, text string) (*template.Template, error) {
+	return template.New(name).Option("missingkey=error").Funcs(template.FuncMap{
+		"secret": func(item, field string) (string, error) { return r.field(item, "", field) },
+		"cred":   func(item, field string) (string, error) { return r.field(item, "Cred", field) },
+		"card":   func(item, field string) (string, error) { return r.field(item, "Card", field) },
+		"text":   func(item string) (string, error) { return r.field(item, "Text", "text") },
+	}).Parse(text)
+}
+
+// field resolves the field of the item, checking the item type if one is given.
+// While checking, failures are collected and an empty value is returned.
+func (r *Renderer) field(name, typ, field string) (string, error) {
+	value, err := r.resolve(name, typ, field)
+	if err != nil && r.unresolved != nil {
+		*r.unresolved = append(*r.unresolved, err.Error())
+		return "", nil
+	}
+	return value, err
+}
+
+// resolve looks up the field of the item, fetching each item once.
+func (r *Renderer) resolve(name, typ, field string) (string, error) {
+	item, ok := r.items[name]
+	if !ok {
+		var err error
+		item, err = r.getItem(name)
+		if err != nil {
+			return "", fmt.Errorf("%s.%s: %w", name, field, err)
+		}
+		r.items[name] = item
+	}
+	if typ != "" && item.Type != typ {
+		return "", fmt.Errorf("%s.%s: item is of type %s, not %s", name, field, item.Type, typ)
+	}
+	value, ok := item.Field(field)
+	if !ok {
+		return "", fmt.Errorf("%s.%s: %w", name, field, constants.ErrFieldNotFound)
+	}
+	return value, nil
+}
@@ -0,0 +1,93 @@
+package render
+
+import (
+	"bytes"
+	"errors"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/Mldlr/storety/internal/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// items is the vault the templates are rendered against.
+var items = map[string]*models.Item{
+	"prod-db": {Name: "prod-db", Type: "Cred", Fields: []models.Field{
+		{Name: "login", Value: "admin"},
+		{Name: "password", Value: "s3cret"},
+	}},
+	"corp": {Name: "corp", Type: "Card", Fields: []models.Field{
+		{Name: "number", Value: "4111111111111111"},
+	}},
+	"motd": {Name: "motd", Type: "Text", Fields: []models.Field{
+		{Name: "text", Value: "hello"},
+	}},
+}
+
+func getItem(name string) (*models.Item, error) {
+	item, ok := items[name]
+	if !ok {
+		return nil, constants.ErrGetData
+	}
+	return item, nil
+}
+
+func TestRenderer_Render(t *testing.T) {
+	tests := []struct {
+		name    string
+		tmpl    string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "All functions",
+			tmpl: `db: {{ secret "prod-db" "login" }}:{{ cred "prod-db" "password" }} card: {{ card "corp" "number" }} {{ text "motd" }}`,
+			want: "db: admin:s3cret card: 4111111111111111 hello",
+		},
+		{
+			name:    "Missing item",
+			tmpl:    `{{ secret "stage-db" "password" }}`,
+			wantErr: constants.ErrGetData,
+		},
+		{
+			name:    "Missing field",
+			tmpl:    `{{ secret "prod-db" "token" }}`,
+			wantErr: constants.ErrFieldNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			err := New(getItem).Render("app.tmpl", tt.tmpl, buf)
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr), err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+
+	err := New(getItem).Render("app.tmpl", `{{ card "prod-db" "number" }}`, &bytes.Buffer{})
+	assert.ErrorContains(t, err, "item is of type Cred, not Card")
+}
+
+func TestRenderer_Check(t *testing.T) {
+	fetched := 0
+	counting := func(name string) (*models.Item, error) {
+		fetched++
+		return getItem(name)
+	}
+	r := New(counting)
+	unresolved, err := r.Check("app.tmpl", `{{ secret "prod-db" "login" }}{{ secret "prod-db" "token" }}{{ text "stage" }}{{ cred "corp" "number" }}`)
+	require.NoError(t, err)
+	assert.Len(t, unresolved, 3)
+	assert.Equal(t, 3, fetched)
+
+	unresolved, err = r.Check("app.tmpl", `{{ secret "prod-db" "login" }}`)
+	require.NoError(t, err)
+	assert.Empty(t, unresolved)
+
+	_, err = r.Check("app.tmpl", `{{ secret "prod-db" }`)
+	assert.Error(t, err, "syntax errors fail the check")
+}
@@ -0,0 +1,106 @@
+package secretenv
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"sync"
+)
+
+// Mask replaces secrets in masked output.
+const Mask = "*****"
+
+// Masker is a writer replacing secrets with Mask before writing to the underlying writer.
+// Secrets split across writes are masked too: the tail of the written data that could start a secret
+// is held back until the next write or Flush, except for complete lines when no secret contains a line break.
+type Masker struct {
+	w       io.Writer
+	secrets [][]byte
+	maxLen  int
+	newline bool
+
+	mu      sync.Mutex
+	pending []byte
+}
+
+// NewMasker creates a Masker writing to w. Empty secrets are ignored.
+func NewMasker(w io.Writer, secrets []string) *Masker {
+	m := &Masker{w: w}
+	for _, s := range secrets {
+		if s == "" {
+			continue
+		}
+		m.secrets = append(m.secrets, []byte(s))
+		if len(s) > m.maxLen {
+			m.maxLen = len(s)
+		}
+		if bytes.ContainsRune([]byte(s), '\n') {
+			m.newline = true
+		}
+	}
+	// Longer secrets first, so a secret containing another one is masked as a whole.
+	sort.Slice(m.secrets, func(i, j int) bool { return len(m.secrets[i]) > len(m.secrets[j]) })
+	return m
+}
+
+// Write implements the io.Writer interface Write method.
+func (m *Masker) Write(p []byte) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pending = append(m.pending, p...)
+	if err := m.flush(false); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush writes the held back data.
+func (m *Masker) Flush() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.flush(true)
+}
+
+// flush masks the pending data and writes all of it that can no longer be part of a secret.
+func (m *Masker) flush(all bool) error {
+	var out []byte
+	rest := m.pending
+	for {
+		start, length := m.next(rest)
+		if start < 0 {
+			break
+		}
+		out = append(out, rest[:start]...)
+		out = append(out, Mask...)
+		rest = rest[start+length:]
+	}
+	keep := 0
+	if !all && m.maxLen > 1 {
+		keep = m.maxLen - 1
+		if keep > len(rest) {
+			keep = len(rest)
+		}
+		if i := bytes.LastIndexByte(rest, '\n'); !m.newline && i >= len(rest)-keep {
+			keep = len(rest) - i - 1
+		}
+	}
+	out = append(out, rest[:len(rest)-keep]...)
+	m.pending = append(m.pending[:0], rest[len(rest)-keep:]...)
+	if len(out) == 0 {
+		return nil
+	}
+	_, err := m.w.Write(out)
+	return err
+}
+
+// next returns the position and length of the first secret in b, or -1 if there is none.
+func (m *Masker) next(b []byte) (int, int) {
+	start, length := -1, 0
+	for _, s := range m.secrets {
+		i := bytes.Index(b, s)
+		if i >= 0 && (start < 0 || i < start) {
+			start, length = i, len(s)
+		}
+	}
+	return start, length
+}
@@ -0,0 +1,71 @@
+package secretenv
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestMasker(t *testing.T) {
+	tests := []struct {
+		name    string
+		secrets []string
+		writes  []string
+		want    string
+	}{
+		{
+			name:    "Secret in a single write",
+			secrets: []string{"s3cret"},
+			writes:  []string{"password is s3cret\n"},
+			want:    "password is *****\n",
+		},
+		{
+			name:    "Secret split across writes",
+			secrets: []string{"s3cret"},
+			writes:  []string{"password is s3", "cr", "et!"},
+			want:    "password is *****!",
+		},
+		{
+			name:    "Several secrets and occurrences",
+			secrets: []string{"admin", "s3cret", ""},
+			writes:  []string{"admin:s3cret@db admin\n"},
+			want:    "*****:*****@db *****\n",
+		},
+		{
+			name:    "Longer secret containing a shorter one",
+			secrets: []string{"key", "monkey"},
+			writes:  []string{"monkey key"},
+			want:    "***** *****",
+		},
+		{
+			name:   "No secrets",
+			writes: []string{"plain output"},
+			want:   "plain output",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			m := NewMasker(buf, tt.secrets)
+			for _, w := range tt.writes {
+				n, err := m.Write([]byte(w))
+				require.NoError(t, err)
+				assert.Equal(t, len(w), n)
+			}
+			require.NoError(t, m.Flush())
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestMasker_HoldsBackOnlyPossibleSecretStart(t *testing.T) {
+	buf := &bytes.Buffer{}
+	m := NewMasker(buf, []string{"s3cret"})
+	_, err := m.Write([]byte("first line\nsecond"))
+	require.NoError(t, err)
+	assert.Equal(t, "first line\ns", buf.String())
+	_, err = m.Write([]byte(" line\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "first line\nsecond line\n", buf.String(), "complete lines are written immediately")
+}
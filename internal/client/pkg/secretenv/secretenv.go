@@ -0,0 +1,92 @@
+// Package secretenv resolves references to fields of stored items into environment variables
+// for child processes and masks the resolved secrets in their output.
+package secretenv
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/Mldlr/storety/internal/constants"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// varName matches valid environment variable names.
+var varName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Mapping maps an environment variable to a field of a stored item.
+type Mapping struct {
+	Var   string
+	Item  string
+	Field string
+}
+
+// ParseMapping parses a mapping of the form VAR=item.field.
+// The item name may contain dots, the field is the part after the last dot.
+func ParseMapping(s string) (Mapping, error) {
+	name, ref, ok := strings.Cut(strings.TrimSpace(s), "=")
+	name, ref = strings.TrimSpace(name), strings.TrimSpace(ref)
+	if !ok || !varName.MatchString(name) {
+		return Mapping{}, fmt.Errorf("invalid mapping %q, expected VAR=item.field", s)
+	}
+	dot := strings.LastIndex(ref, ".")
+	if dot <= 0 || dot == len(ref)-1 {
+		return Mapping{}, fmt.Errorf("invalid reference %q in mapping of %s, expected item.field", ref, name)
+	}
+	return Mapping{Var: name, Item: ref[:dot], Field: ref[dot+1:]}, nil
+}
+
+// ParseFile parses a mapping manifest with one VAR=item.field mapping per line.
+// Empty lines and lines starting with # are skipped.
+func ParseFile(r io.Reader) ([]Mapping, error) {
+	var mappings []Mapping
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		m, err := ParseMapping(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		mappings = append(mappings, m)
+	}
+	return mappings, scanner.Err()
+}
+
+// Resolve looks up the referenced fields and returns the environment entries in VAR=value form
+// together with the resolved values to mask. Each item is fetched once.
+// A later mapping of the same variable overrides an earlier one.
+func Resolve(mappings []Mapping, getItem func(name string) (*models.Item, error)) ([]string, []string, error) {
+	items := make(map[string]*models.Item)
+	values := make(map[string]string)
+	order := make([]string, 0, len(mappings))
+	for _, m := range mappings {
+		item, ok := items[m.Item]
+		if !ok {
+			var err error
+			item, err = getItem(m.Item)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to resolve %s: %w", m.Var, err)
+			}
+			items[m.Item] = item
+		}
+		value, ok := item.Field(m.Field)
+		if !ok {
+			return nil, nil, fmt.Errorf("failed to resolve %s: %w: %s has no field %q", m.Var, constants.ErrFieldNotFound, m.Item, m.Field)
+		}
+		if _, ok = values[m.Var]; !ok {
+			order = append(order, m.Var)
+		}
+		values[m.Var] = value
+	}
+	env := make([]string, len(order))
+	secrets := make([]string, 0, len(order))
+	for i, name := range order {
+		env[i] = name + "=" + values[name]
+		secrets = append(secrets, values[name])
+	}
+	return env, secrets, nil
+}
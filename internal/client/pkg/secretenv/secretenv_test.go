@@ -0,0 +1,87 @@
+package secretenv
+
+import (
+	"errors"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/Mldlr/storety/internal/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestParseMapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    Mapping
+		wantErr bool
+	}{
+		{
+			name: "Simple reference",
+			in:   "DB_PASS=prod-db.password",
+			want: Mapping{Var: "DB_PASS", Item: "prod-db", Field: "password"},
+		},
+		{
+			name: "Item name with dots",
+			in:   "API_KEY=api.example.com.text",
+			want: Mapping{Var: "API_KEY", Item: "api.example.com", Field: "text"},
+		},
+		{name: "Missing field", in: "DB_PASS=prod-db", wantErr: true},
+		{name: "Empty field", in: "DB_PASS=prod-db.", wantErr: true},
+		{name: "Invalid variable", in: "1DB=prod-db.password", wantErr: true},
+		{name: "Missing reference", in: "DB_PASS", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMapping(tt.in)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseFile(t *testing.T) {
+	manifest := "# database\nDB_USER=prod-db.login\n\n  DB_PASS = prod-db.password\n"
+	mappings, err := ParseFile(strings.NewReader(manifest))
+	require.NoError(t, err)
+	assert.Equal(t, []Mapping{
+		{Var: "DB_USER", Item: "prod-db", Field: "login"},
+		{Var: "DB_PASS", Item: "prod-db", Field: "password"},
+	}, mappings)
+
+	_, err = ParseFile(strings.NewReader("DB_USER=prod-db.login\nbroken\n"))
+	assert.ErrorContains(t, err, "line 2")
+}
+
+func TestResolve(t *testing.T) {
+	fetched := 0
+	getItem := func(name string) (*models.Item, error) {
+		fetched++
+		if name != "prod-db" {
+			return nil, constants.ErrGetData
+		}
+		return &models.Item{Name: name, Type: "Cred", Fields: []models.Field{
+			{Name: "login", Value: "admin"},
+			{Name: "password", Value: "s3cret"},
+		}}, nil
+	}
+	env, secrets, err := Resolve([]Mapping{
+		{Var: "DB_USER", Item: "prod-db", Field: "login"},
+		{Var: "DB_PASS", Item: "prod-db", Field: "login"},
+		{Var: "DB_PASS", Item: "prod-db", Field: "password"},
+	}, getItem)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DB_USER=admin", "DB_PASS=s3cret"}, env)
+	assert.Equal(t, []string{"admin", "s3cret"}, secrets)
+	assert.Equal(t, 1, fetched)
+
+	_, _, err = Resolve([]Mapping{{Var: "X", Item: "missing", Field: "text"}}, getItem)
+	assert.True(t, errors.Is(err, constants.ErrGetData))
+	_, _, err = Resolve([]Mapping{{Var: "X", Item: "prod-db", Field: "cvv"}}, getItem)
+	assert.True(t, errors.Is(err, constants.ErrFieldNotFound))
+}
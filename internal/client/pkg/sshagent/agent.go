@@ -0,0 +1,210 @@
+package sshagent
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/models"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"log"
+	"net"
+	"sync"
+)
+
+var (
+	// ErrReadOnly is returned for requests changing the keys or locking the agent,
+	// which are managed through the vault instead.
+	ErrReadOnly = errors.New("keys are managed in the vault, use the storety client to change them")
+
+	// ErrKeyNotFound is returned when a signature is requested for a key that is not in the vault.
+	ErrKeyNotFound = errors.New("key not found")
+
+	// ErrRefused is returned when the user refuses the use of a key requiring confirmation.
+	ErrRefused = errors.New("use of the key was refused")
+)
+
+// Vault is the part of the vault service read by the ssh agent.
+type Vault interface {
+	ListData() ([]models.DataInfo, error)
+	GetData(name string) ([]byte, string, error)
+}
+
+// ConfirmFunc asks the user whether the key stored in the named item may be used and reports the answer.
+type ConfirmFunc func(name, comment string) bool
+
+// Agent is an agent.ExtendedAgent serving the SSHKey items of the vault.
+// Keys with confirmation enabled are used only after the confirm function approves every signature.
+type Agent struct {
+	vault   Vault
+	confirm ConfirmFunc
+
+	// mu serializes requests, so that confirmations are asked one at a time.
+	mu sync.Mutex
+}
+
+// key is a decoded SSHKey item.
+type key struct {
+	name   string
+	key    *models.SSHKey
+	signer ssh.Signer
+}
+
+// New creates an Agent reading keys from the vault and asking for confirmation with the confirm function.
+// A nil confirm function refuses every key requiring confirmation.
+func New(vault Vault, confirm ConfirmFunc) *Agent {
+	return &Agent{vault: vault, confirm: confirm}
+}
+
+// Serve serves agent connections accepted on the listener until the context is done.
+func (a *Agent) Serve(ctx context.Context, listener net.Listener) error {
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go func() {
+			defer conn.Close()
+			agent.ServeAgent(a, conn)
+		}()
+	}
+}
+
+// keys reads the SSH keys from the vault. Items that fail to decode are logged and skipped.
+func (a *Agent) keys() ([]key, error) {
+	infos, err := a.vault.ListData()
+	if err != nil {
+		return nil, err
+	}
+	var keys []key
+	for _, info := range infos {
+		if info.Type != ItemType {
+			continue
+		}
+		content, _, err := a.vault.GetData(info.Name)
+		if err != nil {
+			return nil, err
+		}
+		k, signer, err := parseKey(content)
+		if err != nil {
+			log.Printf("Skipping ssh key %s: %v\n", info.Name, err)
+			continue
+		}
+		keys = append(keys, key{name: info.Name, key: k, signer: signer})
+	}
+	return keys, nil
+}
+
+// List returns the public keys stored in the vault.
+func (a *Agent) List() ([]*agent.Key, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	keys, err := a.keys()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*agent.Key, 0, len(keys))
+	for _, k := range keys {
+		public := k.signer.PublicKey()
+		list = append(list, &agent.Key{Format: public.Type(), Blob: public.Marshal(), Comment: k.key.Comment})
+	}
+	return list, nil
+}
+
+// Sign returns a signature of the data made with the stored key matching the public key.
+func (a *Agent) Sign(public ssh.PublicKey, data []byte) (*ssh.Signature, error) {
+	return a.SignWithFlags(public, data, 0)
+}
+
+// SignWithFlags signs like Sign, selecting the RSA signature algorithm with the flags.
+func (a *Agent) SignWithFlags(public ssh.PublicKey, data []byte, flags agent.SignatureFlags) (*ssh.Signature, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	keys, err := a.keys()
+	if err != nil {
+		return nil, err
+	}
+	wanted := public.Marshal()
+	for _, k := range keys {
+		if !bytes.Equal(k.signer.PublicKey().Marshal(), wanted) {
+			continue
+		}
+		if k.key.Confirm && (a.confirm == nil || !a.confirm(k.name, k.key.Comment)) {
+			return nil, ErrRefused
+		}
+		if flags == 0 {
+			return k.signer.Sign(rand.Reader, data)
+		}
+		algorithmSigner, ok := k.signer.(ssh.AlgorithmSigner)
+		if !ok {
+			return nil, fmt.Errorf("key %s does not support signature flags", k.name)
+		}
+		var algorithm string
+		switch flags {
+		case agent.SignatureFlagRsaSha256:
+			algorithm = ssh.KeyAlgoRSASHA256
+		case agent.SignatureFlagRsaSha512:
+			algorithm = ssh.KeyAlgoRSASHA512
+		default:
+			return nil, fmt.Errorf("unsupported signature flags: %d", flags)
+		}
+		return algorithmSigner.SignWithAlgorithm(rand.Reader, data, algorithm)
+	}
+	return nil, ErrKeyNotFound
+}
+
+// Signers returns signers for the stored keys that do not require confirmation.
+func (a *Agent) Signers() ([]ssh.Signer, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	keys, err := a.keys()
+	if err != nil {
+		return nil, err
+	}
+	var signers []ssh.Signer
+	for _, k := range keys {
+		if !k.key.Confirm {
+			signers = append(signers, k.signer)
+		}
+	}
+	return signers, nil
+}
+
+// Add is not supported, keys are created in the vault.
+func (a *Agent) Add(key agent.AddedKey) error {
+	return ErrReadOnly
+}
+
+// Remove is not supported, keys are deleted from the vault.
+func (a *Agent) Remove(key ssh.PublicKey) error {
+	return ErrReadOnly
+}
+
+// RemoveAll is not supported, keys are deleted from the vault.
+func (a *Agent) RemoveAll() error {
+	return ErrReadOnly
+}
+
+// Lock is not supported, the vault is locked with the lock command instead.
+func (a *Agent) Lock(passphrase []byte) error {
+	return ErrReadOnly
+}
+
+// Unlock is not supported, the vault is unlocked with the unlock command instead.
+func (a *Agent) Unlock(passphrase []byte) error {
+	return ErrReadOnly
+}
+
+// Extension is not supported.
+func (a *Agent) Extension(extensionType string, contents []byte) ([]byte, error) {
+	return nil, agent.ErrExtensionUnsupported
+}
@@ -0,0 +1,100 @@
+package sshagent
+
+import (
+	"encoding/json"
+	"github.com/Mldlr/storety/internal/client/service/vault/vaulttest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"net"
+	"testing"
+)
+
+// addKey generates and stores an SSHKey item, returning its public key.
+func addKey(t *testing.T, v *vaulttest.Fake, name string, confirm bool) ssh.PublicKey {
+	key, err := GenerateKey(name)
+	require.NoError(t, err)
+	key.Confirm = confirm
+	content, err := json.Marshal(key)
+	require.NoError(t, err)
+	v.Put(name, ItemType, content)
+	public, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key.PublicKey))
+	require.NoError(t, err)
+	return public
+}
+
+// dial serves the agent over a pipe and returns a client of it.
+func dial(t *testing.T, a *Agent) agent.ExtendedAgent {
+	server, client := net.Pipe()
+	go agent.ServeAgent(a, server)
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+	return agent.NewClient(client)
+}
+
+func TestAgent_List(t *testing.T) {
+	vault := vaulttest.New(nil)
+	addKey(t, vault, "github", false)
+	vault.Put("note", "Text", []byte(`{"text":"","meta":""}`))
+	vault.Put("broken", ItemType, []byte(`{"private_key":"garbage"}`))
+
+	keys, err := dial(t, New(vault, nil)).List()
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, "github", keys[0].Comment)
+	assert.Equal(t, ssh.KeyAlgoED25519, keys[0].Format)
+}
+
+func TestAgent_Sign(t *testing.T) {
+	vault := vaulttest.New(nil)
+	plain := addKey(t, vault, "github", false)
+	guarded := addKey(t, vault, "prod", true)
+	unknown, err := GenerateKey("unknown")
+	require.NoError(t, err)
+	unknownPublic, _, _, _, err := ssh.ParseAuthorizedKey([]byte(unknown.PublicKey))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		key      ssh.PublicKey
+		confirm  ConfirmFunc
+		wantErr  bool
+		confirms []string
+	}{
+		{name: "Key without confirmation", key: plain},
+		{name: "Confirmed key", key: guarded, confirm: func(name, comment string) bool { return true }, confirms: []string{"prod"}},
+		{name: "Refused key", key: guarded, confirm: func(name, comment string) bool { return false }, wantErr: true, confirms: []string{"prod"}},
+		{name: "Confirmation unavailable", key: guarded, wantErr: true},
+		{name: "Unknown key", key: unknownPublic, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var confirms []string
+			var confirm ConfirmFunc
+			if tt.confirm != nil {
+				confirm = func(name, comment string) bool {
+					confirms = append(confirms, name)
+					return tt.confirm(name, comment)
+				}
+			}
+			data := []byte("session")
+			signature, err := dial(t, New(vault, confirm)).Sign(tt.key, data)
+			assert.Equal(t, tt.confirms, confirms)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.NoError(t, tt.key.Verify(data, signature))
+		})
+	}
+}
+
+func TestAgent_ReadOnly(t *testing.T) {
+	client := dial(t, New(vaulttest.New(nil), nil))
+	assert.Error(t, client.RemoveAll())
+	assert.Error(t, client.Lock([]byte("passphrase")))
+}
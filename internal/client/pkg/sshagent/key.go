@@ -0,0 +1,90 @@
+// Package sshagent implements an ssh agent serving the SSH keys stored in the vault.
+// Keys are SSHKey items read from the vault on every request, so keys added or deleted
+// on other devices are served after the next sync without restarting the agent.
+package sshagent
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/models"
+	"golang.org/x/crypto/ssh"
+	"strings"
+)
+
+// ItemType is the type of the data items holding SSH keys.
+const ItemType = "SSHKey"
+
+// ErrPassphraseRequired is returned by ImportKey for an encrypted key imported without a passphrase.
+var ErrPassphraseRequired = errors.New("private key is encrypted, a passphrase is required")
+
+// GenerateKey generates a new Ed25519 key pair with the comment.
+func GenerateKey(comment string) (*models.SSHKey, error) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return newKey(private, comment)
+}
+
+// ImportKey parses a PEM encoded private key in the OpenSSH, PKCS#1, PKCS#8 or SEC 1 format.
+// Encrypted keys are decrypted with the passphrase, as the vault encrypts the stored key itself.
+func ImportKey(pemBytes, passphrase []byte, comment string) (*models.SSHKey, error) {
+	var raw interface{}
+	var err error
+	if len(passphrase) == 0 {
+		raw, err = ssh.ParseRawPrivateKey(pemBytes)
+	} else {
+		raw, err = ssh.ParseRawPrivateKeyWithPassphrase(pemBytes, passphrase)
+	}
+	if err != nil {
+		var missing *ssh.PassphraseMissingError
+		if errors.As(err, &missing) {
+			return nil, ErrPassphraseRequired
+		}
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	return newKey(raw, comment)
+}
+
+// newKey encodes the private key as PKCS#8 PEM together with its public key in the authorized_keys format.
+func newKey(raw interface{}, comment string) (*models.SSHKey, error) {
+	// OpenSSH keys are parsed into a pointer, which PKCS#8 does not accept.
+	if private, ok := raw.(*ed25519.PrivateKey); ok {
+		raw = *private
+	}
+	signer, err := ssh.NewSignerFromKey(raw)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(raw)
+	if err != nil {
+		return nil, err
+	}
+	public := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(signer.PublicKey())))
+	if comment != "" {
+		public += " " + comment
+	}
+	return &models.SSHKey{
+		PrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		PublicKey:  public,
+		Comment:    comment,
+	}, nil
+}
+
+// parseKey decodes the content of an SSHKey item and creates a signer for its private key.
+func parseKey(content []byte) (*models.SSHKey, ssh.Signer, error) {
+	key := &models.SSHKey{}
+	if err := json.Unmarshal(content, key); err != nil {
+		return nil, nil, err
+	}
+	signer, err := ssh.ParsePrivateKey([]byte(key.PrivateKey))
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, signer, nil
+}
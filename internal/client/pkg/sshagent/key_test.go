@@ -0,0 +1,72 @@
+package sshagent
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"strings"
+	"testing"
+)
+
+func TestGenerateKey(t *testing.T) {
+	key, err := GenerateKey("alice@laptop")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(key.PublicKey, "ssh-ed25519 "))
+	assert.True(t, strings.HasSuffix(key.PublicKey, " alice@laptop"))
+	assert.Equal(t, "alice@laptop", key.Comment)
+
+	signer, err := ssh.ParsePrivateKey([]byte(key.PrivateKey))
+	require.NoError(t, err)
+	public, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(key.PublicKey))
+	require.NoError(t, err)
+	assert.Equal(t, "alice@laptop", comment)
+	assert.Equal(t, signer.PublicKey().Marshal(), public.Marshal())
+}
+
+func TestImportKey(t *testing.T) {
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalECPrivateKey(private)
+	require.NoError(t, err)
+	plain := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+	// Legacy PEM encryption is what older ssh-keygen versions write.
+	block, err := x509.EncryptPEMBlock(rand.Reader, "EC PRIVATE KEY", der, []byte("passphrase"), x509.PEMCipherAES256)
+	require.NoError(t, err)
+	encrypted := pem.EncodeToMemory(block)
+
+	tests := []struct {
+		name       string
+		pem        []byte
+		passphrase string
+		wantErr    error
+	}{
+		{name: "Plain key", pem: plain},
+		{name: "Encrypted key", pem: encrypted, passphrase: "passphrase"},
+		{name: "Encrypted key without passphrase", pem: encrypted, wantErr: ErrPassphraseRequired},
+		{name: "Not a key", pem: []byte("garbage"), wantErr: assert.AnError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := ImportKey(tt.pem, []byte(tt.passphrase), "")
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				if tt.wantErr != assert.AnError {
+					assert.ErrorIs(t, err, tt.wantErr)
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(key.PublicKey, "ecdsa-sha2-nistp256 "))
+			signer, err := ssh.ParsePrivateKey([]byte(key.PrivateKey))
+			require.NoError(t, err)
+			expected, err := ssh.NewPublicKey(&private.PublicKey)
+			require.NoError(t, err)
+			assert.Equal(t, expected.Marshal(), signer.PublicKey().Marshal())
+		})
+	}
+}
@@ -0,0 +1,124 @@
+// Package tls provides the TLS configuration used by the client to verify the Storety server.
+package tls
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/config"
+	"net"
+	"os"
+	"time"
+)
+
+// fingerprintPrefix prefixes fingerprints with the name of the hash algorithm.
+const fingerprintPrefix = "sha256/"
+
+var (
+	// ErrNoTrustAnchor is returned when neither a CA bundle nor a server fingerprint is configured.
+	ErrNoTrustAnchor = errors.New("no CA bundle or server fingerprint configured")
+
+	// ErrFingerprintMismatch is returned when the server presents a key different from the pinned one.
+	ErrFingerprintMismatch = errors.New("server key fingerprint mismatch")
+)
+
+// Fingerprint returns the SHA-256 fingerprint of the certificate's subject public key info in the "sha256/<base64>" form.
+func Fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return fingerprintPrefix + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// NewConfig returns a TLS configuration verifying the server against the CA bundle and the pinned fingerprint from the config.
+// With a CA bundle the certificate chain and the server name are verified, with a fingerprint the leaf key must match the pin.
+// If both are set, both checks must pass. The enrolled device certificate is presented to the server when requested.
+func NewConfig(cfg *config.Config) (*tls.Config, error) {
+	if cfg.CAFile == "" && cfg.ServerFingerprint == "" {
+		return nil, ErrNoTrustAnchor
+	}
+	tlsCfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", cfg.CAFile)
+		}
+		tlsCfg.RootCAs = pool
+		tlsCfg.ServerName = serverName(cfg)
+	} else {
+		// The pinned key replaces chain and name verification, which is done in VerifyPeerCertificate instead.
+		tlsCfg.InsecureSkipVerify = true
+	}
+	if cfg.ServerFingerprint != "" {
+		tlsCfg.VerifyPeerCertificate = verifyFingerprint(cfg.ServerFingerprint)
+	}
+	tlsCfg.GetClientCertificate = deviceCertificate(cfg)
+	return tlsCfg, nil
+}
+
+// FetchFingerprint connects to the address without verifying the server and returns the fingerprint of the presented key.
+// It is used to offer trust on first use.
+func FetchFingerprint(address string, timeout time.Duration) (string, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", address, &tls.Config{InsecureSkipVerify: true})
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return "", errors.New("server presented no certificate")
+	}
+	return Fingerprint(certs[0]), nil
+}
+
+// verifyFingerprint returns a VerifyPeerCertificate callback comparing the leaf key with the pinned fingerprint.
+func verifyFingerprint(pinned string) func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return errors.New("server presented no certificate")
+		}
+		leaf, err := x509.ParseCertificate(rawCerts[0])
+		if err != nil {
+			return err
+		}
+		if got := Fingerprint(leaf); got != pinned {
+			return fmt.Errorf("%w: expected %s, got %s; the server key has changed or the connection is intercepted, "+
+				"update server_fingerprint in the config only if the change is expected", ErrFingerprintMismatch, pinned, got)
+		}
+		return nil
+	}
+}
+
+// deviceCertificate returns a GetClientCertificate callback presenting the enrolled device certificate.
+// The files are read on every handshake, so a certificate enrolled after startup is used by new connections.
+// Without an enrolled device no certificate is presented.
+func deviceCertificate(cfg *config.Config) func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		cert, err := tls.LoadX509KeyPair(cfg.DeviceCertFile, cfg.DeviceKeyFile)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return &tls.Certificate{}, nil
+			}
+			return nil, fmt.Errorf("failed to load device certificate: %w", err)
+		}
+		return &cert, nil
+	}
+}
+
+// serverName returns the name verified against the server certificate.
+func serverName(cfg *config.Config) string {
+	if cfg.ServerName != "" {
+		return cfg.ServerName
+	}
+	host, _, err := net.SplitHostPort(cfg.ServiceAddress)
+	if err != nil || host == "" {
+		return "localhost"
+	}
+	return host
+}
@@ -0,0 +1,182 @@
+package tls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/Mldlr/storety/internal/client/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestCert returns a self-signed certificate valid for localhost and 127.0.0.1.
+func newTestCert(t *testing.T) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+// serveTLS starts a TLS listener completing handshakes with the certificate and returns its address.
+func serveTLS(t *testing.T, cert tls.Certificate) string {
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_ = conn.(*tls.Conn).Handshake()
+				conn.Close()
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestNewConfig(t *testing.T) {
+	cert := newTestCert(t)
+	other := newTestCert(t)
+	address := serveTLS(t, cert)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}), 0600)
+	require.NoError(t, err)
+	otherCAFile := filepath.Join(t.TempDir(), "other.pem")
+	err = os.WriteFile(otherCAFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: other.Certificate[0]}), 0600)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		cfg           *config.Config
+		wantConfigErr error
+		wantDialErr   bool
+	}{
+		{
+			name:          "No trust anchor",
+			cfg:           &config.Config{ServiceAddress: address},
+			wantConfigErr: ErrNoTrustAnchor,
+		},
+		{
+			name: "Pinned fingerprint matches",
+			cfg:  &config.Config{ServiceAddress: address, ServerFingerprint: Fingerprint(cert.Leaf)},
+		},
+		{
+			name:        "Pinned fingerprint mismatch",
+			cfg:         &config.Config{ServiceAddress: address, ServerFingerprint: Fingerprint(other.Leaf)},
+			wantDialErr: true,
+		},
+		{
+			name: "Trusted CA bundle",
+			cfg:  &config.Config{ServiceAddress: address, CAFile: caFile},
+		},
+		{
+			name:        "Untrusted CA bundle",
+			cfg:         &config.Config{ServiceAddress: address, CAFile: otherCAFile},
+			wantDialErr: true,
+		},
+		{
+			name:        "Wrong server name",
+			cfg:         &config.Config{ServiceAddress: address, CAFile: caFile, ServerName: "storety.example.com"},
+			wantDialErr: true,
+		},
+		{
+			name: "CA bundle and pin",
+			cfg:  &config.Config{ServiceAddress: address, CAFile: caFile, ServerFingerprint: Fingerprint(cert.Leaf)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tlsCfg, err := NewConfig(tt.cfg)
+			if tt.wantConfigErr != nil {
+				assert.ErrorIs(t, err, tt.wantConfigErr)
+				return
+			}
+			require.NoError(t, err)
+			conn, err := tls.Dial("tcp", address, tlsCfg)
+			if tt.wantDialErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			conn.Close()
+		})
+	}
+}
+
+func TestVerifyFingerprint(t *testing.T) {
+	cert := newTestCert(t)
+	other := newTestCert(t)
+
+	verify := verifyFingerprint(Fingerprint(cert.Leaf))
+	assert.NoError(t, verify(cert.Certificate, nil))
+	assert.ErrorIs(t, verify(other.Certificate, nil), ErrFingerprintMismatch)
+	assert.Error(t, verify(nil, nil))
+}
+
+func TestFetchFingerprint(t *testing.T) {
+	cert := newTestCert(t)
+	address := serveTLS(t, cert)
+
+	fingerprint, err := FetchFingerprint(address, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, Fingerprint(cert.Leaf), fingerprint)
+	assert.Regexp(t, `^sha256/[A-Za-z0-9+/]{43}=$`, fingerprint)
+}
+
+func TestDeviceCertificate(t *testing.T) {
+	cert := newTestCert(t)
+	tmpDir := t.TempDir()
+	cfg := &config.Config{
+		DeviceCertFile: filepath.Join(tmpDir, "device.pem"),
+		DeviceKeyFile:  filepath.Join(tmpDir, "device_key.pem"),
+	}
+	getCert := deviceCertificate(cfg)
+
+	got, err := getCert(nil)
+	require.NoError(t, err)
+	assert.Empty(t, got.Certificate)
+
+	keyBytes, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	require.NoError(t, err)
+	err = os.WriteFile(cfg.DeviceCertFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}), 0600)
+	require.NoError(t, err)
+	err = os.WriteFile(cfg.DeviceKeyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyBytes}), 0600)
+	require.NoError(t, err)
+	got, err = getCert(nil)
+	require.NoError(t, err)
+	assert.Equal(t, cert.Certificate, got.Certificate)
+
+	err = os.WriteFile(cfg.DeviceKeyFile, []byte("broken"), 0600)
+	require.NoError(t, err)
+	_, err = getCert(nil)
+	assert.Error(t, err)
+}
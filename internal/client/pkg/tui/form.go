@@ -0,0 +1,329 @@
+package tui
+
+import (
+	"encoding/base64"
+	"errors"
+	"github.com/Mldlr/storety/internal/client/itemtype"
+	"github.com/Mldlr/storety/internal/client/pkg/passgen"
+	"github.com/Mldlr/storety/internal/client/service/vault"
+	"github.com/gdamore/tcell/v2"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// input is a single line text input.
+type input struct {
+	label  string
+	value  []rune
+	cursor int
+	// secret inputs are shown as asterisks unless revealed.
+	secret bool
+	// field is the item type field or flag set by the input, nil for the item name.
+	field *itemtype.Field
+	// flag inputs set the additional flags of the type read by its Prepare hook.
+	flag bool
+	// generated is set while the value is a generated password.
+	generated bool
+}
+
+// set replaces the value, moving the cursor to its end.
+func (in *input) set(value string) {
+	in.value = []rune(value)
+	in.cursor = len(in.value)
+	in.generated = false
+}
+
+// String returns the value.
+func (in *input) String() string {
+	return string(in.value)
+}
+
+// display returns the value as shown, asterisks for secret values unless revealed.
+func (in *input) display(reveal bool) string {
+	if in.field != nil && in.field.Kind == itemtype.Bool {
+		if in.String() == "true" {
+			return "[x]"
+		}
+		return "[ ]"
+	}
+	if in.secret && !reveal {
+		return strings.Repeat("*", len(in.value))
+	}
+	return in.String()
+}
+
+// handleKey edits the value, space toggles bool inputs.
+func (in *input) handleKey(ev *tcell.EventKey) {
+	if in.field != nil && in.field.Kind == itemtype.Bool {
+		if ev.Key() == tcell.KeyRune && ev.Rune() == ' ' {
+			in.set(strconv.FormatBool(in.String() != "true"))
+		}
+		return
+	}
+	switch ev.Key() {
+	case tcell.KeyLeft:
+		in.cursor = clamp(in.cursor-1, 0, len(in.value))
+	case tcell.KeyRight:
+		in.cursor = clamp(in.cursor+1, 0, len(in.value))
+	case tcell.KeyHome, tcell.KeyCtrlA:
+		in.cursor = 0
+	case tcell.KeyEnd, tcell.KeyCtrlE:
+		in.cursor = len(in.value)
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if in.cursor > 0 {
+			in.value = append(in.value[:in.cursor-1], in.value[in.cursor:]...)
+			in.cursor--
+			in.generated = false
+		}
+	case tcell.KeyDelete:
+		if in.cursor < len(in.value) {
+			in.value = append(in.value[:in.cursor], in.value[in.cursor+1:]...)
+			in.generated = false
+		}
+	case tcell.KeyCtrlU:
+		in.set("")
+	case tcell.KeyRune:
+		in.value = append(in.value[:in.cursor], append([]rune{ev.Rune()}, in.value[in.cursor:]...)...)
+		in.cursor++
+		in.generated = false
+	}
+}
+
+// form is the form creating a new item of a type or editing an item.
+type form struct {
+	t *itemtype.Type
+	// name is the input of the name of a new item, nil when editing.
+	name *input
+	// editing is the name of the edited item, content its content and original its values.
+	editing  string
+	content  []byte
+	original itemtype.Values
+	inputs   []*input
+	focus    int
+	reveal   bool
+	// policy is the password policy of generated passwords, stored in the item with them.
+	policy passgen.Policy
+	// policies are the policies of item name prefixes, giving the policy of a new item once it is named.
+	policies passgen.PrefixPolicies
+	err      string
+}
+
+// newCreateForm returns the form creating an item of the type, with inputs for the fields the user enters
+// and for the flags of the type. File fields take the path of the file.
+func newCreateForm(t *itemtype.Type, policies passgen.PrefixPolicies) *form {
+	f := &form{t: t, name: &input{label: "item name"}, policy: passgen.DefaultPolicy, policies: policies}
+	f.inputs = append(f.inputs, f.name)
+	for i := range t.Fields {
+		field := &t.Fields[i]
+		if field.Input == itemtype.Generated {
+			continue
+		}
+		in := newInput(field)
+		if field.Input == itemtype.File {
+			in.label += " file"
+		}
+		f.inputs = append(f.inputs, in)
+	}
+	for i := range t.Flags {
+		in := newInput(&t.Flags[i])
+		in.flag = true
+		f.inputs = append(f.inputs, in)
+	}
+	return f
+}
+
+// newEditForm returns the form editing the item with the content, with inputs for the fields the edit command changes.
+func newEditForm(t *itemtype.Type, name string, content []byte, policies passgen.PrefixPolicies) (*form, error) {
+	values, err := t.Decode(content)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := policies.Resolve(name, content)
+	if err != nil {
+		return nil, err
+	}
+	f := &form{t: t, editing: name, content: content, original: values, policy: policy}
+	for i := range t.Fields {
+		field := &t.Fields[i]
+		if field.Input == itemtype.Generated || field.Kind == itemtype.Bytes {
+			continue
+		}
+		in := newInput(field)
+		in.set(values[field.Name])
+		f.inputs = append(f.inputs, in)
+	}
+	if len(f.inputs) == 0 {
+		return nil, errors.New("fields of " + t.Noun + " items cannot be edited, create a new item instead")
+	}
+	return f, nil
+}
+
+// newInput returns the input of the field, labelled with its name like in the detail pane.
+func newInput(field *itemtype.Field) *input {
+	in := &input{label: field.Name, secret: field.Secret, field: field}
+	if field.Kind == itemtype.Bool {
+		in.set("false")
+	}
+	return in
+}
+
+// itemName returns the name of the created or edited item.
+func (f *form) itemName() string {
+	if f.name != nil {
+		return strings.TrimSpace(f.name.String())
+	}
+	return f.editing
+}
+
+// title returns the title of the form.
+func (f *form) title() string {
+	if f.name != nil {
+		return "New " + f.t.Noun
+	}
+	return "Edit " + f.editing
+}
+
+// next moves the focus by delta inputs, wrapping around.
+func (f *form) next(delta int) {
+	f.focus = (f.focus + delta + len(f.inputs)) % len(f.inputs)
+}
+
+// handleKey moves between the inputs or edits the focused one.
+func (f *form) handleKey(ev *tcell.EventKey) {
+	switch ev.Key() {
+	case tcell.KeyTab, tcell.KeyDown:
+		f.next(1)
+	case tcell.KeyBacktab, tcell.KeyUp:
+		f.next(-1)
+	default:
+		f.inputs[f.focus].handleKey(ev)
+	}
+}
+
+// generate sets the focused input to a password generated with the policy of the item.
+// A new item takes the policy of the prefix of the name entered so far.
+func (f *form) generate() error {
+	in := f.inputs[f.focus]
+	if in.field == nil || !in.field.Generate {
+		return errors.New("only password fields can be generated")
+	}
+	if f.name != nil {
+		f.policy, _ = f.policies.Resolve(f.itemName(), nil)
+	}
+	password, err := passgen.Generate(f.policy)
+	if err != nil {
+		return err
+	}
+	in.set(password)
+	in.generated = true
+	return nil
+}
+
+// save creates or updates the item, returning the message to show.
+// The Prepare hook of a new item gets the prompter to ask for further values like an otpauth URI.
+func (f *form) save(v vault.Service, p itemtype.Prompter) (string, error) {
+	if f.name != nil {
+		return f.create(v, p)
+	}
+	return f.update(v)
+}
+
+// create creates the item from the inputs, the same way the create commands do.
+func (f *form) create(v vault.Service, p itemtype.Prompter) (string, error) {
+	name := f.itemName()
+	if name == "" {
+		return "", errors.New("item name must not be empty")
+	}
+	values := itemtype.Values{}
+	for _, in := range f.inputs {
+		if in.field == nil || in.flag {
+			continue
+		}
+		values[in.field.Name] = in.String()
+		if in.field.Input == itemtype.File && in.String() != "" {
+			content, err := os.ReadFile(in.String())
+			if err != nil {
+				return "", err
+			}
+			values[in.field.Name] = base64.StdEncoding.EncodeToString(content)
+		}
+	}
+	if f.t.Prepare != nil {
+		if err := f.t.Prepare(values, p); err != nil {
+			return "", err
+		}
+	}
+	if err := vault.CheckLinks(v, f.t, values); err != nil {
+		return "", err
+	}
+	content, err := f.t.Encode(values)
+	if err != nil {
+		return "", err
+	}
+	if content, err = f.storePolicy(content); err != nil {
+		return "", err
+	}
+	if err = v.CreateData(name, f.t.Name, content); err != nil {
+		return "", err
+	}
+	return f.t.CreatedMessage(values), nil
+}
+
+// update sets the changed inputs in the content of the edited item.
+func (f *form) update(v vault.Service) (string, error) {
+	changes := itemtype.Values{}
+	for _, in := range f.inputs {
+		if in.String() != f.original[in.field.Name] {
+			changes[in.field.Name] = in.String()
+		}
+	}
+	if len(changes) == 0 {
+		return "", errors.New("nothing changed")
+	}
+	if err := vault.CheckLinks(v, f.t, changes); err != nil {
+		return "", err
+	}
+	updated, err := f.t.Update(f.content, changes)
+	if err != nil {
+		return "", err
+	}
+	if updated, err = f.storePolicy(updated); err != nil {
+		return "", err
+	}
+	if err = v.UpdateData(f.editing, updated); err != nil {
+		return "", err
+	}
+	return "Successfully updated " + f.t.Noun, nil
+}
+
+// storePolicy stores the password policy in the content when an input holds a generated password.
+func (f *form) storePolicy(content []byte) ([]byte, error) {
+	for _, in := range f.inputs {
+		if in.generated {
+			return passgen.SetItemPolicy(content, f.policy)
+		}
+	}
+	return content, nil
+}
+
+// prompter implements itemtype.Prompter for the Prepare hooks of new items.
+type prompter struct {
+	app  *App
+	form *form
+}
+
+// Secret implements the itemtype.Prompter Secret method.
+func (p prompter) Secret(prompt string) (string, error) {
+	return p.app.ask(prompt, true)
+}
+
+// Flag implements the itemtype.Prompter Flag method.
+func (p prompter) Flag(name string) string {
+	for _, in := range p.form.inputs {
+		if in.field != nil && in.field.Name == name {
+			return in.String()
+		}
+	}
+	return ""
+}
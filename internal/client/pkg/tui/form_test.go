@@ -0,0 +1,173 @@
+package tui
+
+import (
+	"github.com/Mldlr/storety/internal/client/itemtype"
+	"github.com/Mldlr/storety/internal/client/pkg/otp"
+	"github.com/Mldlr/storety/internal/client/pkg/passgen"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// chooseType opens the form of a new item of the type.
+func chooseType(t *testing.T, a *App, name string) {
+	t.Helper()
+	typeText(a, "n")
+	require.Equal(t, focusTypes, a.focus)
+	for a.types[a.typeIndex].Name != name {
+		require.Less(t, a.typeIndex, len(a.types)-1, "type %s is not offered", name)
+		press(a, tcell.KeyDown)
+	}
+	press(a, tcell.KeyEnter)
+	require.Equal(t, focusForm, a.focus)
+}
+
+func TestForm_Create(t *testing.T) {
+	a, v, screen, _ := newTestApp(t)
+	chooseType(t, a, itemtype.Cred)
+	assert.Contains(t, contents(screen), "New credentials pair")
+
+	typeText(a, "bank")
+	press(a, tcell.KeyTab)
+	typeText(a, "alice")
+	press(a, tcell.KeyTab)
+	typeText(a, "pa55word")
+	assert.Contains(t, contents(screen), "********")
+	assert.NotContains(t, contents(screen), "pa55word")
+	press(a, tcell.KeyCtrlS)
+
+	assert.Equal(t, focusList, a.focus)
+	assert.Equal(t, "Successfully created new credentials pair", a.status)
+	assert.Equal(t, "bank", a.selectedName())
+	assert.Equal(t, 1, a.sync.changes)
+	values, err := mustLookup(t, itemtype.Cred).Decode(stored(t, v, "bank"))
+	require.NoError(t, err)
+	assert.Equal(t, "alice", values["login"])
+	assert.Equal(t, "pa55word", values["password"])
+}
+
+func TestForm_CreateErrors(t *testing.T) {
+	a, v, screen, _ := newTestApp(t)
+	chooseType(t, a, itemtype.Cred)
+	press(a, tcell.KeyCtrlS)
+	assert.Equal(t, "item name must not be empty", a.form.err)
+
+	typeText(a, "bank")
+	press(a, tcell.KeyTab, tcell.KeyTab, tcell.KeyTab)
+	typeText(a, "not a url")
+	press(a, tcell.KeyCtrlS)
+	assert.Equal(t, focusForm, a.focus, "the form stays open to fix the error")
+	assert.Contains(t, contents(screen), "invalid url")
+	assert.False(t, v.Has("bank"))
+
+	press(a, tcell.KeyEscape)
+	assert.Nil(t, a.form)
+	assert.Equal(t, focusList, a.focus)
+}
+
+func TestForm_CreatePrepared(t *testing.T) {
+	a, v, screen, _ := newTestApp(t)
+	chooseType(t, a, otp.ItemType)
+	typeText(a, "aws-2fa")
+	inject(screen, "otpauth://totp/AWS:alice?secret=JBSWY3DPEHPK3PXP")
+	press(a, tcell.KeyCtrlS)
+
+	require.True(t, v.Has("aws-2fa"), a.status)
+	o, err := otp.Find(v, "aws-2fa")
+	require.NoError(t, err)
+	assert.Equal(t, "AWS", o.Issuer)
+	assert.Equal(t, "alice", o.Account)
+
+	chooseType(t, a, otp.ItemType)
+	typeText(a, "cancelled")
+	screen.InjectKey(tcell.KeyEscape, 0, tcell.ModNone)
+	press(a, tcell.KeyCtrlS)
+	assert.Equal(t, focusForm, a.focus)
+	assert.Empty(t, a.form.err, "cancelling the prompt is no error")
+	assert.False(t, v.Has("cancelled"))
+}
+
+func TestForm_Edit(t *testing.T) {
+	a, v, _, _ := newTestApp(t)
+	typeText(a, "e")
+	require.Equal(t, focusForm, a.focus)
+	assert.Equal(t, "Edit github", a.form.title())
+
+	press(a, tcell.KeyCtrlS)
+	assert.Equal(t, "nothing changed", a.form.err)
+
+	press(a, tcell.KeyCtrlU)
+	typeText(a, "hubot")
+	press(a, tcell.KeyTab, tcell.KeyCtrlG)
+	generated := a.form.inputs[1].String()
+	assert.Len(t, generated, passgen.DefaultPolicy.Length)
+	press(a, tcell.KeyCtrlS)
+	assert.Equal(t, "Successfully updated credentials pair", a.status)
+
+	values, err := mustLookup(t, itemtype.Cred).Decode(stored(t, v, "github"))
+	require.NoError(t, err)
+	assert.Equal(t, "hubot", values["login"])
+	assert.Equal(t, generated, values["password"])
+	assert.Equal(t, "github-2fa", values["otp"], "unchanged fields are kept")
+	_, ok, err := passgen.ItemPolicy(stored(t, v, "github"))
+	require.NoError(t, err)
+	assert.True(t, ok, "the policy of the generated password is stored")
+	assert.Equal(t, "hubot", a.item.Fields[0].Value, "the shown item is reloaded")
+}
+
+func TestForm_Generate(t *testing.T) {
+	f := newCreateForm(mustLookup(t, itemtype.Cred), nil)
+	assert.Error(t, f.generate(), "the name cannot be generated")
+	f.focus = 2
+	require.NoError(t, f.generate())
+	assert.True(t, f.inputs[2].generated)
+	f.handleKey(tcell.NewEventKey(tcell.KeyRune, 'x', tcell.ModNone))
+	assert.False(t, f.inputs[2].generated, "a changed password is no longer generated")
+}
+
+func TestForm_GeneratePrefixPolicy(t *testing.T) {
+	work := passgen.Policy{Length: 8, Classes: []string{passgen.Digits}}
+	f := newCreateForm(mustLookup(t, itemtype.Cred), passgen.PrefixPolicies{"work/": work})
+	f.name.set("work/mail")
+	f.focus = 2
+	require.NoError(t, f.generate())
+	assert.Equal(t, work, f.policy, "a new item takes the policy of its prefix")
+	assert.Regexp(t, `^[0-9]{8}$`, f.inputs[2].String())
+}
+
+func TestInput(t *testing.T) {
+	in := &input{secret: true}
+	for _, k := range []*tcell.EventKey{
+		tcell.NewEventKey(tcell.KeyRune, 'a', tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyRune, 'c', tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyLeft, 0, tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyRune, 'b', tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyEnd, 0, tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyRune, 'd', tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyHome, 0, tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyDelete, 0, tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyRune, 'A', tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyEnd, 0, tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyBackspace2, 0, tcell.ModNone),
+	} {
+		in.handleKey(k)
+	}
+	assert.Equal(t, "Abc", in.String())
+	assert.Equal(t, "***", in.display(false))
+	assert.Equal(t, "Abc", in.display(true))
+
+	toggle := newInput(&itemtype.Field{Name: "confirm", Kind: itemtype.Bool})
+	assert.Equal(t, "[ ]", toggle.display(false))
+	toggle.handleKey(tcell.NewEventKey(tcell.KeyRune, ' ', tcell.ModNone))
+	assert.Equal(t, "true", toggle.String())
+	assert.Equal(t, "[x]", toggle.display(false))
+}
+
+// mustLookup returns the registered type with the name.
+func mustLookup(t *testing.T, name string) *itemtype.Type {
+	t.Helper()
+	typ, ok := itemtype.Lookup(name)
+	require.True(t, ok)
+	return typ
+}
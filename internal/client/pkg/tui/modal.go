@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"github.com/gdamore/tcell/v2"
+)
+
+// modal is a question asked in the status line, answered with an input or with y or n.
+type modal struct {
+	question string
+	// input takes the answer, nil for yes or no questions.
+	input *input
+}
+
+// ask asks the question in the status line and returns the answer, errCancelled if the user presses Esc.
+// It runs its own event loop, so it can be called from code expecting an answer right away,
+// like the Prepare hooks of item types.
+func (a *App) ask(question string, secret bool) (string, error) {
+	in := &input{secret: secret}
+	a.modal = &modal{question: question, input: in}
+	defer func() { a.modal = nil }()
+	for {
+		a.draw()
+		switch ev := a.screen.PollEvent().(type) {
+		case nil:
+			return "", errCancelled
+		case *tcell.EventKey:
+			switch ev.Key() {
+			case tcell.KeyEnter:
+				return in.String(), nil
+			case tcell.KeyEscape, tcell.KeyCtrlC:
+				return "", errCancelled
+			}
+			in.handleKey(ev)
+		default:
+			a.handle(ev)
+		}
+	}
+}
+
+// confirm asks the yes or no question in the status line and reports whether the user pressed y.
+func (a *App) confirm(question string) bool {
+	a.modal = &modal{question: question}
+	defer func() { a.modal = nil }()
+	for {
+		a.draw()
+		switch ev := a.screen.PollEvent().(type) {
+		case nil:
+			return false
+		case *tcell.EventKey:
+			return ev.Key() == tcell.KeyRune && (ev.Rune() == 'y' || ev.Rune() == 'Y')
+		default:
+			a.handle(ev)
+		}
+	}
+}
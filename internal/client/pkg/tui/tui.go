@@ -0,0 +1,594 @@
+// Package tui is the full-screen terminal UI of the client.
+// It lists the items of the vault with a search filter, shows the selected item with secret fields masked,
+// creates and edits items with forms built from their item type and copies fields to the clipboard.
+// All data access goes through the vault service, so the UI works with a logged in client and with the agent.
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/itemtype"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/Mldlr/storety/internal/client/pkg/clipboard"
+	"github.com/Mldlr/storety/internal/client/pkg/otp"
+	"github.com/Mldlr/storety/internal/client/pkg/passgen"
+	"github.com/Mldlr/storety/internal/client/service/vault"
+	"github.com/gdamore/tcell/v2"
+	"sort"
+	"strings"
+	"time"
+)
+
+// errCancelled is returned by prompts the user left with Esc.
+var errCancelled = errors.New("cancelled")
+
+// Options configure the terminal UI.
+type Options struct {
+	// Clipboard receives the copied fields, copying is disabled when nil.
+	Clipboard clipboard.Backend
+	// ClearAfter is how long copied fields stay on the clipboard, zero keeps them.
+	ClearAfter time.Duration
+	// ScheduleClear clears the clipboard after the timeout if it still holds the value.
+	// The clipboard is cleared by this process when nil, which does not happen if it exits first.
+	ScheduleClear func(b clipboard.Backend, value string, timeout time.Duration) error
+	// SyncOnStart syncs the vault with the server when the UI starts.
+	SyncOnStart bool
+	// Policies are the password policies of item name prefixes, used for items without a stored policy.
+	Policies passgen.PrefixPolicies
+	// Now returns the current time, time.Now when nil.
+	Now func() time.Time
+}
+
+// focus is the part of the screen receiving the keys.
+type focus int
+
+const (
+	focusList focus = iota
+	focusDetail
+	focusSearch
+	focusTypes
+	focusForm
+)
+
+// App is the state of the terminal UI.
+type App struct {
+	vault  vault.Service
+	opts   Options
+	screen tcell.Screen
+
+	// items are all items of the vault sorted by name, visible the ones matching the query.
+	items   []models.DataInfo
+	visible []models.DataInfo
+	query   []rune
+	// selected is the index of the selected item in visible, offset the first visible row of the list.
+	selected int
+	offset   int
+	// item is the decoded selected item, itemErr the error decoding it.
+	item    *models.Item
+	itemErr error
+	// field is the index of the selected field of the item.
+	field  int
+	reveal bool
+	focus  focus
+
+	// types are the item types offered for new items, typeIndex the selected one.
+	types     []*itemtype.Type
+	typeIndex int
+	form      *form
+	// modal is the question asked in the status line, nil when there is none.
+	modal *modal
+
+	status    string
+	statusErr bool
+	sync      syncState
+	quit      bool
+}
+
+// syncState is the state of the sync with the server shown in the title bar.
+type syncState struct {
+	running bool
+	// at is when the last sync succeeded, err why the last one failed.
+	at  time.Time
+	err error
+	// changes counts the items changed since the last successful sync.
+	changes int
+}
+
+// String describes the sync state.
+func (s syncState) String() string {
+	var state string
+	switch {
+	case s.running:
+		state = "syncing..."
+	case s.err != nil:
+		state = "sync failed"
+	case s.at.IsZero():
+		state = "not synced"
+	default:
+		state = "synced " + s.at.Local().Format("15:04")
+	}
+	switch {
+	case s.changes == 1:
+		state += ", 1 change to sync"
+	case s.changes > 1:
+		state += fmt.Sprintf(", %d changes to sync", s.changes)
+	}
+	return state
+}
+
+// syncDone is posted to the event loop when a sync started by startSync finishes.
+type syncDone struct {
+	tcell.EventTime
+	err error
+}
+
+// New returns the terminal UI of the vault.
+func New(v vault.Service, opts Options) *App {
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &App{vault: v, opts: opts, types: itemtype.All()}
+}
+
+// Run shows the UI on the initialized screen until the user quits.
+func (a *App) Run(screen tcell.Screen) error {
+	a.screen = screen
+	if err := a.reload(""); err != nil {
+		return err
+	}
+	if a.opts.SyncOnStart {
+		a.startSync()
+	}
+	for !a.quit {
+		a.draw()
+		ev := screen.PollEvent()
+		if ev == nil {
+			return nil
+		}
+		a.handle(ev)
+	}
+	return nil
+}
+
+// handle handles an event of the screen.
+func (a *App) handle(ev tcell.Event) {
+	switch ev := ev.(type) {
+	case *tcell.EventResize:
+		a.screen.Sync()
+	case *syncDone:
+		a.finishSync(ev.err)
+	case *tcell.EventKey:
+		a.status, a.statusErr = "", false
+		switch a.focus {
+		case focusSearch:
+			a.handleSearchKey(ev)
+		case focusTypes:
+			a.handleTypesKey(ev)
+		case focusForm:
+			a.handleFormKey(ev)
+		default:
+			a.handleBrowseKey(ev)
+		}
+	}
+}
+
+// handleBrowseKey handles the keys of the item list and the detail pane.
+func (a *App) handleBrowseKey(ev *tcell.EventKey) {
+	switch ev.Key() {
+	case tcell.KeyCtrlC:
+		a.quit = true
+	case tcell.KeyUp:
+		a.move(-1)
+	case tcell.KeyDown:
+		a.move(1)
+	case tcell.KeyPgUp:
+		a.move(-a.listHeight())
+	case tcell.KeyPgDn:
+		a.move(a.listHeight())
+	case tcell.KeyHome:
+		a.move(-len(a.visible))
+	case tcell.KeyEnd:
+		a.move(len(a.visible))
+	case tcell.KeyTab, tcell.KeyBacktab:
+		if a.focus == focusList && a.item != nil {
+			a.focus = focusDetail
+		} else {
+			a.focus = focusList
+		}
+	case tcell.KeyEnter:
+		if a.item != nil {
+			a.focus = focusDetail
+		}
+	case tcell.KeyEscape:
+		if a.focus == focusDetail {
+			a.focus = focusList
+		} else if len(a.query) > 0 {
+			a.setQuery(nil)
+		}
+	case tcell.KeyRune:
+		a.handleBrowseRune(ev.Rune())
+	}
+}
+
+// handleBrowseRune handles the shortcuts of the item list and the detail pane.
+func (a *App) handleBrowseRune(r rune) {
+	switch {
+	case r == 'q':
+		a.quit = true
+	case r == 'k':
+		a.move(-1)
+	case r == 'j':
+		a.move(1)
+	case r == '/':
+		a.focus = focusSearch
+	case r == 'r':
+		a.reveal = !a.reveal
+	case r == 'c':
+		if a.focus == focusDetail {
+			a.copyField(a.field)
+		} else {
+			a.copyField(-1)
+		}
+	case r >= '1' && r <= '9':
+		a.copyField(int(r - '1'))
+	case r == 'o':
+		a.copyOTP()
+	case r == 'n':
+		a.focus, a.typeIndex = focusTypes, 0
+	case r == 'e':
+		a.edit()
+	case r == 'd':
+		a.delete()
+	case r == 's':
+		a.startSync()
+	}
+}
+
+// handleSearchKey edits the search query, filtering the list as it is typed.
+func (a *App) handleSearchKey(ev *tcell.EventKey) {
+	switch ev.Key() {
+	case tcell.KeyEnter, tcell.KeyTab:
+		a.focus = focusList
+	case tcell.KeyEscape:
+		a.setQuery(nil)
+		a.focus = focusList
+	case tcell.KeyUp:
+		a.move(-1)
+	case tcell.KeyDown:
+		a.move(1)
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if len(a.query) > 0 {
+			a.setQuery(a.query[:len(a.query)-1])
+		}
+	case tcell.KeyCtrlU:
+		a.setQuery(nil)
+	case tcell.KeyRune:
+		a.setQuery(append(a.query, ev.Rune()))
+	}
+}
+
+// handleTypesKey handles the list of item types offered for a new item.
+func (a *App) handleTypesKey(ev *tcell.EventKey) {
+	switch ev.Key() {
+	case tcell.KeyEscape:
+		a.focus = focusList
+	case tcell.KeyUp:
+		a.typeIndex = clamp(a.typeIndex-1, 0, len(a.types)-1)
+	case tcell.KeyDown:
+		a.typeIndex = clamp(a.typeIndex+1, 0, len(a.types)-1)
+	case tcell.KeyEnter:
+		a.form = newCreateForm(a.types[a.typeIndex], a.opts.Policies)
+		a.focus = focusForm
+	case tcell.KeyRune:
+		switch ev.Rune() {
+		case 'k':
+			a.typeIndex = clamp(a.typeIndex-1, 0, len(a.types)-1)
+		case 'j':
+			a.typeIndex = clamp(a.typeIndex+1, 0, len(a.types)-1)
+		case 'q':
+			a.focus = focusList
+		}
+	}
+}
+
+// handleFormKey handles the keys of the create and edit forms, saving with Ctrl+S.
+func (a *App) handleFormKey(ev *tcell.EventKey) {
+	switch ev.Key() {
+	case tcell.KeyEscape:
+		a.form, a.focus = nil, focusList
+	case tcell.KeyCtrlS:
+		a.save()
+	case tcell.KeyCtrlG:
+		if err := a.form.generate(); err != nil {
+			a.fail(err)
+		}
+	case tcell.KeyCtrlR:
+		a.form.reveal = !a.form.reveal
+	case tcell.KeyEnter:
+		if a.form.focus == len(a.form.inputs)-1 {
+			a.save()
+		} else {
+			a.form.next(1)
+		}
+	default:
+		a.form.handleKey(ev)
+	}
+}
+
+// setQuery sets the search query and filters the list, keeping the selected item if it still matches.
+func (a *App) setQuery(query []rune) {
+	a.query = query
+	a.filter(a.selectedName())
+}
+
+// reload reads the items of the vault, selecting the item with the name or keeping the selection.
+func (a *App) reload(name string) error {
+	if name == "" {
+		name = a.selectedName()
+	}
+	items, err := a.vault.ListData()
+	if err != nil {
+		return err
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+	a.items = items
+	a.filter(name)
+	return nil
+}
+
+// filter shows the items matching the query, selecting the item with the name if it is shown.
+func (a *App) filter(name string) {
+	a.visible = filterItems(a.items, string(a.query))
+	a.selected = 0
+	for i, item := range a.visible {
+		if item.Name == name {
+			a.selected = i
+		}
+	}
+	a.load()
+}
+
+// filterItems returns the items whose name or type contains every word of the query, ignoring case.
+func filterItems(items []models.DataInfo, query string) []models.DataInfo {
+	words := strings.Fields(strings.ToLower(query))
+	var matches []models.DataInfo
+	for _, item := range items {
+		text := strings.ToLower(item.Name + " " + item.Type)
+		match := true
+		for _, w := range words {
+			if !strings.Contains(text, w) {
+				match = false
+				break
+			}
+		}
+		if match {
+			matches = append(matches, item)
+		}
+	}
+	return matches
+}
+
+// selectedName returns the name of the selected item, empty if no item is shown.
+func (a *App) selectedName() string {
+	if a.selected >= len(a.visible) {
+		return ""
+	}
+	return a.visible[a.selected].Name
+}
+
+// move moves the selection in the list or the detail pane by delta rows.
+func (a *App) move(delta int) {
+	if a.focus == focusDetail {
+		if a.item != nil {
+			a.field = clamp(a.field+delta, 0, len(a.item.Fields)-1)
+		}
+		return
+	}
+	selected := clamp(a.selected+delta, 0, len(a.visible)-1)
+	if selected != a.selected {
+		a.selected = selected
+		a.load()
+	}
+}
+
+// load decodes the selected item, hiding its secrets again.
+func (a *App) load() {
+	a.item, a.itemErr, a.field, a.reveal = nil, nil, 0, false
+	name := a.selectedName()
+	if name == "" {
+		if a.focus == focusDetail {
+			a.focus = focusList
+		}
+		return
+	}
+	a.item, a.itemErr = a.vault.GetItem(name)
+}
+
+// copyField copies the field with the index of the selected item, the default field when the index is negative.
+func (a *App) copyField(index int) {
+	if a.item == nil {
+		return
+	}
+	if index < 0 {
+		index = defaultField(a.item)
+	}
+	if index >= len(a.item.Fields) {
+		a.fail(fmt.Errorf("%s has no field %d", a.item.Name, index+1))
+		return
+	}
+	f := a.item.Fields[index]
+	a.copy(f.Name+" of "+a.item.Name, f.Value)
+}
+
+// copyOTP copies the current code of the selected OTP item, or of the OTP linked to the selected credentials.
+func (a *App) copyOTP() {
+	name := a.selectedName()
+	if name == "" {
+		return
+	}
+	o, err := otp.Find(a.vault, name)
+	if err != nil {
+		a.fail(err)
+		return
+	}
+	code, _, err := otp.Code(o, a.opts.Now())
+	if err != nil {
+		a.fail(err)
+		return
+	}
+	a.copy("OTP code of "+name, code)
+}
+
+// copy puts the value on the clipboard and has it cleared after the timeout of the options.
+func (a *App) copy(what, value string) {
+	b := a.opts.Clipboard
+	if b == nil {
+		a.fail(errors.New("no clipboard available"))
+		return
+	}
+	if err := b.Copy(value); err != nil {
+		a.fail(fmt.Errorf("failed to copy to the clipboard: %w", err))
+		return
+	}
+	a.status = "Copied " + what
+	timeout := a.opts.ClearAfter
+	if timeout <= 0 {
+		return
+	}
+	if a.opts.ScheduleClear != nil {
+		if err := a.opts.ScheduleClear(b, value, timeout); err != nil {
+			a.fail(fmt.Errorf("copied %s, but failed to schedule clearing the clipboard: %w", what, err))
+			return
+		}
+	} else {
+		go clipboard.ClearAfter(context.Background(), b, clipboard.Hash(value), timeout)
+	}
+	a.status += fmt.Sprintf(", clearing it in %s", timeout)
+}
+
+// defaultField returns the index of the field copied by default, the first secret field of the item type.
+func defaultField(item *models.Item) int {
+	if t, ok := itemtype.Lookup(item.Type); ok {
+		for i, field := range item.Fields {
+			if f, ok := t.Field(field.Name); ok && f.Secret {
+				return i
+			}
+		}
+	}
+	return 0
+}
+
+// edit opens the edit form of the selected item.
+func (a *App) edit() {
+	name := a.selectedName()
+	if name == "" {
+		return
+	}
+	content, typ, err := a.vault.GetData(name)
+	if err != nil {
+		a.fail(err)
+		return
+	}
+	t, ok := itemtype.Lookup(typ)
+	if !ok {
+		a.fail(fmt.Errorf("items of type %s cannot be edited by this client", typ))
+		return
+	}
+	f, err := newEditForm(t, name, content, a.opts.Policies)
+	if err != nil {
+		a.fail(err)
+		return
+	}
+	a.form, a.focus = f, focusForm
+}
+
+// save creates or updates the item of the form, closing the form on success.
+func (a *App) save() {
+	message, err := a.form.save(a.vault, prompter{app: a, form: a.form})
+	if err != nil {
+		if !errors.Is(err, errCancelled) {
+			a.form.err = err.Error()
+		}
+		return
+	}
+	name := a.form.itemName()
+	a.form, a.focus = nil, focusList
+	a.sync.changes++
+	a.status = message
+	if err = a.reload(name); err != nil {
+		a.fail(err)
+	}
+}
+
+// delete deletes the selected item after asking for confirmation.
+func (a *App) delete() {
+	name := a.selectedName()
+	if name == "" {
+		return
+	}
+	if !a.confirm(fmt.Sprintf("Delete %s? (y/n)", name)) {
+		return
+	}
+	if err := a.vault.DeleteData(name); err != nil {
+		a.fail(err)
+		return
+	}
+	a.sync.changes++
+	a.status = "Deleted " + name
+	next := a.selected + 1
+	if next >= len(a.visible) {
+		next = a.selected - 1
+	}
+	nextName := ""
+	if next >= 0 {
+		nextName = a.visible[next].Name
+	}
+	if err := a.reload(nextName); err != nil {
+		a.fail(err)
+	}
+}
+
+// startSync syncs the vault with the server in the background, the UI keeps working meanwhile.
+func (a *App) startSync() {
+	if a.sync.running {
+		return
+	}
+	a.sync.running = true
+	go func() {
+		ev := &syncDone{err: a.vault.SyncData()}
+		ev.SetEventNow()
+		for a.screen.PostEvent(ev) != nil {
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+}
+
+// finishSync records the result of a sync and reloads the items it may have changed.
+func (a *App) finishSync(err error) {
+	a.sync.running, a.sync.err = false, err
+	if err != nil {
+		a.fail(fmt.Errorf("sync failed: %w", err))
+		return
+	}
+	a.sync.at, a.sync.changes = a.opts.Now(), 0
+	if err = a.reload(""); err != nil {
+		a.fail(err)
+	}
+}
+
+// fail shows the error in the status line.
+func (a *App) fail(err error) {
+	a.status, a.statusErr = err.Error(), true
+}
+
+// clamp limits n to the range from low to high, low when the range is empty.
+func clamp(n, low, high int) int {
+	if n > high {
+		n = high
+	}
+	if n < low {
+		n = low
+	}
+	return n
+}
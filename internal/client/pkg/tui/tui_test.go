@@ -0,0 +1,338 @@
+package tui
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/Mldlr/storety/internal/client/itemtype"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/Mldlr/storety/internal/client/pkg/clipboard"
+	"github.com/Mldlr/storety/internal/client/pkg/otp"
+	"github.com/Mldlr/storety/internal/client/service/vault"
+	"github.com/Mldlr/storety/internal/client/service/vault/vaulttest"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stored returns the content of the item stored in the vault.
+func stored(t *testing.T, v *vaulttest.Fake, name string) []byte {
+	t.Helper()
+	content, _, err := v.GetData(name)
+	require.NoError(t, err)
+	return content
+}
+
+// now is the time of the tests.
+var now = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// seed is the OTP secret linked from the github credentials.
+var seed = &models.OTP{Secret: "JBSWY3DPEHPK3PXP", Issuer: "GitHub", Algorithm: "SHA1", Digits: 6, Period: 30}
+
+// newTestApp returns an app showing the vault on a simulation screen.
+func newTestApp(t *testing.T) (*App, *vaulttest.Fake, tcell.SimulationScreen, *clipboard.Fake) {
+	t.Helper()
+	v := vaulttest.New(vault.DecodeItem)
+	add := func(name, typ string, content interface{}) {
+		encoded, err := json.Marshal(content)
+		require.NoError(t, err)
+		v.Put(name, typ, encoded)
+	}
+	add("github", itemtype.Cred, &models.Credentials{Login: "octocat", Password: "hunter2", OTP: "github-2fa"})
+	add("github-2fa", otp.ItemType, seed)
+	add("gitlab", itemtype.Cred, &models.Credentials{Login: "tanuki", Password: "s3cret"})
+	add("visa", itemtype.Card, &models.Card{Number: "4111111111111111", Expires: "12/29", CVV: "123"})
+	add("notes", itemtype.Text, &models.Text{Text: "first line\nsecond line"})
+
+	screen := tcell.NewSimulationScreen("UTF-8")
+	require.NoError(t, screen.Init())
+	screen.SetSize(120, 30)
+	t.Cleanup(screen.Fini)
+	board := &clipboard.Fake{}
+	a := New(v, Options{Clipboard: board, Now: func() time.Time { return now }})
+	a.screen = screen
+	require.NoError(t, a.reload(""))
+	return a, v, screen, board
+}
+
+// press handles the keys and draws the screen.
+func press(a *App, keys ...tcell.Key) {
+	for _, k := range keys {
+		a.handle(tcell.NewEventKey(k, 0, tcell.ModNone))
+	}
+	a.draw()
+}
+
+// typeText handles the runes as typed keys and draws the screen.
+func typeText(a *App, text string) {
+	for _, r := range text {
+		a.handle(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+	a.draw()
+}
+
+// inject posts the runes followed by Enter in the background, to be read by a nested event loop like ask.
+// Keys are posted as the loop reads them, as the event queue of the screen is short.
+func inject(screen tcell.SimulationScreen, text string) {
+	var events []tcell.Event
+	for _, r := range text {
+		events = append(events, tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+	events = append(events, tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+	go func() {
+		for _, ev := range events {
+			for screen.PostEvent(ev) != nil {
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}()
+}
+
+// contents returns the text on the screen.
+func contents(screen tcell.SimulationScreen) string {
+	cells, w, _ := screen.GetContents()
+	var sb strings.Builder
+	for i, c := range cells {
+		if len(c.Runes) > 0 {
+			sb.WriteRune(c.Runes[0])
+		} else {
+			sb.WriteRune(' ')
+		}
+		if i%w == w-1 {
+			sb.WriteRune('\n')
+		}
+	}
+	return sb.String()
+}
+
+func TestFilterItems(t *testing.T) {
+	items := []models.DataInfo{
+		{Name: "github", Type: "Cred"},
+		{Name: "GitLab work", Type: "Cred"},
+		{Name: "visa", Type: "Card"},
+	}
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{name: "Empty query", query: "", want: []string{"github", "GitLab work", "visa"}},
+		{name: "Name ignoring case", query: "GIT", want: []string{"github", "GitLab work"}},
+		{name: "Type", query: "card", want: []string{"visa"}},
+		{name: "All words", query: "git work", want: []string{"GitLab work"}},
+		{name: "Word of type", query: "git cred", want: []string{"github", "GitLab work"}},
+		{name: "No match", query: "bank", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, item := range filterItems(items, tt.query) {
+				got = append(got, item.Name)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSyncState_String(t *testing.T) {
+	at := time.Date(2024, 5, 1, 9, 30, 0, 0, time.Local)
+	tests := []struct {
+		name  string
+		state syncState
+		want  string
+	}{
+		{name: "Never synced", state: syncState{}, want: "not synced"},
+		{name: "Running", state: syncState{running: true, at: at}, want: "syncing..."},
+		{name: "Failed", state: syncState{err: errors.New("offline"), at: at}, want: "sync failed"},
+		{name: "Synced", state: syncState{at: at}, want: "synced 09:30"},
+		{name: "One change", state: syncState{at: at, changes: 1}, want: "synced 09:30, 1 change to sync"},
+		{name: "Changes", state: syncState{changes: 3}, want: "not synced, 3 changes to sync"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.state.String())
+		})
+	}
+}
+
+func TestDisplayFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		item   *models.Item
+		reveal bool
+		want   []models.Field
+	}{
+		{
+			name: "Secret fields are hidden",
+			item: &models.Item{Type: itemtype.Cred, Fields: []models.Field{
+				{Name: "login", Value: "octocat"},
+				{Name: "password", Value: "hunter2"},
+				{Name: "pin", Value: "1234", Kind: itemtype.FieldHidden},
+				{Name: "meta", Value: ""},
+			}},
+			want: []models.Field{
+				{Name: "login", Value: "octocat"},
+				{Name: "password", Value: hidden},
+				{Name: "pin", Value: hidden, Kind: itemtype.FieldHidden},
+				{Name: "meta", Value: ""},
+			},
+		},
+		{
+			name: "Revealed",
+			item: &models.Item{Type: itemtype.Cred, Fields: []models.Field{
+				{Name: "password", Value: "hunter2"},
+				{Name: "pin", Value: "1234", Kind: itemtype.FieldHidden},
+			}},
+			reveal: true,
+			want: []models.Field{
+				{Name: "password", Value: "hunter2"},
+				{Name: "pin", Value: "1234", Kind: itemtype.FieldHidden},
+			},
+		},
+		{
+			name: "Masked fields use their masked form",
+			item: &models.Item{Type: itemtype.Card, Fields: []models.Field{
+				{Name: "number", Value: "4111111111111111"},
+				{Name: "cvv", Value: "123"},
+			}},
+			want: []models.Field{
+				{Name: "number", Value: "**** **** **** 1111 (Visa)"},
+				{Name: "cvv", Value: "***"},
+			},
+		},
+		{
+			name: "File content shows its size",
+			item: &models.Item{Type: itemtype.Binary, Fields: []models.Field{
+				{Name: "blob", Value: "aGVsbG8="},
+			}},
+			reveal: true,
+			want: []models.Field{
+				{Name: "blob", Value: "5 bytes"},
+			},
+		},
+		{
+			name: "Unknown type",
+			item: &models.Item{Type: "Future", Fields: []models.Field{{Name: "content", Value: "{}"}}},
+			want: []models.Field{{Name: "content", Value: "{}"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := append([]models.Field{}, tt.item.Fields...)
+			assert.Equal(t, tt.want, displayFields(tt.item, tt.reveal))
+			assert.Equal(t, original, tt.item.Fields, "the item must not change")
+		})
+	}
+}
+
+func TestApp_Browse(t *testing.T) {
+	a, _, screen, _ := newTestApp(t)
+	a.draw()
+	text := contents(screen)
+	assert.Contains(t, text, "5 items")
+	assert.Contains(t, text, "not synced")
+	assert.Less(t, strings.Index(text, "github "), strings.Index(text, "notes"), "items are sorted by name")
+	assert.Contains(t, text, "octocat")
+	assert.NotContains(t, text, "hunter2")
+
+	typeText(a, "r")
+	assert.Contains(t, contents(screen), "hunter2")
+
+	typeText(a, "/visa")
+	text = contents(screen)
+	assert.Contains(t, text, "1 of 5 items")
+	assert.Contains(t, text, "**** **** **** 1111 (Visa)")
+	assert.NotContains(t, text, "4111111111111111")
+	assert.NotContains(t, text, "octocat")
+
+	press(a, tcell.KeyEscape)
+	assert.Equal(t, focusList, a.focus)
+	assert.Empty(t, a.query)
+	assert.Equal(t, "visa", a.selectedName(), "the selection is kept when the filter is cleared")
+
+	typeText(a, "j")
+	assert.Equal(t, "visa", a.selectedName(), "the selection stops at the last item")
+	press(a, tcell.KeyHome)
+	assert.Equal(t, "github", a.selectedName())
+	assert.False(t, a.reveal, "secrets are hidden again for another item")
+
+	press(a, tcell.KeyEnter, tcell.KeyDown)
+	assert.Equal(t, focusDetail, a.focus)
+	assert.Equal(t, 1, a.field)
+}
+
+func TestApp_Copy(t *testing.T) {
+	a, _, _, board := newTestApp(t)
+	var scheduled []string
+	a.opts.ClearAfter = 45 * time.Second
+	a.opts.ScheduleClear = func(b clipboard.Backend, value string, timeout time.Duration) error {
+		scheduled = append(scheduled, value)
+		return nil
+	}
+
+	typeText(a, "c")
+	got, _ := board.Paste()
+	assert.Equal(t, "hunter2", got, "the first secret field is copied by default")
+	assert.Equal(t, "Copied password of github, clearing it in 45s", a.status)
+	assert.Equal(t, []string{"hunter2"}, scheduled)
+
+	typeText(a, "1")
+	got, _ = board.Paste()
+	assert.Equal(t, "octocat", got)
+
+	press(a, tcell.KeyTab, tcell.KeyDown, tcell.KeyDown)
+	typeText(a, "c")
+	got, _ = board.Paste()
+	assert.Equal(t, "github-2fa", got, "the selected field is copied in the detail pane")
+
+	typeText(a, "9")
+	assert.True(t, a.statusErr)
+	assert.Equal(t, "github has no field 9", a.status)
+
+	typeText(a, "o")
+	code, _, err := otp.Code(seed, now)
+	require.NoError(t, err)
+	got, _ = board.Paste()
+	assert.Equal(t, code, got, "the code of the linked OTP is copied")
+
+	a.opts.Clipboard = nil
+	typeText(a, "c")
+	assert.True(t, a.statusErr)
+}
+
+func TestApp_Delete(t *testing.T) {
+	a, v, screen, _ := newTestApp(t)
+	screen.InjectKey(tcell.KeyRune, 'n', tcell.ModNone)
+	typeText(a, "d")
+	assert.True(t, v.Has("github"), "the item is kept unless confirmed")
+
+	screen.InjectKey(tcell.KeyRune, 'y', tcell.ModNone)
+	typeText(a, "d")
+	assert.False(t, v.Has("github"))
+	assert.Equal(t, "Deleted github", a.status)
+	assert.Equal(t, "github-2fa", a.selectedName(), "the next item is selected")
+	assert.Equal(t, 1, a.sync.changes)
+}
+
+func TestApp_Sync(t *testing.T) {
+	a, v, screen, _ := newTestApp(t)
+	a.sync.changes = 2
+
+	typeText(a, "s")
+	assert.Contains(t, contents(screen), "syncing...")
+	a.handle(screen.PollEvent())
+	a.draw()
+	assert.Equal(t, 1, v.Syncs())
+	assert.Equal(t, syncState{at: now}, a.sync)
+	assert.Contains(t, contents(screen), "synced "+now.Local().Format("15:04"))
+
+	v.FailSync(errors.New("connection refused"))
+	typeText(a, "s")
+	a.handle(screen.PollEvent())
+	a.draw()
+	assert.Contains(t, contents(screen), "sync failed")
+	assert.Equal(t, "sync failed: connection refused", a.status)
+	assert.True(t, a.statusErr)
+}
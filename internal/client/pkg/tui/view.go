@@ -0,0 +1,328 @@
+package tui
+
+import (
+	"encoding/base64"
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/itemtype"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+	"strings"
+	"unicode"
+)
+
+// hidden is shown for secret values.
+const hidden = "********"
+
+// Styles of the screen.
+var (
+	styleDefault  = tcell.StyleDefault
+	styleBar      = tcell.StyleDefault.Reverse(true)
+	styleSelected = tcell.StyleDefault.Reverse(true)
+	styleInactive = tcell.StyleDefault.Underline(true)
+	styleDim      = tcell.StyleDefault.Dim(true)
+	styleBold     = tcell.StyleDefault.Bold(true)
+	styleError    = tcell.StyleDefault.Foreground(tcell.ColorRed)
+)
+
+// help lists the keys of each focus in the bottom line.
+var help = map[focus]string{
+	focusList:   "/ search  j/k move  tab fields  c copy  1-9 copy field  o copy otp  r reveal  n new  e edit  d delete  s sync  q quit",
+	focusDetail: "j/k select field  c copy field  1-9 copy field  r reveal  e edit  esc back  q quit",
+	focusSearch: "type to filter  enter done  esc clear",
+	focusTypes:  "j/k choose  enter select  esc cancel",
+	focusForm:   "tab next field  enter next/save  ctrl+s save  ctrl+g generate  ctrl+r reveal  space toggle  esc cancel",
+}
+
+// draw draws the whole screen.
+func (a *App) draw() {
+	a.screen.Clear()
+	a.screen.HideCursor()
+	w, h := a.screen.Size()
+	a.drawTitle(w)
+	a.drawSearch(w)
+	listWidth := clamp(w/3, 20, 40)
+	if listWidth > w/2 {
+		listWidth = w / 2
+	}
+	for y := 2; y < h-2; y++ {
+		a.screen.SetContent(listWidth, y, tcell.RuneVLine, nil, styleDim)
+	}
+	a.drawList(listWidth)
+	x := listWidth + 2
+	switch a.focus {
+	case focusTypes:
+		a.drawTypes(x, w-x)
+	case focusForm:
+		a.drawForm(x, w-x)
+	default:
+		a.drawDetail(x, w-x)
+	}
+	a.drawStatus(w, h)
+	a.text(0, h-1, w, styleDim, help[a.focus])
+	a.screen.Show()
+}
+
+// drawTitle draws the title bar with the number of items and the sync state.
+func (a *App) drawTitle(w int) {
+	a.fill(0, 0, w, styleBar)
+	count := fmt.Sprintf(" Storety  %d items", len(a.items))
+	if len(a.visible) != len(a.items) {
+		count = fmt.Sprintf(" Storety  %d of %d items", len(a.visible), len(a.items))
+	}
+	a.text(0, 0, w, styleBar, count)
+	state := a.sync.String() + " "
+	style := styleBar
+	switch {
+	case a.sync.err != nil:
+		style = styleBar.Foreground(tcell.ColorRed)
+	case a.sync.running || a.sync.changes > 0:
+		style = styleBar.Foreground(tcell.ColorYellow)
+	}
+	a.text(w-runewidth.StringWidth(state), 0, w, style, state)
+}
+
+// drawSearch draws the search line.
+func (a *App) drawSearch(w int) {
+	if a.focus != focusSearch && len(a.query) == 0 {
+		a.text(0, 1, w, styleDim, "/ to search")
+		return
+	}
+	x := a.text(0, 1, w, styleBold, "/ ")
+	x = a.text(x, 1, w-x, styleDefault, string(a.query))
+	if a.focus == focusSearch {
+		a.screen.ShowCursor(x, 1)
+	}
+}
+
+// drawList draws the visible items, scrolling the list to keep the selected item shown.
+func (a *App) drawList(width int) {
+	height := a.listHeight()
+	if len(a.visible) == 0 {
+		message := "No items, press n to create one"
+		if len(a.items) > 0 {
+			message = "No matching items"
+		}
+		a.text(1, 2, width-1, styleDim, message)
+		return
+	}
+	if a.selected < a.offset {
+		a.offset = a.selected
+	}
+	if a.selected >= a.offset+height {
+		a.offset = a.selected - height + 1
+	}
+	for row := 0; row < height && a.offset+row < len(a.visible); row++ {
+		i := a.offset + row
+		item := a.visible[i]
+		y := 2 + row
+		style, typeStyle := styleDefault, styleDim
+		if i == a.selected {
+			style = styleInactive
+			if a.focus == focusList || a.focus == focusSearch {
+				style = styleSelected
+			}
+			typeStyle = style
+			a.fill(0, y, width, style)
+		}
+		typeWidth := runewidth.StringWidth(item.Type)
+		a.text(1, y, width-typeWidth-3, style, item.Name)
+		a.text(width-typeWidth-1, y, typeWidth, typeStyle, item.Type)
+	}
+}
+
+// drawDetail draws the fields of the selected item, secret values hidden unless revealed.
+func (a *App) drawDetail(x, width int) {
+	_, h := a.screen.Size()
+	if a.itemErr != nil {
+		a.text(x, 2, width, styleError, a.itemErr.Error())
+		return
+	}
+	if a.item == nil {
+		return
+	}
+	end := a.text(x, 2, width, styleBold, a.item.Name)
+	a.text(end+2, 2, width-(end-x)-2, styleDim, a.item.Type)
+	fields := displayFields(a.item, a.reveal)
+	labelWidth := 0
+	for _, f := range fields {
+		if w := runewidth.StringWidth(f.Name); w > labelWidth {
+			labelWidth = w
+		}
+	}
+	bottom := h - 2
+	if !a.reveal {
+		bottom--
+		a.text(x, bottom, width, styleDim, "press r to reveal secret fields")
+	}
+	y := 4
+	for i, f := range fields {
+		if y >= bottom {
+			break
+		}
+		style := styleDefault
+		if i == a.field && a.focus == focusDetail {
+			style = styleSelected
+			a.fill(x, y, width, style)
+		}
+		key := "  "
+		if i < 9 {
+			key = fmt.Sprintf("%d ", i+1)
+		}
+		a.text(x, y, 2, styleDim, key)
+		a.text(x+2, y, labelWidth, style.Bold(true), f.Name)
+		valueX := x + 2 + labelWidth + 2
+		for j, line := range strings.Split(f.Value, "\n") {
+			if y >= bottom {
+				break
+			}
+			if j > 0 && style == styleSelected {
+				a.fill(x, y, width, style)
+			}
+			a.text(valueX, y, width-(valueX-x), style, line)
+			y++
+		}
+	}
+}
+
+// displayFields returns the fields of the item as shown: masked values, like card numbers, use their masked form
+// and secret values are hidden unless revealed. Values of file fields are replaced by their size.
+func displayFields(item *models.Item, reveal bool) []models.Field {
+	shown := &models.Item{Name: item.Name, Type: item.Type, Fields: append([]models.Field{}, item.Fields...)}
+	if !reveal {
+		itemtype.Mask(shown)
+	}
+	t, known := itemtype.Lookup(item.Type)
+	for i, field := range shown.Fields {
+		var f *itemtype.Field
+		if known {
+			f, _ = t.Field(field.Name)
+		}
+		switch {
+		case field.Value == "":
+		case f != nil && f.Kind == itemtype.Bytes:
+			content, _ := base64.StdEncoding.DecodeString(field.Value)
+			shown.Fields[i].Value = fmt.Sprintf("%d bytes", len(content))
+		case reveal:
+		case f != nil && f.Mask != nil:
+		case f != nil && f.Secret, field.Kind == itemtype.FieldHidden:
+			shown.Fields[i].Value = hidden
+		}
+	}
+	return shown.Fields
+}
+
+// drawTypes draws the item types offered for a new item.
+func (a *App) drawTypes(x, width int) {
+	a.text(x, 2, width, styleBold, "New item")
+	commandWidth := 0
+	for _, t := range a.types {
+		if w := runewidth.StringWidth(t.Command); w > commandWidth {
+			commandWidth = w
+		}
+	}
+	for i, t := range a.types {
+		y := 4 + i
+		style := styleDefault
+		if i == a.typeIndex {
+			style = styleSelected
+			a.fill(x, y, width, style)
+		}
+		a.text(x+1, y, commandWidth, style.Bold(true), t.Command)
+		a.text(x+commandWidth+3, y, width-commandWidth-3, style, t.Short)
+	}
+}
+
+// drawForm draws the inputs of the form with the usage of the focused field and the last error.
+func (a *App) drawForm(x, width int) {
+	f := a.form
+	a.text(x, 2, width, styleBold, f.title())
+	labelWidth := 0
+	for _, in := range f.inputs {
+		if w := runewidth.StringWidth(in.label); w > labelWidth {
+			labelWidth = w
+		}
+	}
+	y := 4
+	for i, in := range f.inputs {
+		style := styleDefault
+		if i == f.focus {
+			style = styleBold
+		}
+		a.text(x, y, labelWidth, style, in.label)
+		valueX := x + labelWidth + 2
+		valueStyle := styleDefault.Underline(true)
+		a.fill(valueX, y, width-(valueX-x), valueStyle)
+		end := a.text(valueX, y, width-(valueX-x), valueStyle, in.display(f.reveal))
+		if i == f.focus && (in.field == nil || in.field.Kind != itemtype.Bool) {
+			a.screen.ShowCursor(valueX+runewidth.StringWidth(string([]rune(in.display(f.reveal))[:in.cursor])), y)
+		}
+		if in.field != nil && in.field.Generate {
+			a.text(end+2, y, width-(end-x)-2, styleDim, "ctrl+g generates")
+		}
+		y++
+	}
+	y++
+	if in := f.inputs[f.focus]; in.field != nil && in.field.Usage != "" {
+		a.text(x, y, width, styleDim, in.field.Usage)
+		y++
+	}
+	if f.err != "" {
+		a.text(x, y, width, styleError, f.err)
+	}
+}
+
+// drawStatus draws the question of the modal or the status message.
+func (a *App) drawStatus(w, h int) {
+	y := h - 2
+	if a.modal != nil {
+		x := a.text(0, y, w, styleBold, a.modal.question+" ")
+		if in := a.modal.input; in != nil {
+			x = a.text(x, y, w-x, styleDefault, in.display(false))
+			a.screen.ShowCursor(x, y)
+		}
+		return
+	}
+	style := styleDefault
+	if a.statusErr {
+		style = styleError
+	}
+	a.text(0, y, w, style, a.status)
+}
+
+// listHeight returns the number of rows of the item list.
+func (a *App) listHeight() int {
+	_, h := a.screen.Size()
+	if h < 5 {
+		return 1
+	}
+	return h - 4
+}
+
+// text draws the string from x in row y, cut at the width, and returns the column after it.
+// Control characters are drawn as spaces, so values cannot move the cursor.
+func (a *App) text(x, y, width int, style tcell.Style, s string) int {
+	for _, r := range s {
+		if unicode.IsControl(r) {
+			r = ' '
+		}
+		w := runewidth.RuneWidth(r)
+		if w == 0 {
+			continue
+		}
+		if width < w {
+			break
+		}
+		a.screen.SetContent(x, y, r, nil, style)
+		x += w
+		width -= w
+	}
+	return x
+}
+
+// fill fills width columns of row y from x with spaces of the style.
+func (a *App) fill(x, y, width int, style tcell.Style) {
+	for i := 0; i < width; i++ {
+		a.screen.SetContent(x+i, y, ' ', nil, style)
+	}
+}
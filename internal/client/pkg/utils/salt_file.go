@@ -68,3 +68,31 @@ func GetAuthData(filename, userId string) ([]byte, []byte, string, string, error
 
 	return hashedKey, salt, userData.AuthToken, userData.RefreshToken, nil
 }
+
+// DeleteAuthData removes the stored authorization data of the given user ID
+func DeleteAuthData(filename, userId string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	keysAndSalts := make(map[string]models.AuthData)
+	if len(data) > 0 {
+		err = json.Unmarshal(data, &keysAndSalts)
+		if err != nil {
+			return err
+		}
+	}
+	if _, ok := keysAndSalts[userId]; !ok {
+		return nil
+	}
+	delete(keysAndSalts, userId)
+
+	newData, err := json.MarshalIndent(keysAndSalts, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, newData, 0600)
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"crypto/rand"
+	"github.com/Mldlr/storety/internal/constants"
 	"github.com/stretchr/testify/assert"
 	"os"
 	"testing"
@@ -52,3 +53,22 @@ func TestSaveAndGetHashedKeyAndSalt(t *testing.T) {
 
 	os.Remove(testFile)
 }
+
+func TestDeleteAuthData(t *testing.T) {
+	defer os.Remove(testFile)
+	for _, userId := range []string{"test_user1", "test_user2"} {
+		err := SaveAuthData(testFile, userId, []byte("key"), []byte("salt"), "authToken", "refreshToken")
+		assert.NoError(t, err, "Error saving auth data")
+	}
+
+	err := DeleteAuthData(testFile, "test_user1")
+	assert.NoError(t, err, "Error deleting auth data")
+
+	_, _, _, _, err = GetAuthData(testFile, "test_user1")
+	assert.ErrorIs(t, err, constants.ErrUserNotFound)
+	_, _, _, _, err = GetAuthData(testFile, "test_user2")
+	assert.NoError(t, err, "Auth data of other users must be kept")
+
+	err = DeleteAuthData(testFile, "missing_user")
+	assert.NoError(t, err, "Deleting missing user must not fail")
+}
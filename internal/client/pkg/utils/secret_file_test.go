@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteSecretFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "app.yaml")
+	require.NoError(t, os.WriteFile(filename, []byte("old"), 0644))
+
+	require.NoError(t, WriteSecretFile(filename, []byte("password: s3cret\n")))
+	content, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.Equal(t, "password: s3cret\n", string(content))
+	info, err := os.Stat(filename)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	entries, err := os.ReadDir(filepath.Dir(filename))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary file must be removed")
+}
@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"encoding/json"
+	"github.com/Mldlr/storety/internal/client/models"
+	"os"
+)
+
+// SaveVaultExport writes the vault export to the file readable only by the owner.
+func SaveVaultExport(filename string, export *models.VaultExport) error {
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0600)
+}
+
+// ReadVaultExport reads the vault export from the file.
+func ReadVaultExport(filename string) (*models.VaultExport, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	export := &models.VaultExport{}
+	err = json.Unmarshal(data, export)
+	if err != nil {
+		return nil, err
+	}
+	return export, nil
+}
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSaveAndReadVaultExport(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "export.json")
+	export := &models.VaultExport{
+		Username:   "test_user",
+		Salt:       "c2FsdA==",
+		ExportedAt: time.Now().UTC().Truncate(time.Second),
+		Items: []models.ExportItem{
+			{
+				ID:        uuid.New(),
+				Name:      "item",
+				Type:      "Text",
+				Content:   []byte("encrypted"),
+				UpdatedAt: time.Now().UTC().Truncate(time.Second),
+			},
+		},
+	}
+	err := SaveVaultExport(filename, export)
+	assert.NoError(t, err, "Error saving vault export")
+
+	info, err := os.Stat(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "Export must be readable only by the owner")
+
+	read, err := ReadVaultExport(filename)
+	assert.NoError(t, err, "Error reading vault export")
+	assert.Equal(t, export, read)
+}
@@ -5,8 +5,10 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
+	"crypto/sha256"
 	"github.com/Mldlr/storety/internal/client/config"
 	"github.com/samber/do"
+	"golang.org/x/crypto/pbkdf2"
 	"io"
 )
 
@@ -24,6 +26,18 @@ func NewCrypto(i *do.Injector) *Crypto {
 	}
 }
 
+// NewCryptoWithKey creates a new Crypto instance using the key instead of the key of the logged in user.
+func NewCryptoWithKey(key []byte) *Crypto {
+	return &Crypto{
+		cfg: &config.Config{EncryptionKey: key},
+	}
+}
+
+// DeriveKey derives the encryption key of an account from its password and salt.
+func DeriveKey(password string, salt []byte) []byte {
+	return pbkdf2.Key([]byte(password), salt, 10000, 32, sha256.New)
+}
+
 // EncryptWithAES256 encrypts data with AES256.
 // It takes a byte slice of data to be encrypted and returns the encrypted data or an error.
 func (c *Crypto) EncryptWithAES256(data []byte) ([]byte, error) {
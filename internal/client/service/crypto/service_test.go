@@ -48,3 +48,18 @@ func TestEncryptDecryptWithAES256(t *testing.T) {
 		})
 	}
 }
+
+func TestNewCryptoWithKey(t *testing.T) {
+	key := DeriveKey("password", []byte("salt"))
+	assert.Len(t, key, 32)
+	assert.Equal(t, key, DeriveKey("password", []byte("salt")))
+	assert.NotEqual(t, key, DeriveKey("password", []byte("other salt")))
+
+	encrypted, err := (&Crypto{cfg: &config.Config{EncryptionKey: key}}).EncryptWithAES256([]byte("hello world"))
+	assert.NoError(t, err)
+	decrypted, err := NewCryptoWithKey(key).DecryptWithAES256(encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("hello world"), decrypted)
+	_, err = NewCryptoWithKey(DeriveKey("wrong", []byte("salt"))).DecryptWithAES256(encrypted)
+	assert.Error(t, err)
+}
@@ -10,12 +10,22 @@ type Service interface {
 	// CreateData creates a new data entry locally.
 	CreateData(n, t string, content []byte) error
 
+	// CreateBatchData creates several new data entries locally at once, so a single sync pushes them to the server.
+	// The entries get new IDs; none is created if any name is taken.
+	CreateBatchData(data []models.Data) error
+
 	// ListData gets list of data from local storage.
 	ListData() ([]models.DataInfo, error)
 
 	// GetData gets data from local storage.
 	GetData(n string) ([]byte, string, error)
 
+	// ExportData gets all encrypted data entries from local storage.
+	ExportData() ([]models.Data, error)
+
+	// UpdateData replaces the content of a data entry locally, keeping its ID.
+	UpdateData(n string, content []byte) error
+
 	// DeleteData deletes data locally.
 	DeleteData(n string) error
 
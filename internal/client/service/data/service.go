@@ -10,6 +10,7 @@ import (
 	"github.com/samber/do"
 	"google.golang.org/grpc"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"sync"
 	"time"
 )
 
@@ -20,6 +21,8 @@ type ServiceImpl struct {
 	conn         *grpc.ClientConn
 	storage      storage.Storage
 	cfg          *config.Config
+	// syncMu keeps the storage from being replaced while a sync is running.
+	syncMu sync.Mutex
 }
 
 // NewServiceImpl creates a new ServiceImpl instance and returns a pointer to it.
@@ -37,6 +40,8 @@ func NewServiceImpl(i *do.Injector) *ServiceImpl {
 
 // SetStorage implements the Service interface SetStorage method.
 func (c *ServiceImpl) SetStorage(s storage.Storage) {
+	c.syncMu.Lock()
+	defer c.syncMu.Unlock()
 	if c.storage != nil {
 		c.storage.Close()
 	}
@@ -62,6 +67,19 @@ func (c *ServiceImpl) CreateData(name, typ string, content []byte) error {
 	return nil
 }
 
+// CreateBatchData implements the Service interface CreateBatchData method.
+func (c *ServiceImpl) CreateBatchData(data []models.Data) error {
+	batch := make([]models.Data, len(data))
+	for i, d := range data {
+		id, err := uuid.NewRandom()
+		if err != nil {
+			return err
+		}
+		batch[i] = models.Data{ID: id, Name: d.Name, Type: d.Type, Content: d.Content}
+	}
+	return c.storage.CreateBatchData(c.ctx, batch)
+}
+
 // ListData implements the Service interface ListData method.
 func (c *ServiceImpl) ListData() ([]models.DataInfo, error) {
 	return c.storage.GetAllDataInfo(c.ctx)
@@ -72,6 +90,16 @@ func (c *ServiceImpl) GetData(name string) ([]byte, string, error) {
 	return c.storage.GetDataContentByName(c.ctx, name)
 }
 
+// ExportData implements the Service interface ExportData method.
+func (c *ServiceImpl) ExportData() ([]models.Data, error) {
+	return c.storage.GetAllData(c.ctx)
+}
+
+// UpdateData implements the Service interface UpdateData method.
+func (c *ServiceImpl) UpdateData(name string, content []byte) error {
+	return c.storage.UpdateDataByName(c.ctx, name, content)
+}
+
 // DeleteData implements the Service interface DeleteData method.
 func (c *ServiceImpl) DeleteData(name string) error {
 	return c.storage.DeleteDataByName(c.ctx, name)
@@ -79,6 +107,11 @@ func (c *ServiceImpl) DeleteData(name string) error {
 
 // SyncData implements the Service interface SyncData method.
 func (c *ServiceImpl) SyncData() error {
+	c.syncMu.Lock()
+	defer c.syncMu.Unlock()
+	if c.storage == nil {
+		return nil
+	}
 	newData, err := c.storage.GetNewData(c.ctx)
 	if err != nil {
 		return err
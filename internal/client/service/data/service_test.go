@@ -4,11 +4,13 @@ import (
 	"context"
 	"github.com/Mldlr/storety/internal/client/mocks"
 	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/Mldlr/storety/internal/constants"
 	pb "github.com/Mldlr/storety/internal/proto"
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"testing"
 	"time"
 )
@@ -36,6 +38,48 @@ func TestCreateData(t *testing.T) {
 	assert.Equal(t, content, actualData.Content)
 }
 
+func TestCreateBatchData(t *testing.T) {
+	ctx := context.Background()
+	storageMock := new(mocks.Storage)
+	dataService := ServiceImpl{
+		ctx:     ctx,
+		storage: storageMock,
+	}
+	data := []models.Data{
+		{Name: "first", Type: "Cred", Content: []byte("one")},
+		{Name: "second", Type: "Text", Content: []byte("two")},
+	}
+
+	storageMock.On("CreateBatchData", ctx, mock.AnythingOfType("[]models.Data")).Return(nil).Once()
+	err := dataService.CreateBatchData(data)
+	assert.NoError(t, err)
+
+	batch := storageMock.Calls[0].Arguments.Get(1).([]models.Data)
+	require.Len(t, batch, 2)
+	assert.NotEqual(t, batch[0].ID, batch[1].ID)
+	for i := range data {
+		assert.Equal(t, data[i].Name, batch[i].Name)
+		assert.Equal(t, data[i].Type, batch[i].Type)
+		assert.Equal(t, data[i].Content, batch[i].Content)
+	}
+	storageMock.AssertExpectations(t)
+}
+
+func TestUpdateData(t *testing.T) {
+	ctx := context.Background()
+	storageMock := new(mocks.Storage)
+	dataService := ServiceImpl{
+		ctx:     ctx,
+		storage: storageMock,
+	}
+
+	storageMock.On("UpdateDataByName", ctx, "name", []byte("content")).Return(nil).Once()
+	assert.NoError(t, dataService.UpdateData("name", []byte("content")))
+	storageMock.On("UpdateDataByName", ctx, "missing", []byte("content")).Return(constants.ErrGetData).Once()
+	assert.ErrorIs(t, dataService.UpdateData("missing", []byte("content")), constants.ErrGetData)
+	storageMock.AssertExpectations(t)
+}
+
 func TestSyncData(t *testing.T) {
 	ctx := context.Background()
 	storageMock := new(mocks.Storage)
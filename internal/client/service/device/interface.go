@@ -0,0 +1,16 @@
+package device
+
+import "github.com/Mldlr/storety/internal/client/models"
+
+// Service is the interface for the device service.
+type Service interface {
+	// Enroll generates a new device key, makes a request to the Enroll RPC with its certificate signing request
+	// and stores the issued client certificate and the key in the configured files.
+	Enroll(name string) (string, error)
+
+	// ListDevices makes a request to the ListDevices RPC and returns the devices of the user.
+	ListDevices() ([]models.Device, error)
+
+	// RevokeDevice makes a request to the RevokeDevice RPC to revoke the client certificate of the device.
+	RevokeDevice(id string) error
+}
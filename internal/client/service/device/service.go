@@ -0,0 +1,100 @@
+package device
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/config"
+	"github.com/Mldlr/storety/internal/client/models"
+	pb "github.com/Mldlr/storety/internal/proto"
+	"github.com/samber/do"
+	"google.golang.org/grpc"
+	"os"
+)
+
+// ServiceImpl is a client for the Device service.
+type ServiceImpl struct {
+	ctx          context.Context
+	remoteClient pb.DeviceClient
+	cfg          *config.Config
+}
+
+// NewServiceImpl creates a new ServiceImpl instance and returns a pointer to it.
+func NewServiceImpl(i *do.Injector) *ServiceImpl {
+	conn := do.MustInvoke[*grpc.ClientConn](i)
+	cfg := do.MustInvoke[*config.Config](i)
+	return &ServiceImpl{
+		ctx:          context.Background(),
+		remoteClient: pb.NewDeviceClient(conn),
+		cfg:          cfg,
+	}
+}
+
+// Enroll implements the Enroll method of the Service interface.
+func (s *ServiceImpl) Enroll(name string) (string, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate device key: %v", err)
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: name},
+	}, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to create certificate signing request: %v", err)
+	}
+	request := &pb.EnrollDeviceRequest{
+		Name: name,
+		Csr:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr}),
+	}
+	result, err := s.remoteClient.Enroll(s.ctx, request)
+	if err != nil {
+		return "", fmt.Errorf("failed to enroll device: %v", err)
+	}
+	keyBytes, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal device key: %v", err)
+	}
+	err = os.WriteFile(s.cfg.DeviceKeyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyBytes}), 0600)
+	if err != nil {
+		return "", fmt.Errorf("failed to write device key: %v", err)
+	}
+	err = os.WriteFile(s.cfg.DeviceCertFile, result.Certificate, 0600)
+	if err != nil {
+		return "", fmt.Errorf("failed to write device certificate: %v", err)
+	}
+	return result.Id, nil
+}
+
+// ListDevices implements the ListDevices method of the Service interface.
+func (s *ServiceImpl) ListDevices() ([]models.Device, error) {
+	result, err := s.remoteClient.ListDevices(s.ctx, &pb.ListDevicesRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list devices: %v", err)
+	}
+	devices := make([]models.Device, len(result.Devices))
+	for i, d := range result.Devices {
+		devices[i] = models.Device{
+			ID:        d.Id,
+			Name:      d.Name,
+			CreatedAt: d.CreatedAt.AsTime(),
+			ExpiresAt: d.ExpiresAt.AsTime(),
+			Revoked:   d.Revoked,
+			Current:   d.Current,
+		}
+	}
+	return devices, nil
+}
+
+// RevokeDevice implements the RevokeDevice method of the Service interface.
+func (s *ServiceImpl) RevokeDevice(id string) error {
+	_, err := s.remoteClient.RevokeDevice(s.ctx, &pb.RevokeDeviceRequest{Id: id})
+	if err != nil {
+		return fmt.Errorf("failed to revoke device: %v", err)
+	}
+	return nil
+}
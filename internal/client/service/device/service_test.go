@@ -0,0 +1,132 @@
+package device
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"github.com/Mldlr/storety/internal/client/config"
+	"github.com/Mldlr/storety/internal/client/mocks"
+	"github.com/Mldlr/storety/internal/client/models"
+	pb "github.com/Mldlr/storety/internal/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEnroll(t *testing.T) {
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	remoteClientMock := new(mocks.DeviceClient)
+	cfg := &config.Config{
+		DeviceCertFile: filepath.Join(tmpDir, "device.pem"),
+		DeviceKeyFile:  filepath.Join(tmpDir, "device_key.pem"),
+	}
+	service := &ServiceImpl{
+		ctx:          ctx,
+		remoteClient: remoteClientMock,
+		cfg:          cfg,
+	}
+
+	var certPEM []byte
+	remoteClientMock.On("Enroll", ctx, mock.AnythingOfType("*proto.EnrollDeviceRequest")).
+		Return(func(ctx context.Context, in *pb.EnrollDeviceRequest, _ ...grpc.CallOption) *pb.EnrollDeviceResponse {
+			certPEM = selfSignCSR(t, in.Csr)
+			return &pb.EnrollDeviceResponse{Id: "device-id", Certificate: certPEM}
+		}, nil).Once()
+
+	id, err := service.Enroll("laptop")
+	require.NoError(t, err)
+	assert.Equal(t, "device-id", id)
+
+	info, err := os.Stat(cfg.DeviceKeyFile)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	_, err = tls.LoadX509KeyPair(cfg.DeviceCertFile, cfg.DeviceKeyFile)
+	assert.NoError(t, err)
+
+	remoteClientMock.On("Enroll", ctx, mock.AnythingOfType("*proto.EnrollDeviceRequest")).
+		Return(nil, errors.New("rpc error")).Once()
+	err = os.Remove(cfg.DeviceCertFile)
+	require.NoError(t, err)
+	_, err = service.Enroll("laptop")
+	assert.Error(t, err)
+	_, err = os.Stat(cfg.DeviceCertFile)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	remoteClientMock.AssertExpectations(t)
+}
+
+func TestListDevices(t *testing.T) {
+	ctx := context.Background()
+	remoteClientMock := new(mocks.DeviceClient)
+	service := &ServiceImpl{
+		ctx:          ctx,
+		remoteClient: remoteClientMock,
+		cfg:          &config.Config{},
+	}
+	now := time.Now().UTC()
+	remoteClientMock.On("ListDevices", ctx, &pb.ListDevicesRequest{}).
+		Return(&pb.ListDevicesResponse{Devices: []*pb.DeviceInfo{
+			{
+				Id:        "id",
+				Name:      "laptop",
+				CreatedAt: timestamppb.New(now),
+				ExpiresAt: timestamppb.New(now),
+				Current:   true,
+			},
+		}}, nil)
+
+	devices, err := service.ListDevices()
+	require.NoError(t, err)
+	assert.Equal(t, []models.Device{{ID: "id", Name: "laptop", CreatedAt: now, ExpiresAt: now, Current: true}}, devices)
+	remoteClientMock.AssertExpectations(t)
+}
+
+func TestRevokeDevice(t *testing.T) {
+	ctx := context.Background()
+	remoteClientMock := new(mocks.DeviceClient)
+	service := &ServiceImpl{
+		ctx:          ctx,
+		remoteClient: remoteClientMock,
+		cfg:          &config.Config{},
+	}
+	remoteClientMock.On("RevokeDevice", ctx, &pb.RevokeDeviceRequest{Id: "id"}).
+		Return(&pb.RevokeDeviceResponse{}, nil).Once()
+	remoteClientMock.On("RevokeDevice", ctx, &pb.RevokeDeviceRequest{Id: "unknown"}).
+		Return(nil, errors.New("not found")).Once()
+
+	assert.NoError(t, service.RevokeDevice("id"))
+	assert.Error(t, service.RevokeDevice("unknown"))
+	remoteClientMock.AssertExpectations(t)
+}
+
+// selfSignCSR issues a certificate for the public key of the CSR signed by a throwaway key, standing in for the server CA.
+func selfSignCSR(t *testing.T, csrPEM []byte) []byte {
+	block, _ := pem.Decode(csrPEM)
+	require.NotNil(t, block)
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	require.NoError(t, err)
+	require.NoError(t, csr.CheckSignature())
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ca := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      csr.Subject,
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, ca, ca, csr.PublicKey, caKey)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
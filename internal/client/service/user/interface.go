@@ -8,6 +8,13 @@ type Service interface {
 	// LogInUser attempts user authorization on the remote server and if it fails, attempts local authorization.
 	LogInUser(username, password string) error
 
+	// VerifyPassword checks the password of the user against the local authorization data without logging in.
+	VerifyPassword(username, password string) error
+
 	// RefreshToken makes a request to the RefreshUserSession RPC to refresh the user's session and updates the config.
 	RefreshToken() error
+
+	// DeleteAccount makes a request to the DeleteAccount RPC to delete the account of the logged in user,
+	// removes the user's local authorization data and clears the config.
+	DeleteAccount(password string) error
 }
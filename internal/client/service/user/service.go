@@ -3,17 +3,16 @@ package user
 import (
 	"context"
 	"crypto/rand"
-	"crypto/sha256"
 	"encoding/base64"
 	"errors"
 	"fmt"
 	"github.com/Mldlr/storety/internal/client/config"
 	"github.com/Mldlr/storety/internal/client/pkg/utils"
+	"github.com/Mldlr/storety/internal/client/service/crypto"
 	"github.com/Mldlr/storety/internal/constants"
 	pb "github.com/Mldlr/storety/internal/proto"
 	"github.com/samber/do"
 	"golang.org/x/crypto/bcrypt"
-	"golang.org/x/crypto/pbkdf2"
 	"google.golang.org/grpc"
 	"log"
 	"os"
@@ -57,12 +56,13 @@ func (c *ServiceImpl) CreateUser(username, password string) error {
 		return err
 	}
 	c.cfg.UpdateTokens(result.AuthToken, result.RefreshToken)
-	key := pbkdf2.Key([]byte(password), salt, 10000, 32, sha256.New)
+	key := crypto.DeriveKey(password, salt)
 	hashedKey, err := bcrypt.GenerateFromPassword(key, 14)
 	if err != nil {
 		return err
 	}
 	c.cfg.UpdateKey(key)
+	c.cfg.UpdateUsername(username)
 	err = utils.SaveAuthData(c.cfg.SaltsFile, username, hashedKey, salt, result.AuthToken, result.RefreshToken)
 	if err != nil {
 		return err
@@ -75,8 +75,7 @@ func (c *ServiceImpl) CreateUser(username, password string) error {
 func (c *ServiceImpl) LogInUser(username, password string) error {
 	err := c.remoteLogInUser(username, password)
 	if err != nil {
-		log.Println("Failed to log in on remote server, attempting local login")
-		fmt.Println(err)
+		log.Println("Failed to log in on remote server:", err)
 	} else {
 		log.Println("Successful remote log in")
 		return nil
@@ -84,7 +83,7 @@ func (c *ServiceImpl) LogInUser(username, password string) error {
 	log.Println("attempting local login")
 	err = c.localLogin(username, password)
 	if err != nil {
-		return fmt.Errorf("failed local login: %v", err)
+		return fmt.Errorf("failed local login: %w", err)
 	}
 	return nil
 }
@@ -104,13 +103,13 @@ func (c *ServiceImpl) remoteLogInUser(username, password string) error {
 	if err != nil {
 		return err
 	}
-	key := pbkdf2.Key([]byte(password), salt, 10000, 32, sha256.New)
+	key := crypto.DeriveKey(password, salt)
 	hashedKey, err := bcrypt.GenerateFromPassword(key, 14)
 	if err != nil {
 		return err
 	}
 	c.cfg.UpdateKey(key)
-	fmt.Println("salt", salt)
+	c.cfg.UpdateUsername(username)
 	err = utils.SaveAuthData(c.cfg.SaltsFile, username, hashedKey, salt, result.AuthToken, result.RefreshToken)
 	if err != nil {
 		return err
@@ -130,13 +129,27 @@ func (c *ServiceImpl) localLogin(username, password string) error {
 		}
 		return err
 	}
-	key := pbkdf2.Key([]byte(password), salt, 10000, 32, sha256.New)
+	key := crypto.DeriveKey(password, salt)
 	c.cfg.UpdateKey(key)
 	c.cfg.UpdateTokens(authToken, refreshToken)
 	err = bcrypt.CompareHashAndPassword(hashedKey, key)
 	if err != nil {
 		return constants.ErrInvalidCredentials
 	}
+	c.cfg.UpdateUsername(username)
+	return nil
+}
+
+// VerifyPassword implements the Service interface method VerifyPassword.
+func (c *ServiceImpl) VerifyPassword(username, password string) error {
+	hashedKey, salt, _, _, err := utils.GetAuthData(c.cfg.SaltsFile, username)
+	if err != nil {
+		return err
+	}
+	key := crypto.DeriveKey(password, salt)
+	if bcrypt.CompareHashAndPassword(hashedKey, key) != nil {
+		return constants.ErrInvalidCredentials
+	}
 	return nil
 }
 
@@ -145,8 +158,23 @@ func (c *ServiceImpl) RefreshToken() error {
 	request := &pb.RefreshUserSessionRequest{}
 	result, err := c.remoteClient.RefreshUserSession(c.ctx, request)
 	if err != nil {
-		return fmt.Errorf("failed to refresh token: %v", err)
+		return fmt.Errorf("failed to refresh token: %w", err)
 	}
 	c.cfg.UpdateTokens(result.AuthToken, result.RefreshToken)
 	return nil
 }
+
+// DeleteAccount implements the Service interface method DeleteAccount.
+func (c *ServiceImpl) DeleteAccount(password string) error {
+	request := &pb.DeleteAccountRequest{Password: password}
+	_, err := c.remoteClient.DeleteAccount(c.ctx, request)
+	if err != nil {
+		return fmt.Errorf("failed to delete account: %w", err)
+	}
+	err = utils.DeleteAuthData(c.cfg.SaltsFile, c.cfg.Username)
+	if err != nil {
+		return err
+	}
+	c.cfg.Logout()
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/Mldlr/storety/internal/client/config"
 	"github.com/Mldlr/storety/internal/client/mocks"
 	"github.com/Mldlr/storety/internal/client/pkg/utils"
+	"github.com/Mldlr/storety/internal/constants"
 	pb "github.com/Mldlr/storety/internal/proto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -131,6 +132,8 @@ func TestLogInUser(t *testing.T) {
 				assert.NoError(t, err)
 				assert.Equal(t, "new-auth-token", authToken)
 				assert.Equal(t, "new-refresh-token", refreshToken)
+				assert.NoError(t, service.VerifyPassword("username", "password"))
+				assert.Equal(t, constants.ErrInvalidCredentials, service.VerifyPassword("username", "wrong"))
 
 				remoteClientMock.AssertCalled(t, "LogInUser", ctx, mock.AnythingOfType("*proto.LoginUserRequest"))
 				remoteClientMock.AssertNumberOfCalls(t, "LogInUser", 1)
@@ -140,3 +143,40 @@ func TestLogInUser(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteAccount(t *testing.T) {
+	ctx := context.Background()
+	remoteClientMock := new(mocks.UserClient)
+
+	saltsFile, err := os.CreateTemp("", "salts-test.json")
+	assert.NoError(t, err)
+	defer os.Remove(saltsFile.Name())
+	err = utils.SaveAuthData(saltsFile.Name(), "username", []byte("key"), []byte("salt"), "auth", "refresh")
+	assert.NoError(t, err)
+
+	cfg := &config.Config{
+		Username:        "username",
+		JWTAuthToken:    "auth",
+		JWTRefreshToken: "refresh",
+		EncryptionKey:   []byte("key"),
+		SaltsFile:       saltsFile.Name(),
+	}
+	service := ServiceImpl{
+		ctx:          ctx,
+		remoteClient: remoteClientMock,
+		cfg:          cfg,
+	}
+
+	remoteClientMock.On("DeleteAccount", ctx, &pb.DeleteAccountRequest{Password: "password"}).
+		Return(&pb.DeleteAccountResponse{}, nil)
+
+	err = service.DeleteAccount("password")
+	assert.NoError(t, err)
+	remoteClientMock.AssertNumberOfCalls(t, "DeleteAccount", 1)
+
+	_, _, _, _, err = utils.GetAuthData(saltsFile.Name(), "username")
+	assert.Error(t, err)
+	assert.Empty(t, cfg.Username)
+	assert.Empty(t, cfg.JWTAuthToken)
+	assert.Nil(t, cfg.EncryptionKey)
+}
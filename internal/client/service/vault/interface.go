@@ -0,0 +1,39 @@
+package vault
+
+import "github.com/Mldlr/storety/internal/client/models"
+
+// Service is the interface for the vault service, storing and reading decrypted data entries.
+// Entries are encrypted with the key of the user logged in by this process,
+// or by the agent when this process is not logged in.
+type Service interface {
+	// CreateData encrypts and stores a new data entry.
+	CreateData(name, typ string, content []byte) error
+
+	// CreateBatchData encrypts and stores several new data entries at once.
+	// Only the name, type and content of the entries are used; none is stored if any name is taken.
+	CreateBatchData(data []models.Data) error
+
+	// GetData returns the decrypted content and the type of a data entry.
+	GetData(name string) ([]byte, string, error)
+
+	// GetItem returns the data entry decoded into the fields of its type.
+	GetItem(name string) (*models.Item, error)
+
+	// ListData returns the names and types of the stored data entries.
+	ListData() ([]models.DataInfo, error)
+
+	// ExportData returns all stored data entries with decrypted contents.
+	ExportData() ([]models.Data, error)
+
+	// UpdateData encrypts and stores the new content of a data entry, keeping its ID and type.
+	UpdateData(name string, content []byte) error
+
+	// DeleteData deletes a data entry.
+	DeleteData(name string) error
+
+	// SyncData syncs the local data with the server.
+	SyncData() error
+
+	// Unlocked reports whether a key is available, either in this process or in an unlocked agent.
+	Unlocked() bool
+}
@@ -0,0 +1,35 @@
+package vault
+
+import (
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/itemtype"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/Mldlr/storety/internal/constants"
+)
+
+// DecodeItem decodes the decrypted content of a data entry into the fields of its registered type.
+// Content of an unknown type is returned as a single content field.
+func DecodeItem(name, typ string, content []byte) (*models.Item, error) {
+	t, ok := itemtype.Lookup(typ)
+	if !ok {
+		return &models.Item{Name: name, Type: typ, Fields: []models.Field{{Name: "content", Value: string(content)}}}, nil
+	}
+	return t.Item(name, content)
+}
+
+// CheckLinks checks that the values of link fields name items of the linked type.
+func CheckLinks(s Service, t *itemtype.Type, values itemtype.Values) error {
+	for _, f := range t.Fields {
+		if f.Link == "" || values[f.Name] == "" {
+			continue
+		}
+		_, typ, err := s.GetData(values[f.Name])
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", f.Name, values[f.Name], err)
+		}
+		if typ != f.Link {
+			return fmt.Errorf("%w: %s %s is not an item of type %s", constants.ErrFieldNotFound, f.Name, values[f.Name], f.Link)
+		}
+	}
+	return nil
+}
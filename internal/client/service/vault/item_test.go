@@ -0,0 +1,112 @@
+package vault
+
+import (
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDecodeItem(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     string
+		content string
+		want    []models.Field
+		wantErr bool
+	}{
+		{
+			name:    "Credentials",
+			typ:     "Cred",
+			content: `{"login":"octocat","password":"secret","meta":"work"}`,
+			want: []models.Field{
+				{Name: "login", Value: "octocat"},
+				{Name: "password", Value: "secret"},
+				{Name: "meta", Value: "work"},
+			},
+		},
+		{
+			name:    "Credentials with url",
+			typ:     "Cred",
+			content: `{"login":"octocat","password":"secret","url":"https://github.com","meta":""}`,
+			want: []models.Field{
+				{Name: "login", Value: "octocat"},
+				{Name: "password", Value: "secret"},
+				{Name: "url", Value: "https://github.com"},
+				{Name: "meta", Value: ""},
+			},
+		},
+		{
+			name:    "Binary is base64 encoded",
+			typ:     "Binary",
+			content: `{"blob":"AQI=","meta":""}`,
+			want: []models.Field{
+				{Name: "blob", Value: "AQI="},
+				{Name: "meta", Value: ""},
+			},
+		},
+		{
+			name:    "SSH key",
+			typ:     "SSHKey",
+			content: `{"private_key":"PEM","public_key":"ssh-ed25519 AAAA laptop","comment":"laptop","confirm":true,"meta":""}`,
+			want: []models.Field{
+				{Name: "public_key", Value: "ssh-ed25519 AAAA laptop"},
+				{Name: "private_key", Value: "PEM"},
+				{Name: "comment", Value: "laptop"},
+				{Name: "confirm", Value: "true"},
+				{Name: "meta", Value: ""},
+			},
+		},
+		{
+			name:    "Credentials with linked otp",
+			typ:     "Cred",
+			content: `{"login":"octocat","password":"secret","otp":"github-2fa","meta":""}`,
+			want: []models.Field{
+				{Name: "login", Value: "octocat"},
+				{Name: "password", Value: "secret"},
+				{Name: "otp", Value: "github-2fa"},
+				{Name: "meta", Value: ""},
+			},
+		},
+		{
+			name:    "OTP",
+			typ:     "OTP",
+			content: `{"secret":"JBSWY3DPEHPK3PXP","issuer":"GitHub","account":"octocat","algorithm":"SHA1","digits":6,"period":30,"meta":""}`,
+			want: []models.Field{
+				{Name: "issuer", Value: "GitHub"},
+				{Name: "account", Value: "octocat"},
+				{Name: "secret", Value: "JBSWY3DPEHPK3PXP"},
+				{Name: "algorithm", Value: "SHA1"},
+				{Name: "digits", Value: "6"},
+				{Name: "period", Value: "30"},
+				{Name: "meta", Value: ""},
+			},
+		},
+		{
+			name:    "Unknown type",
+			typ:     "Note",
+			content: "plain",
+			want:    []models.Field{{Name: "content", Value: "plain"}},
+		},
+		{
+			name:    "Corrupted content",
+			typ:     "Text",
+			content: "{",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item, err := DecodeItem("name", tt.typ, []byte(tt.content))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, item.Fields)
+			value, ok := item.Field(tt.want[0].Name)
+			assert.True(t, ok)
+			assert.Equal(t, tt.want[0].Value, value)
+		})
+	}
+}
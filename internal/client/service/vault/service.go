@@ -0,0 +1,220 @@
+package vault
+
+import (
+	"fmt"
+	"github.com/Mldlr/storety/internal/client/agent"
+	"github.com/Mldlr/storety/internal/client/config"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/Mldlr/storety/internal/client/service/crypto"
+	"github.com/Mldlr/storety/internal/client/service/data"
+	"github.com/samber/do"
+	"sync"
+)
+
+// remote is the part of the agent client used when this process is not logged in.
+type remote interface {
+	Status() (*agent.Status, error)
+	CreateData(name, typ string, content []byte) error
+	CreateBatchData(data []models.Data) error
+	GetData(name string) ([]byte, string, error)
+	ListData() ([]models.DataInfo, error)
+	ExportData() ([]models.Data, error)
+	UpdateData(name string, content []byte) error
+	DeleteData(name string) error
+	SyncData() error
+}
+
+// ServiceImpl is the implementation of the vault service.
+type ServiceImpl struct {
+	cfg         *config.Config
+	dataService data.Service
+	crypto      crypto.Crypto
+	dialAgent   func() (remote, error)
+	// agentMu guards the lazy connection to the agent, which callers on several goroutines may make at once.
+	agentMu sync.Mutex
+	agent   remote
+}
+
+// NewServiceImpl creates a new ServiceImpl instance and returns a pointer to it.
+func NewServiceImpl(i *do.Injector) *ServiceImpl {
+	cfg := do.MustInvoke[*config.Config](i)
+	return &ServiceImpl{
+		cfg:         cfg,
+		dataService: do.MustInvoke[data.Service](i),
+		crypto:      do.MustInvoke[crypto.Crypto](i),
+		dialAgent: func() (remote, error) {
+			return agent.Dial(cfg.AgentSocket)
+		},
+	}
+}
+
+// local reports whether the user is logged in by this process.
+func (s *ServiceImpl) local() bool {
+	return s.cfg.EncryptionKey != nil
+}
+
+// remote returns the client of the agent, connecting on first use.
+func (s *ServiceImpl) remote() (remote, error) {
+	s.agentMu.Lock()
+	defer s.agentMu.Unlock()
+	if s.agent == nil {
+		a, err := s.dialAgent()
+		if err != nil {
+			return nil, err
+		}
+		s.agent = a
+	}
+	return s.agent, nil
+}
+
+// Unlocked implements the Service interface Unlocked method.
+func (s *ServiceImpl) Unlocked() bool {
+	if s.local() {
+		return true
+	}
+	a, err := s.remote()
+	if err != nil {
+		return false
+	}
+	status, err := a.Status()
+	return err == nil && status.Unlocked
+}
+
+// CreateData implements the Service interface CreateData method.
+func (s *ServiceImpl) CreateData(name, typ string, content []byte) error {
+	if !s.local() {
+		a, err := s.remote()
+		if err != nil {
+			return err
+		}
+		return a.CreateData(name, typ, content)
+	}
+	encrypted, err := s.crypto.EncryptWithAES256(content)
+	if err != nil {
+		return err
+	}
+	return s.dataService.CreateData(name, typ, encrypted)
+}
+
+// CreateBatchData implements the Service interface CreateBatchData method.
+func (s *ServiceImpl) CreateBatchData(data []models.Data) error {
+	if !s.local() {
+		a, err := s.remote()
+		if err != nil {
+			return err
+		}
+		return a.CreateBatchData(data)
+	}
+	batch := make([]models.Data, len(data))
+	for i, d := range data {
+		encrypted, err := s.crypto.EncryptWithAES256(d.Content)
+		if err != nil {
+			return err
+		}
+		batch[i] = models.Data{Name: d.Name, Type: d.Type, Content: encrypted}
+	}
+	return s.dataService.CreateBatchData(batch)
+}
+
+// GetData implements the Service interface GetData method.
+func (s *ServiceImpl) GetData(name string) ([]byte, string, error) {
+	if !s.local() {
+		a, err := s.remote()
+		if err != nil {
+			return nil, "", err
+		}
+		return a.GetData(name)
+	}
+	content, typ, err := s.dataService.GetData(name)
+	if err != nil {
+		return nil, "", err
+	}
+	decrypted, err := s.crypto.DecryptWithAES256(content)
+	if err != nil {
+		return nil, "", err
+	}
+	return decrypted, typ, nil
+}
+
+// GetItem implements the Service interface GetItem method.
+func (s *ServiceImpl) GetItem(name string) (*models.Item, error) {
+	content, typ, err := s.GetData(name)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeItem(name, typ, content)
+}
+
+// ListData implements the Service interface ListData method.
+func (s *ServiceImpl) ListData() ([]models.DataInfo, error) {
+	if !s.local() {
+		a, err := s.remote()
+		if err != nil {
+			return nil, err
+		}
+		return a.ListData()
+	}
+	return s.dataService.ListData()
+}
+
+// ExportData implements the Service interface ExportData method.
+func (s *ServiceImpl) ExportData() ([]models.Data, error) {
+	if !s.local() {
+		a, err := s.remote()
+		if err != nil {
+			return nil, err
+		}
+		return a.ExportData()
+	}
+	data, err := s.dataService.ExportData()
+	if err != nil {
+		return nil, err
+	}
+	for i := range data {
+		data[i].Content, err = s.crypto.DecryptWithAES256(data[i].Content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt %s: %w", data[i].Name, err)
+		}
+	}
+	return data, nil
+}
+
+// UpdateData implements the Service interface UpdateData method.
+func (s *ServiceImpl) UpdateData(name string, content []byte) error {
+	if !s.local() {
+		a, err := s.remote()
+		if err != nil {
+			return err
+		}
+		return a.UpdateData(name, content)
+	}
+	encrypted, err := s.crypto.EncryptWithAES256(content)
+	if err != nil {
+		return err
+	}
+	return s.dataService.UpdateData(name, encrypted)
+}
+
+// DeleteData implements the Service interface DeleteData method.
+func (s *ServiceImpl) DeleteData(name string) error {
+	if !s.local() {
+		a, err := s.remote()
+		if err != nil {
+			return err
+		}
+		return a.DeleteData(name)
+	}
+	return s.dataService.DeleteData(name)
+}
+
+// SyncData implements the Service interface SyncData method.
+func (s *ServiceImpl) SyncData() error {
+	if !s.local() {
+		a, err := s.remote()
+		if err != nil {
+			return err
+		}
+		return a.SyncData()
+	}
+	return s.dataService.SyncData()
+}
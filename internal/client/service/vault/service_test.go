@@ -0,0 +1,179 @@
+package vault
+
+import (
+	"errors"
+	"github.com/Mldlr/storety/internal/client/agent"
+	"github.com/Mldlr/storety/internal/client/config"
+	"github.com/Mldlr/storety/internal/client/mocks"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/Mldlr/storety/internal/client/service/crypto"
+	"github.com/Mldlr/storety/internal/client/service/data"
+	"github.com/Mldlr/storety/internal/constants"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// fakeAgent is an unlocked agent storing entries in memory.
+type fakeAgent struct {
+	entries map[string][]byte
+}
+
+func (f *fakeAgent) Status() (*agent.Status, error) {
+	return &agent.Status{Unlocked: true, Username: "username"}, nil
+}
+func (f *fakeAgent) CreateData(name, typ string, content []byte) error {
+	f.entries[name] = content
+	return nil
+}
+func (f *fakeAgent) CreateBatchData(data []models.Data) error {
+	for _, d := range data {
+		f.entries[d.Name] = d.Content
+	}
+	return nil
+}
+func (f *fakeAgent) GetData(name string) ([]byte, string, error) {
+	return f.entries[name], "Text", nil
+}
+func (f *fakeAgent) ListData() ([]models.DataInfo, error) {
+	return []models.DataInfo{{Name: "name", Type: "Text"}}, nil
+}
+func (f *fakeAgent) ExportData() ([]models.Data, error) {
+	var data []models.Data
+	for name, content := range f.entries {
+		data = append(data, models.Data{Name: name, Type: "Text", Content: content})
+	}
+	return data, nil
+}
+func (f *fakeAgent) UpdateData(name string, content []byte) error {
+	f.entries[name] = content
+	return nil
+}
+func (f *fakeAgent) DeleteData(name string) error {
+	delete(f.entries, name)
+	return nil
+}
+func (f *fakeAgent) SyncData() error { return nil }
+
+func TestServiceImpl_Local(t *testing.T) {
+	cfg := &config.Config{EncryptionKey: make([]byte, 32)}
+	i := do.New()
+	do.ProvideValue(i, cfg)
+	storageMock := new(mocks.Storage)
+	dataService := &data.ServiceImpl{}
+	dataService.SetStorage(storageMock)
+	var stored []byte
+	storageMock.On("CreateData", mock.Anything, mock.AnythingOfType("*models.Data")).
+		Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*models.Data).Content
+		}).Return(nil).Once()
+	service := &ServiceImpl{
+		cfg:         cfg,
+		dataService: dataService,
+		crypto:      *crypto.NewCrypto(i),
+		dialAgent: func() (remote, error) {
+			t.Fatal("agent must not be dialed while logged in")
+			return nil, nil
+		},
+	}
+
+	assert.True(t, service.Unlocked())
+	require.NoError(t, service.CreateData("name", "Text", []byte("secret")))
+	assert.NotEqual(t, []byte("secret"), stored, "content must be stored encrypted")
+
+	storageMock.On("GetDataContentByName", mock.Anything, "name").Return(stored, "Text", nil).Once()
+	content, typ, err := service.GetData("name")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret"), content)
+	assert.Equal(t, "Text", typ)
+
+	var batch []models.Data
+	storageMock.On("CreateBatchData", mock.Anything, mock.AnythingOfType("[]models.Data")).
+		Run(func(args mock.Arguments) {
+			batch = args.Get(1).([]models.Data)
+		}).Return(nil).Once()
+	require.NoError(t, service.CreateBatchData([]models.Data{{Name: "first", Type: "Text", Content: []byte("one")}}))
+	require.Len(t, batch, 1)
+	assert.Equal(t, "first", batch[0].Name)
+	assert.NotEqual(t, []byte("one"), batch[0].Content, "content must be stored encrypted")
+	assert.NotEqual(t, uuid.Nil, batch[0].ID)
+
+	storageMock.On("GetAllData", mock.Anything).Return([]models.Data{{Name: "name", Type: "Text", Content: stored}}, nil).Once()
+	exported, err := service.ExportData()
+	require.NoError(t, err)
+	assert.Equal(t, []models.Data{{Name: "name", Type: "Text", Content: []byte("secret")}}, exported)
+
+	var updated []byte
+	storageMock.On("UpdateDataByName", mock.Anything, "name", mock.AnythingOfType("[]uint8")).
+		Run(func(args mock.Arguments) {
+			updated = args.Get(2).([]byte)
+		}).Return(nil).Once()
+	require.NoError(t, service.UpdateData("name", []byte("changed")))
+	assert.NotEqual(t, []byte("changed"), updated, "content must be stored encrypted")
+	storageMock.AssertExpectations(t)
+}
+
+func TestServiceImpl_Agent(t *testing.T) {
+	a := &fakeAgent{entries: map[string][]byte{}}
+	dials := 0
+	service := &ServiceImpl{
+		cfg: &config.Config{},
+		dialAgent: func() (remote, error) {
+			dials++
+			return a, nil
+		},
+	}
+
+	assert.True(t, service.Unlocked())
+	require.NoError(t, service.CreateData("name", "Text", []byte("secret")))
+	assert.Equal(t, []byte("secret"), a.entries["name"])
+	content, _, err := service.GetData("name")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret"), content)
+	exported, err := service.ExportData()
+	require.NoError(t, err)
+	assert.Equal(t, []models.Data{{Name: "name", Type: "Text", Content: []byte("secret")}}, exported)
+	require.NoError(t, service.UpdateData("name", []byte("changed")))
+	assert.Equal(t, []byte("changed"), a.entries["name"])
+	require.NoError(t, service.DeleteData("name"))
+	assert.Empty(t, a.entries)
+	assert.Equal(t, 1, dials)
+}
+
+func TestServiceImpl_AgentDialedOnce(t *testing.T) {
+	var dials int32
+	service := &ServiceImpl{
+		cfg: &config.Config{},
+		dialAgent: func() (remote, error) {
+			atomic.AddInt32(&dials, 1)
+			return &fakeAgent{entries: map[string][]byte{}}, nil
+		},
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, service.SyncData())
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&dials), "concurrent calls share one connection")
+}
+
+func TestServiceImpl_NoAgent(t *testing.T) {
+	service := &ServiceImpl{
+		cfg: &config.Config{},
+		dialAgent: func() (remote, error) {
+			return nil, constants.ErrAgentNotRunning
+		},
+	}
+	assert.False(t, service.Unlocked())
+	_, err := service.ListData()
+	assert.True(t, errors.Is(err, constants.ErrAgentNotRunning))
+}
@@ -0,0 +1,185 @@
+// Package vaulttest provides an in-memory vault for the tests of packages built on the vault service.
+// It does not import the vault package, so the packages the vault depends on can use it too.
+package vaulttest
+
+import (
+	"errors"
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/Mldlr/storety/internal/constants"
+	"github.com/google/uuid"
+	"sort"
+	"sync"
+)
+
+// Decoder decodes the content of a data entry into the fields of its type, like vault.DecodeItem.
+type Decoder func(name, typ string, content []byte) (*models.Item, error)
+
+// Fake is a vault keeping decrypted data entries in memory. It is safe for concurrent use.
+type Fake struct {
+	mu      sync.Mutex
+	decode  Decoder
+	entries map[string]models.Data
+	syncs   int
+	syncErr error
+}
+
+// New creates an empty unlocked Fake decoding items with decode.
+// A nil decode is enough for tests that never call GetItem.
+func New(decode Decoder) *Fake {
+	return &Fake{decode: decode, entries: map[string]models.Data{}}
+}
+
+// Put stores the data entry with a new ID, replacing one of the same name.
+func (f *Fake) Put(name, typ string, content []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.entries[name] = models.Data{ID: uuid.New(), Name: name, Type: typ, Content: content}
+}
+
+// ID returns the ID of the stored data entry with the name, or uuid.Nil if there is none.
+// An entry keeps its ID when updated and gets a new one when deleted and created again.
+func (f *Fake) ID(name string) uuid.UUID {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.entries[name].ID
+}
+
+// Has reports whether a data entry with the name is stored.
+func (f *Fake) Has(name string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, ok := f.entries[name]
+	return ok
+}
+
+// Len returns the number of stored data entries.
+func (f *Fake) Len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.entries)
+}
+
+// Syncs returns the number of SyncData calls.
+func (f *Fake) Syncs() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.syncs
+}
+
+// FailSync makes the following SyncData calls return err, or succeed again if err is nil.
+func (f *Fake) FailSync(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.syncErr = err
+}
+
+// CreateData implements the vault.Service interface CreateData method.
+func (f *Fake) CreateData(name, typ string, content []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.create([]models.Data{{Name: name, Type: typ, Content: content}})
+}
+
+// CreateBatchData implements the vault.Service interface CreateBatchData method.
+func (f *Fake) CreateBatchData(data []models.Data) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.create(data)
+}
+
+// create stores the data entries, or none of them if a name is taken.
+func (f *Fake) create(data []models.Data) error {
+	for _, d := range data {
+		if _, ok := f.entries[d.Name]; ok {
+			return constants.ErrDataExists
+		}
+	}
+	for _, d := range data {
+		f.entries[d.Name] = models.Data{ID: uuid.New(), Name: d.Name, Type: d.Type, Content: d.Content}
+	}
+	return nil
+}
+
+// GetData implements the vault.Service interface GetData method.
+func (f *Fake) GetData(name string) ([]byte, string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	d, ok := f.entries[name]
+	if !ok {
+		return nil, "", constants.ErrGetData
+	}
+	return d.Content, d.Type, nil
+}
+
+// GetItem implements the vault.Service interface GetItem method.
+func (f *Fake) GetItem(name string) (*models.Item, error) {
+	if f.decode == nil {
+		return nil, errors.New("vaulttest: no decoder given")
+	}
+	content, typ, err := f.GetData(name)
+	if err != nil {
+		return nil, err
+	}
+	return f.decode(name, typ, content)
+}
+
+// ListData implements the vault.Service interface ListData method. Entries are sorted by name.
+func (f *Fake) ListData() ([]models.DataInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	list := make([]models.DataInfo, 0, len(f.entries))
+	for _, d := range f.entries {
+		list = append(list, models.DataInfo{Name: d.Name, Type: d.Type})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// ExportData implements the vault.Service interface ExportData method. Entries are sorted by name.
+func (f *Fake) ExportData() ([]models.Data, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data := make([]models.Data, 0, len(f.entries))
+	for _, d := range f.entries {
+		data = append(data, d)
+	}
+	sort.Slice(data, func(i, j int) bool { return data[i].Name < data[j].Name })
+	return data, nil
+}
+
+// UpdateData implements the vault.Service interface UpdateData method.
+func (f *Fake) UpdateData(name string, content []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	d, ok := f.entries[name]
+	if !ok {
+		return errors.Join(constants.ErrUpdateData, constants.ErrGetData)
+	}
+	d.Content = content
+	f.entries[name] = d
+	return nil
+}
+
+// DeleteData implements the vault.Service interface DeleteData method.
+func (f *Fake) DeleteData(name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.entries[name]; !ok {
+		return constants.ErrDeleteData
+	}
+	delete(f.entries, name)
+	return nil
+}
+
+// SyncData implements the vault.Service interface SyncData method.
+func (f *Fake) SyncData() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.syncs++
+	return f.syncErr
+}
+
+// Unlocked implements the vault.Service interface Unlocked method.
+func (f *Fake) Unlocked() bool {
+	return true
+}
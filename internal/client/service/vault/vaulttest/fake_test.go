@@ -0,0 +1,33 @@
+package vaulttest_test
+
+import (
+	"github.com/Mldlr/storety/internal/client/models"
+	"github.com/Mldlr/storety/internal/client/service/vault"
+	"github.com/Mldlr/storety/internal/client/service/vault/vaulttest"
+	"github.com/Mldlr/storety/internal/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+var _ vault.Service = (*vaulttest.Fake)(nil)
+
+func TestFake(t *testing.T) {
+	v := vaulttest.New(vault.DecodeItem)
+	require.NoError(t, v.CreateData("note", "Text", []byte(`{"text":"hello","meta":""}`)))
+	assert.ErrorIs(t, v.CreateData("note", "Text", nil), constants.ErrDataExists)
+	err := v.CreateBatchData([]models.Data{{Name: "other", Type: "Text"}, {Name: "note", Type: "Text"}})
+	assert.ErrorIs(t, err, constants.ErrDataExists)
+	assert.False(t, v.Has("other"), "no entry of a failed batch is stored")
+
+	item, err := v.GetItem("note")
+	require.NoError(t, err)
+	text, _ := item.Field("text")
+	assert.Equal(t, "hello", text)
+
+	require.NoError(t, v.DeleteData("note"))
+	assert.ErrorIs(t, v.DeleteData("note"), constants.ErrDeleteData)
+	_, _, err = v.GetData("note")
+	assert.ErrorIs(t, err, constants.ErrGetData)
+	assert.Zero(t, v.Len())
+}
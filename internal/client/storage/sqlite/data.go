@@ -6,6 +6,7 @@ import (
 	"database/sql"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"github.com/Mldlr/storety/internal/client/models"
 	"github.com/Mldlr/storety/internal/constants"
 	"github.com/google/uuid"
@@ -21,9 +22,35 @@ func (d *DB) CreateData(ctx context.Context, data *models.Data) error {
 	}
 	defer d.commitTx(tx, err)
 	res, err := tx.ExecContext(ctx, createData, data.ID, data.Name, data.Type, data.Content, time.Now().UTC())
-	if affected, _ := res.RowsAffected(); affected == 0 || err != nil {
+	if err != nil {
 		return errors.Join(constants.ErrCreateData, err)
 	}
+	// The insert ignores conflicts, so no affected row means the name is taken.
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return errors.Join(constants.ErrCreateData, constants.ErrDataExists)
+	}
+	return nil
+}
+
+// CreateBatchData creates several new data entries in one transaction, rolling back all of them on a conflict.
+func (d *DB) CreateBatchData(ctx context.Context, data []models.Data) error {
+	tx, err := d.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { d.commitTx(tx, err) }()
+	now := time.Now().UTC()
+	for _, v := range data {
+		var res sql.Result
+		res, err = tx.ExecContext(ctx, createData, v.ID, v.Name, v.Type, v.Content, now)
+		if err != nil {
+			return errors.Join(constants.ErrCreateData, err)
+		}
+		if affected, _ := res.RowsAffected(); affected == 0 {
+			err = fmt.Errorf("%w: %s", constants.ErrDataExists, v.Name)
+			return errors.Join(constants.ErrCreateData, err)
+		}
+	}
 	return nil
 }
 
@@ -41,6 +68,23 @@ func (d *DB) GetDataContentByName(ctx context.Context, name string) ([]byte, str
 	return content, contentType, nil
 }
 
+// UpdateDataByName replaces the content of a data entry by name and bumps its update time.
+func (d *DB) UpdateDataByName(ctx context.Context, name string, content []byte) error {
+	tx, err := d.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { d.commitTx(tx, err) }()
+	res, err := tx.ExecContext(ctx, updateDataByName, content, time.Now().UTC(), name)
+	if err != nil {
+		return errors.Join(constants.ErrUpdateData, err)
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return errors.Join(constants.ErrUpdateData, constants.ErrGetData)
+	}
+	return nil
+}
+
 // DeleteDataByName deletes a data entry by name for a specific user.
 func (d *DB) DeleteDataByName(ctx context.Context, name string) error {
 	tx, err := d.conn.BeginTx(ctx, nil)
@@ -77,6 +121,28 @@ func (d *DB) GetAllDataInfo(ctx context.Context) ([]models.DataInfo, error) {
 	return list, nil
 }
 
+// GetAllData retrieves all data entries that are not deleted.
+func (d *DB) GetAllData(ctx context.Context) ([]models.Data, error) {
+	var list []models.Data
+	rows, err := d.conn.QueryContext(ctx, getAllData)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var data models.Data
+		var name, dataType sql.NullString
+		err = rows.Scan(&data.ID, &name, &dataType, &data.Content, &data.UpdatedAt, &data.Deleted)
+		if err != nil {
+			return nil, err
+		}
+		data.Name = name.String
+		data.Type = dataType.String
+		list = append(list, data)
+	}
+	return list, rows.Err()
+}
+
 // GetNewData retrieves id and last updated timestamp for all entries that were never synced.
 func (d *DB) GetNewData(ctx context.Context) ([]models.Data, error) {
 	var newData []models.Data
@@ -48,9 +48,57 @@ func NewDB(databasePath, username string) (*DB, error) {
 	if err != nil {
 		log.Fatalf("Error creating table: %v", err)
 	}
+	if err = migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
 	return &DB{conn: db}, nil
 }
 
+// migrations are the schema changes applied to existing databases, the index plus one being the schema version.
+var migrations = []string{
+	// 1: drop the check of the item type, the client only stores the types it knows.
+	rebuildTableData,
+}
+
+// migrate applies the migrations newer than the schema version of the database.
+// Every migration runs in its own transaction together with the version update.
+func migrate(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow(getSchemaVersion).Scan(&version); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	for ; version < len(migrations); version++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err = tx.Exec(migrations[version]); err == nil {
+			_, err = tx.Exec(fmt.Sprintf(setSchemaVersion, version+1))
+		}
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to migrate database to version %d: %w", version+1, err)
+		}
+		if err = tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveDB deletes the SQLite database file of the user together with its journal and write-ahead log files.
+func RemoveDB(databasePath, username string) error {
+	filename := filepath.Join(databasePath, username+".db")
+	for _, suffix := range []string{"", "-journal", "-wal", "-shm"} {
+		err := os.Remove(filename + suffix)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
 // Close closes the database connection.
 func (d *DB) Close() error {
 	return d.conn.Close()
@@ -1,16 +1,34 @@
 package sqlite
 
 const (
-	// createTables is a query to create the data table.
-	createTableData = `CREATE TABLE IF NOT EXISTS data (
+	// dataColumns is the column definition of the current data table.
+	dataColumns = `(
     id TEXT NOT NULL PRIMARY KEY,
 	name TEXT UNIQUE,
-	type TEXT CHECK (type IN ('Card', 'Cred', 'Binary', 'Text')),
+	type TEXT,
 	content BLOB,
 	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 	first_synced BOOLEAN NOT NULL DEFAULT 0,
 	deleted BOOLEAN NOT NULL DEFAULT 0
-	);`
+	)`
+
+	// createTables is a query to create the data table.
+	createTableData = `CREATE TABLE IF NOT EXISTS data ` + dataColumns + `;`
+
+	// rebuildTableData is a query to recreate the data table with the current column definition,
+	// as SQLite cannot alter constraints in place.
+	rebuildTableData = `
+	CREATE TABLE data_new ` + dataColumns + `;
+	INSERT INTO data_new (id, name, type, content, updated_at, first_synced, deleted)
+	SELECT id, name, type, content, updated_at, first_synced, deleted FROM data;
+	DROP TABLE data;
+	ALTER TABLE data_new RENAME TO data;`
+
+	// getSchemaVersion is a query to get the version of the database schema.
+	getSchemaVersion = `PRAGMA user_version;`
+
+	// setSchemaVersion is a query to set the version of the database schema.
+	setSchemaVersion = `PRAGMA user_version = %d;`
 
 	// createData is a query to insert a new data record.
 	createData = `INSERT OR IGNORE INTO data 
@@ -40,6 +58,18 @@ const (
 	FROM data
 	WHERE deleted = 0;`
 
+	// getAllData is a query to get all data records that are not deleted.
+	getAllData = `
+	SELECT id, name, type, content, updated_at, deleted
+	FROM data
+	WHERE deleted = 0;`
+
+	// updateDataByName is a query to replace the content of a data record by its name.
+	updateDataByName = `
+	UPDATE data
+	SET content = ?, updated_at = ?
+	WHERE name = ? AND deleted = 0`
+
 	// deleteDataByName is a quy to delete a data record by its name and user ID.
 	deleteDataByName = `
 	UPDATE data
@@ -15,12 +15,22 @@ type Storage interface {
 	// CreateData creates a new data entry in the storage for a user.
 	CreateData(ctx context.Context, data *models.Data) error
 
+	// CreateBatchData creates several new data entries in one transaction.
+	// No entry is created if the name of any entry is taken.
+	CreateBatchData(ctx context.Context, data []models.Data) error
+
 	// GetDataContentByName retrieves the content and type of data entry by name.
 	GetDataContentByName(ctx context.Context, name string) ([]byte, string, error)
 
 	// GetAllDataInfo retrieves the list of all data entries' information.
 	GetAllDataInfo(ctx context.Context) ([]models.DataInfo, error)
 
+	// GetAllData retrieves all data entries that are not deleted.
+	GetAllData(ctx context.Context) ([]models.Data, error)
+
+	// UpdateDataByName replaces the content of a data entry by name, keeping its ID so the change syncs as an update.
+	UpdateDataByName(ctx context.Context, name string, content []byte) error
+
 	// DeleteDataByName deletes a data entry by name.
 	DeleteDataByName(ctx context.Context, name string) error
 
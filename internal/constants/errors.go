@@ -42,4 +42,40 @@ var (
 
 	// ErrExpiredToken is returned when the token is expired.
 	ErrExpiredToken = errors.New("token is expired")
+
+	// ErrTooManyAttempts is returned when a rate limiter key has no tokens left or is locked out.
+	ErrTooManyAttempts = errors.New("too many attempts")
+
+	// ErrDeviceNotFound is returned when a device does not exist.
+	ErrDeviceNotFound = errors.New("device not found")
+
+	// ErrDeviceRevoked is returned when the client certificate of a device was revoked or replaced.
+	ErrDeviceRevoked = errors.New("device revoked")
+
+	// ErrDeviceCertRequired is returned when a route requires a device client certificate and none was presented.
+	ErrDeviceCertRequired = errors.New("device certificate required")
+
+	// ErrInvalidCSR is returned when a certificate signing request can not be parsed or verified.
+	ErrInvalidCSR = errors.New("invalid certificate signing request")
+
+	// ErrDataExists is returned when a data entry with the same name is already stored.
+	ErrDataExists = errors.New("data with this name already exists")
+
+	// ErrFieldNotFound is returned when a data item has no field with the requested name.
+	ErrFieldNotFound = errors.New("field not found")
+
+	// ErrNotLoggedIn is returned by client commands requiring a logged in user or an unlocked agent.
+	ErrNotLoggedIn = errors.New("not logged in")
+
+	// ErrNoConnection is returned by client commands requiring a server connection when there is none.
+	ErrNoConnection = errors.New("no server connection")
+
+	// ErrAuditFindings is returned by the audit command when asked to fail on findings and the vault has issues.
+	ErrAuditFindings = errors.New("audit found issues")
+
+	// ErrAgentNotRunning is returned when no agent listens on the socket.
+	ErrAgentNotRunning = errors.New("agent is not running")
+
+	// ErrAgentLocked is returned by the agent for data requests while no user is unlocked.
+	ErrAgentLocked = errors.New("agent is locked")
 )
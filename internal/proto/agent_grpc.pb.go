@@ -0,0 +1,467 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.19.6
+// source: agent.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// AgentClient is the client API for Agent service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+//go:generate mockery --name=AgentClient -r --case underscore --with-expecter --structname AgentClient --filename agent_client.go
+type AgentClient interface {
+	Unlock(ctx context.Context, in *UnlockRequest, opts ...grpc.CallOption) (*UnlockResponse, error)
+	Lock(ctx context.Context, in *LockRequest, opts ...grpc.CallOption) (*LockResponse, error)
+	Status(ctx context.Context, in *AgentStatusRequest, opts ...grpc.CallOption) (*AgentStatusResponse, error)
+	CreateData(ctx context.Context, in *AgentCreateDataRequest, opts ...grpc.CallOption) (*AgentCreateDataResponse, error)
+	CreateBatchData(ctx context.Context, in *AgentCreateBatchDataRequest, opts ...grpc.CallOption) (*AgentCreateBatchDataResponse, error)
+	GetData(ctx context.Context, in *AgentGetDataRequest, opts ...grpc.CallOption) (*AgentGetDataResponse, error)
+	ListData(ctx context.Context, in *AgentListDataRequest, opts ...grpc.CallOption) (*AgentListDataResponse, error)
+	ExportData(ctx context.Context, in *AgentExportDataRequest, opts ...grpc.CallOption) (*AgentExportDataResponse, error)
+	UpdateData(ctx context.Context, in *AgentUpdateDataRequest, opts ...grpc.CallOption) (*AgentUpdateDataResponse, error)
+	DeleteData(ctx context.Context, in *AgentDeleteDataRequest, opts ...grpc.CallOption) (*AgentDeleteDataResponse, error)
+	SyncData(ctx context.Context, in *AgentSyncDataRequest, opts ...grpc.CallOption) (*AgentSyncDataResponse, error)
+}
+
+type agentClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAgentClient(cc grpc.ClientConnInterface) AgentClient {
+	return &agentClient{cc}
+}
+
+func (c *agentClient) Unlock(ctx context.Context, in *UnlockRequest, opts ...grpc.CallOption) (*UnlockResponse, error) {
+	out := new(UnlockResponse)
+	err := c.cc.Invoke(ctx, "/proto.Agent/Unlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) Lock(ctx context.Context, in *LockRequest, opts ...grpc.CallOption) (*LockResponse, error) {
+	out := new(LockResponse)
+	err := c.cc.Invoke(ctx, "/proto.Agent/Lock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) Status(ctx context.Context, in *AgentStatusRequest, opts ...grpc.CallOption) (*AgentStatusResponse, error) {
+	out := new(AgentStatusResponse)
+	err := c.cc.Invoke(ctx, "/proto.Agent/Status", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) CreateData(ctx context.Context, in *AgentCreateDataRequest, opts ...grpc.CallOption) (*AgentCreateDataResponse, error) {
+	out := new(AgentCreateDataResponse)
+	err := c.cc.Invoke(ctx, "/proto.Agent/CreateData", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) CreateBatchData(ctx context.Context, in *AgentCreateBatchDataRequest, opts ...grpc.CallOption) (*AgentCreateBatchDataResponse, error) {
+	out := new(AgentCreateBatchDataResponse)
+	err := c.cc.Invoke(ctx, "/proto.Agent/CreateBatchData", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) GetData(ctx context.Context, in *AgentGetDataRequest, opts ...grpc.CallOption) (*AgentGetDataResponse, error) {
+	out := new(AgentGetDataResponse)
+	err := c.cc.Invoke(ctx, "/proto.Agent/GetData", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) ListData(ctx context.Context, in *AgentListDataRequest, opts ...grpc.CallOption) (*AgentListDataResponse, error) {
+	out := new(AgentListDataResponse)
+	err := c.cc.Invoke(ctx, "/proto.Agent/ListData", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) ExportData(ctx context.Context, in *AgentExportDataRequest, opts ...grpc.CallOption) (*AgentExportDataResponse, error) {
+	out := new(AgentExportDataResponse)
+	err := c.cc.Invoke(ctx, "/proto.Agent/ExportData", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) UpdateData(ctx context.Context, in *AgentUpdateDataRequest, opts ...grpc.CallOption) (*AgentUpdateDataResponse, error) {
+	out := new(AgentUpdateDataResponse)
+	err := c.cc.Invoke(ctx, "/proto.Agent/UpdateData", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) DeleteData(ctx context.Context, in *AgentDeleteDataRequest, opts ...grpc.CallOption) (*AgentDeleteDataResponse, error) {
+	out := new(AgentDeleteDataResponse)
+	err := c.cc.Invoke(ctx, "/proto.Agent/DeleteData", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) SyncData(ctx context.Context, in *AgentSyncDataRequest, opts ...grpc.CallOption) (*AgentSyncDataResponse, error) {
+	out := new(AgentSyncDataResponse)
+	err := c.cc.Invoke(ctx, "/proto.Agent/SyncData", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AgentServer is the server API for Agent service.
+// All implementations must embed UnimplementedAgentServer
+// for forward compatibility
+type AgentServer interface {
+	Unlock(context.Context, *UnlockRequest) (*UnlockResponse, error)
+	Lock(context.Context, *LockRequest) (*LockResponse, error)
+	Status(context.Context, *AgentStatusRequest) (*AgentStatusResponse, error)
+	CreateData(context.Context, *AgentCreateDataRequest) (*AgentCreateDataResponse, error)
+	CreateBatchData(context.Context, *AgentCreateBatchDataRequest) (*AgentCreateBatchDataResponse, error)
+	GetData(context.Context, *AgentGetDataRequest) (*AgentGetDataResponse, error)
+	ListData(context.Context, *AgentListDataRequest) (*AgentListDataResponse, error)
+	ExportData(context.Context, *AgentExportDataRequest) (*AgentExportDataResponse, error)
+	UpdateData(context.Context, *AgentUpdateDataRequest) (*AgentUpdateDataResponse, error)
+	DeleteData(context.Context, *AgentDeleteDataRequest) (*AgentDeleteDataResponse, error)
+	SyncData(context.Context, *AgentSyncDataRequest) (*AgentSyncDataResponse, error)
+	mustEmbedUnimplementedAgentServer()
+}
+
+// UnimplementedAgentServer must be embedded to have forward compatible implementations.
+type UnimplementedAgentServer struct {
+}
+
+func (UnimplementedAgentServer) Unlock(context.Context, *UnlockRequest) (*UnlockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unlock not implemented")
+}
+func (UnimplementedAgentServer) Lock(context.Context, *LockRequest) (*LockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Lock not implemented")
+}
+func (UnimplementedAgentServer) Status(context.Context, *AgentStatusRequest) (*AgentStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Status not implemented")
+}
+func (UnimplementedAgentServer) CreateData(context.Context, *AgentCreateDataRequest) (*AgentCreateDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateData not implemented")
+}
+func (UnimplementedAgentServer) CreateBatchData(context.Context, *AgentCreateBatchDataRequest) (*AgentCreateBatchDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateBatchData not implemented")
+}
+func (UnimplementedAgentServer) GetData(context.Context, *AgentGetDataRequest) (*AgentGetDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetData not implemented")
+}
+func (UnimplementedAgentServer) ListData(context.Context, *AgentListDataRequest) (*AgentListDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListData not implemented")
+}
+func (UnimplementedAgentServer) ExportData(context.Context, *AgentExportDataRequest) (*AgentExportDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportData not implemented")
+}
+func (UnimplementedAgentServer) UpdateData(context.Context, *AgentUpdateDataRequest) (*AgentUpdateDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateData not implemented")
+}
+func (UnimplementedAgentServer) DeleteData(context.Context, *AgentDeleteDataRequest) (*AgentDeleteDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteData not implemented")
+}
+func (UnimplementedAgentServer) SyncData(context.Context, *AgentSyncDataRequest) (*AgentSyncDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SyncData not implemented")
+}
+func (UnimplementedAgentServer) mustEmbedUnimplementedAgentServer() {}
+
+// UnsafeAgentServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AgentServer will
+// result in compilation errors.
+type UnsafeAgentServer interface {
+	mustEmbedUnimplementedAgentServer()
+}
+
+func RegisterAgentServer(s grpc.ServiceRegistrar, srv AgentServer) {
+	s.RegisterService(&Agent_ServiceDesc, srv)
+}
+
+func _Agent_Unlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).Unlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Agent/Unlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).Unlock(ctx, req.(*UnlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_Lock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).Lock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Agent/Lock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).Lock(ctx, req.(*LockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_Status_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AgentStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).Status(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Agent/Status",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).Status(ctx, req.(*AgentStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_CreateData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AgentCreateDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).CreateData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Agent/CreateData",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).CreateData(ctx, req.(*AgentCreateDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_CreateBatchData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AgentCreateBatchDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).CreateBatchData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Agent/CreateBatchData",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).CreateBatchData(ctx, req.(*AgentCreateBatchDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_GetData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AgentGetDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).GetData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Agent/GetData",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).GetData(ctx, req.(*AgentGetDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_ListData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AgentListDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).ListData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Agent/ListData",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).ListData(ctx, req.(*AgentListDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_ExportData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AgentExportDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).ExportData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Agent/ExportData",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).ExportData(ctx, req.(*AgentExportDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_UpdateData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AgentUpdateDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).UpdateData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Agent/UpdateData",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).UpdateData(ctx, req.(*AgentUpdateDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_DeleteData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AgentDeleteDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).DeleteData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Agent/DeleteData",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).DeleteData(ctx, req.(*AgentDeleteDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_SyncData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AgentSyncDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).SyncData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Agent/SyncData",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).SyncData(ctx, req.(*AgentSyncDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Agent_ServiceDesc is the grpc.ServiceDesc for Agent service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Agent_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.Agent",
+	HandlerType: (*AgentServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Unlock",
+			Handler:    _Agent_Unlock_Handler,
+		},
+		{
+			MethodName: "Lock",
+			Handler:    _Agent_Lock_Handler,
+		},
+		{
+			MethodName: "Status",
+			Handler:    _Agent_Status_Handler,
+		},
+		{
+			MethodName: "CreateData",
+			Handler:    _Agent_CreateData_Handler,
+		},
+		{
+			MethodName: "CreateBatchData",
+			Handler:    _Agent_CreateBatchData_Handler,
+		},
+		{
+			MethodName: "GetData",
+			Handler:    _Agent_GetData_Handler,
+		},
+		{
+			MethodName: "ListData",
+			Handler:    _Agent_ListData_Handler,
+		},
+		{
+			MethodName: "ExportData",
+			Handler:    _Agent_ExportData_Handler,
+		},
+		{
+			MethodName: "UpdateData",
+			Handler:    _Agent_UpdateData_Handler,
+		},
+		{
+			MethodName: "DeleteData",
+			Handler:    _Agent_DeleteData_Handler,
+		},
+		{
+			MethodName: "SyncData",
+			Handler:    _Agent_SyncData_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "agent.proto",
+}
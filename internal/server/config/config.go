@@ -16,14 +16,28 @@ type Config struct {
 	JWTRefreshLifeTimeHours int    `envconfig:"JWT_REFRESH_LIFETIME_HOURS" default:"48"`
 	CertFile                string `envconfig:"TLS_CERT_FILE" default:"cert.pem" json:"cert_file"`
 	KeyFile                 string `envconfig:"TLS_KEY_FILE" default:"key.pem" json:"key_file"`
+	CertHosts               string `envconfig:"TLS_CERT_HOSTS" default:"localhost,127.0.0.1,::1"`
+	CertKeyType             string `envconfig:"TLS_CERT_KEY_TYPE" default:"ecdsa"`
+	CertValidityDays        int    `envconfig:"TLS_CERT_VALIDITY_DAYS" default:"365"`
+	CAFile                  string `envconfig:"TLS_CA_FILE" default:"ca.pem" json:"ca_file"`
+	CAKeyFile               string `envconfig:"TLS_CA_KEY_FILE" default:"ca_key.pem" json:"ca_key_file"`
+	RequireClientCert       bool   `envconfig:"REQUIRE_CLIENT_CERT" default:"false"`
+	DeviceCertLifetimeDays  int    `envconfig:"DEVICE_CERT_LIFETIME_DAYS" default:"365"`
+	LimiterStore            string `envconfig:"LIMITER_STORE" default:"memory"`
+	LimiterIPPerMinute      int    `envconfig:"LIMITER_IP_PER_MINUTE" default:"30"`
+	LimiterIPBurst          int    `envconfig:"LIMITER_IP_BURST" default:"10"`
+	LimiterIPMaxFailures    int    `envconfig:"LIMITER_IP_MAX_FAILURES" default:"20"`
+	LimiterUserPerMinute    int    `envconfig:"LIMITER_USER_PER_MINUTE" default:"10"`
+	LimiterUserBurst        int    `envconfig:"LIMITER_USER_BURST" default:"5"`
+	LimiterUserMaxFailures  int    `envconfig:"LIMITER_USER_MAX_FAILURES" default:"5"`
+	LimiterLockoutSeconds   int    `envconfig:"LIMITER_LOCKOUT_SECONDS" default:"30"`
+	LimiterMaxLockoutMins   int    `envconfig:"LIMITER_MAX_LOCKOUT_MINUTES" default:"60"`
 }
 
 // NewConfig creates a new Config instance and returns a pointer to it.
 // It reads configuration values from environment variables and command-line flags.
 func NewConfig() *Config {
-	var cfg Config
-	decimal.MarshalJSONWithoutQuotes = true
-	envconfig.MustProcess("", &cfg)
+	cfg := *NewEnvConfig()
 	flag.StringVar(&cfg.ServiceAddress, "a", cfg.ServiceAddress, "grpcServer address")
 	flag.StringVar(&cfg.PostgresURI, "d", cfg.PostgresURI, "db address")
 	flag.StringVar(&cfg.JWTAuthKey, "j", cfg.JWTAuthKey, "token token key")
@@ -31,6 +45,19 @@ func NewConfig() *Config {
 	flag.IntVar(&cfg.JWTRefreshLifeTimeHours, "r", cfg.JWTRefreshLifeTimeHours, "token refresh token lifetime in hours")
 	flag.StringVar(&cfg.CertFile, "c", cfg.CertFile, "tls cert file path")
 	flag.StringVar(&cfg.KeyFile, "k", cfg.KeyFile, "tls key file path")
+	flag.StringVar(&cfg.CAFile, "ca", cfg.CAFile, "device CA cert file path")
+	flag.StringVar(&cfg.CAKeyFile, "ca-key", cfg.CAKeyFile, "device CA key file path")
+	flag.BoolVar(&cfg.RequireClientCert, "require-client-cert", cfg.RequireClientCert, "require an enrolled device cert on authenticated routes")
+	flag.StringVar(&cfg.LimiterStore, "limiter-store", cfg.LimiterStore, "login limiter state store: memory or postgres")
 	flag.Parse()
 	return &cfg
 }
+
+// NewEnvConfig creates a new Config instance from environment variables only,
+// for subcommands parsing their own command-line flags.
+func NewEnvConfig() *Config {
+	var cfg Config
+	decimal.MarshalJSONWithoutQuotes = true
+	envconfig.MustProcess("", &cfg)
+	return &cfg
+}
@@ -1,10 +1,15 @@
 package di
 
 import (
+	"github.com/Mldlr/storety/internal/server/config"
+	pkgTls "github.com/Mldlr/storety/internal/server/pkg/tls"
 	"github.com/Mldlr/storety/internal/server/pkg/token"
 	"github.com/Mldlr/storety/internal/server/service/data"
+	"github.com/Mldlr/storety/internal/server/service/device"
+	"github.com/Mldlr/storety/internal/server/service/limiter"
 	"github.com/Mldlr/storety/internal/server/service/user"
 	"github.com/samber/do"
+	"go.uber.org/zap"
 )
 
 // configureServices configures the services for the Storety server.
@@ -30,4 +35,28 @@ func configureServices(i *do.Injector) {
 			return userService, nil
 		},
 	)
+	limiterService := limiter.NewService(i)
+	do.Provide(
+		i,
+		func(i *do.Injector) (limiter.Service, error) {
+			return limiterService, nil
+		},
+	)
+	ca, err := pkgTls.LoadOrCreateCA(do.MustInvoke[*config.Config](i))
+	if err != nil {
+		do.MustInvoke[*zap.Logger](i).Fatal("Error loading device CA", zap.Error(err))
+	}
+	do.Provide(
+		i,
+		func(i *do.Injector) (*pkgTls.CA, error) {
+			return ca, nil
+		},
+	)
+	deviceService := device.NewService(i)
+	do.Provide(
+		i,
+		func(i *do.Injector) (device.Service, error) {
+			return deviceService, nil
+		},
+	)
 }
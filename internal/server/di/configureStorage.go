@@ -5,6 +5,7 @@ import (
 	"github.com/Mldlr/storety/internal/server/config"
 	"github.com/Mldlr/storety/internal/server/migration"
 	"github.com/Mldlr/storety/internal/server/storage"
+	"github.com/Mldlr/storety/internal/server/storage/memory"
 	"github.com/Mldlr/storety/internal/server/storage/postgres"
 	"github.com/samber/do"
 	"go.uber.org/zap"
@@ -36,6 +37,16 @@ func configureStorage(i *do.Injector) {
 				return d, nil
 			},
 		)
+
+		do.Provide(
+			i,
+			func(i *do.Injector) (storage.LimiterStorage, error) {
+				if cfg.LimiterStore == "postgres" {
+					return d, nil
+				}
+				return memory.NewLimiterStorage(), nil
+			},
+		)
 		return
 	}
 
@@ -2,6 +2,7 @@
 package grpcServer
 
 import (
+	"context"
 	"crypto/tls"
 	pb "github.com/Mldlr/storety/internal/proto"
 	"github.com/Mldlr/storety/internal/server/config"
@@ -12,16 +13,23 @@ import (
 	"github.com/samber/do"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"net"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 )
 
+// certWatchInterval is how often the certificate files are checked for changes.
+const certWatchInterval = 30 * time.Second
+
 // GRPCServer is the gRPC server for the Storety service.
 type GRPCServer struct {
-	srv *grpc.Server
-	cfg *config.Config
-	log *zap.Logger
+	srv      *grpc.Server
+	cfg      *config.Config
+	reloader *pkgTls.CertReloader
+	log      *zap.Logger
 }
 
 // NewGRPCServer creates a new GRPCServer with the provided dependency injector.
@@ -30,12 +38,15 @@ func NewGRPCServer(i *do.Injector) *GRPCServer {
 	cfg := do.MustInvoke[*config.Config](i)
 	log := do.MustInvoke[*zap.Logger](i)
 	authInterceptor := interceptors.NewAuthInterceptor(i)
+	rateLimitInterceptor := interceptors.NewRateLimitInterceptor(i)
+	deviceInterceptor := interceptors.NewDeviceInterceptor(i)
+	ca := do.MustInvoke[*pkgTls.CA](i)
 	h := handler.NewStoretyHandler(i)
 
 	certFiles := []string{cfg.CertFile, cfg.KeyFile}
 	for _, file := range certFiles {
 		if _, err := os.Stat(file); err != nil {
-			err = pkgTls.GenerateCert(cfg)
+			err = pkgTls.GenerateCert(pkgTls.NewCertOptions(cfg), ca)
 			if err != nil {
 				log.Fatal("failed to generate certificate", zap.Error(err))
 			}
@@ -43,29 +54,59 @@ func NewGRPCServer(i *do.Injector) *GRPCServer {
 		}
 	}
 
-	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(grpc_zap.UnaryServerInterceptor(log), authInterceptor.UnaryInterceptor))
+	reloader, err := pkgTls.NewCertReloader(cfg.CertFile, cfg.KeyFile, log)
+	if err != nil {
+		log.Fatal("failed to load certificate", zap.Error(err))
+	}
+	// Client certs are verified when given, routes requiring an enrolled device are enforced by the device interceptor,
+	// so that new devices can still log in and enroll.
+	creds := credentials.NewTLS(&tls.Config{
+		GetCertificate: reloader.GetCertificate,
+		ClientAuth:     tls.VerifyClientCertIfGiven,
+		ClientCAs:      ca.Pool(),
+	})
+
+	srv := grpc.NewServer(grpc.Creds(creds), grpc.ChainUnaryInterceptor(
+		grpc_zap.UnaryServerInterceptor(log),
+		authInterceptor.UnaryInterceptor,
+		rateLimitInterceptor.UnaryInterceptor,
+		deviceInterceptor.UnaryInterceptor,
+	))
 	pb.RegisterDataServer(srv, h)
 	pb.RegisterUserServer(srv, h)
+	pb.RegisterDeviceServer(srv, h)
 	return &GRPCServer{
-		srv: srv,
-		cfg: cfg,
-		log: log,
+		srv:      srv,
+		cfg:      cfg,
+		reloader: reloader,
+		log:      log,
 	}
 }
 
 // Run starts the gRPC server and listens for incoming connections.
-// It also handles graceful shutdown on receiving termination signals.
+// It reloads the certificate when its files change or on SIGHUP,
+// and handles graceful shutdown on receiving termination signals.
 func (s *GRPCServer) Run() {
-	cert, err := tls.LoadX509KeyPair(s.cfg.CertFile, s.cfg.KeyFile)
-	if err != nil {
-		s.log.Fatal("failed to load certificate", zap.Error(err))
-	}
-
-	listener, err := tls.Listen("tcp", s.cfg.ServiceAddress, &tls.Config{Certificates: []tls.Certificate{cert}})
+	listener, err := net.Listen("tcp", s.cfg.ServiceAddress)
 	if err != nil {
 		s.log.Fatal("failed to listen", zap.String("address", s.cfg.ServiceAddress))
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.reloader.Watch(ctx, certWatchInterval)
+
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	go func() {
+		for range sighup {
+			s.log.Info("reloading certificate on SIGHUP")
+			if err := s.reloader.Reload(); err != nil {
+				s.log.Error("failed to reload certificate", zap.Error(err))
+			}
+		}
+	}()
+
 	sigint := make(chan os.Signal, 1)
 	signal.Notify(sigint, syscall.SIGTERM, syscall.SIGINT, syscall.SIGQUIT)
 	go func() {
@@ -75,6 +116,7 @@ func (s *GRPCServer) Run() {
 	}()
 	<-sigint
 	s.log.Info("shutting down")
+	signal.Stop(sighup)
 	s.srv.GracefulStop()
 	listener.Close()
 }
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Mldlr/storety/internal/constants"
+	pb "github.com/Mldlr/storety/internal/proto"
+	"github.com/Mldlr/storety/internal/server/models"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Enroll issues a client certificate for a new device of the user.
+func (s *StoretyHandler) Enroll(ctx context.Context, request *pb.EnrollDeviceRequest) (*pb.EnrollDeviceResponse, error) {
+	session := ctx.Value(models.SessionKey{}).(*models.Session)
+	if request.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "empty device name")
+	}
+	device, cert, err := s.deviceService.Enroll(ctx, session.UserID, request.Name, request.Csr)
+	if err != nil {
+		if errors.Is(err, constants.ErrInvalidCSR) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.EnrollDeviceResponse{Id: device.ID.String(), Certificate: cert}, nil
+}
+
+// ListDevices lists the devices enrolled by the user.
+func (s *StoretyHandler) ListDevices(ctx context.Context, request *pb.ListDevicesRequest) (*pb.ListDevicesResponse, error) {
+	session := ctx.Value(models.SessionKey{}).(*models.Session)
+	devices, err := s.deviceService.GetDevices(ctx, session.UserID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	var currentID uuid.UUID
+	if current, ok := ctx.Value(models.DeviceKey{}).(*models.Device); ok {
+		currentID = current.ID
+	}
+	response := &pb.ListDevicesResponse{Devices: make([]*pb.DeviceInfo, len(devices))}
+	for i, d := range devices {
+		response.Devices[i] = &pb.DeviceInfo{
+			Id:        d.ID.String(),
+			Name:      d.Name,
+			CreatedAt: timestamppb.New(d.CreatedAt),
+			ExpiresAt: timestamppb.New(d.ExpiresAt),
+			Revoked:   d.Revoked,
+			Current:   d.ID == currentID,
+		}
+	}
+	return response, nil
+}
+
+// RevokeDevice revokes the client certificate of a device of the user.
+func (s *StoretyHandler) RevokeDevice(ctx context.Context, request *pb.RevokeDeviceRequest) (*pb.RevokeDeviceResponse, error) {
+	session := ctx.Value(models.SessionKey{}).(*models.Session)
+	id, err := uuid.Parse(request.Id)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid device id: %v", err))
+	}
+	err = s.deviceService.RevokeDevice(ctx, session.UserID, id)
+	if err != nil {
+		if errors.Is(err, constants.ErrDeviceNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.RevokeDeviceResponse{}, nil
+}
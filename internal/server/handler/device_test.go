@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"github.com/Mldlr/storety/internal/constants"
+	pb "github.com/Mldlr/storety/internal/proto"
+	"github.com/Mldlr/storety/internal/server/mocks"
+	"github.com/Mldlr/storety/internal/server/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"testing"
+	"time"
+)
+
+func TestEnroll(t *testing.T) {
+	userID := uuid.New()
+	deviceID := uuid.New()
+	tests := []struct {
+		name    string
+		setup   func(ds *mocks.DeviceService)
+		req     *pb.EnrollDeviceRequest
+		want    *pb.EnrollDeviceResponse
+		errCode codes.Code
+	}{
+		{
+			name: "Enroll device successfully",
+			setup: func(ds *mocks.DeviceService) {
+				ds.EXPECT().Enroll(mock.Anything, userID, "laptop", []byte("csr")).
+					Return(&models.Device{ID: deviceID}, []byte("cert"), nil)
+			},
+			req:     &pb.EnrollDeviceRequest{Name: "laptop", Csr: []byte("csr")},
+			want:    &pb.EnrollDeviceResponse{Id: deviceID.String(), Certificate: []byte("cert")},
+			errCode: codes.OK,
+		},
+		{
+			name: "Fail to enroll device with invalid CSR",
+			setup: func(ds *mocks.DeviceService) {
+				ds.EXPECT().Enroll(mock.Anything, userID, "laptop", []byte("csr")).
+					Return(nil, nil, constants.ErrInvalidCSR)
+			},
+			req:     &pb.EnrollDeviceRequest{Name: "laptop", Csr: []byte("csr")},
+			errCode: codes.InvalidArgument,
+		},
+		{
+			name:    "Fail to enroll device without name",
+			req:     &pb.EnrollDeviceRequest{Csr: []byte("csr")},
+			errCode: codes.InvalidArgument,
+		},
+		{
+			name: "Fail to store device",
+			setup: func(ds *mocks.DeviceService) {
+				ds.EXPECT().Enroll(mock.Anything, userID, "laptop", []byte("csr")).
+					Return(nil, nil, errors.New("db error"))
+			},
+			req:     &pb.EnrollDeviceRequest{Name: "laptop", Csr: []byte("csr")},
+			errCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDeviceSrv := new(mocks.DeviceService)
+			if tt.setup != nil {
+				tt.setup(mockDeviceSrv)
+			}
+			ctx := context.WithValue(context.Background(), models.SessionKey{}, &models.Session{UserID: userID})
+			mockHandler := StoretyHandler{deviceService: mockDeviceSrv}
+			resp, err := mockHandler.Enroll(ctx, tt.req)
+			require.EqualValues(t, tt.want, resp)
+			require.Equal(t, tt.errCode.String(), status.Code(err).String())
+			mockDeviceSrv.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListDevices(t *testing.T) {
+	userID := uuid.New()
+	now := time.Now()
+	current := models.Device{ID: uuid.New(), UserID: userID, Name: "laptop", CreatedAt: now, ExpiresAt: now}
+	revoked := models.Device{ID: uuid.New(), UserID: userID, Name: "phone", CreatedAt: now, ExpiresAt: now, Revoked: true}
+
+	mockDeviceSrv := new(mocks.DeviceService)
+	mockDeviceSrv.EXPECT().GetDevices(mock.Anything, userID).Return([]models.Device{current, revoked}, nil)
+	ctx := context.WithValue(context.Background(), models.SessionKey{}, &models.Session{UserID: userID})
+	ctx = context.WithValue(ctx, models.DeviceKey{}, &current)
+	mockHandler := StoretyHandler{deviceService: mockDeviceSrv}
+
+	resp, err := mockHandler.ListDevices(ctx, &pb.ListDevicesRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, []*pb.DeviceInfo{
+		{
+			Id:        current.ID.String(),
+			Name:      "laptop",
+			CreatedAt: timestamppb.New(now),
+			ExpiresAt: timestamppb.New(now),
+			Current:   true,
+		},
+		{
+			Id:        revoked.ID.String(),
+			Name:      "phone",
+			CreatedAt: timestamppb.New(now),
+			ExpiresAt: timestamppb.New(now),
+			Revoked:   true,
+		},
+	}, resp.Devices)
+	mockDeviceSrv.AssertExpectations(t)
+}
+
+func TestRevokeDevice(t *testing.T) {
+	userID := uuid.New()
+	deviceID := uuid.New()
+	tests := []struct {
+		name    string
+		setup   func(ds *mocks.DeviceService)
+		req     *pb.RevokeDeviceRequest
+		want    *pb.RevokeDeviceResponse
+		errCode codes.Code
+	}{
+		{
+			name: "Revoke device successfully",
+			setup: func(ds *mocks.DeviceService) {
+				ds.EXPECT().RevokeDevice(mock.Anything, userID, deviceID).Return(nil)
+			},
+			req:     &pb.RevokeDeviceRequest{Id: deviceID.String()},
+			want:    &pb.RevokeDeviceResponse{},
+			errCode: codes.OK,
+		},
+		{
+			name: "Fail to revoke unknown device",
+			setup: func(ds *mocks.DeviceService) {
+				ds.EXPECT().RevokeDevice(mock.Anything, userID, deviceID).Return(constants.ErrDeviceNotFound)
+			},
+			req:     &pb.RevokeDeviceRequest{Id: deviceID.String()},
+			errCode: codes.NotFound,
+		},
+		{
+			name:    "Fail to revoke device with invalid id",
+			req:     &pb.RevokeDeviceRequest{Id: "device"},
+			errCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDeviceSrv := new(mocks.DeviceService)
+			if tt.setup != nil {
+				tt.setup(mockDeviceSrv)
+			}
+			ctx := context.WithValue(context.Background(), models.SessionKey{}, &models.Session{UserID: userID})
+			mockHandler := StoretyHandler{deviceService: mockDeviceSrv}
+			resp, err := mockHandler.RevokeDevice(ctx, tt.req)
+			require.EqualValues(t, tt.want, resp)
+			require.Equal(t, tt.errCode.String(), status.Code(err).String())
+			mockDeviceSrv.AssertExpectations(t)
+		})
+	}
+}
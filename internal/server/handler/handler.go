@@ -5,20 +5,23 @@ import (
 	pb "github.com/Mldlr/storety/internal/proto"
 	"github.com/Mldlr/storety/internal/server/config"
 	"github.com/Mldlr/storety/internal/server/service/data"
+	"github.com/Mldlr/storety/internal/server/service/device"
 	"github.com/Mldlr/storety/internal/server/service/user"
 	"github.com/samber/do"
 	"go.uber.org/zap"
 )
 
 // StoretyHandler is the handler for the Storety gRPC server.
-// It embeds both UnimplementedDataServer and UnimplementedUserServer interfaces.
+// It embeds the UnimplementedDataServer, UnimplementedUserServer and UnimplementedDeviceServer interfaces.
 type StoretyHandler struct {
 	pb.UnimplementedDataServer
 	pb.UnimplementedUserServer
-	userService user.Service
-	dataService data.Service
-	cfg         *config.Config
-	log         *zap.Logger
+	pb.UnimplementedDeviceServer
+	userService   user.Service
+	dataService   data.Service
+	deviceService device.Service
+	cfg           *config.Config
+	log           *zap.Logger
 }
 
 // NewStoretyHandler creates a new StoretyHandler with the provided dependencies.
@@ -26,12 +29,14 @@ type StoretyHandler struct {
 func NewStoretyHandler(i *do.Injector) *StoretyHandler {
 	userService := do.MustInvoke[user.Service](i)
 	dataService := do.MustInvoke[data.Service](i)
+	deviceService := do.MustInvoke[device.Service](i)
 	cfg := do.MustInvoke[*config.Config](i)
 	logger := do.MustInvoke[*zap.Logger](i)
 	return &StoretyHandler{
-		userService: userService,
-		dataService: dataService,
-		cfg:         cfg,
-		log:         logger,
+		userService:   userService,
+		dataService:   dataService,
+		deviceService: deviceService,
+		cfg:           cfg,
+		log:           logger,
 	}
 }
@@ -64,3 +64,19 @@ func (s *StoretyHandler) RefreshUserSession(ctx context.Context, request *pb.Ref
 	}
 	return &pb.RefreshUserSessionResponse{AuthToken: session.AuthToken, RefreshToken: session.RefreshToken}, nil
 }
+
+// DeleteAccount deletes the user's account after re-authenticating the user with the password.
+func (s *StoretyHandler) DeleteAccount(ctx context.Context, request *pb.DeleteAccountRequest) (*pb.DeleteAccountResponse, error) {
+	session := ctx.Value(models.SessionKey{}).(*models.Session)
+	if request.Password == "" {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("%v: %v", constants.ErrInvalidCredentials, validators.ErrEmptyPass))
+	}
+	err := s.userService.DeleteAccount(ctx, session.UserID, request.Password)
+	if err != nil {
+		if errors.Is(err, constants.ErrInvalidCredentials) {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("%v: %v", constants.ErrInvalidCredentials, err))
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.DeleteAccountResponse{}, nil
+}
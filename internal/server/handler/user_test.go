@@ -219,3 +219,57 @@ func TestRefreshUserSession(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteAccount(t *testing.T) {
+	id, err := uuid.NewRandom()
+	assert.NoError(t, err)
+	tests := []struct {
+		name    string
+		setup   func(ctx context.Context, us *mocks.UserService)
+		req     *pb.DeleteAccountRequest
+		want    *pb.DeleteAccountResponse
+		errCode codes.Code
+	}{
+		{
+			name: "Delete account successfully",
+			setup: func(ctx context.Context, us *mocks.UserService) {
+				us.EXPECT().DeleteAccount(mock.Anything, id, "password").Return(nil)
+			},
+			req:     &pb.DeleteAccountRequest{Password: "password"},
+			want:    &pb.DeleteAccountResponse{},
+			errCode: codes.OK,
+		},
+		{
+			name: "Fail to delete account with wrong password",
+			setup: func(ctx context.Context, us *mocks.UserService) {
+				us.EXPECT().DeleteAccount(mock.Anything, id, "wrong").
+					Return(errors.Join(constants.ErrInvalidCredentials, errors.New("mismatch")))
+			},
+			req:     &pb.DeleteAccountRequest{Password: "wrong"},
+			want:    nil,
+			errCode: codes.InvalidArgument,
+		},
+		{
+			name:    "Fail to delete account with empty password",
+			req:     &pb.DeleteAccountRequest{},
+			want:    nil,
+			errCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockUserSrv := new(mocks.UserService)
+			if tt.setup != nil {
+				tt.setup(ctx, mockUserSrv)
+			}
+			incCtx := context.WithValue(ctx, models.SessionKey{}, &models.Session{UserID: id})
+			mockHandler := StoretyHandler{userService: mockUserSrv}
+			resp, err := mockHandler.DeleteAccount(incCtx, tt.req)
+			require.EqualValues(t, tt.want, resp)
+			require.Equal(t, tt.errCode.String(), status.Code(err).String())
+			mockUserSrv.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package interceptors
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/Mldlr/storety/internal/constants"
+	"github.com/Mldlr/storety/internal/server/config"
+	"github.com/Mldlr/storety/internal/server/models"
+	"github.com/Mldlr/storety/internal/server/service/device"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// DeviceServerInterceptor implements a gRPC server interceptor mapping client certificates to enrolled devices.
+type DeviceServerInterceptor struct {
+	deviceService device.Service
+	requireCert   bool
+	enrollRoutes  map[string]struct{}
+}
+
+// NewDeviceInterceptor returns a new device interceptor.
+func NewDeviceInterceptor(i *do.Injector) *DeviceServerInterceptor {
+	deviceService := do.MustInvoke[device.Service](i)
+	cfg := do.MustInvoke[*config.Config](i)
+	return &DeviceServerInterceptor{
+		deviceService: deviceService,
+		requireCert:   cfg.RequireClientCert,
+		enrollRoutes: map[string]struct{}{
+			"/proto.User/CreateUser": struct{}{},
+			"/proto.User/LogInUser":  struct{}{},
+			"/proto.Device/Enroll":   struct{}{},
+		},
+	}
+}
+
+// UnaryInterceptor implements the UnaryInterceptor method of the grpc.UnaryServerInterceptor interface.
+// It rejects client certificates of revoked devices and of devices belonging to another user than the session,
+// and stores the device in the context. Without a client certificate, only the routes needed to enroll a device
+// are allowed when client certificates are required.
+func (d *DeviceServerInterceptor) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	cert := peerCertificate(ctx)
+	if cert == nil {
+		if _, ok := d.enrollRoutes[info.FullMethod]; ok || !d.requireCert {
+			return handler(ctx, req)
+		}
+		return nil, status.Error(codes.Unauthenticated, constants.ErrDeviceCertRequired.Error())
+	}
+	dev, err := d.deviceService.VerifyDevice(ctx, cert)
+	if err != nil {
+		if errors.Is(err, constants.ErrDeviceRevoked) || errors.Is(err, constants.ErrDeviceNotFound) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if session, ok := ctx.Value(models.SessionKey{}).(*models.Session); ok &&
+		session.UserID != uuid.Nil && session.UserID != dev.UserID {
+		return nil, status.Error(codes.PermissionDenied, fmt.Sprintf("%v: device belongs to another user", constants.ErrDeviceNotFound))
+	}
+	ctxNew := context.WithValue(ctx, models.DeviceKey{}, dev)
+	return handler(ctxNew, req)
+}
+
+// peerCertificate returns the verified client certificate of the peer, or nil if none was presented.
+func peerCertificate(ctx context.Context) *x509.Certificate {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return tlsInfo.State.VerifiedChains[0][0]
+}
@@ -0,0 +1,113 @@
+package interceptors
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"github.com/Mldlr/storety/internal/constants"
+	"github.com/Mldlr/storety/internal/server/mocks"
+	"github.com/Mldlr/storety/internal/server/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"math/big"
+	"testing"
+)
+
+func TestDeviceInterceptor_UnaryInterceptor(t *testing.T) {
+	userID := uuid.New()
+	cert := &x509.Certificate{SerialNumber: big.NewInt(1)}
+	device := &models.Device{ID: uuid.New(), UserID: userID}
+	certCtx := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}},
+	})
+	sessionCtx := context.WithValue(certCtx, models.SessionKey{}, &models.Session{UserID: userID})
+	listInfo := &grpc.UnaryServerInfo{FullMethod: "/proto.Data/ListData"}
+	tests := []struct {
+		name        string
+		setup       func(ds *mocks.DeviceService)
+		ctx         context.Context
+		info        *grpc.UnaryServerInfo
+		requireCert bool
+		wantDevice  *models.Device
+		errCode     codes.Code
+	}{
+		{
+			name:    "No certificate when not required",
+			ctx:     context.Background(),
+			info:    listInfo,
+			errCode: codes.OK,
+		},
+		{
+			name:        "No certificate when required",
+			ctx:         context.Background(),
+			info:        listInfo,
+			requireCert: true,
+			errCode:     codes.Unauthenticated,
+		},
+		{
+			name:        "No certificate on enroll route",
+			ctx:         context.Background(),
+			info:        &grpc.UnaryServerInfo{FullMethod: "/proto.Device/Enroll"},
+			requireCert: true,
+			errCode:     codes.OK,
+		},
+		{
+			name: "Enrolled device",
+			setup: func(ds *mocks.DeviceService) {
+				ds.EXPECT().VerifyDevice(mock.Anything, cert).Return(device, nil)
+			},
+			ctx:         sessionCtx,
+			info:        listInfo,
+			requireCert: true,
+			wantDevice:  device,
+			errCode:     codes.OK,
+		},
+		{
+			name: "Revoked device",
+			setup: func(ds *mocks.DeviceService) {
+				ds.EXPECT().VerifyDevice(mock.Anything, cert).Return(nil, constants.ErrDeviceRevoked)
+			},
+			ctx:     sessionCtx,
+			info:    listInfo,
+			errCode: codes.PermissionDenied,
+		},
+		{
+			name: "Device of another user",
+			setup: func(ds *mocks.DeviceService) {
+				ds.EXPECT().VerifyDevice(mock.Anything, cert).Return(&models.Device{ID: device.ID, UserID: uuid.New()}, nil)
+			},
+			ctx:     sessionCtx,
+			info:    listInfo,
+			errCode: codes.PermissionDenied,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deviceMock := new(mocks.DeviceService)
+			if tt.setup != nil {
+				tt.setup(deviceMock)
+			}
+			interceptor := &DeviceServerInterceptor{
+				deviceService: deviceMock,
+				requireCert:   tt.requireCert,
+				enrollRoutes: map[string]struct{}{
+					"/proto.Device/Enroll": struct{}{},
+				},
+			}
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				gotDevice, _ := ctx.Value(models.DeviceKey{}).(*models.Device)
+				require.Equal(t, tt.wantDevice, gotDevice)
+				return nil, nil
+			}
+			_, err := interceptor.UnaryInterceptor(tt.ctx, nil, tt.info, handler)
+			require.Equal(t, tt.errCode, status.Code(err))
+			deviceMock.AssertExpectations(t)
+		})
+	}
+}
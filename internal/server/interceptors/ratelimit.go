@@ -0,0 +1,105 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Mldlr/storety/internal/constants"
+	"github.com/Mldlr/storety/internal/server/models"
+	"github.com/Mldlr/storety/internal/server/service/limiter"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"net"
+	"time"
+)
+
+// RateLimitServerInterceptor implements a gRPC server interceptor throttling authentication attempts.
+type RateLimitServerInterceptor struct {
+	limiter       limiter.Service
+	limitedRoutes map[string]struct{}
+}
+
+// NewRateLimitInterceptor returns a new rate limiting interceptor.
+func NewRateLimitInterceptor(i *do.Injector) *RateLimitServerInterceptor {
+	limiterService := do.MustInvoke[limiter.Service](i)
+	return &RateLimitServerInterceptor{
+		limiter: limiterService,
+		limitedRoutes: map[string]struct{}{
+			"/proto.User/CreateUser":    struct{}{},
+			"/proto.User/LogInUser":     struct{}{},
+			"/proto.User/DeleteAccount": struct{}{},
+		},
+	}
+}
+
+// UnaryInterceptor implements the UnaryInterceptor method of the grpc.UnaryServerInterceptor interface.
+// It rejects attempts on limited routes with codes.ResourceExhausted while the client IP or the user
+// is throttled, and reports the outcome of allowed attempts back to the limiter.
+// It runs after the auth interceptor, so attempts of authenticated routes are limited per account.
+func (r *RateLimitServerInterceptor) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if _, ok := r.limitedRoutes[info.FullMethod]; !ok {
+		return handler(ctx, req)
+	}
+	ip := peerIP(ctx)
+	user := limitedUser(ctx, req)
+	wait, err := r.limiter.Allow(ctx, ip, user)
+	if err != nil {
+		if errors.Is(err, constants.ErrTooManyAttempts) {
+			return nil, retryAfterError(wait)
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	resp, err := handler(ctx, req)
+	switch status.Code(err) {
+	case codes.OK:
+		_ = r.limiter.Reset(ctx, user)
+	case codes.InvalidArgument, codes.AlreadyExists:
+		_ = r.limiter.Fail(ctx, ip, user)
+	}
+	return resp, err
+}
+
+// limitedUser returns the limiter user of the attempt: the username of login requests
+// or the account of the session set by the auth interceptor, or an empty string if there is neither.
+func limitedUser(ctx context.Context, req interface{}) string {
+	if loginReq, ok := req.(interface{ GetLogin() string }); ok {
+		return limiter.Username(loginReq.GetLogin())
+	}
+	if session, ok := ctx.Value(models.SessionKey{}).(*models.Session); ok && session.UserID != uuid.Nil {
+		return limiter.Account(session.UserID)
+	}
+	return ""
+}
+
+// peerIP returns the IP address of the client that sent the request, or an empty string if it is unknown.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// retryAfterError returns a codes.ResourceExhausted status error carrying the retry delay as errdetails.RetryInfo.
+func retryAfterError(wait time.Duration) error {
+	wait = wait.Round(time.Second)
+	if wait < time.Second {
+		wait = time.Second
+	}
+	st := status.New(codes.ResourceExhausted, fmt.Sprintf("%v, retry after %v", constants.ErrTooManyAttempts, wait))
+	detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(wait)})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
@@ -0,0 +1,133 @@
+package interceptors
+
+import (
+	"context"
+	"github.com/Mldlr/storety/internal/constants"
+	pb "github.com/Mldlr/storety/internal/proto"
+	"github.com/Mldlr/storety/internal/server/mocks"
+	"github.com/Mldlr/storety/internal/server/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestRateLimitInterceptor_UnaryInterceptor(t *testing.T) {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 5555},
+	})
+	loginInfo := &grpc.UnaryServerInfo{FullMethod: "/proto.User/LogInUser"}
+	loginReq := &pb.LoginUserRequest{Login: "username", Password: "password"}
+	userID := uuid.New()
+	tests := []struct {
+		name       string
+		setup      func(l *mocks.LimiterService)
+		info       *grpc.UnaryServerInfo
+		req        interface{}
+		session    *models.Session
+		handlerErr error
+		errCode    codes.Code
+		retryAfter time.Duration
+	}{
+		{
+			name:    "Unlimited route",
+			info:    &grpc.UnaryServerInfo{FullMethod: "/proto.Data/ListData"},
+			req:     &pb.ListDataRequest{},
+			errCode: codes.OK,
+		},
+		{
+			name: "Successful login resets limiter",
+			setup: func(l *mocks.LimiterService) {
+				l.EXPECT().Allow(ctx, "10.0.0.1", "user:username").Return(0, nil)
+				l.EXPECT().Reset(ctx, "user:username").Return(nil)
+			},
+			info:    loginInfo,
+			req:     loginReq,
+			errCode: codes.OK,
+		},
+		{
+			name: "Failed login is registered",
+			setup: func(l *mocks.LimiterService) {
+				l.EXPECT().Allow(ctx, "10.0.0.1", "user:username").Return(0, nil)
+				l.EXPECT().Fail(ctx, "10.0.0.1", "user:username").Return(nil)
+			},
+			info:       loginInfo,
+			req:        loginReq,
+			handlerErr: status.Error(codes.InvalidArgument, constants.ErrInvalidCredentials.Error()),
+			errCode:    codes.InvalidArgument,
+		},
+		{
+			name: "Duplicate user creation is registered",
+			setup: func(l *mocks.LimiterService) {
+				l.EXPECT().Allow(ctx, "10.0.0.1", "user:username").Return(0, nil)
+				l.EXPECT().Fail(ctx, "10.0.0.1", "user:username").Return(nil)
+			},
+			info:       &grpc.UnaryServerInfo{FullMethod: "/proto.User/CreateUser"},
+			req:        &pb.CreateUserRequest{Login: "username", Password: "password"},
+			handlerErr: status.Error(codes.AlreadyExists, constants.ErrUserExists.Error()),
+			errCode:    codes.AlreadyExists,
+		},
+		{
+			name: "Failed account deletion is registered for the account",
+			setup: func(l *mocks.LimiterService) {
+				l.EXPECT().Allow(mock.Anything, "10.0.0.1", "account:"+userID.String()).Return(0, nil)
+				l.EXPECT().Fail(mock.Anything, "10.0.0.1", "account:"+userID.String()).Return(nil)
+			},
+			info:       &grpc.UnaryServerInfo{FullMethod: "/proto.User/DeleteAccount"},
+			req:        &pb.DeleteAccountRequest{Password: "wrong"},
+			session:    &models.Session{UserID: userID},
+			handlerErr: status.Error(codes.InvalidArgument, constants.ErrInvalidCredentials.Error()),
+			errCode:    codes.InvalidArgument,
+		},
+		{
+			name: "Throttled login",
+			setup: func(l *mocks.LimiterService) {
+				l.EXPECT().Allow(ctx, "10.0.0.1", "user:username").Return(90*time.Second, constants.ErrTooManyAttempts)
+			},
+			info:       loginInfo,
+			req:        loginReq,
+			errCode:    codes.ResourceExhausted,
+			retryAfter: 90 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiterMock := new(mocks.LimiterService)
+			if tt.setup != nil {
+				tt.setup(limiterMock)
+			}
+			interceptor := &RateLimitServerInterceptor{
+				limiter: limiterMock,
+				limitedRoutes: map[string]struct{}{
+					"/proto.User/CreateUser":    struct{}{},
+					"/proto.User/LogInUser":     struct{}{},
+					"/proto.User/DeleteAccount": struct{}{},
+				},
+			}
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, tt.handlerErr
+			}
+			reqCtx := ctx
+			if tt.session != nil {
+				reqCtx = context.WithValue(ctx, models.SessionKey{}, tt.session)
+			}
+			_, err := interceptor.UnaryInterceptor(reqCtx, tt.req, tt.info, handler)
+			st, _ := status.FromError(err)
+			require.Equal(t, tt.errCode, st.Code())
+			if tt.retryAfter > 0 {
+				require.Len(t, st.Details(), 1)
+				retryInfo, ok := st.Details()[0].(*errdetails.RetryInfo)
+				require.True(t, ok)
+				require.Equal(t, tt.retryAfter, retryInfo.RetryDelay.AsDuration())
+			}
+			limiterMock.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS limiter_state (
+    key text UNIQUE NOT NULL PRIMARY KEY,
+    tokens double precision NOT NULL DEFAULT 0,
+    updated_at timestamp,
+    failures integer NOT NULL DEFAULT 0,
+    locked_until timestamp
+);
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "limiter_state";
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS devices (
+    id uuid UNIQUE NOT NULL PRIMARY KEY,
+    user_id uuid NOT NULL,
+    name text NOT NULL,
+    serial text NOT NULL,
+    created_at timestamp DEFAULT CURRENT_TIMESTAMP,
+    expires_at timestamp NOT NULL,
+    revoked boolean NOT NULL DEFAULT false,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "devices";
+-- +goose StatementEnd
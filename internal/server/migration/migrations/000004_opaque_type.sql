@@ -0,0 +1,10 @@
+-- +goose Up
+-- Item types are defined by the clients, the server stores them as opaque strings.
+ALTER TABLE data DROP CONSTRAINT IF EXISTS data_type_check;
+ALTER TABLE data ALTER COLUMN type TYPE text;
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE data ALTER COLUMN type TYPE varchar(10);
+ALTER TABLE data ADD CONSTRAINT data_type_check CHECK (type IN ('Card', 'Cred', 'Binary', 'Text'));
+-- +goose StatementEnd
@@ -0,0 +1,263 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/Mldlr/storety/internal/server/models"
+
+	uuid "github.com/google/uuid"
+
+	x509 "crypto/x509"
+)
+
+// DeviceService is an autogenerated mock type for the Service type
+type DeviceService struct {
+	mock.Mock
+}
+
+type DeviceService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *DeviceService) EXPECT() *DeviceService_Expecter {
+	return &DeviceService_Expecter{mock: &_m.Mock}
+}
+
+// Enroll provides a mock function with given fields: ctx, userID, name, csr
+func (_m *DeviceService) Enroll(ctx context.Context, userID uuid.UUID, name string, csr []byte) (*models.Device, []byte, error) {
+	ret := _m.Called(ctx, userID, name, csr)
+
+	var r0 *models.Device
+	var r1 []byte
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, []byte) (*models.Device, []byte, error)); ok {
+		return rf(ctx, userID, name, csr)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, []byte) *models.Device); ok {
+		r0 = rf(ctx, userID, name, csr)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Device)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string, []byte) []byte); ok {
+		r1 = rf(ctx, userID, name, csr)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID, string, []byte) error); ok {
+		r2 = rf(ctx, userID, name, csr)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// DeviceService_Enroll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Enroll'
+type DeviceService_Enroll_Call struct {
+	*mock.Call
+}
+
+// Enroll is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - name string
+//   - csr []byte
+func (_e *DeviceService_Expecter) Enroll(ctx interface{}, userID interface{}, name interface{}, csr interface{}) *DeviceService_Enroll_Call {
+	return &DeviceService_Enroll_Call{Call: _e.mock.On("Enroll", ctx, userID, name, csr)}
+}
+
+func (_c *DeviceService_Enroll_Call) Run(run func(ctx context.Context, userID uuid.UUID, name string, csr []byte)) *DeviceService_Enroll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string), args[3].([]byte))
+	})
+	return _c
+}
+
+func (_c *DeviceService_Enroll_Call) Return(_a0 *models.Device, _a1 []byte, _a2 error) *DeviceService_Enroll_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *DeviceService_Enroll_Call) RunAndReturn(run func(context.Context, uuid.UUID, string, []byte) (*models.Device, []byte, error)) *DeviceService_Enroll_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDevices provides a mock function with given fields: ctx, userID
+func (_m *DeviceService) GetDevices(ctx context.Context, userID uuid.UUID) ([]models.Device, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []models.Device
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]models.Device, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []models.Device); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Device)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeviceService_GetDevices_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDevices'
+type DeviceService_GetDevices_Call struct {
+	*mock.Call
+}
+
+// GetDevices is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *DeviceService_Expecter) GetDevices(ctx interface{}, userID interface{}) *DeviceService_GetDevices_Call {
+	return &DeviceService_GetDevices_Call{Call: _e.mock.On("GetDevices", ctx, userID)}
+}
+
+func (_c *DeviceService_GetDevices_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *DeviceService_GetDevices_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *DeviceService_GetDevices_Call) Return(_a0 []models.Device, _a1 error) *DeviceService_GetDevices_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DeviceService_GetDevices_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]models.Device, error)) *DeviceService_GetDevices_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeDevice provides a mock function with given fields: ctx, userID, deviceID
+func (_m *DeviceService) RevokeDevice(ctx context.Context, userID uuid.UUID, deviceID uuid.UUID) error {
+	ret := _m.Called(ctx, userID, deviceID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID, deviceID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeviceService_RevokeDevice_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeDevice'
+type DeviceService_RevokeDevice_Call struct {
+	*mock.Call
+}
+
+// RevokeDevice is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - deviceID uuid.UUID
+func (_e *DeviceService_Expecter) RevokeDevice(ctx interface{}, userID interface{}, deviceID interface{}) *DeviceService_RevokeDevice_Call {
+	return &DeviceService_RevokeDevice_Call{Call: _e.mock.On("RevokeDevice", ctx, userID, deviceID)}
+}
+
+func (_c *DeviceService_RevokeDevice_Call) Run(run func(ctx context.Context, userID uuid.UUID, deviceID uuid.UUID)) *DeviceService_RevokeDevice_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *DeviceService_RevokeDevice_Call) Return(_a0 error) *DeviceService_RevokeDevice_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *DeviceService_RevokeDevice_Call) RunAndReturn(run func(context.Context, uuid.UUID, uuid.UUID) error) *DeviceService_RevokeDevice_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// VerifyDevice provides a mock function with given fields: ctx, cert
+func (_m *DeviceService) VerifyDevice(ctx context.Context, cert *x509.Certificate) (*models.Device, error) {
+	ret := _m.Called(ctx, cert)
+
+	var r0 *models.Device
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *x509.Certificate) (*models.Device, error)); ok {
+		return rf(ctx, cert)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *x509.Certificate) *models.Device); ok {
+		r0 = rf(ctx, cert)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Device)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *x509.Certificate) error); ok {
+		r1 = rf(ctx, cert)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeviceService_VerifyDevice_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyDevice'
+type DeviceService_VerifyDevice_Call struct {
+	*mock.Call
+}
+
+// VerifyDevice is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cert *x509.Certificate
+func (_e *DeviceService_Expecter) VerifyDevice(ctx interface{}, cert interface{}) *DeviceService_VerifyDevice_Call {
+	return &DeviceService_VerifyDevice_Call{Call: _e.mock.On("VerifyDevice", ctx, cert)}
+}
+
+func (_c *DeviceService_VerifyDevice_Call) Run(run func(ctx context.Context, cert *x509.Certificate)) *DeviceService_VerifyDevice_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*x509.Certificate))
+	})
+	return _c
+}
+
+func (_c *DeviceService_VerifyDevice_Call) Return(_a0 *models.Device, _a1 error) *DeviceService_VerifyDevice_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DeviceService_VerifyDevice_Call) RunAndReturn(run func(context.Context, *x509.Certificate) (*models.Device, error)) *DeviceService_VerifyDevice_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+type mockConstructorTestingTNewDeviceService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewDeviceService creates a new instance of DeviceService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewDeviceService(t mockConstructorTestingTNewDeviceService) *DeviceService {
+	mock := &DeviceService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,180 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// LimiterService is an autogenerated mock type for the Service type
+type LimiterService struct {
+	mock.Mock
+}
+
+type LimiterService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *LimiterService) EXPECT() *LimiterService_Expecter {
+	return &LimiterService_Expecter{mock: &_m.Mock}
+}
+
+// Allow provides a mock function with given fields: ctx, ip, user
+func (_m *LimiterService) Allow(ctx context.Context, ip string, user string) (time.Duration, error) {
+	ret := _m.Called(ctx, ip, user)
+
+	var r0 time.Duration
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (time.Duration, error)); ok {
+		return rf(ctx, ip, user)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) time.Duration); ok {
+		r0 = rf(ctx, ip, user)
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, ip, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LimiterService_Allow_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Allow'
+type LimiterService_Allow_Call struct {
+	*mock.Call
+}
+
+// Allow is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ip string
+//   - user string
+func (_e *LimiterService_Expecter) Allow(ctx interface{}, ip interface{}, user interface{}) *LimiterService_Allow_Call {
+	return &LimiterService_Allow_Call{Call: _e.mock.On("Allow", ctx, ip, user)}
+}
+
+func (_c *LimiterService_Allow_Call) Run(run func(ctx context.Context, ip string, user string)) *LimiterService_Allow_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *LimiterService_Allow_Call) Return(_a0 time.Duration, _a1 error) *LimiterService_Allow_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *LimiterService_Allow_Call) RunAndReturn(run func(context.Context, string, string) (time.Duration, error)) *LimiterService_Allow_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Fail provides a mock function with given fields: ctx, ip, user
+func (_m *LimiterService) Fail(ctx context.Context, ip string, user string) error {
+	ret := _m.Called(ctx, ip, user)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, ip, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LimiterService_Fail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Fail'
+type LimiterService_Fail_Call struct {
+	*mock.Call
+}
+
+// Fail is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ip string
+//   - user string
+func (_e *LimiterService_Expecter) Fail(ctx interface{}, ip interface{}, user interface{}) *LimiterService_Fail_Call {
+	return &LimiterService_Fail_Call{Call: _e.mock.On("Fail", ctx, ip, user)}
+}
+
+func (_c *LimiterService_Fail_Call) Run(run func(ctx context.Context, ip string, user string)) *LimiterService_Fail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *LimiterService_Fail_Call) Return(_a0 error) *LimiterService_Fail_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *LimiterService_Fail_Call) RunAndReturn(run func(context.Context, string, string) error) *LimiterService_Fail_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Reset provides a mock function with given fields: ctx, user
+func (_m *LimiterService) Reset(ctx context.Context, user string) error {
+	ret := _m.Called(ctx, user)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LimiterService_Reset_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Reset'
+type LimiterService_Reset_Call struct {
+	*mock.Call
+}
+
+// Reset is a helper method to define mock.On call
+//   - ctx context.Context
+//   - user string
+func (_e *LimiterService_Expecter) Reset(ctx interface{}, user interface{}) *LimiterService_Reset_Call {
+	return &LimiterService_Reset_Call{Call: _e.mock.On("Reset", ctx, user)}
+}
+
+func (_c *LimiterService_Reset_Call) Run(run func(ctx context.Context, user string)) *LimiterService_Reset_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *LimiterService_Reset_Call) Return(_a0 error) *LimiterService_Reset_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *LimiterService_Reset_Call) RunAndReturn(run func(context.Context, string) error) *LimiterService_Reset_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+type mockConstructorTestingTNewLimiterService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewLimiterService creates a new instance of LimiterService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewLimiterService(t mockConstructorTestingTNewLimiterService) *LimiterService {
+	mock := &LimiterService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,127 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/Mldlr/storety/internal/server/models"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// LimiterStorage is an autogenerated mock type for the LimiterStorage type
+type LimiterStorage struct {
+	mock.Mock
+}
+
+type LimiterStorage_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *LimiterStorage) EXPECT() *LimiterStorage_Expecter {
+	return &LimiterStorage_Expecter{mock: &_m.Mock}
+}
+
+// DeleteIdleLimiterStates provides a mock function with given fields: ctx, before
+func (_m *LimiterStorage) DeleteIdleLimiterStates(ctx context.Context, before time.Time) error {
+	ret := _m.Called(ctx, before)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) error); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LimiterStorage_DeleteIdleLimiterStates_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteIdleLimiterStates'
+type LimiterStorage_DeleteIdleLimiterStates_Call struct {
+	*mock.Call
+}
+
+// DeleteIdleLimiterStates is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+func (_e *LimiterStorage_Expecter) DeleteIdleLimiterStates(ctx interface{}, before interface{}) *LimiterStorage_DeleteIdleLimiterStates_Call {
+	return &LimiterStorage_DeleteIdleLimiterStates_Call{Call: _e.mock.On("DeleteIdleLimiterStates", ctx, before)}
+}
+
+func (_c *LimiterStorage_DeleteIdleLimiterStates_Call) Run(run func(ctx context.Context, before time.Time)) *LimiterStorage_DeleteIdleLimiterStates_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *LimiterStorage_DeleteIdleLimiterStates_Call) Return(_a0 error) *LimiterStorage_DeleteIdleLimiterStates_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *LimiterStorage_DeleteIdleLimiterStates_Call) RunAndReturn(run func(context.Context, time.Time) error) *LimiterStorage_DeleteIdleLimiterStates_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateLimiterStates provides a mock function with given fields: ctx, keys, fn
+func (_m *LimiterStorage) UpdateLimiterStates(ctx context.Context, keys []string, fn func([]*models.LimiterState) error) error {
+	ret := _m.Called(ctx, keys, fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, func([]*models.LimiterState) error) error); ok {
+		r0 = rf(ctx, keys, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LimiterStorage_UpdateLimiterStates_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateLimiterStates'
+type LimiterStorage_UpdateLimiterStates_Call struct {
+	*mock.Call
+}
+
+// UpdateLimiterStates is a helper method to define mock.On call
+//   - ctx context.Context
+//   - keys []string
+//   - fn func([]*models.LimiterState) error
+func (_e *LimiterStorage_Expecter) UpdateLimiterStates(ctx interface{}, keys interface{}, fn interface{}) *LimiterStorage_UpdateLimiterStates_Call {
+	return &LimiterStorage_UpdateLimiterStates_Call{Call: _e.mock.On("UpdateLimiterStates", ctx, keys, fn)}
+}
+
+func (_c *LimiterStorage_UpdateLimiterStates_Call) Run(run func(ctx context.Context, keys []string, fn func([]*models.LimiterState) error)) *LimiterStorage_UpdateLimiterStates_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string), args[2].(func([]*models.LimiterState) error))
+	})
+	return _c
+}
+
+func (_c *LimiterStorage_UpdateLimiterStates_Call) Return(_a0 error) *LimiterStorage_UpdateLimiterStates_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *LimiterStorage_UpdateLimiterStates_Call) RunAndReturn(run func(context.Context, []string, func([]*models.LimiterState) error) error) *LimiterStorage_UpdateLimiterStates_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+type mockConstructorTestingTNewLimiterStorage interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewLimiterStorage creates a new instance of LimiterStorage. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewLimiterStorage(t mockConstructorTestingTNewLimiterStorage) *LimiterStorage {
+	mock := &LimiterStorage{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
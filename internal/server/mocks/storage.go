@@ -112,6 +112,49 @@ func (_c *Storage_CreateData_Call) RunAndReturn(run func(context.Context, uuid.U
 	return _c
 }
 
+// CreateDevice provides a mock function with given fields: ctx, device
+func (_m *Storage) CreateDevice(ctx context.Context, device *models.Device) error {
+	ret := _m.Called(ctx, device)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Device) error); ok {
+		r0 = rf(ctx, device)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Storage_CreateDevice_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateDevice'
+type Storage_CreateDevice_Call struct {
+	*mock.Call
+}
+
+// CreateDevice is a helper method to define mock.On call
+//   - ctx context.Context
+//   - device *models.Device
+func (_e *Storage_Expecter) CreateDevice(ctx interface{}, device interface{}) *Storage_CreateDevice_Call {
+	return &Storage_CreateDevice_Call{Call: _e.mock.On("CreateDevice", ctx, device)}
+}
+
+func (_c *Storage_CreateDevice_Call) Run(run func(ctx context.Context, device *models.Device)) *Storage_CreateDevice_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.Device))
+	})
+	return _c
+}
+
+func (_c *Storage_CreateDevice_Call) Return(_a0 error) *Storage_CreateDevice_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Storage_CreateDevice_Call) RunAndReturn(run func(context.Context, *models.Device) error) *Storage_CreateDevice_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateSession provides a mock function with given fields: ctx, session, oldSession
 func (_m *Storage) CreateSession(ctx context.Context, session *models.Session, oldSession *models.Session) error {
 	ret := _m.Called(ctx, session, oldSession)
@@ -243,6 +286,49 @@ func (_c *Storage_DeleteDataByName_Call) RunAndReturn(run func(context.Context,
 	return _c
 }
 
+// DeleteUser provides a mock function with given fields: ctx, userID
+func (_m *Storage) DeleteUser(ctx context.Context, userID uuid.UUID) error {
+	ret := _m.Called(ctx, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Storage_DeleteUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteUser'
+type Storage_DeleteUser_Call struct {
+	*mock.Call
+}
+
+// DeleteUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *Storage_Expecter) DeleteUser(ctx interface{}, userID interface{}) *Storage_DeleteUser_Call {
+	return &Storage_DeleteUser_Call{Call: _e.mock.On("DeleteUser", ctx, userID)}
+}
+
+func (_c *Storage_DeleteUser_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *Storage_DeleteUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *Storage_DeleteUser_Call) Return(_a0 error) *Storage_DeleteUser_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Storage_DeleteUser_Call) RunAndReturn(run func(context.Context, uuid.UUID) error) *Storage_DeleteUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetAllDataInfo provides a mock function with given fields: ctx, userID
 func (_m *Storage) GetAllDataInfo(ctx context.Context, userID uuid.UUID) ([]models.DataInfo, error) {
 	ret := _m.Called(ctx, userID)
@@ -426,6 +512,116 @@ func (_c *Storage_GetDataContentByName_Call) RunAndReturn(run func(context.Conte
 	return _c
 }
 
+// GetDevice provides a mock function with given fields: ctx, deviceID
+func (_m *Storage) GetDevice(ctx context.Context, deviceID uuid.UUID) (*models.Device, error) {
+	ret := _m.Called(ctx, deviceID)
+
+	var r0 *models.Device
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*models.Device, error)); ok {
+		return rf(ctx, deviceID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *models.Device); ok {
+		r0 = rf(ctx, deviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Device)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, deviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Storage_GetDevice_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDevice'
+type Storage_GetDevice_Call struct {
+	*mock.Call
+}
+
+// GetDevice is a helper method to define mock.On call
+//   - ctx context.Context
+//   - deviceID uuid.UUID
+func (_e *Storage_Expecter) GetDevice(ctx interface{}, deviceID interface{}) *Storage_GetDevice_Call {
+	return &Storage_GetDevice_Call{Call: _e.mock.On("GetDevice", ctx, deviceID)}
+}
+
+func (_c *Storage_GetDevice_Call) Run(run func(ctx context.Context, deviceID uuid.UUID)) *Storage_GetDevice_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *Storage_GetDevice_Call) Return(_a0 *models.Device, _a1 error) *Storage_GetDevice_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Storage_GetDevice_Call) RunAndReturn(run func(context.Context, uuid.UUID) (*models.Device, error)) *Storage_GetDevice_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDevices provides a mock function with given fields: ctx, userID
+func (_m *Storage) GetDevices(ctx context.Context, userID uuid.UUID) ([]models.Device, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []models.Device
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]models.Device, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []models.Device); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Device)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Storage_GetDevices_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDevices'
+type Storage_GetDevices_Call struct {
+	*mock.Call
+}
+
+// GetDevices is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *Storage_Expecter) GetDevices(ctx interface{}, userID interface{}) *Storage_GetDevices_Call {
+	return &Storage_GetDevices_Call{Call: _e.mock.On("GetDevices", ctx, userID)}
+}
+
+func (_c *Storage_GetDevices_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *Storage_GetDevices_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *Storage_GetDevices_Call) Return(_a0 []models.Device, _a1 error) *Storage_GetDevices_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Storage_GetDevices_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]models.Device, error)) *Storage_GetDevices_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetNewData provides a mock function with given fields: ctx, userID, ids
 func (_m *Storage) GetNewData(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) ([]models.Data, error) {
 	ret := _m.Called(ctx, userID, ids)
@@ -607,6 +803,103 @@ func (_c *Storage_GetUserDataByName_Call) RunAndReturn(run func(context.Context,
 	return _c
 }
 
+// GetUserPasswordByID provides a mock function with given fields: ctx, userID
+func (_m *Storage) GetUserPasswordByID(ctx context.Context, userID uuid.UUID) (string, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (string, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) string); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Storage_GetUserPasswordByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserPasswordByID'
+type Storage_GetUserPasswordByID_Call struct {
+	*mock.Call
+}
+
+// GetUserPasswordByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *Storage_Expecter) GetUserPasswordByID(ctx interface{}, userID interface{}) *Storage_GetUserPasswordByID_Call {
+	return &Storage_GetUserPasswordByID_Call{Call: _e.mock.On("GetUserPasswordByID", ctx, userID)}
+}
+
+func (_c *Storage_GetUserPasswordByID_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *Storage_GetUserPasswordByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *Storage_GetUserPasswordByID_Call) Return(_a0 string, _a1 error) *Storage_GetUserPasswordByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Storage_GetUserPasswordByID_Call) RunAndReturn(run func(context.Context, uuid.UUID) (string, error)) *Storage_GetUserPasswordByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeDevice provides a mock function with given fields: ctx, userID, deviceID
+func (_m *Storage) RevokeDevice(ctx context.Context, userID uuid.UUID, deviceID uuid.UUID) error {
+	ret := _m.Called(ctx, userID, deviceID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID, deviceID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Storage_RevokeDevice_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeDevice'
+type Storage_RevokeDevice_Call struct {
+	*mock.Call
+}
+
+// RevokeDevice is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - deviceID uuid.UUID
+func (_e *Storage_Expecter) RevokeDevice(ctx interface{}, userID interface{}, deviceID interface{}) *Storage_RevokeDevice_Call {
+	return &Storage_RevokeDevice_Call{Call: _e.mock.On("RevokeDevice", ctx, userID, deviceID)}
+}
+
+func (_c *Storage_RevokeDevice_Call) Run(run func(ctx context.Context, userID uuid.UUID, deviceID uuid.UUID)) *Storage_RevokeDevice_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *Storage_RevokeDevice_Call) Return(_a0 error) *Storage_RevokeDevice_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Storage_RevokeDevice_Call) RunAndReturn(run func(context.Context, uuid.UUID, uuid.UUID) error) *Storage_RevokeDevice_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateBatch provides a mock function with given fields: ctx, userID, dataBatch
 func (_m *Storage) UpdateBatch(ctx context.Context, userID uuid.UUID, dataBatch []models.Data) error {
 	ret := _m.Called(ctx, userID, dataBatch)
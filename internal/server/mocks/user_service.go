@@ -7,6 +7,8 @@ import (
 
 	models "github.com/Mldlr/storety/internal/server/models"
 	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
 )
 
 // UserService is an autogenerated mock type for the Service type
@@ -77,6 +79,50 @@ func (_c *UserService_CreateUser_Call) RunAndReturn(run func(context.Context, *m
 	return _c
 }
 
+// DeleteAccount provides a mock function with given fields: ctx, userID, password
+func (_m *UserService) DeleteAccount(ctx context.Context, userID uuid.UUID, password string) error {
+	ret := _m.Called(ctx, userID, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, userID, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserService_DeleteAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteAccount'
+type UserService_DeleteAccount_Call struct {
+	*mock.Call
+}
+
+// DeleteAccount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - password string
+func (_e *UserService_Expecter) DeleteAccount(ctx interface{}, userID interface{}, password interface{}) *UserService_DeleteAccount_Call {
+	return &UserService_DeleteAccount_Call{Call: _e.mock.On("DeleteAccount", ctx, userID, password)}
+}
+
+func (_c *UserService_DeleteAccount_Call) Run(run func(ctx context.Context, userID uuid.UUID, password string)) *UserService_DeleteAccount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *UserService_DeleteAccount_Call) Return(_a0 error) *UserService_DeleteAccount_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserService_DeleteAccount_Call) RunAndReturn(run func(context.Context, uuid.UUID, string) error) *UserService_DeleteAccount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // LogInUser provides a mock function with given fields: ctx, _a1
 func (_m *UserService) LogInUser(ctx context.Context, _a1 *models.User) (*models.Session, string, error) {
 	ret := _m.Called(ctx, _a1)
@@ -25,6 +25,20 @@ type Session struct {
 	ID           uuid.UUID
 }
 
+// DeviceKey for retrieval of the device from the context.
+type DeviceKey struct{}
+
+// Device is a device of a user enrolled with a client certificate issued by the server CA.
+type Device struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Name      string
+	Serial    string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	Revoked   bool
+}
+
 // Data is the data model.
 type Data struct {
 	ID        uuid.UUID
@@ -48,3 +62,11 @@ type SyncData struct {
 	UpdatedAt time.Time
 	Hash      string
 }
+
+// LimiterState is the token bucket and lockout state of a single rate limiter key.
+type LimiterState struct {
+	Tokens      float64
+	UpdatedAt   time.Time
+	Failures    int
+	LockedUntil time.Time
+}
@@ -0,0 +1,215 @@
+package tls
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/Mldlr/storety/internal/constants"
+	"github.com/Mldlr/storety/internal/server/config"
+	"github.com/google/uuid"
+	"math/big"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// deviceURIScheme is the scheme of the URI SAN binding a device certificate to a user and a device.
+const deviceURIScheme = "storety"
+
+// CA is the internal certificate authority issuing the server certificate and the device client certificates.
+type CA struct {
+	cert    *x509.Certificate
+	certPEM []byte
+	key     crypto.Signer
+}
+
+// LoadOrCreateCA loads the CA certificate and key from the configured files.
+// If either file is missing, a new CA is generated and written to them.
+func LoadOrCreateCA(c *config.Config) (*CA, error) {
+	_, certErr := os.Stat(c.CAFile)
+	_, keyErr := os.Stat(c.CAKeyFile)
+	if certErr != nil || keyErr != nil {
+		return createCA(c)
+	}
+	certPEM, err := os.ReadFile(c.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA cert: %v", err)
+	}
+	keyPEM, err := os.ReadFile(c.CAKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA key: %v", err)
+	}
+	return parseCA(certPEM, keyPEM)
+}
+
+// createCA generates a new self-signed CA and writes its certificate and key to .pem files.
+func createCA(c *config.Config) (*CA, error) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate CA key: %v", err)
+	}
+	serialNumber, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			Organization: []string{"Storety"},
+			CommonName:   "Storety CA",
+		},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().AddDate(10, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, priv.Public(), priv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CA certificate: %v", err)
+	}
+	privBytes, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal CA key: %v", err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certBytes})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privBytes})
+	err = os.WriteFile(c.CAKeyFile, keyPEM, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write CA key to file: %v", err)
+	}
+	err = os.WriteFile(c.CAFile, certPEM, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write CA cert to file: %v", err)
+	}
+	return parseCA(certPEM, keyPEM)
+}
+
+// parseCA parses the PEM encoded CA certificate and PKCS #8 key.
+func parseCA(certPEM, keyPEM []byte) (*CA, error) {
+	certBlock, _ := pem.Decode(certPEM)
+	if certBlock == nil || certBlock.Type != "CERTIFICATE" {
+		return nil, errors.New("failed to decode CA cert pem")
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA cert: %v", err)
+	}
+	if !cert.IsCA {
+		return nil, errors.New("CA cert is not a certificate authority")
+	}
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil || keyBlock.Type != "PRIVATE KEY" {
+		return nil, errors.New("failed to decode CA key pem")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA key: %v", err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("CA key can not sign")
+	}
+	return &CA{cert: cert, certPEM: certPEM, key: signer}, nil
+}
+
+// CertPEM returns the PEM encoded CA certificate.
+func (ca *CA) CertPEM() []byte {
+	return ca.certPEM
+}
+
+// Pool returns a certificate pool containing the CA certificate.
+func (ca *CA) Pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	return pool
+}
+
+// SignDeviceCSR issues a client certificate for the public key of the PEM encoded certificate signing request.
+// The subject requested in the CSR is ignored, the certificate is bound to the user and the device with a URI SAN.
+// It returns the parsed and the PEM encoded certificate.
+func (ca *CA) SignDeviceCSR(csrPEM []byte, userID, deviceID uuid.UUID, lifetime time.Duration) (*x509.Certificate, []byte, error) {
+	block, _ := pem.Decode(csrPEM)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, nil, fmt.Errorf("%w: failed to decode pem", constants.ErrInvalidCSR)
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", constants.ErrInvalidCSR, err)
+	}
+	if err = csr.CheckSignature(); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", constants.ErrInvalidCSR, err)
+	}
+	serialNumber, err := newSerialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			Organization: []string{"Storety"},
+			CommonName:   deviceID.String(),
+		},
+		URIs:        []*url.URL{deviceURI(userID, deviceID)},
+		NotBefore:   time.Now(),
+		NotAfter:    time.Now().Add(lifetime),
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, ca.cert, csr.PublicKey, ca.key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create device certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(certBytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse device certificate: %v", err)
+	}
+	return cert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certBytes}), nil
+}
+
+// DeviceIdentity returns the user and device IDs a device certificate was issued for.
+func DeviceIdentity(cert *x509.Certificate) (uuid.UUID, uuid.UUID, error) {
+	for _, u := range cert.URIs {
+		if u.Scheme != deviceURIScheme || !strings.HasPrefix(u.Path, "/devices/") {
+			continue
+		}
+		userID, err := uuid.Parse(u.Host)
+		if err != nil {
+			return uuid.Nil, uuid.Nil, fmt.Errorf("invalid user in device certificate: %v", err)
+		}
+		deviceID, err := uuid.Parse(strings.TrimPrefix(u.Path, "/devices/"))
+		if err != nil {
+			return uuid.Nil, uuid.Nil, fmt.Errorf("invalid device in device certificate: %v", err)
+		}
+		return userID, deviceID, nil
+	}
+	return uuid.Nil, uuid.Nil, errors.New("certificate is not bound to a device")
+}
+
+// SerialString returns the serial number of the certificate in the form stored for devices.
+func SerialString(cert *x509.Certificate) string {
+	return cert.SerialNumber.Text(16)
+}
+
+// deviceURI returns the URI SAN identifying the device of the user.
+func deviceURI(userID, deviceID uuid.UUID) *url.URL {
+	return &url.URL{Scheme: deviceURIScheme, Host: userID.String(), Path: "/devices/" + deviceID.String()}
+}
+
+// newSerialNumber returns a random 128 bit certificate serial number.
+func newSerialNumber() (*big.Int, error) {
+	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
+	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %v", err)
+	}
+	return serialNumber, nil
+}
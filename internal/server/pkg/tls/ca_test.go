@@ -0,0 +1,111 @@
+package tls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/Mldlr/storety/internal/constants"
+	"github.com/Mldlr/storety/internal/server/config"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadOrCreateCA(t *testing.T) {
+	tmpDir := t.TempDir()
+	c := &config.Config{
+		CAFile:    filepath.Join(tmpDir, "ca.pem"),
+		CAKeyFile: filepath.Join(tmpDir, "ca_key.pem"),
+	}
+
+	ca, err := LoadOrCreateCA(c)
+	require.NoError(t, err)
+	assert.True(t, ca.cert.IsCA)
+
+	info, err := os.Stat(c.CAKeyFile)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	loaded, err := LoadOrCreateCA(c)
+	require.NoError(t, err)
+	assert.Equal(t, ca.CertPEM(), loaded.CertPEM())
+
+	err = os.WriteFile(c.CAKeyFile, []byte("broken"), 0600)
+	require.NoError(t, err)
+	_, err = LoadOrCreateCA(c)
+	assert.Error(t, err)
+}
+
+func TestCA_SignDeviceCSR(t *testing.T) {
+	tmpDir := t.TempDir()
+	ca, err := LoadOrCreateCA(&config.Config{
+		CAFile:    filepath.Join(tmpDir, "ca.pem"),
+		CAKeyFile: filepath.Join(tmpDir, "ca_key.pem"),
+	})
+	require.NoError(t, err)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	csrBytes, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: "laptop"},
+	}, key)
+	require.NoError(t, err)
+	csrPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrBytes})
+
+	userID := uuid.New()
+	deviceID := uuid.New()
+
+	tests := []struct {
+		name    string
+		csr     []byte
+		wantErr error
+	}{
+		{
+			name: "Sign CSR successfully",
+			csr:  csrPEM,
+		},
+		{
+			name:    "Not a pem",
+			csr:     []byte("csr"),
+			wantErr: constants.ErrInvalidCSR,
+		},
+		{
+			name:    "Not a CSR",
+			csr:     pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: []byte("csr")}),
+			wantErr: constants.ErrInvalidCSR,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cert, certPEM, err := ca.SignDeviceCSR(tt.csr, userID, deviceID, time.Hour)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, certPEM)
+			assert.Equal(t, deviceID.String(), cert.Subject.CommonName)
+
+			_, err = cert.Verify(x509.VerifyOptions{Roots: ca.Pool(), KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}})
+			assert.NoError(t, err)
+
+			gotUserID, gotDeviceID, err := DeviceIdentity(cert)
+			assert.NoError(t, err)
+			assert.Equal(t, userID, gotUserID)
+			assert.Equal(t, deviceID, gotDeviceID)
+			assert.NotEmpty(t, SerialString(cert))
+		})
+	}
+}
+
+func TestDeviceIdentity(t *testing.T) {
+	_, _, err := DeviceIdentity(&x509.Certificate{})
+	assert.Error(t, err)
+}
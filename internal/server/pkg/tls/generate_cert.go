@@ -2,6 +2,10 @@ package tls
 
 import (
 	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha1"
@@ -10,45 +14,110 @@ import (
 	"encoding/pem"
 	"fmt"
 	"github.com/Mldlr/storety/internal/server/config"
-	"math/big"
 	"net"
 	"os"
+	"strings"
 	"time"
 )
 
+// Supported key types of generated certificates.
+const (
+	KeyTypeECDSA   = "ecdsa"
+	KeyTypeEd25519 = "ed25519"
+	KeyTypeRSA     = "rsa"
+)
+
+// CertOptions describes the certificate created by GenerateCert.
+type CertOptions struct {
+	// Hosts are the DNS names and IP addresses the certificate is valid for.
+	Hosts []string
+	// KeyType is one of KeyTypeECDSA, KeyTypeEd25519 and KeyTypeRSA.
+	KeyType string
+	// RSABits is the size of RSA keys.
+	RSABits int
+	// Validity is the lifetime of the certificate.
+	Validity time.Duration
+	// CertFile and KeyFile are the output paths of the certificate and the key.
+	CertFile string
+	KeyFile  string
+}
+
+// NewCertOptions returns the options of the server certificate generated on startup from the config.
+func NewCertOptions(c *config.Config) CertOptions {
+	return CertOptions{
+		Hosts:    ParseHosts(c.CertHosts),
+		KeyType:  c.CertKeyType,
+		RSABits:  2048,
+		Validity: time.Duration(c.CertValidityDays) * 24 * time.Hour,
+		CertFile: c.CertFile,
+		KeyFile:  c.KeyFile,
+	}
+}
+
+// ParseHosts splits a comma separated list of DNS names and IP addresses.
+func ParseHosts(hosts string) []string {
+	var list []string
+	for _, h := range strings.Split(hosts, ",") {
+		if h = strings.TrimSpace(h); h != "" {
+			list = append(list, h)
+		}
+	}
+	return list
+}
+
 // GenerateCert generates a new pair of tls certificate and key and writes them to .pem files.
-func GenerateCert(c *config.Config) error {
-	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+// The certificate is signed by the CA, or self-signed if the CA is nil. The key is only readable by the owner.
+func GenerateCert(opts CertOptions, ca *CA) error {
+	if len(opts.Hosts) == 0 {
+		return fmt.Errorf("no hosts for certificate")
+	}
+	priv, err := generateKey(opts.KeyType, opts.RSABits)
 	if err != nil {
 		err = fmt.Errorf("failed to generate private key: %v", err)
 		return err
 	}
 
-	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
-	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
+	serialNumber, err := newSerialNumber()
 	if err != nil {
-		err = fmt.Errorf("failed to generate serial number: %v", err)
 		return err
 	}
 
-	keyBytes := x509.MarshalPKCS1PublicKey(&priv.PublicKey)
+	keyBytes, err := x509.MarshalPKIXPublicKey(priv.Public())
+	if err != nil {
+		err = fmt.Errorf("failed to marshal public key: %v", err)
+		return err
+	}
 	keyHash := sha1.Sum(keyBytes)
 	ski := keyHash[:]
 
 	template := &x509.Certificate{
 		SerialNumber: serialNumber,
 		Subject: pkix.Name{
-			Organization: []string{"shortener"},
+			Organization: []string{"Storety"},
+			CommonName:   opts.Hosts[0],
 		},
-		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
 		NotBefore:    time.Now(),
-		NotAfter:     time.Now().AddDate(1, 0, 0),
+		NotAfter:     time.Now().Add(opts.Validity),
 		SubjectKeyId: ski,
 		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
 		KeyUsage:     x509.KeyUsageDigitalSignature,
 	}
+	for _, h := range opts.Hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, h)
+		}
+	}
+	if _, ok := priv.(*rsa.PrivateKey); ok {
+		template.KeyUsage |= x509.KeyUsageKeyEncipherment
+	}
 
-	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, priv.Public(), priv)
+	parent, signer := template, priv
+	if ca != nil {
+		parent, signer = ca.cert, ca.key
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, parent, priv.Public(), signer)
 	if err != nil {
 		err = fmt.Errorf("failed to create certificate: %v", err)
 		return err
@@ -61,12 +130,6 @@ func GenerateCert(c *config.Config) error {
 		return err
 	}
 
-	err = os.WriteFile(c.CertFile, certBuf.Bytes(), 0644)
-	if err != nil {
-		err = fmt.Errorf("failed to write cert key to file: %v", err)
-		return err
-	}
-
 	privBytes, err := x509.MarshalPKCS8PrivateKey(priv)
 	if err != nil {
 		err = fmt.Errorf("failed to marshal private key: %v", err)
@@ -80,11 +143,52 @@ func GenerateCert(c *config.Config) error {
 		return err
 	}
 
-	err = os.WriteFile(c.KeyFile, privKeyBuf.Bytes(), 0644)
+	// The key is written first, so a reloading server never pairs the new certificate with the old key.
+	err = writeFile(opts.KeyFile, privKeyBuf.Bytes(), 0600)
 	if err != nil {
 		err = fmt.Errorf("failed to write private key to file: %v", err)
 		return err
 	}
 
+	err = writeFile(opts.CertFile, certBuf.Bytes(), 0644)
+	if err != nil {
+		err = fmt.Errorf("failed to write cert key to file: %v", err)
+		return err
+	}
+
 	return nil
 }
+
+// generateKey generates a private key of the given type.
+func generateKey(keyType string, rsaBits int) (crypto.Signer, error) {
+	switch strings.ToLower(keyType) {
+	case KeyTypeECDSA, "":
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case KeyTypeEd25519:
+		_, priv, err := ed25519.GenerateKey(rand.Reader)
+		return priv, err
+	case KeyTypeRSA:
+		if rsaBits < 2048 {
+			return nil, fmt.Errorf("rsa keys must have at least 2048 bits, got %d", rsaBits)
+		}
+		return rsa.GenerateKey(rand.Reader, rsaBits)
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", keyType)
+	}
+}
+
+// writeFile atomically replaces the file with the data by renaming a temporary file written next to it.
+// The permissions of the file are set to perm even if it already existed.
+func writeFile(name string, data []byte, perm os.FileMode) error {
+	tmp := name + ".tmp"
+	err := os.WriteFile(tmp, data, perm)
+	if err != nil {
+		return err
+	}
+	err = os.Chmod(tmp, perm)
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, name)
+}
@@ -1,13 +1,19 @@
 package tls
 
 import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
 	"github.com/Mldlr/storety/internal/server/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestGenerateCert(t *testing.T) {
@@ -15,31 +21,111 @@ func TestGenerateCert(t *testing.T) {
 	assert.NoError(t, err)
 	defer os.RemoveAll(tmpDir)
 
-	c := &config.Config{
-		CertFile: filepath.Join(tmpDir, "cert.pem"),
-		KeyFile:  filepath.Join(tmpDir, "key.pem"),
+	ca, err := LoadOrCreateCA(&config.Config{
+		CAFile:    filepath.Join(tmpDir, "ca.pem"),
+		CAKeyFile: filepath.Join(tmpDir, "ca_key.pem"),
+	})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		keyType    string
+		selfSigned bool
+		wantKey    interface{}
+		wantErr    bool
+	}{
+		{
+			name:    "ECDSA signed by CA",
+			keyType: KeyTypeECDSA,
+			wantKey: &ecdsa.PrivateKey{},
+		},
+		{
+			name:    "Ed25519 signed by CA",
+			keyType: KeyTypeEd25519,
+			wantKey: ed25519.PrivateKey{},
+		},
+		{
+			name:       "Self-signed RSA",
+			keyType:    KeyTypeRSA,
+			selfSigned: true,
+			wantKey:    &rsa.PrivateKey{},
+		},
+		{
+			name:    "Unsupported key type",
+			keyType: "dsa",
+			wantErr: true,
+		},
 	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := CertOptions{
+				Hosts:    []string{"storety.example.com", "10.0.0.1"},
+				KeyType:  tt.keyType,
+				RSABits:  2048,
+				Validity: 48 * time.Hour,
+				CertFile: filepath.Join(tmpDir, tt.keyType+"_cert.pem"),
+				KeyFile:  filepath.Join(tmpDir, tt.keyType+"_key.pem"),
+			}
+			signer := ca
+			if tt.selfSigned {
+				signer = nil
+			}
+			err := GenerateCert(opts, signer)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
 
-	err = GenerateCert(c)
-	assert.NoError(t, err)
+			certData, err := os.ReadFile(opts.CertFile)
+			assert.NoError(t, err)
 
-	certData, err := os.ReadFile(c.CertFile)
-	assert.NoError(t, err)
+			block, _ := pem.Decode(certData)
+			assert.NotNil(t, block)
+			assert.Equal(t, block.Type, "CERTIFICATE")
 
-	block, _ := pem.Decode(certData)
-	assert.NotNil(t, block)
-	assert.Equal(t, block.Type, "CERTIFICATE")
+			cert, err := x509.ParseCertificate(block.Bytes)
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"storety.example.com"}, cert.DNSNames)
+			assert.True(t, cert.IPAddresses[0].Equal(net.ParseIP("10.0.0.1")))
+			assert.WithinDuration(t, time.Now().Add(48*time.Hour), cert.NotAfter, time.Minute)
 
-	_, err = x509.ParseCertificate(block.Bytes)
-	assert.NoError(t, err)
+			roots := ca.Pool()
+			if tt.selfSigned {
+				roots = x509.NewCertPool()
+				roots.AddCert(cert)
+			}
+			_, err = cert.Verify(x509.VerifyOptions{
+				DNSName:   "storety.example.com",
+				Roots:     roots,
+				KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+			})
+			assert.NoError(t, err)
 
-	keyData, err := os.ReadFile(c.KeyFile)
-	assert.NoError(t, err)
+			info, err := os.Stat(opts.KeyFile)
+			assert.NoError(t, err)
+			assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
 
-	block, _ = pem.Decode(keyData)
-	assert.NotNil(t, block)
-	assert.Equal(t, block.Type, "PRIVATE KEY")
+			keyData, err := os.ReadFile(opts.KeyFile)
+			assert.NoError(t, err)
 
-	_, err = x509.ParsePKCS8PrivateKey(block.Bytes)
-	assert.NoError(t, err)
+			block, _ = pem.Decode(keyData)
+			assert.NotNil(t, block)
+			assert.Equal(t, block.Type, "PRIVATE KEY")
+
+			key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+			assert.NoError(t, err)
+			assert.IsType(t, tt.wantKey, key)
+		})
+	}
+}
+
+func TestGenerateCert_NoHosts(t *testing.T) {
+	err := GenerateCert(CertOptions{KeyType: KeyTypeECDSA}, nil)
+	assert.Error(t, err)
+}
+
+func TestParseHosts(t *testing.T) {
+	assert.Equal(t, []string{"localhost", "127.0.0.1", "::1"}, ParseHosts(" localhost, 127.0.0.1,,::1 "))
+	assert.Nil(t, ParseHosts(""))
 }
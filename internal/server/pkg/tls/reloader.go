@@ -0,0 +1,137 @@
+package tls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"go.uber.org/zap"
+	"os"
+	"sync"
+	"time"
+)
+
+// expiryWarning is how long before expiry the reloader starts warning about the certificate.
+const expiryWarning = 30 * 24 * time.Hour
+
+// CertReloader serves the server certificate to new TLS handshakes and reloads it when the files change.
+// Established connections keep the certificate they were opened with.
+type CertReloader struct {
+	certFile string
+	keyFile  string
+	log      *zap.Logger
+	now      func() time.Time
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	modTimes [2]time.Time
+	lastWarn time.Time
+}
+
+// NewCertReloader loads the certificate and key from the files and returns a reloader serving them.
+func NewCertReloader(certFile, keyFile string, log *zap.Logger) (*CertReloader, error) {
+	r := &CertReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		log:      log,
+		now:      time.Now,
+	}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload loads the certificate and key from the files. On failure the previous certificate keeps being served.
+func (r *CertReloader) Reload() error {
+	modTimes, err := r.stat()
+	if err != nil {
+		return err
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load certificate: %v", err)
+	}
+	cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return fmt.Errorf("failed to parse certificate: %v", err)
+	}
+	r.mu.Lock()
+	r.cert = &cert
+	r.modTimes = modTimes
+	r.lastWarn = time.Time{}
+	r.mu.Unlock()
+	r.log.Info("loaded certificate", zap.String("file", r.certFile), zap.Time("not_after", cert.Leaf.NotAfter))
+	r.checkExpiry()
+	return nil
+}
+
+// GetCertificate returns the current certificate, it is meant to be used as tls.Config.GetCertificate.
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// Watch checks the files every interval until the context is done, reloading the certificate when either file changed.
+// It also repeats the expiry warning once a day.
+func (r *CertReloader) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if r.changed() {
+				if err := r.Reload(); err != nil {
+					r.log.Error("failed to reload certificate", zap.Error(err))
+				}
+				continue
+			}
+			r.checkExpiry()
+		}
+	}
+}
+
+// changed reports whether the modification time of the certificate or the key differs from the loaded ones.
+func (r *CertReloader) changed() bool {
+	modTimes, err := r.stat()
+	if err != nil {
+		return false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return modTimes != r.modTimes
+}
+
+// stat returns the modification times of the certificate and the key files.
+func (r *CertReloader) stat() ([2]time.Time, error) {
+	var modTimes [2]time.Time
+	for i, name := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(name)
+		if err != nil {
+			return modTimes, err
+		}
+		modTimes[i] = info.ModTime()
+	}
+	return modTimes, nil
+}
+
+// checkExpiry logs a warning if the certificate expires within expiryWarning, at most once a day.
+func (r *CertReloader) checkExpiry() {
+	now := r.now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	left := r.cert.Leaf.NotAfter.Sub(now)
+	if left > expiryWarning || now.Sub(r.lastWarn) < 24*time.Hour {
+		return
+	}
+	r.lastWarn = now
+	if left <= 0 {
+		r.log.Error("certificate expired", zap.String("file", r.certFile), zap.Time("not_after", r.cert.Leaf.NotAfter))
+		return
+	}
+	r.log.Warn("certificate expires soon", zap.String("file", r.certFile),
+		zap.Time("not_after", r.cert.Leaf.NotAfter), zap.Duration("left", left.Round(time.Hour)))
+}
@@ -0,0 +1,80 @@
+package tls
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCertReloader(t *testing.T) {
+	tmpDir := t.TempDir()
+	opts := CertOptions{
+		Hosts:    []string{"localhost"},
+		KeyType:  KeyTypeECDSA,
+		Validity: 365 * 24 * time.Hour,
+		CertFile: filepath.Join(tmpDir, "cert.pem"),
+		KeyFile:  filepath.Join(tmpDir, "key.pem"),
+	}
+	require.NoError(t, GenerateCert(opts, nil))
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	r, err := NewCertReloader(opts.CertFile, opts.KeyFile, zap.New(core))
+	require.NoError(t, err)
+	first, err := r.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.Zero(t, logs.FilterLevelExact(zapcore.WarnLevel).Len())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Watch(ctx, 10*time.Millisecond)
+
+	opts.Validity = 10 * 24 * time.Hour
+	require.NoError(t, GenerateCert(opts, nil))
+	future := time.Now().Add(time.Second)
+	require.NoError(t, os.Chtimes(opts.CertFile, future, future))
+	assert.Eventually(t, func() bool {
+		cert, _ := r.GetCertificate(nil)
+		return cert != first
+	}, time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		return logs.FilterMessage("certificate expires soon").Len() == 1
+	}, time.Second, 10*time.Millisecond)
+
+	second, _ := r.GetCertificate(nil)
+	require.NoError(t, os.WriteFile(opts.KeyFile, []byte("broken"), 0600))
+	assert.Error(t, r.Reload())
+	cert, _ := r.GetCertificate(nil)
+	assert.Same(t, second, cert)
+}
+
+func TestCertReloader_checkExpiry(t *testing.T) {
+	tmpDir := t.TempDir()
+	opts := CertOptions{
+		Hosts:    []string{"localhost"},
+		KeyType:  KeyTypeECDSA,
+		Validity: 40 * 24 * time.Hour,
+		CertFile: filepath.Join(tmpDir, "cert.pem"),
+		KeyFile:  filepath.Join(tmpDir, "key.pem"),
+	}
+	require.NoError(t, GenerateCert(opts, nil))
+	core, logs := observer.New(zapcore.InfoLevel)
+	r, err := NewCertReloader(opts.CertFile, opts.KeyFile, zap.New(core))
+	require.NoError(t, err)
+
+	now := time.Now().Add(20 * 24 * time.Hour)
+	r.now = func() time.Time { return now }
+	r.checkExpiry()
+	r.checkExpiry()
+	assert.Equal(t, 1, logs.FilterMessage("certificate expires soon").Len())
+
+	now = now.Add(25 * 24 * time.Hour)
+	r.checkExpiry()
+	assert.Equal(t, 1, logs.FilterMessage("certificate expired").Len())
+}
@@ -0,0 +1,31 @@
+// Package device provides the interface for the device enrollment service.
+package device
+
+import (
+	"context"
+	"crypto/x509"
+	"github.com/Mldlr/storety/internal/server/models"
+	"github.com/google/uuid"
+)
+
+// Service is the interface for the device service.
+//
+// Service issues client certificates for the user's devices from the server CA,
+// maps presented client certificates to enrolled devices and revokes them.
+//
+//go:generate mockery --name=Service -r --case underscore --with-expecter --structname DeviceService --filename device_service.go
+type Service interface {
+	// Enroll signs the PEM encoded certificate signing request of a new device of the user and stores the device.
+	// It returns the enrolled device and its PEM encoded client certificate, or constants.ErrInvalidCSR.
+	Enroll(ctx context.Context, userID uuid.UUID, name string, csr []byte) (*models.Device, []byte, error)
+
+	// GetDevices returns all devices enrolled by the user.
+	GetDevices(ctx context.Context, userID uuid.UUID) ([]models.Device, error)
+
+	// RevokeDevice revokes the client certificate of the user's device.
+	RevokeDevice(ctx context.Context, userID, deviceID uuid.UUID) error
+
+	// VerifyDevice returns the enrolled device the verified client certificate was issued for,
+	// or constants.ErrDeviceRevoked if the device was revoked or the certificate replaced.
+	VerifyDevice(ctx context.Context, cert *x509.Certificate) (*models.Device, error)
+}
@@ -0,0 +1,88 @@
+package device
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"github.com/Mldlr/storety/internal/constants"
+	"github.com/Mldlr/storety/internal/server/config"
+	"github.com/Mldlr/storety/internal/server/models"
+	pkgTls "github.com/Mldlr/storety/internal/server/pkg/tls"
+	"github.com/Mldlr/storety/internal/server/storage"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"time"
+)
+
+// ServiceImpl is the implementation of the device service.
+type ServiceImpl struct {
+	storage  storage.Storage
+	ca       *pkgTls.CA
+	lifetime time.Duration
+}
+
+// NewService creates a new device service.
+func NewService(i *do.Injector) *ServiceImpl {
+	repo := do.MustInvoke[storage.Storage](i)
+	ca := do.MustInvoke[*pkgTls.CA](i)
+	cfg := do.MustInvoke[*config.Config](i)
+	return &ServiceImpl{
+		storage:  repo,
+		ca:       ca,
+		lifetime: time.Duration(cfg.DeviceCertLifetimeDays) * 24 * time.Hour,
+	}
+}
+
+// Enroll implements the device service interface Enroll method.
+func (s *ServiceImpl) Enroll(ctx context.Context, userID uuid.UUID, name string, csr []byte) (*models.Device, []byte, error) {
+	deviceID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, certPEM, err := s.ca.SignDeviceCSR(csr, userID, deviceID, s.lifetime)
+	if err != nil {
+		return nil, nil, err
+	}
+	device := &models.Device{
+		ID:        deviceID,
+		UserID:    userID,
+		Name:      name,
+		Serial:    pkgTls.SerialString(cert),
+		CreatedAt: cert.NotBefore,
+		ExpiresAt: cert.NotAfter,
+	}
+	err = s.storage.CreateDevice(ctx, device)
+	if err != nil {
+		return nil, nil, err
+	}
+	return device, certPEM, nil
+}
+
+// GetDevices implements the device service interface GetDevices method.
+func (s *ServiceImpl) GetDevices(ctx context.Context, userID uuid.UUID) ([]models.Device, error) {
+	return s.storage.GetDevices(ctx, userID)
+}
+
+// RevokeDevice implements the device service interface RevokeDevice method.
+func (s *ServiceImpl) RevokeDevice(ctx context.Context, userID, deviceID uuid.UUID) error {
+	return s.storage.RevokeDevice(ctx, userID, deviceID)
+}
+
+// VerifyDevice implements the device service interface VerifyDevice method.
+func (s *ServiceImpl) VerifyDevice(ctx context.Context, cert *x509.Certificate) (*models.Device, error) {
+	userID, deviceID, err := pkgTls.DeviceIdentity(cert)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", constants.ErrDeviceNotFound, err)
+	}
+	device, err := s.storage.GetDevice(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if device.UserID != userID {
+		return nil, constants.ErrDeviceNotFound
+	}
+	if device.Revoked || device.Serial != pkgTls.SerialString(cert) {
+		return nil, constants.ErrDeviceRevoked
+	}
+	return device, nil
+}
@@ -0,0 +1,149 @@
+package device
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"github.com/Mldlr/storety/internal/constants"
+	"github.com/Mldlr/storety/internal/server/config"
+	"github.com/Mldlr/storety/internal/server/mocks"
+	"github.com/Mldlr/storety/internal/server/models"
+	pkgTls "github.com/Mldlr/storety/internal/server/pkg/tls"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestService(t *testing.T, repo *mocks.Storage) *ServiceImpl {
+	tmpDir := t.TempDir()
+	ca, err := pkgTls.LoadOrCreateCA(&config.Config{
+		CAFile:    filepath.Join(tmpDir, "ca.pem"),
+		CAKeyFile: filepath.Join(tmpDir, "ca_key.pem"),
+	})
+	require.NoError(t, err)
+	return &ServiceImpl{storage: repo, ca: ca, lifetime: time.Hour}
+}
+
+func newTestCSR(t *testing.T) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{}, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr})
+}
+
+func TestService_Enroll(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("Enroll device successfully", func(t *testing.T) {
+		repo := new(mocks.Storage)
+		s := newTestService(t, repo)
+		repo.EXPECT().CreateDevice(ctx, mock.MatchedBy(func(d *models.Device) bool {
+			return d.UserID == userID && d.Name == "laptop" && d.Serial != "" && !d.Revoked
+		})).Return(nil)
+
+		device, certPEM, err := s.Enroll(ctx, userID, "laptop", newTestCSR(t))
+		require.NoError(t, err)
+		block, _ := pem.Decode(certPEM)
+		require.NotNil(t, block)
+		cert, err := x509.ParseCertificate(block.Bytes)
+		require.NoError(t, err)
+		assert.Equal(t, pkgTls.SerialString(cert), device.Serial)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), device.ExpiresAt, time.Minute)
+
+		gotUserID, gotDeviceID, err := pkgTls.DeviceIdentity(cert)
+		require.NoError(t, err)
+		assert.Equal(t, userID, gotUserID)
+		assert.Equal(t, device.ID, gotDeviceID)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Fail to enroll device with invalid CSR", func(t *testing.T) {
+		repo := new(mocks.Storage)
+		s := newTestService(t, repo)
+		_, _, err := s.Enroll(ctx, userID, "laptop", []byte("csr"))
+		assert.ErrorIs(t, err, constants.ErrInvalidCSR)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Fail to store device", func(t *testing.T) {
+		repo := new(mocks.Storage)
+		s := newTestService(t, repo)
+		repo.EXPECT().CreateDevice(ctx, mock.Anything).Return(errors.New("db error"))
+		_, _, err := s.Enroll(ctx, userID, "laptop", newTestCSR(t))
+		assert.Error(t, err)
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestService_VerifyDevice(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	deviceID := uuid.New()
+	s := newTestService(t, nil)
+	cert, _, err := s.ca.SignDeviceCSR(newTestCSR(t), userID, deviceID, time.Hour)
+	require.NoError(t, err)
+	serial := pkgTls.SerialString(cert)
+
+	tests := []struct {
+		name    string
+		stored  *models.Device
+		getErr  error
+		wantErr error
+	}{
+		{
+			name:   "Enrolled device",
+			stored: &models.Device{ID: deviceID, UserID: userID, Serial: serial},
+		},
+		{
+			name:    "Revoked device",
+			stored:  &models.Device{ID: deviceID, UserID: userID, Serial: serial, Revoked: true},
+			wantErr: constants.ErrDeviceRevoked,
+		},
+		{
+			name:    "Replaced certificate",
+			stored:  &models.Device{ID: deviceID, UserID: userID, Serial: "other"},
+			wantErr: constants.ErrDeviceRevoked,
+		},
+		{
+			name:    "Device of another user",
+			stored:  &models.Device{ID: deviceID, UserID: uuid.New(), Serial: serial},
+			wantErr: constants.ErrDeviceNotFound,
+		},
+		{
+			name:    "Unknown device",
+			getErr:  constants.ErrDeviceNotFound,
+			wantErr: constants.ErrDeviceNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.Storage)
+			s.storage = repo
+			repo.EXPECT().GetDevice(ctx, deviceID).Return(tt.stored, tt.getErr)
+			device, err := s.VerifyDevice(ctx, cert)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, device)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.stored, device)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("Certificate not bound to a device", func(t *testing.T) {
+		_, err := s.VerifyDevice(ctx, &x509.Certificate{})
+		assert.ErrorIs(t, err, constants.ErrDeviceNotFound)
+	})
+}
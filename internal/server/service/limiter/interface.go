@@ -0,0 +1,26 @@
+// Package limiter provides the interface for the login rate limiter service.
+package limiter
+
+import (
+	"context"
+	"time"
+)
+
+// Service is the interface for the limiter service.
+//
+// Service throttles authentication attempts with per-IP and per-user token buckets
+// and locks keys out with a growing backoff after repeated failures.
+// Users are given by Username for attempts naming a username and by Account for authenticated attempts.
+//
+//go:generate mockery --name=Service -r --case underscore --with-expecter --structname LimiterService --filename limiter_service.go
+type Service interface {
+	// Allow takes a token for the IP and the user, or returns constants.ErrTooManyAttempts
+	// along with the delay after which the attempt may be retried.
+	Allow(ctx context.Context, ip, user string) (time.Duration, error)
+
+	// Fail registers a failed attempt for the IP and the user, locking them out once the failure limit is reached.
+	Fail(ctx context.Context, ip, user string) error
+
+	// Reset clears failures and lockout of the user after a successful attempt.
+	Reset(ctx context.Context, user string) error
+}
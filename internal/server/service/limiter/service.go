@@ -0,0 +1,246 @@
+package limiter
+
+import (
+	"context"
+	"github.com/Mldlr/storety/internal/constants"
+	"github.com/Mldlr/storety/internal/server/config"
+	"github.com/Mldlr/storety/internal/server/models"
+	"github.com/Mldlr/storety/internal/server/storage"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"go.uber.org/zap"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// ipPrefix prefixes limiter keys of client IPs.
+	ipPrefix = "ip:"
+	// userPrefix prefixes limiter keys of usernames.
+	userPrefix = "user:"
+	// accountPrefix prefixes limiter keys of the IDs of authenticated accounts.
+	accountPrefix = "account:"
+	// maxBackoffShift caps the exponent of the lockout backoff.
+	maxBackoffShift = 16
+	// purgeInterval is the minimum time between deletions of idle limiter states.
+	purgeInterval = time.Minute
+)
+
+// rule describes a token bucket and the number of failures tolerated before lockout.
+type rule struct {
+	rate        float64
+	burst       float64
+	maxFailures int
+}
+
+// ServiceImpl is the implementation of the limiter service.
+type ServiceImpl struct {
+	storage    storage.LimiterStorage
+	ipRule     rule
+	userRule   rule
+	lockout    time.Duration
+	maxLockout time.Duration
+	now        func() time.Time
+	log        *zap.Logger
+	mu         sync.Mutex
+	lastPurge  time.Time
+}
+
+// NewService creates a new limiter service.
+func NewService(i *do.Injector) *ServiceImpl {
+	repo := do.MustInvoke[storage.LimiterStorage](i)
+	cfg := do.MustInvoke[*config.Config](i)
+	return &ServiceImpl{
+		storage: repo,
+		ipRule: rule{
+			rate:        float64(cfg.LimiterIPPerMinute) / 60,
+			burst:       float64(cfg.LimiterIPBurst),
+			maxFailures: cfg.LimiterIPMaxFailures,
+		},
+		userRule: rule{
+			rate:        float64(cfg.LimiterUserPerMinute) / 60,
+			burst:       float64(cfg.LimiterUserBurst),
+			maxFailures: cfg.LimiterUserMaxFailures,
+		},
+		lockout:    time.Duration(cfg.LimiterLockoutSeconds) * time.Second,
+		maxLockout: time.Duration(cfg.LimiterMaxLockoutMins) * time.Minute,
+		now:        time.Now,
+		log:        do.MustInvoke[*zap.Logger](i),
+	}
+}
+
+// Username returns the user of attempts naming the account by its username, like logins.
+func Username(login string) string {
+	if login == "" {
+		return ""
+	}
+	return userPrefix + login
+}
+
+// Account returns the user of attempts of an authenticated account.
+// It never equals the user of a username, so attempts on a username cannot lock out another account.
+func Account(id uuid.UUID) string {
+	return accountPrefix + id.String()
+}
+
+// Allow implements the limiter service interface Allow method.
+// The buckets of all keys are checked and taken from in a single storage update, so concurrent attempts
+// cannot overdraw them, and an attempt rejected for the user does not use up the token of the IP.
+func (s *ServiceImpl) Allow(ctx context.Context, ip, user string) (time.Duration, error) {
+	s.purge(ctx)
+	keys, rules := s.keys(ip, user)
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	var wait time.Duration
+	err := s.storage.UpdateLimiterStates(ctx, keys, func(states []*models.LimiterState) error {
+		now := s.now()
+		wait = 0
+		for i, state := range states {
+			s.refill(state, rules[i], now)
+			if d := s.wait(state, rules[i], now); d > wait {
+				wait = d
+			}
+		}
+		if wait == 0 {
+			for _, state := range states {
+				state.Tokens--
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if wait > 0 {
+		return wait, constants.ErrTooManyAttempts
+	}
+	return 0, nil
+}
+
+// Fail implements the limiter service interface Fail method.
+func (s *ServiceImpl) Fail(ctx context.Context, ip, user string) error {
+	keys, rules := s.keys(ip, user)
+	if len(keys) == 0 {
+		return nil
+	}
+	return s.storage.UpdateLimiterStates(ctx, keys, func(states []*models.LimiterState) error {
+		now := s.now()
+		for i, state := range states {
+			s.refill(state, rules[i], now)
+			state.Failures++
+			if state.Failures >= rules[i].maxFailures {
+				state.LockedUntil = now.Add(s.backoff(state.Failures - rules[i].maxFailures))
+			}
+		}
+		return nil
+	})
+}
+
+// Reset implements the limiter service interface Reset method.
+func (s *ServiceImpl) Reset(ctx context.Context, user string) error {
+	if user == "" {
+		return nil
+	}
+	return s.storage.UpdateLimiterStates(ctx, []string{user}, func(states []*models.LimiterState) error {
+		states[0].Failures = 0
+		states[0].LockedUntil = time.Time{}
+		return nil
+	})
+}
+
+// keys returns the limiter keys of the IP and the user with their rules, skipping empty ones.
+func (s *ServiceImpl) keys(ip, user string) ([]string, []rule) {
+	var keys []string
+	var rules []rule
+	if ip != "" {
+		keys = append(keys, ipPrefix+ip)
+		rules = append(rules, s.ipRule)
+	}
+	if user != "" {
+		keys = append(keys, user)
+		rules = append(rules, s.userRule)
+	}
+	return keys, rules
+}
+
+// wait returns the delay until the next attempt is allowed if the key is locked or the bucket is empty.
+func (s *ServiceImpl) wait(state *models.LimiterState, r rule, now time.Time) time.Duration {
+	if now.Before(state.LockedUntil) {
+		return state.LockedUntil.Sub(now)
+	}
+	if state.Tokens < 1 {
+		if r.rate <= 0 {
+			return s.maxLockout
+		}
+		return time.Duration((1 - state.Tokens) / r.rate * float64(time.Second))
+	}
+	return 0
+}
+
+// refill adds the tokens earned since the last update and forgets failures of keys idle for longer than the maximum lockout.
+func (s *ServiceImpl) refill(state *models.LimiterState, r rule, now time.Time) {
+	if state.UpdatedAt.IsZero() {
+		state.Tokens = r.burst
+		state.UpdatedAt = now
+		return
+	}
+	elapsed := now.Sub(state.UpdatedAt)
+	if elapsed <= 0 {
+		return
+	}
+	if elapsed > s.maxLockout && now.After(state.LockedUntil) {
+		state.Failures = 0
+	}
+	state.Tokens = math.Min(r.burst, state.Tokens+elapsed.Seconds()*r.rate)
+	state.UpdatedAt = now
+}
+
+// backoff returns the lockout duration for the given number of failures past the limit.
+func (s *ServiceImpl) backoff(extra int) time.Duration {
+	if extra > maxBackoffShift {
+		extra = maxBackoffShift
+	}
+	d := s.lockout << extra
+	if d > s.maxLockout {
+		return s.maxLockout
+	}
+	return d
+}
+
+// purge deletes the states of keys that were idle long enough to be equal to a new key, at most once per purgeInterval:
+// their buckets are full again, their lockouts are over and their failures are forgotten.
+// Failing to purge does not fail the attempt.
+func (s *ServiceImpl) purge(ctx context.Context) {
+	idle := s.idle()
+	if idle <= 0 {
+		return
+	}
+	now := s.now()
+	s.mu.Lock()
+	if now.Sub(s.lastPurge) < purgeInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastPurge = now
+	s.mu.Unlock()
+	if err := s.storage.DeleteIdleLimiterStates(ctx, now.Add(-idle)); err != nil {
+		s.log.Error("failed to purge limiter states", zap.Error(err))
+	}
+}
+
+// idle returns the time after which an untouched key is equal to a new key,
+// or zero if buckets never refill and states must be kept.
+func (s *ServiceImpl) idle() time.Duration {
+	idle := s.maxLockout
+	for _, r := range []rule{s.ipRule, s.userRule} {
+		if r.rate <= 0 {
+			return 0
+		}
+		if fill := time.Duration(r.burst / r.rate * float64(time.Second)); fill > idle {
+			idle = fill
+		}
+	}
+	return idle
+}
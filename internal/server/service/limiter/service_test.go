@@ -0,0 +1,164 @@
+package limiter
+
+import (
+	"context"
+	"github.com/Mldlr/storety/internal/constants"
+	"github.com/Mldlr/storety/internal/server/models"
+	"github.com/Mldlr/storety/internal/server/storage/memory"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestService(now *time.Time) *ServiceImpl {
+	return &ServiceImpl{
+		storage:    memory.NewLimiterStorage(),
+		ipRule:     rule{rate: 1, burst: 4, maxFailures: 10},
+		userRule:   rule{rate: 0.5, burst: 2, maxFailures: 2},
+		lockout:    10 * time.Second,
+		maxLockout: time.Minute,
+		now:        func() time.Time { return *now },
+		log:        zap.NewNop(),
+	}
+}
+
+func TestService_Allow(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	s := newTestService(&now)
+
+	for i := 0; i < 2; i++ {
+		wait, err := s.Allow(ctx, "127.0.0.1", Username("username"))
+		require.NoError(t, err)
+		require.Zero(t, wait)
+	}
+	wait, err := s.Allow(ctx, "127.0.0.1", Username("username"))
+	require.ErrorIs(t, err, constants.ErrTooManyAttempts)
+	require.Equal(t, 2*time.Second, wait)
+
+	// The rejected attempt did not use up a token of the IP.
+	for _, username := range []string{"other", "another"} {
+		wait, err = s.Allow(ctx, "127.0.0.1", Username(username))
+		require.NoError(t, err)
+		require.Zero(t, wait)
+	}
+	_, err = s.Allow(ctx, "127.0.0.1", Username("third"))
+	require.ErrorIs(t, err, constants.ErrTooManyAttempts)
+
+	now = now.Add(2 * time.Second)
+	wait, err = s.Allow(ctx, "127.0.0.1", Username("username"))
+	require.NoError(t, err)
+	require.Zero(t, wait)
+}
+
+func TestService_AllowConcurrent(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	s := newTestService(&now)
+
+	var allowed int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := s.Allow(ctx, "127.0.0.1", Username("username")); err == nil {
+				atomic.AddInt32(&allowed, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(2), allowed, "concurrent attempts cannot overdraw the bucket")
+}
+
+func TestService_Account(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	s := newTestService(&now)
+	id := uuid.New()
+
+	require.NoError(t, s.Fail(ctx, "127.0.0.1", Username(id.String())))
+	require.NoError(t, s.Fail(ctx, "127.0.0.1", Username(id.String())))
+	_, err := s.Allow(ctx, "127.0.0.2", Account(id))
+	require.NoError(t, err, "failures of a username do not lock out an account")
+
+	require.NoError(t, s.Fail(ctx, "127.0.0.2", Account(id)))
+	require.NoError(t, s.Fail(ctx, "127.0.0.2", Account(id)))
+	_, err = s.Allow(ctx, "127.0.0.3", Account(id))
+	require.ErrorIs(t, err, constants.ErrTooManyAttempts)
+}
+
+func TestService_FailAndReset(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	s := newTestService(&now)
+
+	require.NoError(t, s.Fail(ctx, "127.0.0.1", Username("username")))
+	_, err := s.Allow(ctx, "127.0.0.1", Username("username"))
+	require.NoError(t, err)
+
+	require.NoError(t, s.Fail(ctx, "127.0.0.1", Username("username")))
+	wait, err := s.Allow(ctx, "127.0.0.1", Username("username"))
+	require.ErrorIs(t, err, constants.ErrTooManyAttempts)
+	require.Equal(t, 10*time.Second, wait)
+
+	now = now.Add(10 * time.Second)
+	require.NoError(t, s.Fail(ctx, "127.0.0.1", Username("username")))
+	wait, err = s.Allow(ctx, "127.0.0.1", Username("username"))
+	require.ErrorIs(t, err, constants.ErrTooManyAttempts)
+	require.Equal(t, 20*time.Second, wait)
+
+	require.NoError(t, s.Reset(ctx, Username("username")))
+	wait, err = s.Allow(ctx, "127.0.0.1", Username("username"))
+	require.NoError(t, err)
+	require.Zero(t, wait)
+}
+
+func TestService_purge(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	s := newTestService(&now)
+
+	require.NoError(t, s.Fail(ctx, "127.0.0.1", Username("username")))
+	require.NoError(t, s.Fail(ctx, "127.0.0.1", Username("username")))
+	_, err := s.Allow(ctx, "127.0.0.2", Username("other"))
+	require.NoError(t, err)
+	now = now.Add(30 * time.Second)
+	_, err = s.Allow(ctx, "127.0.0.3", "")
+	require.NoError(t, err)
+	require.True(t, stored(t, s, "user:username"), "states are purged at most once per interval")
+
+	now = now.Add(41 * time.Second)
+	_, err = s.Allow(ctx, "127.0.0.2", "")
+	require.NoError(t, err)
+	require.False(t, stored(t, s, "ip:127.0.0.1"))
+	require.False(t, stored(t, s, "user:username"))
+	require.False(t, stored(t, s, "user:other"))
+	require.True(t, stored(t, s, "ip:127.0.0.2"))
+	require.True(t, stored(t, s, "ip:127.0.0.3"))
+}
+
+// stored reports whether a state is stored for the key.
+func stored(t *testing.T, s *ServiceImpl, key string) bool {
+	t.Helper()
+	var ok bool
+	err := s.storage.UpdateLimiterStates(context.Background(), []string{key}, func(states []*models.LimiterState) error {
+		ok = !states[0].UpdatedAt.IsZero()
+		return nil
+	})
+	require.NoError(t, err)
+	return ok
+}
+
+func TestService_backoff(t *testing.T) {
+	now := time.Now()
+	s := newTestService(&now)
+	require.Equal(t, 10*time.Second, s.backoff(0))
+	require.Equal(t, 40*time.Second, s.backoff(2))
+	require.Equal(t, time.Minute, s.backoff(3))
+	require.Equal(t, time.Minute, s.backoff(100))
+}
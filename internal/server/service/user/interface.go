@@ -4,12 +4,13 @@ package user
 import (
 	"context"
 	"github.com/Mldlr/storety/internal/server/models"
+	"github.com/google/uuid"
 )
 
 // Service is the interface for the user service.
 //
 // Service provides methods for creating a new user, logging in a user,
-// and refreshing a user session.
+// refreshing a user session and deleting a user account.
 
 //go:generate mockery --name=Service -r --case underscore --with-expecter --structname UserService --filename user_service.go
 type Service interface {
@@ -21,4 +22,7 @@ type Service interface {
 
 	// RefreshUserSession refreshes a user session and returns a new session for the user, or an error if any occurs.
 	RefreshUserSession(ctx context.Context, oldSession *models.Session) (*models.Session, error)
+
+	// DeleteAccount deletes the user's account with all of its sessions and data after checking the user's password.
+	DeleteAccount(ctx context.Context, userID uuid.UUID, password string) error
 }
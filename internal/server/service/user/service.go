@@ -116,3 +116,19 @@ func (s *ServiceImpl) RefreshUserSession(ctx context.Context, oldSession *models
 	}
 	return session, nil
 }
+
+// DeleteAccount implements the user service interface DeleteAccount method.
+func (s *ServiceImpl) DeleteAccount(ctx context.Context, userID uuid.UUID, password string) error {
+	hash, err := s.storage.GetUserPasswordByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, constants.ErrUserNotFound) {
+			return errors.Join(constants.ErrInvalidCredentials, err)
+		}
+		return err
+	}
+	err = bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if err != nil {
+		return errors.Join(constants.ErrInvalidCredentials, err)
+	}
+	return s.storage.DeleteUser(ctx, userID)
+}
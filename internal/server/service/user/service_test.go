@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
 	"testing"
 )
 
@@ -186,3 +187,57 @@ func TestService_RefreshUserSession(t *testing.T) {
 		})
 	}
 }
+
+func TestService_DeleteAccount(t *testing.T) {
+	uid, err := uuid.NewRandom()
+	assert.NoError(t, err)
+	hash, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	assert.NoError(t, err)
+	tests := []struct {
+		name      string
+		setup     func(ctx context.Context, s *mocks.Storage)
+		password  string
+		wantedErr error
+	}{
+		{
+			name: "Delete account successfully",
+			setup: func(ctx context.Context, s *mocks.Storage) {
+				s.EXPECT().GetUserPasswordByID(ctx, uid).Return(string(hash), nil)
+				s.EXPECT().DeleteUser(ctx, uid).Return(nil)
+			},
+			password:  "password",
+			wantedErr: nil,
+		},
+		{
+			name: "Fail to delete account with wrong password",
+			setup: func(ctx context.Context, s *mocks.Storage) {
+				s.EXPECT().GetUserPasswordByID(ctx, uid).Return(string(hash), nil)
+			},
+			password:  "wrong",
+			wantedErr: constants.ErrInvalidCredentials,
+		},
+		{
+			name: "Fail to delete nonexistent account",
+			setup: func(ctx context.Context, s *mocks.Storage) {
+				s.EXPECT().GetUserPasswordByID(ctx, uid).Return("", constants.ErrUserNotFound)
+			},
+			password:  "password",
+			wantedErr: constants.ErrInvalidCredentials,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockStorage := new(mocks.Storage)
+			tt.setup(ctx, mockStorage)
+			mockService := ServiceImpl{storage: mockStorage}
+			err := mockService.DeleteAccount(ctx, uid, tt.password)
+			if tt.wantedErr != nil {
+				require.ErrorIs(t, err, tt.wantedErr)
+				return
+			}
+			require.NoError(t, err)
+			mockStorage.AssertExpectations(t)
+		})
+	}
+}
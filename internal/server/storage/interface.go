@@ -6,6 +6,7 @@ import (
 	"context"
 	"github.com/Mldlr/storety/internal/server/models"
 	"github.com/google/uuid"
+	"time"
 )
 
 // Storage is the interface for the storage layer, which defines the methods for handling user sessions and data storage.
@@ -18,6 +19,12 @@ type Storage interface {
 	// GetUserDataByName retrieves the UUID, password and salt for a user with the given username.
 	GetUserDataByName(ctx context.Context, username string) (uuid.UUID, string, string, error)
 
+	// GetUserPasswordByID retrieves the password hash of a user with the given UUID.
+	GetUserPasswordByID(ctx context.Context, userID uuid.UUID) (string, error)
+
+	// DeleteUser deletes a user with the given UUID together with all of the user's sessions and data.
+	DeleteUser(ctx context.Context, userID uuid.UUID) error
+
 	// GetSession retrieves the user's UUID associated with the given session ID and refresh token.
 	GetSession(ctx context.Context, sessionID uuid.UUID, refreshToken string) (uuid.UUID, error)
 
@@ -45,7 +52,32 @@ type Storage interface {
 	// UpdateBatch updates a batch of data entries in the storage for a user.
 	UpdateBatch(ctx context.Context, userID uuid.UUID, dataBatch []models.Data) error
 
+	// CreateDevice creates a new enrolled device.
+	CreateDevice(ctx context.Context, device *models.Device) error
+
+	// GetDevice retrieves a device by its UUID.
+	GetDevice(ctx context.Context, deviceID uuid.UUID) (*models.Device, error)
+
+	// GetDevices retrieves all devices enrolled by the user with the given UUID.
+	GetDevices(ctx context.Context, userID uuid.UUID) ([]models.Device, error)
+
+	// RevokeDevice marks the device with the given UUID belonging to the user as revoked.
+	RevokeDevice(ctx context.Context, userID, deviceID uuid.UUID) error
+
 	// GetDataByUpdateAndHash retrieves data entries that were created after the last sync and have a different hash
 	// and IDs of entries that were updated locally but not synced.
 	GetDataByUpdateAndHash(ctx context.Context, userID uuid.UUID, syncData []models.SyncData) ([]models.Data, []string, error)
 }
+
+// LimiterStorage is the interface for the rate limiter state storage, which may be shared between server replicas.
+//
+//go:generate mockery --name=LimiterStorage -r --case underscore --with-expecter --structname LimiterStorage --filename limiter_storage.go
+type LimiterStorage interface {
+	// UpdateLimiterStates loads the states stored for the keys, applies fn to them and persists the results atomically,
+	// so that fn sees and changes the states of all keys at once. States are passed in the order of the keys,
+	// a key that was never stored is passed to fn as a zero state.
+	UpdateLimiterStates(ctx context.Context, keys []string, fn func(states []*models.LimiterState) error) error
+
+	// DeleteIdleLimiterStates deletes the states last updated and locked until before the given time.
+	DeleteIdleLimiterStates(ctx context.Context, before time.Time) error
+}
@@ -0,0 +1,52 @@
+// Package memory implements process local storage for state that does not need to outlive the server.
+package memory
+
+import (
+	"context"
+	"github.com/Mldlr/storety/internal/server/models"
+	"sync"
+	"time"
+)
+
+// LimiterStorage keeps rate limiter states in memory of a single server replica.
+type LimiterStorage struct {
+	mu     sync.Mutex
+	states map[string]models.LimiterState
+}
+
+// NewLimiterStorage creates a new empty LimiterStorage.
+func NewLimiterStorage() *LimiterStorage {
+	return &LimiterStorage{
+		states: make(map[string]models.LimiterState),
+	}
+}
+
+// UpdateLimiterStates implements the storage.LimiterStorage interface UpdateLimiterStates method.
+func (l *LimiterStorage) UpdateLimiterStates(ctx context.Context, keys []string, fn func(states []*models.LimiterState) error) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	states := make([]*models.LimiterState, len(keys))
+	for i, key := range keys {
+		state := l.states[key]
+		states[i] = &state
+	}
+	if err := fn(states); err != nil {
+		return err
+	}
+	for i, key := range keys {
+		l.states[key] = *states[i]
+	}
+	return nil
+}
+
+// DeleteIdleLimiterStates implements the storage.LimiterStorage interface DeleteIdleLimiterStates method.
+func (l *LimiterStorage) DeleteIdleLimiterStates(ctx context.Context, before time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for key, state := range l.states {
+		if state.UpdatedAt.Before(before) && state.LockedUntil.Before(before) {
+			delete(l.states, key)
+		}
+	}
+	return nil
+}
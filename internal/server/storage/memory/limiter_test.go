@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"github.com/Mldlr/storety/internal/server/models"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestLimiterStorage_UpdateLimiterStates(t *testing.T) {
+	ctx := context.Background()
+	s := NewLimiterStorage()
+	keys := []string{"ip:127.0.0.1", "user:test"}
+
+	err := s.UpdateLimiterStates(ctx, keys, func(states []*models.LimiterState) error {
+		require.Len(t, states, 2)
+		require.Equal(t, models.LimiterState{}, *states[1])
+		states[0].Tokens = 3
+		states[1].Failures = 1
+		return nil
+	})
+	require.NoError(t, err)
+
+	wantErr := errors.New("update failed")
+	err = s.UpdateLimiterStates(ctx, keys, func(states []*models.LimiterState) error {
+		require.Equal(t, 1, states[1].Failures)
+		states[0].Tokens = 0
+		states[1].Failures = 10
+		return wantErr
+	})
+	require.ErrorIs(t, err, wantErr)
+
+	err = s.UpdateLimiterStates(ctx, []string{"user:test", "ip:127.0.0.1"}, func(states []*models.LimiterState) error {
+		require.Equal(t, 1, states[0].Failures, "states follow the order of the keys")
+		require.Equal(t, 3.0, states[1].Tokens, "no state is changed when fn fails")
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestLimiterStorage_DeleteIdleLimiterStates(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	s := NewLimiterStorage()
+	s.states["ip:idle"] = models.LimiterState{UpdatedAt: now.Add(-time.Hour)}
+	s.states["user:locked"] = models.LimiterState{UpdatedAt: now.Add(-time.Hour), LockedUntil: now.Add(time.Minute)}
+	s.states["user:active"] = models.LimiterState{UpdatedAt: now}
+
+	require.NoError(t, s.DeleteIdleLimiterStates(ctx, now.Add(-time.Minute)))
+	require.NotContains(t, s.states, "ip:idle")
+	require.Contains(t, s.states, "user:locked")
+	require.Contains(t, s.states, "user:active")
+}
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"github.com/Mldlr/storety/internal/constants"
+	"github.com/Mldlr/storety/internal/server/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// CreateDevice implements the device service interface CreateDevice method.
+func (d *DB) CreateDevice(ctx context.Context, device *models.Device) error {
+	_, err := d.conn.Exec(ctx, createDevice, device.ID, device.UserID, device.Name, device.Serial, device.CreatedAt, device.ExpiresAt)
+	return err
+}
+
+// GetDevice implements the device service interface GetDevice method.
+func (d *DB) GetDevice(ctx context.Context, deviceID uuid.UUID) (*models.Device, error) {
+	var device models.Device
+	err := d.conn.QueryRow(ctx, getDevice, deviceID).Scan(&device.ID, &device.UserID, &device.Name, &device.Serial,
+		&device.CreatedAt, &device.ExpiresAt, &device.Revoked)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, constants.ErrDeviceNotFound
+		}
+		return nil, err
+	}
+	return &device, nil
+}
+
+// GetDevices implements the device service interface GetDevices method.
+func (d *DB) GetDevices(ctx context.Context, userID uuid.UUID) ([]models.Device, error) {
+	rows, err := d.conn.Query(ctx, getDevices, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var list []models.Device
+	for rows.Next() {
+		var device models.Device
+		err = rows.Scan(&device.ID, &device.UserID, &device.Name, &device.Serial,
+			&device.CreatedAt, &device.ExpiresAt, &device.Revoked)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, device)
+	}
+	return list, rows.Err()
+}
+
+// RevokeDevice implements the device service interface RevokeDevice method.
+func (d *DB) RevokeDevice(ctx context.Context, userID, deviceID uuid.UUID) error {
+	res, err := d.conn.Exec(ctx, revokeDevice, deviceID, userID)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return constants.ErrDeviceNotFound
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"github.com/Mldlr/storety/internal/constants"
+	"github.com/Mldlr/storety/internal/server/models"
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v2"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestDB_CreateDevice(t *testing.T) {
+	now := time.Now()
+	device := &models.Device{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		Name:      "laptop",
+		Serial:    "abc",
+		CreatedAt: now,
+		ExpiresAt: now.AddDate(1, 0, 0),
+	}
+
+	tests := []struct {
+		name    string
+		err     error
+		wantErr error
+	}{
+		{
+			name: "Create device successfully",
+		},
+		{
+			name:    "Database error",
+			err:     errors.New("db error"),
+			wantErr: errors.New("db error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			exec := mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO devices`)).
+				WithArgs(device.ID, device.UserID, device.Name, device.Serial, device.CreatedAt, device.ExpiresAt)
+			if tt.err != nil {
+				exec.WillReturnError(tt.err)
+			} else {
+				exec.WillReturnResult(pgxmock.NewResult("INSERT", 1))
+			}
+			db := &DB{conn: mock}
+			err = db.CreateDevice(context.Background(), device)
+			assert.Equal(t, tt.wantErr, err)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestDB_GetDevice(t *testing.T) {
+	now := time.Now()
+	id := uuid.New()
+	userID := uuid.New()
+	columns := []string{"id", "user_id", "name", "serial", "created_at", "expires_at", "revoked"}
+
+	tests := []struct {
+		name       string
+		rows       *pgxmock.Rows
+		wantDevice *models.Device
+		wantErr    error
+	}{
+		{
+			name: "Get device successfully",
+			rows: pgxmock.NewRows(columns).AddRow(id, userID, "laptop", "abc", now, now, true),
+			wantDevice: &models.Device{
+				ID:        id,
+				UserID:    userID,
+				Name:      "laptop",
+				Serial:    "abc",
+				CreatedAt: now,
+				ExpiresAt: now,
+				Revoked:   true,
+			},
+		},
+		{
+			name:    "Device not found",
+			rows:    pgxmock.NewRows(columns),
+			wantErr: constants.ErrDeviceNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, user_id, name, serial, created_at, expires_at, revoked`)).
+				WithArgs(id).WillReturnRows(tt.rows)
+			db := &DB{conn: mock}
+			device, err := db.GetDevice(context.Background(), id)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantDevice, device)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestDB_GetDevices(t *testing.T) {
+	now := time.Now()
+	userID := uuid.New()
+	first := models.Device{ID: uuid.New(), UserID: userID, Name: "laptop", Serial: "a", CreatedAt: now, ExpiresAt: now}
+	second := models.Device{ID: uuid.New(), UserID: userID, Name: "phone", Serial: "b", CreatedAt: now, ExpiresAt: now, Revoked: true}
+
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, user_id, name, serial, created_at, expires_at, revoked`)).
+		WithArgs(userID).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "name", "serial", "created_at", "expires_at", "revoked"}).
+			AddRow(first.ID, first.UserID, first.Name, first.Serial, first.CreatedAt, first.ExpiresAt, first.Revoked).
+			AddRow(second.ID, second.UserID, second.Name, second.Serial, second.CreatedAt, second.ExpiresAt, second.Revoked))
+	db := &DB{conn: mock}
+	devices, err := db.GetDevices(context.Background(), userID)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Device{first, second}, devices)
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestDB_RevokeDevice(t *testing.T) {
+	id := uuid.New()
+	userID := uuid.New()
+
+	tests := []struct {
+		name     string
+		affected int64
+		wantErr  error
+	}{
+		{
+			name:     "Revoke device successfully",
+			affected: 1,
+		},
+		{
+			name:     "Try to revoke device of another user",
+			affected: 0,
+			wantErr:  constants.ErrDeviceNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			mock.ExpectExec(regexp.QuoteMeta(`UPDATE devices`)).WithArgs(id, userID).
+				WillReturnResult(pgxmock.NewResult("UPDATE", tt.affected))
+			db := &DB{conn: mock}
+			err = db.RevokeDevice(context.Background(), userID, id)
+			assert.ErrorIs(t, err, tt.wantErr)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
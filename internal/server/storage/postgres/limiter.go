@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Mldlr/storety/internal/server/models"
+	"sort"
+	"time"
+)
+
+// UpdateLimiterStates implements the limiter storage interface UpdateLimiterStates method.
+// The state rows are locked for the duration of the transaction, so concurrent replicas update them one at a time.
+// Rows are locked in the order of their keys, so transactions locking the same keys cannot deadlock.
+func (d *DB) UpdateLimiterStates(ctx context.Context, keys []string, fn func(states []*models.LimiterState) error) error {
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { d.commitTx(ctx, tx, err) }()
+	order := make([]int, len(keys))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return keys[order[a]] < keys[order[b]] })
+	states := make([]*models.LimiterState, len(keys))
+	for _, i := range order {
+		_, err = tx.Exec(ctx, createLimiterState, keys[i])
+		if err != nil {
+			return err
+		}
+		state := &models.LimiterState{}
+		var updatedAt, lockedUntil sql.NullTime
+		err = tx.QueryRow(ctx, getLimiterStateForUpdate, keys[i]).Scan(&state.Tokens, &updatedAt, &state.Failures, &lockedUntil)
+		if err != nil {
+			return err
+		}
+		state.UpdatedAt = updatedAt.Time
+		state.LockedUntil = lockedUntil.Time
+		states[i] = state
+	}
+	err = fn(states)
+	if err != nil {
+		return err
+	}
+	for i, state := range states {
+		_, err = tx.Exec(ctx, updateLimiterState, keys[i], state.Tokens, nullTime(state.UpdatedAt), state.Failures, nullTime(state.LockedUntil))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteIdleLimiterStates implements the limiter storage interface DeleteIdleLimiterStates method.
+func (d *DB) DeleteIdleLimiterStates(ctx context.Context, before time.Time) error {
+	_, err := d.conn.Exec(ctx, deleteIdleLimiterStates, before.UTC())
+	return err
+}
+
+// nullTime converts a zero time to a NULL timestamp.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t.UTC(), Valid: !t.IsZero()}
+}
@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/Mldlr/storety/internal/server/models"
+	"github.com/pashagolub/pgxmock/v2"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestDB_UpdateLimiterStates(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	fnErr := errors.New("fn failed")
+	tests := []struct {
+		name    string
+		rows    *pgxmock.Rows
+		fn      func(states []*models.LimiterState) error
+		update  bool
+		wantErr error
+	}{
+		{
+			name: "Update new state",
+			rows: pgxmock.NewRows([]string{"tokens", "updated_at", "failures", "locked_until"}).
+				AddRow(0.0, sql.NullTime{}, 0, sql.NullTime{}),
+			fn: func(states []*models.LimiterState) error {
+				assert.True(t, states[0].UpdatedAt.IsZero())
+				assert.Equal(t, 2.0, states[1].Tokens, "states follow the order of the keys")
+				states[0].Tokens = 4
+				states[0].UpdatedAt = now
+				states[0].Failures = 1
+				return nil
+			},
+			update:  true,
+			wantErr: nil,
+		},
+		{
+			name: "Keep state on fn error",
+			rows: pgxmock.NewRows([]string{"tokens", "updated_at", "failures", "locked_until"}).
+				AddRow(4.0, sql.NullTime{Time: now, Valid: true}, 1, sql.NullTime{}),
+			fn: func(states []*models.LimiterState) error {
+				assert.Equal(t, 1, states[0].Failures)
+				return fnErr
+			},
+			update:  false,
+			wantErr: fnErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			// Rows are locked in the order of their keys, the IP key first.
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO limiter_state`)).
+				WithArgs("ip:127.0.0.1").WillReturnResult(pgxmock.NewResult("INSERT", 1))
+			mock.ExpectQuery(regexp.QuoteMeta(`SELECT tokens, updated_at, failures, locked_until`)).
+				WithArgs("ip:127.0.0.1").WillReturnRows(pgxmock.NewRows([]string{"tokens", "updated_at", "failures", "locked_until"}).
+				AddRow(2.0, sql.NullTime{Time: now, Valid: true}, 0, sql.NullTime{}))
+			mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO limiter_state`)).
+				WithArgs("user:login").WillReturnResult(pgxmock.NewResult("INSERT", 1))
+			mock.ExpectQuery(regexp.QuoteMeta(`SELECT tokens, updated_at, failures, locked_until`)).
+				WithArgs("user:login").WillReturnRows(tt.rows)
+			if tt.update {
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE limiter_state`)).
+					WithArgs("user:login", 4.0, sql.NullTime{Time: now, Valid: true}, 1, sql.NullTime{}).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE limiter_state`)).
+					WithArgs("ip:127.0.0.1", 2.0, sql.NullTime{Time: now, Valid: true}, 0, sql.NullTime{}).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			db := &DB{conn: mock}
+			err = db.UpdateLimiterStates(context.Background(), []string{"user:login", "ip:127.0.0.1"}, tt.fn)
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestDB_DeleteIdleLimiterStates(t *testing.T) {
+	before := time.Now().UTC()
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM limiter_state`)).
+		WithArgs(before).WillReturnResult(pgxmock.NewResult("DELETE", 2))
+
+	db := &DB{conn: mock}
+	assert.NoError(t, db.DeleteIdleLimiterStates(context.Background(), before))
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
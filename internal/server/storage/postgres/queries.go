@@ -20,6 +20,12 @@ const (
 	// getUserDataByName is a query to get a user record by its username.
 	getUserDataByName = `SELECT id, password, salt FROM users WHERE username = $1`
 
+	// getUserPasswordByID is a query to get the password hash of a user by its ID.
+	getUserPasswordByID = `SELECT password FROM users WHERE id = $1`
+
+	// deleteUser is a query to delete a user record, sessions and data are removed by cascade.
+	deleteUser = `DELETE FROM users WHERE id = $1`
+
 	// createNewSession is a query to insert a new session record.
 	createNewSession = `
 	INSERT INTO sessions (
@@ -120,4 +126,52 @@ const (
 	FROM data
 	WHERE user_id = $1 AND id = $2 AND coalesce(md5(content), '') != $3 AND updated_at > $4
 `
+
+	// createLimiterState is a query to insert an empty limiter state for a key if it does not exist yet.
+	createLimiterState = `
+	INSERT INTO limiter_state (key)
+	VALUES ($1)
+	ON CONFLICT DO NOTHING`
+
+	// getLimiterStateForUpdate is a query to get and lock the limiter state of a key.
+	getLimiterStateForUpdate = `
+	SELECT tokens, updated_at, failures, locked_until
+	FROM limiter_state
+	WHERE key = $1
+	FOR UPDATE`
+
+	// updateLimiterState is a query to update the limiter state of a key.
+	updateLimiterState = `
+	UPDATE limiter_state
+	SET tokens = $2, updated_at = $3, failures = $4, locked_until = $5
+	WHERE key = $1`
+
+	// deleteIdleLimiterStates is a query to delete the limiter states last updated and locked until before a time.
+	deleteIdleLimiterStates = `
+	DELETE FROM limiter_state
+	WHERE updated_at < $1 AND (locked_until IS NULL OR locked_until < $1)`
+
+	// createDevice is a query to insert a new device.
+	createDevice = `
+	INSERT INTO devices (id, user_id, name, serial, created_at, expires_at)
+	VALUES ($1, $2, $3, $4, $5, $6)`
+
+	// getDevice is a query to get a device by ID.
+	getDevice = `
+	SELECT id, user_id, name, serial, created_at, expires_at, revoked
+	FROM devices
+	WHERE id = $1`
+
+	// getDevices is a query to get all devices of a user.
+	getDevices = `
+	SELECT id, user_id, name, serial, created_at, expires_at, revoked
+	FROM devices
+	WHERE user_id = $1
+	ORDER BY created_at`
+
+	// revokeDevice is a query to revoke a device of a user.
+	revokeDevice = `
+	UPDATE devices
+	SET revoked = true
+	WHERE id = $1 AND user_id = $2`
 )
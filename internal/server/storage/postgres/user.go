@@ -39,3 +39,28 @@ func (d *DB) GetUserDataByName(ctx context.Context, username string) (uuid.UUID,
 	}
 	return id, password, salt, nil
 }
+
+// GetUserPasswordByID implements the user service interface GetUserPasswordByID method.
+func (d *DB) GetUserPasswordByID(ctx context.Context, userID uuid.UUID) (string, error) {
+	var password string
+	err := d.conn.QueryRow(ctx, getUserPasswordByID, userID).Scan(&password)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", constants.ErrUserNotFound
+		}
+		return "", err
+	}
+	return password, nil
+}
+
+// DeleteUser implements the user service interface DeleteUser method.
+func (d *DB) DeleteUser(ctx context.Context, userID uuid.UUID) error {
+	res, err := d.conn.Exec(ctx, deleteUser, userID)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return constants.ErrUserNotFound
+	}
+	return nil
+}
@@ -106,3 +106,86 @@ func TestDB_GetUserDataByName(t *testing.T) {
 		})
 	}
 }
+
+func TestDB_GetUserPasswordByID(t *testing.T) {
+	id, err := uuid.NewRandom()
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		rows    *pgxmock.Rows
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "Get password",
+			rows:    pgxmock.NewRows([]string{"password"}).AddRow("password"),
+			want:    "password",
+			wantErr: nil,
+		},
+		{
+			name:    "Try to get password for nonexistent user",
+			rows:    pgxmock.NewRows([]string{"password"}),
+			want:    "",
+			wantErr: constants.ErrUserNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			mock.ExpectQuery("SELECT password").WithArgs(id).WillReturnRows(tt.rows)
+			db := &DB{conn: mock}
+			password, err := db.GetUserPasswordByID(context.Background(), id)
+			assert.Equal(t, tt.want, password)
+			assert.ErrorIs(t, err, tt.wantErr)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestDB_DeleteUser(t *testing.T) {
+	id, err := uuid.NewRandom()
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		affected int64
+		wantErr  error
+	}{
+		{
+			name:     "Delete user successfully",
+			affected: 1,
+			wantErr:  nil,
+		},
+		{
+			name:     "Try to delete nonexistent user",
+			affected: 0,
+			wantErr:  constants.ErrUserNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM users`)).WithArgs(id).
+				WillReturnResult(pgxmock.NewResult("DELETE", tt.affected))
+			db := &DB{conn: mock}
+			err = db.DeleteUser(context.Background(), id)
+			assert.ErrorIs(t, err, tt.wantErr)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}